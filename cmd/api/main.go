@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
 
 	"github.com/fekuna/go-store/config"
 	"github.com/fekuna/go-store/internal/server"
@@ -12,6 +14,8 @@ import (
 	"github.com/fekuna/go-store/pkg/utils"
 )
 
+const minioConnectTimeout = 10 * time.Second
+
 // @title Go Example REST API
 // @version 1.0
 // @description Example Golang REST API
@@ -49,6 +53,22 @@ func main() {
 	defer psqlDB.Close()
 
 	minioClient, err := minioS3.NewMinioS3Client(cfg.Minio.Endpoint, cfg.Minio.MinioAccessKey, cfg.Minio.MinioSecretKey, cfg.Minio.UseSSL)
+	if err != nil {
+		appLogger.Fatalf("Minio init: %s", err)
+	}
+
+	minioTimeout := time.Second * cfg.Minio.ConnectTimeout
+	if minioTimeout == 0 {
+		minioTimeout = minioConnectTimeout
+	}
+
+	minioCtx, cancelMinio := context.WithTimeout(context.Background(), minioTimeout)
+	if err = minioS3.InitBuckets(minioCtx, minioClient, cfg.Minio.Buckets); err != nil {
+		cancelMinio()
+		appLogger.Fatalf("Minio storage %s unreachable or misconfigured: %s", cfg.Minio.Endpoint, err)
+	}
+	cancelMinio()
+	appLogger.Infof("Minio connected, Buckets: %d", len(cfg.Minio.Buckets))
 
 	s := server.NewServer(cfg, appLogger, psqlDB, minioClient)
 	if err = s.Run(); err != nil {
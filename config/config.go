@@ -10,10 +10,21 @@ import (
 
 // App config struct
 type Config struct {
-	Server   ServerConfig
-	Logger   LoggerConfig
-	Postgres PostgresConfig
-	Minio    MinioConfig
+	Server       ServerConfig
+	Logger       LoggerConfig
+	Postgres     PostgresConfig
+	Minio        MinioConfig
+	Catalog      CatalogConfig
+	Cart         CartConfig
+	Checkout     CheckoutConfig
+	Payment      PaymentConfig
+	Idempotency  IdempotencyConfig
+	Shipping     ShippingConfig
+	Returns      ReturnsConfig
+	Invoice      InvoiceConfig
+	Mailer       MailerConfig
+	Subscription SubscriptionConfig
+	CartRecovery CartRecoveryConfig
 }
 
 type ServerConfig struct {
@@ -21,6 +32,7 @@ type ServerConfig struct {
 	Port              string
 	Mode              string
 	JwtSecretKey      string
+	TokenSecretKey    string
 	ReadTimeout       time.Duration
 	WriteTimeout      time.Duration
 	CtxDefaultTimeout time.Duration
@@ -55,6 +67,110 @@ type MinioConfig struct {
 	MinioSecretKey string
 	UseSSL         bool
 	MinioEndpoint  string
+	ConnectTimeout time.Duration
+	Buckets        []MinioBucketConfig
+}
+
+// Minio bucket bootstrapped on startup
+type MinioBucketConfig struct {
+	Name           string
+	Region         string
+	Policy         string
+	ExpirationDays int
+	ExpirationPath string
+}
+
+// Catalog bulk import, body limit is in megabytes
+type CatalogConfig struct {
+	ImportBucket    string
+	ImportBodyLimit int
+	ImportMaxRows   int
+	ImportTimeout   time.Duration
+}
+
+// Cart expiration, durations are in seconds
+type CartConfig struct {
+	GuestTTL        time.Duration
+	UserTTL         time.Duration
+	CleanupInterval time.Duration
+}
+
+// Stock reservation of pending orders, durations are in seconds
+type CheckoutConfig struct {
+	ReservationTTL time.Duration
+	ExpiryInterval time.Duration
+}
+
+// Payment gateway, provider is fake or stripe. Durations are in seconds.
+type PaymentConfig struct {
+	Provider         string
+	SecretKey        string
+	WebhookSecret    string
+	APIBase          string
+	Timeout          time.Duration
+	WebhookTolerance time.Duration
+}
+
+// Idempotency-Key storage, durations are in seconds
+type IdempotencyConfig struct {
+	TTL             time.Duration
+	LockTimeout     time.Duration
+	CleanupInterval time.Duration
+}
+
+// Live shipping rates, carrier is fake or none
+type ShippingConfig struct {
+	Carrier string
+}
+
+// Return requests, window counts from delivery and is in seconds
+type ReturnsConfig struct {
+	Window time.Duration
+}
+
+// Invoices and credit notes, numbers run per store code and document type. Files are kept in the bucket and
+// download links expire after LinkTTL seconds.
+type InvoiceConfig struct {
+	StoreCode     string
+	Bucket        string
+	LinkTTL       time.Duration
+	SellerName    string
+	SellerAddress []string
+	SellerTaxID   string
+}
+
+// Outgoing email, provider is smtp or log. The log provider only writes messages to the application log.
+type MailerConfig struct {
+	Provider string
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Subscription scheduler, durations are in seconds. A failed charge is retried RetryDelay times the number of
+// failures later until MaxAttempts charges failed, then the subscription is suspended. ClaimTTL keeps a run
+// picked up by one instance away from the others.
+type SubscriptionConfig struct {
+	RunInterval time.Duration
+	BatchSize   int
+	ClaimTTL    time.Duration
+	RetryDelay  time.Duration
+	MaxAttempts int
+}
+
+// Abandoned cart reminders, durations are in seconds. Reminder n is sent once the cart was idle for Reminders[n]
+// and as long after the previous reminder, an order within AttributionWindow of the first reminder counts as
+// recovered. The links get the signed token as token query param, restore links expire after RestoreLinkTTL.
+type CartRecoveryConfig struct {
+	RunInterval       time.Duration
+	BatchSize         int
+	Reminders         []time.Duration
+	AttributionWindow time.Duration
+	RestoreURL        string
+	RestoreLinkTTL    time.Duration
+	UnsubscribeURL    string
 }
 
 // Load config file from given path
@@ -0,0 +1,12 @@
+package address
+
+import "github.com/labstack/echo/v4"
+
+// Address HTTP Handlers interface
+type Handlers interface {
+	Create() echo.HandlerFunc
+	Update() echo.HandlerFunc
+	Delete() echo.HandlerFunc
+	GetByID() echo.HandlerFunc
+	List() echo.HandlerFunc
+}
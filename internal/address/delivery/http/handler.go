@@ -0,0 +1,168 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/address"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Address handlers
+type addressHandlers struct {
+	cfg       *config.Config
+	logger    logger.Logger
+	addressUC address.UseCase
+}
+
+// Address handlers constructor
+func NewAddressHandlers(cfg *config.Config, logger logger.Logger, addressUC address.UseCase) address.Handlers {
+	return &addressHandlers{
+		cfg:       cfg,
+		logger:    logger,
+		addressUC: addressUC,
+	}
+}
+
+// Create godoc
+// @Summary Create address
+// @Description add address to own address book, the first address becomes default shipping and billing address
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Address
+// @Failure 400 {object} httpErrors.RestError
+// @Router /auth/me/addresses [post]
+func (h *addressHandlers) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		a := &models.Address{}
+		if err := utils.ReadRequest(c, a); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		createdAddress, err := h.addressUC.Create(ctx, a)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, createdAddress)
+	}
+}
+
+// Update godoc
+// @Summary Update address
+// @Description replace own address, setting a default flag moves it from the previous default address
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Param address_id path string true "address_id"
+// @Success 200 {object} models.Address
+// @Failure 400 {object} httpErrors.RestError
+// @Router /auth/me/addresses/{address_id} [put]
+func (h *addressHandlers) Update() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		addressID, err := uuid.Parse(c.Param("address_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		a := &models.Address{}
+		if err = utils.ReadRequest(c, a); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		a.AddressID = addressID
+
+		updatedAddress, err := h.addressUC.Update(ctx, a)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, updatedAddress)
+	}
+}
+
+// Delete godoc
+// @Summary Delete address
+// @Description delete own address
+// @Tags Addresses
+// @Param address_id path string true "address_id"
+// @Success 204
+// @Failure 404 {object} httpErrors.RestError
+// @Router /auth/me/addresses/{address_id} [delete]
+func (h *addressHandlers) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		addressID, err := uuid.Parse(c.Param("address_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.addressUC.Delete(ctx, addressID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetByID godoc
+// @Summary Get address
+// @Description get own address
+// @Tags Addresses
+// @Produce json
+// @Param address_id path string true "address_id"
+// @Success 200 {object} models.Address
+// @Failure 404 {object} httpErrors.RestError
+// @Router /auth/me/addresses/{address_id} [get]
+func (h *addressHandlers) GetByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		addressID, err := uuid.Parse(c.Param("address_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		a, err := h.addressUC.GetByID(ctx, addressID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, a)
+	}
+}
+
+// List godoc
+// @Summary Get addresses
+// @Description get own address book, default addresses first
+// @Tags Addresses
+// @Produce json
+// @Success 200 {array} models.Address
+// @Failure 500 {object} httpErrors.RestError
+// @Router /auth/me/addresses [get]
+func (h *addressHandlers) List() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		addresses, err := h.addressUC.List(ctx)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, addresses)
+	}
+}
@@ -0,0 +1,17 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/address"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func MapAddressRoutes(addressGroup *echo.Group, h address.Handlers, mw *middleware.MiddlewareManager) {
+	addressGroup.Use(mw.AuthJWTMiddleware)
+
+	addressGroup.GET("", h.List())
+	addressGroup.POST("", h.Create())
+	addressGroup.GET("/:address_id", h.GetByID())
+	addressGroup.PUT("/:address_id", h.Update())
+	addressGroup.DELETE("/:address_id", h.Delete())
+}
@@ -0,0 +1,20 @@
+package address
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Address repository, every method only sees addresses of userID
+type Repository interface {
+	Create(ctx context.Context, a *models.Address) (*models.Address, error)
+	Update(ctx context.Context, a *models.Address) (*models.Address, error)
+	Delete(ctx context.Context, userID uuid.UUID, addressID uuid.UUID) error
+	GetByID(ctx context.Context, userID uuid.UUID, addressID uuid.UUID) (*models.Address, error)
+	GetDefaultShipping(ctx context.Context, userID uuid.UUID) (*models.Address, error)
+	GetDefaultBilling(ctx context.Context, userID uuid.UUID) (*models.Address, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Address, error)
+	CountByUser(ctx context.Context, userID uuid.UUID) (int, error)
+}
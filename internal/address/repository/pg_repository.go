@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fekuna/go-store/internal/address"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Address repository
+type addressRepo struct {
+	db *sqlx.DB
+}
+
+// Address repository constructor
+func NewAddressRepository(db *sqlx.DB) address.Repository {
+	return &addressRepo{db: db}
+}
+
+// Create address, a new default takes the flag from the previous one
+func (r *addressRepo) Create(ctx context.Context, a *models.Address) (*models.Address, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "addressRepo.Create.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	if err = clearDefaults(ctx, tx, a); err != nil {
+		return nil, errors.Wrap(err, "addressRepo.Create.clearDefaults")
+	}
+
+	created := &models.Address{}
+	if err = tx.QueryRowxContext(
+		ctx,
+		createAddressQuery,
+		&a.UserID,
+		&a.Label,
+		&a.FirstName,
+		&a.LastName,
+		a.Company,
+		&a.Line1,
+		a.Line2,
+		&a.City,
+		a.Region,
+		a.Postcode,
+		&a.Country,
+		a.PhoneNumber,
+		&a.IsDefaultShipping,
+		&a.IsDefaultBilling,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "addressRepo.Create.StructScan")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "addressRepo.Create.Commit")
+	}
+
+	return created, nil
+}
+
+// Update address, a new default takes the flag from the previous one
+func (r *addressRepo) Update(ctx context.Context, a *models.Address) (*models.Address, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "addressRepo.Update.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	if err = clearDefaults(ctx, tx, a); err != nil {
+		return nil, errors.Wrap(err, "addressRepo.Update.clearDefaults")
+	}
+
+	updated := &models.Address{}
+	if err = tx.GetContext(
+		ctx,
+		updated,
+		updateAddressQuery,
+		&a.Label,
+		&a.FirstName,
+		&a.LastName,
+		a.Company,
+		&a.Line1,
+		a.Line2,
+		&a.City,
+		a.Region,
+		a.Postcode,
+		&a.Country,
+		a.PhoneNumber,
+		&a.IsDefaultShipping,
+		&a.IsDefaultBilling,
+		&a.AddressID,
+		&a.UserID,
+	); err != nil {
+		return nil, errors.Wrap(err, "addressRepo.Update.GetContext")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "addressRepo.Update.Commit")
+	}
+
+	return updated, nil
+}
+
+func (r *addressRepo) Delete(ctx context.Context, userID uuid.UUID, addressID uuid.UUID) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, deleteAddressQuery, addressID, userID)
+	if err != nil {
+		return errors.Wrap(err, "addressRepo.Delete.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "addressRepo.Delete.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "addressRepo.Delete.rowsAffected")
+	}
+
+	return nil
+}
+
+func (r *addressRepo) GetByID(ctx context.Context, userID uuid.UUID, addressID uuid.UUID) (*models.Address, error) {
+	// TODO: Tracing
+
+	a := &models.Address{}
+	if err := r.db.GetContext(ctx, a, getAddressByIdQuery, addressID, userID); err != nil {
+		return nil, errors.Wrap(err, "addressRepo.GetByID.GetContext")
+	}
+
+	return a, nil
+}
+
+func (r *addressRepo) GetDefaultShipping(ctx context.Context, userID uuid.UUID) (*models.Address, error) {
+	// TODO: Tracing
+
+	a := &models.Address{}
+	if err := r.db.GetContext(ctx, a, getDefaultShippingQuery, userID); err != nil {
+		return nil, errors.Wrap(err, "addressRepo.GetDefaultShipping.GetContext")
+	}
+
+	return a, nil
+}
+
+func (r *addressRepo) GetDefaultBilling(ctx context.Context, userID uuid.UUID) (*models.Address, error) {
+	// TODO: Tracing
+
+	a := &models.Address{}
+	if err := r.db.GetContext(ctx, a, getDefaultBillingQuery, userID); err != nil {
+		return nil, errors.Wrap(err, "addressRepo.GetDefaultBilling.GetContext")
+	}
+
+	return a, nil
+}
+
+// Defaults first, then oldest first
+func (r *addressRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Address, error) {
+	// TODO: Tracing
+
+	addresses := make([]*models.Address, 0)
+	if err := r.db.SelectContext(ctx, &addresses, listAddressesByUserQuery, userID); err != nil {
+		return nil, errors.Wrap(err, "addressRepo.ListByUser.SelectContext")
+	}
+
+	return addresses, nil
+}
+
+func (r *addressRepo) CountByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	// TODO: Tracing
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, countAddressesByUserQuery, userID); err != nil {
+		return 0, errors.Wrap(err, "addressRepo.CountByUser.GetContext")
+	}
+
+	return count, nil
+}
+
+// Unset the defaults a is about to take over, a new address has no id yet so every other address is cleared
+func clearDefaults(ctx context.Context, tx *sqlx.Tx, a *models.Address) error {
+	if a.IsDefaultShipping {
+		if _, err := tx.ExecContext(ctx, clearDefaultShippingQuery, a.UserID, a.AddressID); err != nil {
+			return errors.Wrap(err, "shipping")
+		}
+	}
+	if a.IsDefaultBilling {
+		if _, err := tx.ExecContext(ctx, clearDefaultBillingQuery, a.UserID, a.AddressID); err != nil {
+			return errors.Wrap(err, "billing")
+		}
+	}
+
+	return nil
+}
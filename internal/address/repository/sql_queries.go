@@ -0,0 +1,45 @@
+package repository
+
+const (
+	createAddressQuery = `
+		INSERT INTO addresses(user_id, label, first_name, last_name, company, line1, line2, city, region, postcode,
+			country, phone_number, is_default_shipping, is_default_billing, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, now(), now())
+		RETURNING *
+	`
+
+	updateAddressQuery = `
+		UPDATE addresses
+		SET label = $1, first_name = $2, last_name = $3, company = $4, line1 = $5, line2 = $6, city = $7,
+			region = $8, postcode = $9, country = $10, phone_number = $11, is_default_shipping = $12,
+			is_default_billing = $13, updated_at = now()
+		WHERE address_id = $14 AND user_id = $15
+		RETURNING *
+	`
+
+	// Run before setting a new default so the partial unique indexes hold
+	clearDefaultShippingQuery = `
+		UPDATE addresses SET is_default_shipping = FALSE, updated_at = now()
+		WHERE user_id = $1 AND address_id <> $2 AND is_default_shipping
+	`
+
+	clearDefaultBillingQuery = `
+		UPDATE addresses SET is_default_billing = FALSE, updated_at = now()
+		WHERE user_id = $1 AND address_id <> $2 AND is_default_billing
+	`
+
+	deleteAddressQuery = `DELETE FROM addresses WHERE address_id = $1 AND user_id = $2`
+
+	getAddressByIdQuery = `SELECT * FROM addresses WHERE address_id = $1 AND user_id = $2`
+
+	getDefaultShippingQuery = `SELECT * FROM addresses WHERE user_id = $1 AND is_default_shipping`
+
+	getDefaultBillingQuery = `SELECT * FROM addresses WHERE user_id = $1 AND is_default_billing`
+
+	listAddressesByUserQuery = `
+		SELECT * FROM addresses WHERE user_id = $1
+		ORDER BY is_default_shipping DESC, is_default_billing DESC, created_at
+	`
+
+	countAddressesByUserQuery = `SELECT COUNT(*) FROM addresses WHERE user_id = $1`
+)
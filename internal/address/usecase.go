@@ -0,0 +1,17 @@
+package address
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Address UseCase, addresses belong to the user in context
+type UseCase interface {
+	Create(ctx context.Context, a *models.Address) (*models.Address, error)
+	Update(ctx context.Context, a *models.Address) (*models.Address, error)
+	Delete(ctx context.Context, addressID uuid.UUID) error
+	GetByID(ctx context.Context, addressID uuid.UUID) (*models.Address, error)
+	List(ctx context.Context) ([]*models.Address, error)
+}
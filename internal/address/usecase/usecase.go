@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/address"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const maxAddressesPerUser = 20
+
+// Address UseCase
+type addressUC struct {
+	cfg         *config.Config
+	logger      logger.Logger
+	addressRepo address.Repository
+}
+
+// Address UseCase constructor
+func NewAddressUseCase(cfg *config.Config, logger logger.Logger, addressRepo address.Repository) address.UseCase {
+	return &addressUC{
+		cfg:         cfg,
+		logger:      logger,
+		addressRepo: addressRepo,
+	}
+}
+
+// Create address, the first address of a user becomes default shipping and billing address
+func (u *addressUC) Create(ctx context.Context, a *models.Address) (*models.Address, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Prepare()
+	if err = validateAddress(a); err != nil {
+		return nil, err
+	}
+
+	count, err := u.addressRepo.CountByUser(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxAddressesPerUser {
+		return nil, httpErrors.NewBadRequestError(errors.Errorf("addressUC.Create: max %d addresses per user", maxAddressesPerUser))
+	}
+	if count == 0 {
+		a.IsDefaultShipping = true
+		a.IsDefaultBilling = true
+	}
+
+	a.AddressID = uuid.Nil
+	a.UserID = user.UserID
+
+	return u.addressRepo.Create(ctx, a)
+}
+
+// Replace own address, setting a default flag moves it from the previous default
+func (u *addressUC) Update(ctx context.Context, a *models.Address) (*models.Address, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Prepare()
+	if err = validateAddress(a); err != nil {
+		return nil, err
+	}
+
+	a.UserID = user.UserID
+
+	return u.addressRepo.Update(ctx, a)
+}
+
+func (u *addressUC) Delete(ctx context.Context, addressID uuid.UUID) error {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	return u.addressRepo.Delete(ctx, user.UserID, addressID)
+}
+
+func (u *addressUC) GetByID(ctx context.Context, addressID uuid.UUID) (*models.Address, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.addressRepo.GetByID(ctx, user.UserID, addressID)
+}
+
+// Own addresses, defaults first
+func (u *addressUC) List(ctx context.Context) ([]*models.Address, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.addressRepo.ListByUser(ctx, user.UserID)
+}
+
+func validateAddress(a *models.Address) error {
+	if err := a.Validate(); err != nil {
+		return httpErrors.NewRestErrorWithMessage(
+			http.StatusBadRequest,
+			"Invalid address: "+err.Error(),
+			errors.Wrap(err, "addressUC.validateAddress"),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package attribute
+
+import "github.com/labstack/echo/v4"
+
+// Attribute HTTP Handlers interface
+type Handlers interface {
+	CreateSet() echo.HandlerFunc
+	GetSet() echo.HandlerFunc
+	ListSets() echo.HandlerFunc
+	DeleteSet() echo.HandlerFunc
+	CreateAttribute() echo.HandlerFunc
+	UpdateAttribute() echo.HandlerFunc
+	DeleteAttribute() echo.HandlerFunc
+	SetProductAttributeSet() echo.HandlerFunc
+	SetVariantAttributes() echo.HandlerFunc
+}
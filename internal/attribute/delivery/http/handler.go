@@ -0,0 +1,314 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/attribute"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Attribute handlers
+type attributeHandlers struct {
+	cfg         *config.Config
+	logger      logger.Logger
+	attributeUC attribute.UseCase
+}
+
+// Attribute handlers constructor
+func NewAttributeHandlers(cfg *config.Config, logger logger.Logger, attributeUC attribute.UseCase) attribute.Handlers {
+	return &attributeHandlers{
+		cfg:         cfg,
+		logger:      logger,
+		attributeUC: attributeUC,
+	}
+}
+
+// CreateSet godoc
+// @Summary Create attribute set
+// @Description create attribute set, admin only
+// @Tags Attributes
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.AttributeSet
+// @Failure 500 {object} httpErrors.RestError
+// @Router /attribute-sets [post]
+func (h *attributeHandlers) CreateSet() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		set := &models.AttributeSet{}
+		if err := utils.ReadRequest(c, set); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		createdSet, err := h.attributeUC.CreateSet(ctx, set)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, createdSet)
+	}
+}
+
+// GetSet godoc
+// @Summary Get attribute set
+// @Description get attribute set with its attributes, admin only
+// @Tags Attributes
+// @Produce json
+// @Param attribute_set_id path string true "attribute_set_id"
+// @Success 200 {object} models.AttributeSet
+// @Failure 500 {object} httpErrors.RestError
+// @Router /attribute-sets/{attribute_set_id} [get]
+func (h *attributeHandlers) GetSet() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		setID, err := uuid.Parse(c.Param("attribute_set_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		set, err := h.attributeUC.GetSet(ctx, setID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, set)
+	}
+}
+
+// ListSets godoc
+// @Summary List attribute sets
+// @Description list attribute sets, admin only
+// @Tags Attributes
+// @Produce json
+// @Success 200 {array} models.AttributeSet
+// @Failure 500 {object} httpErrors.RestError
+// @Router /attribute-sets [get]
+func (h *attributeHandlers) ListSets() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		sets, err := h.attributeUC.ListSets(ctx)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, sets)
+	}
+}
+
+// DeleteSet godoc
+// @Summary Delete attribute set
+// @Description delete attribute set, products using it are detached, admin only
+// @Tags Attributes
+// @Param attribute_set_id path string true "attribute_set_id"
+// @Success 204
+// @Failure 500 {object} httpErrors.RestError
+// @Router /attribute-sets/{attribute_set_id} [delete]
+func (h *attributeHandlers) DeleteSet() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		setID, err := uuid.Parse(c.Param("attribute_set_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.attributeUC.DeleteSet(ctx, setID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// CreateAttribute godoc
+// @Summary Create attribute
+// @Description add typed attribute to attribute set, admin only
+// @Tags Attributes
+// @Accept json
+// @Produce json
+// @Param attribute_set_id path string true "attribute_set_id"
+// @Success 201 {object} models.Attribute
+// @Failure 500 {object} httpErrors.RestError
+// @Router /attribute-sets/{attribute_set_id}/attributes [post]
+func (h *attributeHandlers) CreateAttribute() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		setID, err := uuid.Parse(c.Param("attribute_set_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		attr := &models.Attribute{}
+		if err = utils.ReadRequest(c, attr); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		attr.AttributeSetID = setID
+
+		createdAttr, err := h.attributeUC.CreateAttribute(ctx, attr)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, createdAttr)
+	}
+}
+
+// UpdateAttribute godoc
+// @Summary Update attribute
+// @Description update attribute label, required flag, enum values, rules and position, code and type are immutable, admin only
+// @Tags Attributes
+// @Accept json
+// @Produce json
+// @Param attribute_set_id path string true "attribute_set_id"
+// @Param attribute_id path string true "attribute_id"
+// @Success 200 {object} models.Attribute
+// @Failure 500 {object} httpErrors.RestError
+// @Router /attribute-sets/{attribute_set_id}/attributes/{attribute_id} [put]
+func (h *attributeHandlers) UpdateAttribute() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		setID, err := uuid.Parse(c.Param("attribute_set_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		attributeID, err := uuid.Parse(c.Param("attribute_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		attr := &models.Attribute{}
+		if err = utils.ReadRequest(c, attr); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		attr.AttributeSetID = setID
+		attr.AttributeID = attributeID
+
+		updatedAttr, err := h.attributeUC.UpdateAttribute(ctx, attr)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, updatedAttr)
+	}
+}
+
+// DeleteAttribute godoc
+// @Summary Delete attribute
+// @Description delete attribute from attribute set, stored variant values are kept, admin only
+// @Tags Attributes
+// @Param attribute_set_id path string true "attribute_set_id"
+// @Param attribute_id path string true "attribute_id"
+// @Success 204
+// @Failure 500 {object} httpErrors.RestError
+// @Router /attribute-sets/{attribute_set_id}/attributes/{attribute_id} [delete]
+func (h *attributeHandlers) DeleteAttribute() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		setID, err := uuid.Parse(c.Param("attribute_set_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		attributeID, err := uuid.Parse(c.Param("attribute_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.attributeUC.DeleteAttribute(ctx, setID, attributeID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// SetProductAttributeSet godoc
+// @Summary Set product attribute set
+// @Description assign attribute set to product, null removes it, admin only
+// @Tags Attributes
+// @Accept json
+// @Param product_id path string true "product_id"
+// @Success 204
+// @Failure 500 {object} httpErrors.RestError
+// @Router /products/{product_id}/attribute-set [put]
+func (h *attributeHandlers) SetProductAttributeSet() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		productID, err := uuid.Parse(c.Param("product_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		input := &models.ProductAttributeSet{}
+		if err = utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.attributeUC.SetProductAttributeSet(ctx, productID, input.AttributeSetID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// SetVariantAttributes godoc
+// @Summary Set variant attributes
+// @Description replace variant attribute values, validated against the product attribute set, admin only
+// @Tags Attributes
+// @Accept json
+// @Produce json
+// @Param product_id path string true "product_id"
+// @Param variant_id path string true "variant_id"
+// @Success 200 {object} models.VariantAttributes
+// @Failure 500 {object} httpErrors.RestError
+// @Router /products/{product_id}/variants/{variant_id}/attributes [put]
+func (h *attributeHandlers) SetVariantAttributes() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		productID, err := uuid.Parse(c.Param("product_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		variantID, err := uuid.Parse(c.Param("variant_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		input := &models.VariantAttributes{}
+		if err = utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		attrs, err := h.attributeUC.SetVariantAttributes(ctx, productID, variantID, input.Attributes)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, &models.VariantAttributes{Attributes: attrs})
+	}
+}
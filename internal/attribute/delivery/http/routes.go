@@ -0,0 +1,24 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/attribute"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func MapAttributeRoutes(attributeGroup *echo.Group, productGroup *echo.Group, h attribute.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	attributeGroup.Use(adminOnly...)
+	attributeGroup.GET("", h.ListSets())
+	attributeGroup.POST("", h.CreateSet())
+	attributeGroup.GET("/:attribute_set_id", h.GetSet())
+	attributeGroup.DELETE("/:attribute_set_id", h.DeleteSet())
+	attributeGroup.POST("/:attribute_set_id/attributes", h.CreateAttribute())
+	attributeGroup.PUT("/:attribute_set_id/attributes/:attribute_id", h.UpdateAttribute())
+	attributeGroup.DELETE("/:attribute_set_id/attributes/:attribute_id", h.DeleteAttribute())
+
+	productGroup.PUT("/:product_id/attribute-set", h.SetProductAttributeSet(), adminOnly...)
+	productGroup.PUT("/:product_id/variants/:variant_id/attributes", h.SetVariantAttributes(), adminOnly...)
+}
@@ -0,0 +1,23 @@
+package attribute
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Attribute repository
+type Repository interface {
+	CreateSet(ctx context.Context, set *models.AttributeSet) (*models.AttributeSet, error)
+	GetSetByID(ctx context.Context, setID uuid.UUID) (*models.AttributeSet, error)
+	ListSets(ctx context.Context) ([]*models.AttributeSet, error)
+	DeleteSet(ctx context.Context, setID uuid.UUID) error
+	CreateAttribute(ctx context.Context, attr *models.Attribute) (*models.Attribute, error)
+	UpdateAttribute(ctx context.Context, attr *models.Attribute) (*models.Attribute, error)
+	DeleteAttribute(ctx context.Context, setID uuid.UUID, attributeID uuid.UUID) error
+	GetAttributes(ctx context.Context, setID uuid.UUID) ([]*models.Attribute, error)
+	SetProductAttributeSet(ctx context.Context, productID uuid.UUID, setID *uuid.UUID) error
+	GetVariantAttributeSetID(ctx context.Context, productID uuid.UUID, variantID uuid.UUID) (*uuid.UUID, error)
+	UpdateVariantAttributes(ctx context.Context, productID uuid.UUID, variantID uuid.UUID, attrs models.JSONMap) error
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fekuna/go-store/internal/attribute"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Attribute repository
+type attributeRepo struct {
+	db *sqlx.DB
+}
+
+// Attribute repository constructor
+func NewAttributeRepository(db *sqlx.DB) attribute.Repository {
+	return &attributeRepo{db: db}
+}
+
+func (r *attributeRepo) CreateSet(ctx context.Context, set *models.AttributeSet) (*models.AttributeSet, error) {
+	// TODO: Tracing
+
+	created := &models.AttributeSet{}
+	if err := r.db.QueryRowxContext(ctx, createSetQuery, &set.Name).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "attributeRepo.CreateSet.StructScan")
+	}
+
+	return created, nil
+}
+
+func (r *attributeRepo) GetSetByID(ctx context.Context, setID uuid.UUID) (*models.AttributeSet, error) {
+	// TODO: Tracing
+
+	set := &models.AttributeSet{}
+	if err := r.db.GetContext(ctx, set, getSetByIdQuery, setID); err != nil {
+		return nil, errors.Wrap(err, "attributeRepo.GetSetByID.GetContext")
+	}
+
+	return set, nil
+}
+
+func (r *attributeRepo) ListSets(ctx context.Context) ([]*models.AttributeSet, error) {
+	// TODO: Tracing
+
+	sets := make([]*models.AttributeSet, 0)
+	if err := r.db.SelectContext(ctx, &sets, listSetsQuery); err != nil {
+		return nil, errors.Wrap(err, "attributeRepo.ListSets.SelectContext")
+	}
+
+	return sets, nil
+}
+
+func (r *attributeRepo) DeleteSet(ctx context.Context, setID uuid.UUID) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, deleteSetQuery, setID)
+	if err != nil {
+		return errors.Wrap(err, "attributeRepo.DeleteSet.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "attributeRepo.DeleteSet.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "attributeRepo.DeleteSet.rowsAffected")
+	}
+
+	return nil
+}
+
+func (r *attributeRepo) CreateAttribute(ctx context.Context, attr *models.Attribute) (*models.Attribute, error) {
+	// TODO: Tracing
+
+	created := &models.Attribute{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		createAttributeQuery,
+		&attr.AttributeSetID,
+		&attr.Code,
+		&attr.Label,
+		&attr.Type,
+		&attr.Required,
+		&attr.EnumValues,
+		&attr.Rules,
+		&attr.Position,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "attributeRepo.CreateAttribute.StructScan")
+	}
+
+	return created, nil
+}
+
+func (r *attributeRepo) UpdateAttribute(ctx context.Context, attr *models.Attribute) (*models.Attribute, error) {
+	// TODO: Tracing
+
+	updated := &models.Attribute{}
+	if err := r.db.GetContext(
+		ctx,
+		updated,
+		updateAttributeQuery,
+		&attr.Label,
+		&attr.Required,
+		&attr.EnumValues,
+		&attr.Rules,
+		&attr.Position,
+		&attr.AttributeID,
+		&attr.AttributeSetID,
+	); err != nil {
+		return nil, errors.Wrap(err, "attributeRepo.UpdateAttribute.GetContext")
+	}
+
+	return updated, nil
+}
+
+func (r *attributeRepo) DeleteAttribute(ctx context.Context, setID uuid.UUID, attributeID uuid.UUID) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, deleteAttributeQuery, attributeID, setID)
+	if err != nil {
+		return errors.Wrap(err, "attributeRepo.DeleteAttribute.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "attributeRepo.DeleteAttribute.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "attributeRepo.DeleteAttribute.rowsAffected")
+	}
+
+	return nil
+}
+
+func (r *attributeRepo) GetAttributes(ctx context.Context, setID uuid.UUID) ([]*models.Attribute, error) {
+	// TODO: Tracing
+
+	attrs := make([]*models.Attribute, 0)
+	if err := r.db.SelectContext(ctx, &attrs, getAttributesQuery, setID); err != nil {
+		return nil, errors.Wrap(err, "attributeRepo.GetAttributes.SelectContext")
+	}
+
+	return attrs, nil
+}
+
+func (r *attributeRepo) SetProductAttributeSet(ctx context.Context, productID uuid.UUID, setID *uuid.UUID) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, setProductAttributeSetQuery, setID, productID)
+	if err != nil {
+		return errors.Wrap(err, "attributeRepo.SetProductAttributeSet.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "attributeRepo.SetProductAttributeSet.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "attributeRepo.SetProductAttributeSet.rowsAffected")
+	}
+
+	return nil
+}
+
+func (r *attributeRepo) GetVariantAttributeSetID(ctx context.Context, productID uuid.UUID, variantID uuid.UUID) (*uuid.UUID, error) {
+	// TODO: Tracing
+
+	var setID *uuid.UUID
+	if err := r.db.GetContext(ctx, &setID, getVariantAttributeSetIdQuery, variantID, productID); err != nil {
+		return nil, errors.Wrap(err, "attributeRepo.GetVariantAttributeSetID.GetContext")
+	}
+
+	return setID, nil
+}
+
+func (r *attributeRepo) UpdateVariantAttributes(ctx context.Context, productID uuid.UUID, variantID uuid.UUID, attrs models.JSONMap) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, updateVariantAttributesQuery, attrs, variantID, productID)
+	if err != nil {
+		return errors.Wrap(err, "attributeRepo.UpdateVariantAttributes.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "attributeRepo.UpdateVariantAttributes.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "attributeRepo.UpdateVariantAttributes.rowsAffected")
+	}
+
+	return nil
+}
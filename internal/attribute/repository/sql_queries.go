@@ -0,0 +1,50 @@
+package repository
+
+const (
+	createSetQuery = `
+		INSERT INTO attribute_sets(name, created_at, updated_at)
+		VALUES ($1, now(), now())
+		RETURNING *
+	`
+
+	getSetByIdQuery = `SELECT * FROM attribute_sets WHERE attribute_set_id = $1`
+
+	listSetsQuery = `SELECT * FROM attribute_sets ORDER BY name`
+
+	deleteSetQuery = `DELETE FROM attribute_sets WHERE attribute_set_id = $1`
+
+	createAttributeQuery = `
+		INSERT INTO attributes(attribute_set_id, code, label, type, required, enum_values, rules, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, now(), now())
+		RETURNING *
+	`
+
+	// Code and type are immutable, values already stored on variants depend on them
+	updateAttributeQuery = `
+		UPDATE attributes
+		SET label = $1, required = $2, enum_values = $3, rules = $4, position = $5, updated_at = now()
+		WHERE attribute_id = $6 AND attribute_set_id = $7
+		RETURNING *
+	`
+
+	deleteAttributeQuery = `DELETE FROM attributes WHERE attribute_id = $1 AND attribute_set_id = $2`
+
+	getAttributesQuery = `SELECT * FROM attributes WHERE attribute_set_id = $1 ORDER BY position, code`
+
+	setProductAttributeSetQuery = `
+		UPDATE products SET attribute_set_id = $1, updated_at = now()
+		WHERE product_id = $2
+	`
+
+	getVariantAttributeSetIdQuery = `
+		SELECT p.attribute_set_id
+		FROM product_variants v
+		JOIN products p ON p.product_id = v.product_id
+		WHERE v.variant_id = $1 AND v.product_id = $2
+	`
+
+	updateVariantAttributesQuery = `
+		UPDATE product_variants SET attributes = $1, updated_at = now()
+		WHERE variant_id = $2 AND product_id = $3
+	`
+)
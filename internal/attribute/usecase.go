@@ -0,0 +1,21 @@
+package attribute
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Attribute UseCase
+type UseCase interface {
+	CreateSet(ctx context.Context, set *models.AttributeSet) (*models.AttributeSet, error)
+	GetSet(ctx context.Context, setID uuid.UUID) (*models.AttributeSet, error)
+	ListSets(ctx context.Context) ([]*models.AttributeSet, error)
+	DeleteSet(ctx context.Context, setID uuid.UUID) error
+	CreateAttribute(ctx context.Context, attr *models.Attribute) (*models.Attribute, error)
+	UpdateAttribute(ctx context.Context, attr *models.Attribute) (*models.Attribute, error)
+	DeleteAttribute(ctx context.Context, setID uuid.UUID, attributeID uuid.UUID) error
+	SetProductAttributeSet(ctx context.Context, productID uuid.UUID, setID *uuid.UUID) error
+	SetVariantAttributes(ctx context.Context, productID uuid.UUID, variantID uuid.UUID, attrs models.JSONMap) (models.JSONMap, error)
+}
@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/attribute"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Attribute UseCase
+type attributeUC struct {
+	cfg           *config.Config
+	logger        logger.Logger
+	attributeRepo attribute.Repository
+}
+
+// Attribute UseCase constructor
+func NewAttributeUseCase(cfg *config.Config, logger logger.Logger, attributeRepo attribute.Repository) attribute.UseCase {
+	return &attributeUC{
+		cfg:           cfg,
+		logger:        logger,
+		attributeRepo: attributeRepo,
+	}
+}
+
+func (u *attributeUC) CreateSet(ctx context.Context, set *models.AttributeSet) (*models.AttributeSet, error) {
+	// TODO: Tracing
+
+	set.Name = strings.TrimSpace(set.Name)
+
+	return u.attributeRepo.CreateSet(ctx, set)
+}
+
+func (u *attributeUC) GetSet(ctx context.Context, setID uuid.UUID) (*models.AttributeSet, error) {
+	// TODO: Tracing
+
+	set, err := u.attributeRepo.GetSetByID(ctx, setID)
+	if err != nil {
+		return nil, err
+	}
+
+	set.Attributes, err = u.attributeRepo.GetAttributes(ctx, setID)
+	if err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+func (u *attributeUC) ListSets(ctx context.Context) ([]*models.AttributeSet, error) {
+	// TODO: Tracing
+
+	return u.attributeRepo.ListSets(ctx)
+}
+
+func (u *attributeUC) DeleteSet(ctx context.Context, setID uuid.UUID) error {
+	// TODO: Tracing
+
+	return u.attributeRepo.DeleteSet(ctx, setID)
+}
+
+func (u *attributeUC) CreateAttribute(ctx context.Context, attr *models.Attribute) (*models.Attribute, error) {
+	// TODO: Tracing
+
+	if _, err := u.attributeRepo.GetSetByID(ctx, attr.AttributeSetID); err != nil {
+		return nil, err
+	}
+
+	attr.Prepare()
+	if attr.Code == "" {
+		return nil, httpErrors.NewBadRequestError(errors.New("attributeUC.CreateAttribute: code can't be empty"))
+	}
+	if err := checkRules(ctx, attr); err != nil {
+		return nil, err
+	}
+
+	return u.attributeRepo.CreateAttribute(ctx, attr)
+}
+
+func (u *attributeUC) UpdateAttribute(ctx context.Context, attr *models.Attribute) (*models.Attribute, error) {
+	// TODO: Tracing
+
+	attrs, err := u.attributeRepo.GetAttributes(ctx, attr.AttributeSetID)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing *models.Attribute
+	for _, a := range attrs {
+		if a.AttributeID == attr.AttributeID {
+			existing = a
+			break
+		}
+	}
+	if existing == nil {
+		return nil, httpErrors.NewNotFoundError(errors.New("attributeUC.UpdateAttribute: attribute not found"))
+	}
+
+	// Code and type are immutable
+	attr.Code = existing.Code
+	attr.Type = existing.Type
+	attr.Prepare()
+	if err = checkRules(ctx, attr); err != nil {
+		return nil, err
+	}
+
+	return u.attributeRepo.UpdateAttribute(ctx, attr)
+}
+
+func (u *attributeUC) DeleteAttribute(ctx context.Context, setID uuid.UUID, attributeID uuid.UUID) error {
+	// TODO: Tracing
+
+	return u.attributeRepo.DeleteAttribute(ctx, setID, attributeID)
+}
+
+func (u *attributeUC) SetProductAttributeSet(ctx context.Context, productID uuid.UUID, setID *uuid.UUID) error {
+	// TODO: Tracing
+
+	if setID != nil {
+		if _, err := u.attributeRepo.GetSetByID(ctx, *setID); err != nil {
+			return err
+		}
+	}
+
+	return u.attributeRepo.SetProductAttributeSet(ctx, productID, setID)
+}
+
+func (u *attributeUC) SetVariantAttributes(ctx context.Context, productID uuid.UUID, variantID uuid.UUID, values models.JSONMap) (models.JSONMap, error) {
+	// TODO: Tracing
+
+	setID, err := u.attributeRepo.GetVariantAttributeSetID(ctx, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if setID == nil {
+		return nil, httpErrors.NewBadRequestError(errors.New("attributeUC.SetVariantAttributes: product has no attribute set"))
+	}
+
+	attrs, err := u.attributeRepo.GetAttributes(ctx, *setID)
+	if err != nil {
+		return nil, err
+	}
+
+	cleaned, err := validateValues(ctx, attrs, values)
+	if err != nil {
+		return nil, httpErrors.NewBadRequestError(err)
+	}
+
+	if err = u.attributeRepo.UpdateVariantAttributes(ctx, productID, variantID, cleaned); err != nil {
+		return nil, err
+	}
+
+	return cleaned, nil
+}
+
+// Check values against attribute definitions, returns only known attributes with normalized values
+func validateValues(ctx context.Context, attrs []*models.Attribute, values models.JSONMap) (models.JSONMap, error) {
+	known := make(map[string]bool, len(attrs))
+	cleaned := make(models.JSONMap, len(values))
+
+	for _, a := range attrs {
+		known[a.Code] = true
+
+		value, ok := values[a.Code]
+		if !ok || value == nil {
+			if a.Required {
+				return nil, errors.Errorf("attribute %s is required", a.Code)
+			}
+			continue
+		}
+
+		normalized, err := normalizeValue(a, value)
+		if err != nil {
+			return nil, err
+		}
+
+		if a.Rules != "" {
+			if err = utils.ValidateVar(ctx, normalized, a.Rules); err != nil {
+				return nil, errors.Wrapf(err, "attribute %s", a.Code)
+			}
+		}
+
+		cleaned[a.Code] = normalized
+	}
+
+	unknown := make([]string, 0)
+	for code := range values {
+		if !known[code] {
+			unknown = append(unknown, code)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, errors.Errorf("unknown attributes: %s", strings.Join(unknown, ", "))
+	}
+
+	return cleaned, nil
+}
+
+// Values come from decoded JSON, so numbers are float64
+func normalizeValue(a *models.Attribute, value interface{}) (interface{}, error) {
+	switch a.Type {
+	case models.AttributeText:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.Errorf("attribute %s must be a string", a.Code)
+		}
+		return strings.TrimSpace(s), nil
+	case models.AttributeNumber:
+		n, ok := value.(float64)
+		if !ok {
+			return nil, errors.Errorf("attribute %s must be a number", a.Code)
+		}
+		return n, nil
+	case models.AttributeBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, errors.Errorf("attribute %s must be a boolean", a.Code)
+		}
+		return b, nil
+	case models.AttributeEnum:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.Errorf("attribute %s must be a string", a.Code)
+		}
+		for _, allowed := range a.EnumValues {
+			if s == allowed {
+				return s, nil
+			}
+		}
+		return nil, errors.Errorf("attribute %s must be one of: %s", a.Code, strings.Join(a.EnumValues, ", "))
+	default:
+		return nil, errors.Errorf("attribute %s has unknown type %s", a.Code, a.Type)
+	}
+}
+
+// Reject malformed validator tags up front instead of failing on every variant update
+func checkRules(ctx context.Context, attr *models.Attribute) error {
+	if attr.Rules == "" {
+		return nil
+	}
+
+	var sample interface{}
+	switch attr.Type {
+	case models.AttributeNumber:
+		sample = float64(0)
+	case models.AttributeBoolean:
+		sample = false
+	default:
+		sample = ""
+	}
+
+	err := utils.ValidateVar(ctx, sample, attr.Rules)
+	var validationErrs validator.ValidationErrors
+	if err != nil && !errors.As(err, &validationErrs) {
+		return httpErrors.NewBadRequestError(errors.Wrap(err, "attributeUC.checkRules"))
+	}
+
+	return nil
+}
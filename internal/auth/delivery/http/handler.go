@@ -42,6 +42,7 @@ func NewAuthHandlers(cfg *config.Config, logger logger.Logger, authUC auth.UseCa
 // @Tags Auth
 // @Accept json
 // @Produce json
+// @Param X-Cart-Token header string false "guest cart token, merged into the user's cart"
 // @Success 201 {object} models.User
 // @Router /auth/register [post]
 func (h *authHandlers) Register() echo.HandlerFunc {
@@ -55,7 +56,7 @@ func (h *authHandlers) Register() echo.HandlerFunc {
 			return c.JSON(httpErrors.ErrorResponse(err))
 		}
 
-		createdUser, err := h.authUC.Register(ctx, user)
+		createdUser, err := h.authUC.Register(ctx, user, c.Request().Header.Get(models.CartTokenHeader))
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
 			return c.JSON(httpErrors.ErrorResponse(err))
@@ -83,6 +84,7 @@ func (h *authHandlers) Register() echo.HandlerFunc {
 // @Tags Auth
 // @Accept json
 // @Produce json
+// @Param X-Cart-Token header string false "guest cart token, merged into the user's cart"
 // @Success 200 {object} models.User
 // @Router /auth/login [post]
 func (h *authHandlers) Login() echo.HandlerFunc {
@@ -103,7 +105,7 @@ func (h *authHandlers) Login() echo.HandlerFunc {
 		userWithToken, err := h.authUC.Login(ctx, &models.User{
 			Email:    login.Email,
 			Password: login.Password,
-		})
+		}, c.Request().Header.Get(models.CartTokenHeader))
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
 			return c.JSON(httpErrors.ErrorResponse(err))
@@ -25,8 +25,7 @@ func (r *authRepo) Register(ctx context.Context, user *models.User) (*models.Use
 	u := &models.User{}
 	if err := r.db.QueryRowxContext(
 		ctx, createUserQuery, &user.FirstName, &user.LastName, &user.Email,
-		&user.Password, &user.Role, &user.About, &user.Avatar, &user.PhoneNumber, &user.Address, &user.City,
-		&user.Gender, &user.Postcode, &user.Birthday,
+		&user.Password, &user.Role, &user.About, &user.Avatar, &user.PhoneNumber, &user.Gender, &user.Birthday,
 	).StructScan(u); err != nil {
 		return nil, errors.Wrap(err, "authRepo.Register.StructScan")
 	}
@@ -59,8 +58,7 @@ func (r *authRepo) Update(ctx context.Context, user *models.User) (*models.User,
 
 	u := &models.User{}
 	if err := r.db.GetContext(ctx, u, updateUserQuery, &user.FirstName, &user.LastName, &user.Email,
-		&user.Role, &user.About, &user.Avatar, &user.PhoneNumber, &user.Address, &user.City, &user.Gender,
-		&user.Postcode, &user.Birthday, &user.UserID,
+		&user.Role, &user.About, &user.Avatar, &user.PhoneNumber, &user.Gender, &user.Birthday, &user.UserID,
 	); err != nil {
 		return nil, errors.Wrap(err, "authRepo.Update.GetContext")
 	}
@@ -2,14 +2,14 @@ package repository
 
 const (
 	findUserByEmail = `
-		SELECT user_id, first_name, last_name, email, role, about, avatar, phone_number, address, city, gender, postcode, birthday, created_at, updated_at, login_date, password
+		SELECT user_id, first_name, last_name, email, role, about, avatar, phone_number, gender, birthday, created_at, updated_at, login_date, password
 		FROM users
 		WHERE email = $1
 	`
 
 	createUserQuery = `
-		INSERT INTO users(first_name, last_name, email, password, role, about, avatar, phone_number, address, city, gender, postcode, birthday, created_at, updated_at, login_date)
-		VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'user'), $6, $7, $8, $9, $10, $11, $12, $13, now(), now(), now())
+		INSERT INTO users(first_name, last_name, email, password, role, about, avatar, phone_number, gender, birthday, created_at, updated_at, login_date)
+		VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'user'), $6, $7, $8, $9, $10, now(), now(), now())
 		RETURNING *
 	`
 
@@ -26,13 +26,10 @@ const (
 			about = COALESCE(NULLIF($5, ''), about),
 			avatar = COALESCE(NULLIF($6, ''), avatar),
 			phone_number = COALESCE(NULLIF($7, ''), phone_number),
-			address = COALESCE(NULLIF($8, ''), address),
-			city = COALESCE(NULLIF($9, ''), city),
-			gender = COALESCE(NULLIF($10, ''), gender),
-			postcode = COALESCE(NULLIF($11, 0), postcode),
-			birthday = COALESCE(NULLIF($12, '')::date, birthday),
+			gender = COALESCE(NULLIF($8, ''), gender),
+			birthday = COALESCE(NULLIF($9, '')::date, birthday),
 			updated_at = now()
-		WHERE user_id = $13
+		WHERE user_id = $10
 		RETURNING *
 	`
 )
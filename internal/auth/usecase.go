@@ -9,8 +9,8 @@ import (
 )
 
 type UseCase interface {
-	Register(ctx context.Context, user *models.User) (*models.UserWithToken, error)
-	Login(ctx context.Context, user *models.User) (*models.UserWithToken, error)
+	Register(ctx context.Context, user *models.User, cartToken string) (*models.UserWithToken, error)
+	Login(ctx context.Context, user *models.User, cartToken string) (*models.UserWithToken, error)
 	GetByID(ctx context.Context, userID uuid.UUID) (*models.User, error)
 	UploadAvatar(ctx context.Context, userID uuid.UUID, file models.UploadInput) (*models.User, error)
 	GetAvatar(ctx context.Context) (*url.URL, error)
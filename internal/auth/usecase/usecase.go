@@ -9,6 +9,7 @@ import (
 
 	"github.com/fekuna/go-store/config"
 	"github.com/fekuna/go-store/internal/auth"
+	"github.com/fekuna/go-store/internal/cart"
 	"github.com/fekuna/go-store/internal/models"
 	"github.com/fekuna/go-store/pkg/httpErrors"
 	"github.com/fekuna/go-store/pkg/logger"
@@ -23,19 +24,22 @@ type authUC struct {
 	logger    logger.Logger
 	authRepo  auth.Repository
 	minioRepo auth.MinioRepository
+	cartUC    cart.UseCase
 }
 
 // Auth usecase constructor
-func NewAuthUseCase(cfg *config.Config, logger logger.Logger, authRepo auth.Repository, minioRepo auth.MinioRepository) auth.UseCase {
+func NewAuthUseCase(cfg *config.Config, logger logger.Logger, authRepo auth.Repository, minioRepo auth.MinioRepository, cartUC cart.UseCase) auth.UseCase {
 	return &authUC{
 		cfg:       cfg,
 		logger:    logger,
 		authRepo:  authRepo,
 		minioRepo: minioRepo,
+		cartUC:    cartUC,
 	}
 }
 
-func (u *authUC) Register(ctx context.Context, user *models.User) (*models.UserWithToken, error) {
+// Register user, guest cart of cartToken is merged into the new user's cart
+func (u *authUC) Register(ctx context.Context, user *models.User, cartToken string) (*models.UserWithToken, error) {
 	// TODO: Tracing
 
 	existsUser, err := u.authRepo.FindByEmail(ctx, user)
@@ -43,6 +47,9 @@ func (u *authUC) Register(ctx context.Context, user *models.User) (*models.UserW
 		return nil, httpErrors.NewRestErrorWithMessage(http.StatusBadRequest, httpErrors.ErrEmailAlreadyExists, err)
 	}
 
+	// Role can't be chosen on self registration
+	user.Role = nil
+
 	if err = user.PrepareCreate(); err != nil {
 		return nil, httpErrors.NewBadRequestError(errors.Wrap(err, "authUC.Register.PrepareCreate"))
 	}
@@ -69,13 +76,16 @@ func (u *authUC) Register(ctx context.Context, user *models.User) (*models.UserW
 		RefreshToken: refreshToken,
 	}
 
+	u.mergeGuestCart(ctx, cartToken, createdUser.UserID)
+
 	return &models.UserWithToken{
 		User:  createdUser,
 		Token: authToken,
 	}, nil
 }
 
-func (u *authUC) Login(ctx context.Context, user *models.User) (*models.UserWithToken, error) {
+// Login user, guest cart of cartToken is merged into the user's cart
+func (u *authUC) Login(ctx context.Context, user *models.User, cartToken string) (*models.UserWithToken, error) {
 	// TODO: tracing
 
 	foundUser, err := u.authRepo.FindByEmail(ctx, user)
@@ -104,6 +114,8 @@ func (u *authUC) Login(ctx context.Context, user *models.User) (*models.UserWith
 		RefreshToken: refreshToken,
 	}
 
+	u.mergeGuestCart(ctx, cartToken, foundUser.UserID)
+
 	return &models.UserWithToken{
 		User:  foundUser,
 		Token: authToken,
@@ -155,3 +167,14 @@ func (u *authUC) GetAvatar(ctx context.Context) (*url.URL, error) {
 func (u *authUC) generateMinioURL(bucket string, key string) string {
 	return fmt.Sprintf("%s/%s/%s", u.cfg.Minio.Endpoint, bucket, key)
 }
+
+// Move guest cart lines into the user's cart, a failed merge must not fail the login
+func (u *authUC) mergeGuestCart(ctx context.Context, cartToken string, userID uuid.UUID) {
+	if cartToken == "" {
+		return
+	}
+
+	if err := u.cartUC.MergeGuestCart(ctx, cartToken, userID); err != nil {
+		u.logger.Errorf("authUC.mergeGuestCart: %v", err)
+	}
+}
@@ -0,0 +1,12 @@
+package cart
+
+import "github.com/labstack/echo/v4"
+
+// Cart HTTP Handlers interface
+type Handlers interface {
+	Get() echo.HandlerFunc
+	AddItem() echo.HandlerFunc
+	UpdateItem() echo.HandlerFunc
+	RemoveItem() echo.HandlerFunc
+	Clear() echo.HandlerFunc
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/cart"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// Cart handlers
+type cartHandlers struct {
+	cfg    *config.Config
+	logger logger.Logger
+	cartUC cart.UseCase
+}
+
+// Cart handlers constructor
+func NewCartHandlers(cfg *config.Config, logger logger.Logger, cartUC cart.UseCase) cart.Handlers {
+	return &cartHandlers{
+		cfg:    cfg,
+		logger: logger,
+		cartUC: cartUC,
+	}
+}
+
+// Get godoc
+// @Summary Get cart
+// @Description get cart of logged in user or guest cart of X-Cart-Token header, with totals
+// @Tags Cart
+// @Produce json
+// @Param X-Cart-Token header string false "guest cart token"
+// @Success 200 {object} models.Cart
+// @Failure 400 {object} httpErrors.RestError
+// @Router /cart [get]
+func (h *cartHandlers) Get() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		currentCart, err := h.cartUC.Get(ctx, c.Request().Header.Get(models.CartTokenHeader))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, currentCart)
+	}
+}
+
+// AddItem godoc
+// @Summary Add cart item
+// @Description add product variant by SKU, guests get a cart_token in the response when a new cart is created
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param X-Cart-Token header string false "guest cart token"
+// @Success 200 {object} models.Cart
+// @Failure 400 {object} httpErrors.RestError
+// @Router /cart/items [post]
+func (h *cartHandlers) AddItem() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		input := &models.CartItemInput{}
+		if err := utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		currentCart, err := h.cartUC.AddItem(ctx, c.Request().Header.Get(models.CartTokenHeader), input)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, currentCart)
+	}
+}
+
+// UpdateItem godoc
+// @Summary Update cart item
+// @Description set cart line quantity, zero removes the line
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param X-Cart-Token header string false "guest cart token"
+// @Param sku path string true "sku"
+// @Success 200 {object} models.Cart
+// @Failure 404 {object} httpErrors.RestError
+// @Router /cart/items/{sku} [put]
+func (h *cartHandlers) UpdateItem() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		input := &models.CartQuantityInput{}
+		if err := utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		currentCart, err := h.cartUC.UpdateItem(ctx, c.Request().Header.Get(models.CartTokenHeader), c.Param("sku"), input.Quantity)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, currentCart)
+	}
+}
+
+// RemoveItem godoc
+// @Summary Remove cart item
+// @Description remove cart line
+// @Tags Cart
+// @Produce json
+// @Param X-Cart-Token header string false "guest cart token"
+// @Param sku path string true "sku"
+// @Success 200 {object} models.Cart
+// @Failure 404 {object} httpErrors.RestError
+// @Router /cart/items/{sku} [delete]
+func (h *cartHandlers) RemoveItem() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		currentCart, err := h.cartUC.RemoveItem(ctx, c.Request().Header.Get(models.CartTokenHeader), c.Param("sku"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, currentCart)
+	}
+}
+
+// Clear godoc
+// @Summary Clear cart
+// @Description remove every cart line
+// @Tags Cart
+// @Produce json
+// @Param X-Cart-Token header string false "guest cart token"
+// @Success 200 {object} models.Cart
+// @Failure 404 {object} httpErrors.RestError
+// @Router /cart [delete]
+func (h *cartHandlers) Clear() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		currentCart, err := h.cartUC.Clear(ctx, c.Request().Header.Get(models.CartTokenHeader))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, currentCart)
+	}
+}
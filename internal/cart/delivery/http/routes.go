@@ -0,0 +1,16 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/cart"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func MapCartRoutes(cartGroup *echo.Group, h cart.Handlers, mw *middleware.MiddlewareManager) {
+	cartGroup.Use(mw.OptionalAuthMiddleware)
+	cartGroup.GET("", h.Get())
+	cartGroup.DELETE("", h.Clear())
+	cartGroup.POST("/items", h.AddItem())
+	cartGroup.PUT("/items/:sku", h.UpdateItem())
+	cartGroup.DELETE("/items/:sku", h.RemoveItem())
+}
@@ -0,0 +1,23 @@
+package cart
+
+import (
+	"context"
+	"time"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Cart repository
+type Repository interface {
+	Create(ctx context.Context, userID *uuid.UUID) (*models.Cart, error)
+	GetByID(ctx context.Context, cartID uuid.UUID) (*models.Cart, error)
+	GetActiveByUser(ctx context.Context, userID uuid.UUID) (*models.Cart, error)
+	GetItems(ctx context.Context, cartID uuid.UUID) ([]*models.CartItem, error)
+	AddItem(ctx context.Context, cartID uuid.UUID, sku string, quantity int) error
+	SetItemQuantity(ctx context.Context, cartID uuid.UUID, sku string, quantity int) error
+	RemoveItem(ctx context.Context, cartID uuid.UUID, sku string) error
+	Clear(ctx context.Context, cartID uuid.UUID) error
+	Merge(ctx context.Context, fromCartID uuid.UUID, toCartID uuid.UUID) error
+	DeleteInactive(ctx context.Context, guestBefore time.Time, userBefore time.Time) (int64, error)
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fekuna/go-store/internal/cart"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Cart repository
+type cartRepo struct {
+	db *sqlx.DB
+}
+
+// Cart repository constructor
+func NewCartRepository(db *sqlx.DB) cart.Repository {
+	return &cartRepo{db: db}
+}
+
+func (r *cartRepo) Create(ctx context.Context, userID *uuid.UUID) (*models.Cart, error) {
+	// TODO: Tracing
+
+	c := &models.Cart{}
+	if err := r.db.QueryRowxContext(ctx, createCartQuery, userID).StructScan(c); err != nil {
+		return nil, errors.Wrap(err, "cartRepo.Create.StructScan")
+	}
+
+	return c, nil
+}
+
+func (r *cartRepo) GetByID(ctx context.Context, cartID uuid.UUID) (*models.Cart, error) {
+	// TODO: Tracing
+
+	c := &models.Cart{}
+	if err := r.db.GetContext(ctx, c, getCartByIdQuery, cartID); err != nil {
+		return nil, errors.Wrap(err, "cartRepo.GetByID.GetContext")
+	}
+
+	return c, nil
+}
+
+func (r *cartRepo) GetActiveByUser(ctx context.Context, userID uuid.UUID) (*models.Cart, error) {
+	// TODO: Tracing
+
+	c := &models.Cart{}
+	if err := r.db.GetContext(ctx, c, getActiveCartByUserQuery, userID); err != nil {
+		return nil, errors.Wrap(err, "cartRepo.GetActiveByUser.GetContext")
+	}
+
+	return c, nil
+}
+
+func (r *cartRepo) GetItems(ctx context.Context, cartID uuid.UUID) ([]*models.CartItem, error) {
+	// TODO: Tracing
+
+	items := make([]*models.CartItem, 0)
+	if err := r.db.SelectContext(ctx, &items, getCartItemsQuery, cartID); err != nil {
+		return nil, errors.Wrap(err, "cartRepo.GetItems.SelectContext")
+	}
+
+	return items, nil
+}
+
+// Add quantity to cart line, creating it if needed
+func (r *cartRepo) AddItem(ctx context.Context, cartID uuid.UUID, sku string, quantity int) error {
+	// TODO: Tracing
+
+	if _, err := r.db.ExecContext(ctx, addCartItemQuery, cartID, sku, quantity); err != nil {
+		return errors.Wrap(err, "cartRepo.AddItem.ExecContext")
+	}
+
+	return r.touch(ctx, cartID)
+}
+
+func (r *cartRepo) SetItemQuantity(ctx context.Context, cartID uuid.UUID, sku string, quantity int) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, setCartItemQuantityQuery, quantity, cartID, sku)
+	if err != nil {
+		return errors.Wrap(err, "cartRepo.SetItemQuantity.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "cartRepo.SetItemQuantity.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "cartRepo.SetItemQuantity.rowsAffected")
+	}
+
+	return r.touch(ctx, cartID)
+}
+
+func (r *cartRepo) RemoveItem(ctx context.Context, cartID uuid.UUID, sku string) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, removeCartItemQuery, cartID, sku)
+	if err != nil {
+		return errors.Wrap(err, "cartRepo.RemoveItem.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "cartRepo.RemoveItem.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "cartRepo.RemoveItem.rowsAffected")
+	}
+
+	return r.touch(ctx, cartID)
+}
+
+func (r *cartRepo) Clear(ctx context.Context, cartID uuid.UUID) error {
+	// TODO: Tracing
+
+	if _, err := r.db.ExecContext(ctx, clearCartQuery, cartID); err != nil {
+		return errors.Wrap(err, "cartRepo.Clear.ExecContext")
+	}
+
+	return r.touch(ctx, cartID)
+}
+
+// Move every line of one cart into another, quantities of the same SKU are added up, source cart is deleted
+func (r *cartRepo) Merge(ctx context.Context, fromCartID uuid.UUID, toCartID uuid.UUID) error {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "cartRepo.Merge.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, mergeCartItemsQuery, fromCartID, toCartID); err != nil {
+		return errors.Wrap(err, "cartRepo.Merge.ExecContext.items")
+	}
+
+	if _, err = tx.ExecContext(ctx, deleteCartQuery, fromCartID); err != nil {
+		return errors.Wrap(err, "cartRepo.Merge.ExecContext.delete")
+	}
+
+	if _, err = tx.ExecContext(ctx, touchCartQuery, toCartID); err != nil {
+		return errors.Wrap(err, "cartRepo.Merge.ExecContext.touch")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "cartRepo.Merge.Commit")
+	}
+
+	return nil
+}
+
+// Delete active carts without activity since the given times
+func (r *cartRepo) DeleteInactive(ctx context.Context, guestBefore time.Time, userBefore time.Time) (int64, error) {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, deleteInactiveCartsQuery, guestBefore, userBefore)
+	if err != nil {
+		return 0, errors.Wrap(err, "cartRepo.DeleteInactive.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "cartRepo.DeleteInactive.RowsAffected")
+	}
+
+	return rowsAffected, nil
+}
+
+func (r *cartRepo) touch(ctx context.Context, cartID uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx, touchCartQuery, cartID); err != nil {
+		return errors.Wrap(err, "cartRepo.touch.ExecContext")
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+const (
+	createCartQuery = `
+		INSERT INTO carts(user_id, status, created_at, updated_at)
+		VALUES ($1, 'active', now(), now())
+		RETURNING *
+	`
+
+	getCartByIdQuery = `SELECT * FROM carts WHERE cart_id = $1`
+
+	getActiveCartByUserQuery = `SELECT * FROM carts WHERE user_id = $1 AND status = 'active'`
+
+	getCartItemsQuery = `
+		SELECT i.cart_id, i.sku, i.quantity, i.added_at,
+			v.variant_id, p.product_id, p.title, v.title AS variant_title,
+			COALESCE(v.price_override, p.price) AS unit_price, p.currency, v.weight_grams,
+			p.status = 'active' AND v.status = 'active' AS available,
+			COALESCE(s.on_hand - s.reserved, 0) >= i.quantity AS in_stock
+		FROM cart_items i
+		JOIN product_variants v ON v.sku = i.sku
+		JOIN products p ON p.product_id = v.product_id
+		LEFT JOIN stock_levels s ON s.sku = i.sku
+		WHERE i.cart_id = $1
+		ORDER BY i.added_at, i.sku
+	`
+
+	addCartItemQuery = `
+		INSERT INTO cart_items(cart_id, sku, quantity, added_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (cart_id, sku) DO UPDATE SET quantity = LEAST(cart_items.quantity + EXCLUDED.quantity, 99)
+	`
+
+	setCartItemQuantityQuery = `UPDATE cart_items SET quantity = $1 WHERE cart_id = $2 AND sku = $3`
+
+	removeCartItemQuery = `DELETE FROM cart_items WHERE cart_id = $1 AND sku = $2`
+
+	clearCartQuery = `DELETE FROM cart_items WHERE cart_id = $1`
+
+	touchCartQuery = `UPDATE carts SET updated_at = now() WHERE cart_id = $1`
+
+	mergeCartItemsQuery = `
+		INSERT INTO cart_items(cart_id, sku, quantity, added_at)
+		SELECT $2, sku, quantity, added_at FROM cart_items WHERE cart_id = $1
+		ON CONFLICT (cart_id, sku) DO UPDATE SET quantity = LEAST(cart_items.quantity + EXCLUDED.quantity, 99)
+	`
+
+	deleteCartQuery = `DELETE FROM carts WHERE cart_id = $1`
+
+	deleteInactiveCartsQuery = `
+		DELETE FROM carts
+		WHERE status = 'active'
+			AND ((user_id IS NULL AND updated_at < $1) OR (user_id IS NOT NULL AND updated_at < $2))
+	`
+)
@@ -0,0 +1,19 @@
+package cart
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Cart UseCase, the cart is the one of the user in context or the guest cart of token
+type UseCase interface {
+	Get(ctx context.Context, token string) (*models.Cart, error)
+	AddItem(ctx context.Context, token string, input *models.CartItemInput) (*models.Cart, error)
+	UpdateItem(ctx context.Context, token string, sku string, quantity int) (*models.Cart, error)
+	RemoveItem(ctx context.Context, token string, sku string) (*models.Cart, error)
+	Clear(ctx context.Context, token string) (*models.Cart, error)
+	MergeGuestCart(ctx context.Context, token string, userID uuid.UUID) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
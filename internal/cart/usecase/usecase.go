@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/cart"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/product"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Purpose of the signed guest cart tokens
+const cartTokenPurpose = "cart"
+
+// Cart UseCase
+type cartUC struct {
+	cfg         *config.Config
+	logger      logger.Logger
+	cartRepo    cart.Repository
+	productRepo product.Repository
+}
+
+// Cart UseCase constructor
+func NewCartUseCase(cfg *config.Config, logger logger.Logger, cartRepo cart.Repository, productRepo product.Repository) cart.UseCase {
+	return &cartUC{
+		cfg:         cfg,
+		logger:      logger,
+		cartRepo:    cartRepo,
+		productRepo: productRepo,
+	}
+}
+
+// Get current cart, an empty cart is returned without storing it when there is none yet
+func (u *cartUC) Get(ctx context.Context, token string) (*models.Cart, error) {
+	// TODO: Tracing
+
+	c, err := u.resolveCart(ctx, token, false)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		c = &models.Cart{Status: models.CartStatusActive}
+	}
+
+	return u.withItems(ctx, c)
+}
+
+// Add active product variant to cart, quantities of the same SKU add up
+func (u *cartUC) AddItem(ctx context.Context, token string, input *models.CartItemInput) (*models.Cart, error) {
+	// TODO: Tracing
+
+	sku := models.NormalizeSKU(input.SKU)
+
+	variant, err := u.productRepo.GetVariantBySKU(ctx, sku)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, httpErrors.NewBadRequestError(errors.Errorf("cartUC.AddItem: unknown sku %s", sku))
+		}
+		return nil, err
+	}
+
+	p, err := u.productRepo.GetByID(ctx, variant.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != models.ProductStatusActive || variant.Status != models.VariantStatusActive {
+		return nil, httpErrors.NewBadRequestError(errors.Errorf("cartUC.AddItem: sku %s is not available", sku))
+	}
+
+	c, err := u.resolveCart(ctx, token, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = u.cartRepo.AddItem(ctx, c.CartID, variant.SKU, input.Quantity); err != nil {
+		return nil, err
+	}
+
+	return u.withItems(ctx, c)
+}
+
+// Set line quantity, zero removes the line
+func (u *cartUC) UpdateItem(ctx context.Context, token string, sku string, quantity int) (*models.Cart, error) {
+	// TODO: Tracing
+
+	if quantity == 0 {
+		return u.RemoveItem(ctx, token, sku)
+	}
+
+	c, err := u.mustResolveCart(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = u.cartRepo.SetItemQuantity(ctx, c.CartID, models.NormalizeSKU(sku), quantity); err != nil {
+		return nil, err
+	}
+
+	return u.withItems(ctx, c)
+}
+
+func (u *cartUC) RemoveItem(ctx context.Context, token string, sku string) (*models.Cart, error) {
+	// TODO: Tracing
+
+	c, err := u.mustResolveCart(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = u.cartRepo.RemoveItem(ctx, c.CartID, models.NormalizeSKU(sku)); err != nil {
+		return nil, err
+	}
+
+	return u.withItems(ctx, c)
+}
+
+func (u *cartUC) Clear(ctx context.Context, token string) (*models.Cart, error) {
+	// TODO: Tracing
+
+	c, err := u.mustResolveCart(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = u.cartRepo.Clear(ctx, c.CartID); err != nil {
+		return nil, err
+	}
+
+	return u.withItems(ctx, c)
+}
+
+// Move guest cart lines into the user's cart, used right after login
+func (u *cartUC) MergeGuestCart(ctx context.Context, token string, userID uuid.UUID) error {
+	// TODO: Tracing
+
+	guest, err := u.guestCart(ctx, token)
+	if err != nil {
+		return err
+	}
+	if guest == nil {
+		return nil
+	}
+
+	userCart, err := u.userCart(ctx, userID, true)
+	if err != nil {
+		return err
+	}
+
+	return u.cartRepo.Merge(ctx, guest.CartID, userCart.CartID)
+}
+
+// Delete carts idle for longer than the configured TTL
+func (u *cartUC) DeleteExpired(ctx context.Context) (int64, error) {
+	// TODO: Tracing
+
+	now := time.Now()
+
+	return u.cartRepo.DeleteInactive(
+		ctx,
+		now.Add(-time.Second*u.cfg.Cart.GuestTTL),
+		now.Add(-time.Second*u.cfg.Cart.UserTTL),
+	)
+}
+
+// Cart of the user in context, otherwise the guest cart of token. Returns nil if none and create is false.
+func (u *cartUC) resolveCart(ctx context.Context, token string, create bool) (*models.Cart, error) {
+	if user, err := middleware.GetUserFromCtx(ctx); err == nil {
+		return u.userCart(ctx, user.UserID, create)
+	}
+
+	c, err := u.guestCart(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if c != nil || !create {
+		return c, nil
+	}
+
+	c, err = u.cartRepo.Create(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.Token = utils.SignID(u.cfg.Server.TokenSecretKey, cartTokenPurpose, c.CartID, time.Time{})
+
+	return c, nil
+}
+
+func (u *cartUC) mustResolveCart(ctx context.Context, token string) (*models.Cart, error) {
+	c, err := u.resolveCart(ctx, token, false)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, httpErrors.NewNotFoundError(errors.New("cartUC.mustResolveCart: cart not found"))
+	}
+
+	return c, nil
+}
+
+func (u *cartUC) userCart(ctx context.Context, userID uuid.UUID, create bool) (*models.Cart, error) {
+	c, err := u.cartRepo.GetActiveByUser(ctx, userID)
+	if err == nil {
+		return c, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if !create {
+		return nil, nil
+	}
+
+	return u.cartRepo.Create(ctx, &userID)
+}
+
+// Active guest cart of token, nil when there is no token or the cart is gone
+func (u *cartUC) guestCart(ctx context.Context, token string) (*models.Cart, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	cartID, err := utils.VerifySignedID(u.cfg.Server.TokenSecretKey, cartTokenPurpose, token, time.Now())
+	if err != nil {
+		return nil, httpErrors.NewBadRequestError(errors.Wrap(err, "cartUC.guestCart: invalid cart token"))
+	}
+
+	c, err := u.cartRepo.GetByID(ctx, cartID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if c.UserID != nil || c.Status != models.CartStatusActive {
+		return nil, nil
+	}
+	c.Token = token
+
+	return c, nil
+}
+
+func (u *cartUC) withItems(ctx context.Context, c *models.Cart) (*models.Cart, error) {
+	c.Items = make([]*models.CartItem, 0)
+	if c.CartID != uuid.Nil {
+		items, err := u.cartRepo.GetItems(ctx, c.CartID)
+		if err != nil {
+			return nil, err
+		}
+		c.Items = items
+	}
+
+	c.CalculateTotals()
+
+	return c, nil
+}
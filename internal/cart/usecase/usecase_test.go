@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/cart"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Cart repository that only knows carts by id, other methods are not used by the token tests
+type tokenCartRepo struct {
+	cart.Repository
+	carts map[uuid.UUID]*models.Cart
+}
+
+func (r *tokenCartRepo) Create(ctx context.Context, userID *uuid.UUID) (*models.Cart, error) {
+	c := &models.Cart{CartID: uuid.New(), UserID: userID, Status: models.CartStatusActive}
+	r.carts[c.CartID] = c
+	return c, nil
+}
+
+func (r *tokenCartRepo) GetByID(ctx context.Context, cartID uuid.UUID) (*models.Cart, error) {
+	c, ok := r.carts[cartID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *c
+	return &copied, nil
+}
+
+func TestGuestCartToken(t *testing.T) {
+	cfg := &config.Config{Server: config.ServerConfig{TokenSecretKey: "token-secret"}}
+	repo := &tokenCartRepo{carts: map[uuid.UUID]*models.Cart{}}
+	u := &cartUC{cfg: cfg, cartRepo: repo}
+	ctx := context.Background()
+
+	guest, err := u.resolveCart(ctx, "", true)
+	if err != nil {
+		t.Fatalf("resolveCart() error = %v", err)
+	}
+	if guest.Token == "" {
+		t.Fatal("new guest cart has no token")
+	}
+
+	userID := uuid.New()
+	owned, _ := repo.Create(ctx, &userID)
+	converted, _ := repo.Create(ctx, nil)
+	repo.carts[converted.CartID].Status = models.CartStatusConverted
+
+	sign := func(secret string, purpose string, cartID uuid.UUID) string {
+		return utils.SignID(secret, purpose, cartID, time.Time{})
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantCartID uuid.UUID
+		wantStatus int
+	}{
+		{name: "no token"},
+		{name: "issued token", token: guest.Token, wantCartID: guest.CartID},
+		{name: "unknown cart", token: sign("token-secret", cartTokenPurpose, uuid.New())},
+		{name: "cart of a user", token: sign("token-secret", cartTokenPurpose, owned.CartID)},
+		{name: "converted cart", token: sign("token-secret", cartTokenPurpose, converted.CartID)},
+		{name: "other secret", token: sign("jwt-secret", cartTokenPurpose, guest.CartID), wantStatus: http.StatusBadRequest},
+		{name: "other purpose", token: sign("token-secret", "cart-recovery", guest.CartID), wantStatus: http.StatusBadRequest},
+		{name: "malformed", token: "not-a-token", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := u.guestCart(ctx, tt.token)
+			if tt.wantStatus != 0 {
+				if err == nil || httpErrors.ParseError(err).Status() != tt.wantStatus {
+					t.Fatalf("guestCart() error = %v, want status %d", err, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("guestCart() error = %v", err)
+			}
+
+			if tt.wantCartID == uuid.Nil {
+				if c != nil {
+					t.Fatalf("guestCart() = cart %s, want none", c.CartID)
+				}
+				return
+			}
+			if c == nil || c.CartID != tt.wantCartID || c.Token != tt.token {
+				t.Fatalf("guestCart() = %+v, want cart %s with its token", c, tt.wantCartID)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package cartrecovery
+
+import "github.com/labstack/echo/v4"
+
+// Cart recovery HTTP Handlers interface
+type Handlers interface {
+	Restore() echo.HandlerFunc
+	Unsubscribe() echo.HandlerFunc
+	GetStats() echo.HandlerFunc
+}
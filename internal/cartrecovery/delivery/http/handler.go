@@ -0,0 +1,112 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/cartrecovery"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// Cart recovery handlers
+type cartRecoveryHandlers struct {
+	cfg            *config.Config
+	logger         logger.Logger
+	cartRecoveryUC cartrecovery.UseCase
+}
+
+// Cart recovery handlers constructor
+func NewCartRecoveryHandlers(cfg *config.Config, logger logger.Logger, cartRecoveryUC cartrecovery.UseCase) cartrecovery.Handlers {
+	return &cartRecoveryHandlers{
+		cfg:            cfg,
+		logger:         logger,
+		cartRecoveryUC: cartRecoveryUC,
+	}
+}
+
+// Restore godoc
+// @Summary Restore abandoned cart
+// @Description bring the lines of a cart reminder back into own cart, lines already in the cart are kept
+// @Tags CartRecovery
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Cart
+// @Failure 400 {object} httpErrors.RestError
+// @Failure 404 {object} httpErrors.RestError
+// @Router /cart-recovery/restore [post]
+func (h *cartRecoveryHandlers) Restore() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		input := &models.CartRecoveryTokenInput{}
+		if err := utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		cart, err := h.cartRecoveryUC.Restore(ctx, input.Token)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, cart)
+	}
+}
+
+// Unsubscribe godoc
+// @Summary Unsubscribe from cart reminders
+// @Description stop abandoned cart reminders with the token of the email link, no login needed
+// @Tags CartRecovery
+// @Accept json
+// @Success 204
+// @Failure 400 {object} httpErrors.RestError
+// @Router /cart-recovery/unsubscribe [post]
+func (h *cartRecoveryHandlers) Unsubscribe() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		input := &models.CartRecoveryTokenInput{}
+		if err := utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err := h.cartRecoveryUC.Unsubscribe(ctx, input.Token); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetStats godoc
+// @Summary Get cart recovery stats
+// @Description reminders sent, restored and converted carts and recovered revenue of the recoveries started in the given days, admin only
+// @Tags CartRecovery
+// @Produce json
+// @Param from query string false "first day, YYYY-MM-DD"
+// @Param to query string false "last day, YYYY-MM-DD"
+// @Success 200 {object} models.CartRecoveryStats
+// @Failure 400 {object} httpErrors.RestError
+// @Router /cart-recovery/stats [get]
+func (h *cartRecoveryHandlers) GetStats() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		query := &models.CartRecoveryStatsQuery{}
+		if err := utils.ReadRequest(c, query); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		stats, err := h.cartRecoveryUC.GetStats(ctx, query)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, stats)
+	}
+}
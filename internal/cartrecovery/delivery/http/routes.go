@@ -0,0 +1,16 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/cartrecovery"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func MapCartRecoveryRoutes(cartRecoveryGroup *echo.Group, h cartrecovery.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	cartRecoveryGroup.POST("/restore", h.Restore(), mw.AuthJWTMiddleware)
+	cartRecoveryGroup.POST("/unsubscribe", h.Unsubscribe())
+	cartRecoveryGroup.GET("/stats", h.GetStats(), adminOnly...)
+}
@@ -0,0 +1,23 @@
+package cartrecovery
+
+import (
+	"context"
+	"time"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Cart recovery repository
+type Repository interface {
+	ListAbandoned(ctx context.Context, idleBefore time.Time, idleAfter time.Time, limit int) ([]*models.AbandonedCart, error)
+	ListDue(ctx context.Context, step int, sentBefore time.Time, idleBefore time.Time, limit int) ([]*models.AbandonedCart, error)
+	Create(ctx context.Context, recovery *models.CartRecovery) (*models.CartRecovery, error)
+	Advance(ctx context.Context, recovery *models.CartRecovery) (*models.CartRecovery, error)
+	GetByID(ctx context.Context, recoveryID uuid.UUID) (*models.CartRecovery, error)
+	MarkRestored(ctx context.Context, recoveryID uuid.UUID) error
+	MarkConverted(ctx context.Context, window time.Duration) (int64, error)
+	Expire(ctx context.Context, startedBefore time.Time) (int64, error)
+	Unsubscribe(ctx context.Context, userID uuid.UUID, list string) error
+	GetStats(ctx context.Context, from time.Time, to time.Time) (*models.CartRecoveryStats, error)
+}
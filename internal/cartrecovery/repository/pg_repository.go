@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fekuna/go-store/internal/cartrecovery"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Cart recovery repository
+type cartRecoveryRepo struct {
+	db *sqlx.DB
+}
+
+// Cart recovery repository constructor
+func NewCartRecoveryRepository(db *sqlx.DB) cartrecovery.Repository {
+	return &cartRecoveryRepo{db: db}
+}
+
+// User carts idle since idleBefore, but not since before idleAfter, due for the first reminder
+func (r *cartRecoveryRepo) ListAbandoned(ctx context.Context, idleBefore time.Time, idleAfter time.Time, limit int) ([]*models.AbandonedCart, error) {
+	// TODO: Tracing
+
+	list := make([]*models.AbandonedCart, 0)
+	if err := r.db.SelectContext(ctx, &list, listAbandonedCartsQuery, idleBefore, idleAfter, limit); err != nil {
+		return nil, errors.Wrap(err, "cartRecoveryRepo.ListAbandoned.SelectContext")
+	}
+
+	return list, nil
+}
+
+// Carts of open recoveries that sent step reminders, the last one before sentBefore
+func (r *cartRecoveryRepo) ListDue(ctx context.Context, step int, sentBefore time.Time, idleBefore time.Time, limit int) ([]*models.AbandonedCart, error) {
+	// TODO: Tracing
+
+	list := make([]*models.AbandonedCart, 0)
+	if err := r.db.SelectContext(ctx, &list, listDueRecoveriesQuery, step, sentBefore, idleBefore, limit); err != nil {
+		return nil, errors.Wrap(err, "cartRecoveryRepo.ListDue.SelectContext")
+	}
+
+	return list, nil
+}
+
+// Start the reminder sequence of a cart with the first reminder, sql.ErrNoRows if it has an open one
+func (r *cartRecoveryRepo) Create(ctx context.Context, recovery *models.CartRecovery) (*models.CartRecovery, error) {
+	// TODO: Tracing
+
+	created := &models.CartRecovery{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		createCartRecoveryQuery,
+		recovery.CartID,
+		&recovery.UserID,
+		recovery.Items,
+		&recovery.Currency,
+		&recovery.CartTotal,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "cartRecoveryRepo.Create.StructScan")
+	}
+
+	return created, nil
+}
+
+// Record the next reminder with the current cart lines, sql.ErrNoRows if the step moved on or the sequence ended
+func (r *cartRecoveryRepo) Advance(ctx context.Context, recovery *models.CartRecovery) (*models.CartRecovery, error) {
+	// TODO: Tracing
+
+	updated := &models.CartRecovery{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		advanceCartRecoveryQuery,
+		&recovery.RecoveryID,
+		&recovery.Step,
+		recovery.Items,
+		&recovery.Currency,
+		&recovery.CartTotal,
+	).StructScan(updated); err != nil {
+		return nil, errors.Wrap(err, "cartRecoveryRepo.Advance.StructScan")
+	}
+
+	return updated, nil
+}
+
+func (r *cartRecoveryRepo) GetByID(ctx context.Context, recoveryID uuid.UUID) (*models.CartRecovery, error) {
+	// TODO: Tracing
+
+	recovery := &models.CartRecovery{}
+	if err := r.db.GetContext(ctx, recovery, getCartRecoveryByIdQuery, recoveryID); err != nil {
+		return nil, errors.Wrap(err, "cartRecoveryRepo.GetByID.GetContext")
+	}
+
+	return recovery, nil
+}
+
+// Record the first use of the restore link
+func (r *cartRecoveryRepo) MarkRestored(ctx context.Context, recoveryID uuid.UUID) error {
+	// TODO: Tracing
+
+	if _, err := r.db.ExecContext(ctx, markCartRecoveryRestoredQuery, recoveryID); err != nil {
+		return errors.Wrap(err, "cartRecoveryRepo.MarkRestored.ExecContext")
+	}
+
+	return nil
+}
+
+// Convert open recoveries followed by an order of the user within window of the first reminder
+func (r *cartRecoveryRepo) MarkConverted(ctx context.Context, window time.Duration) (int64, error) {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, markCartRecoveriesConvertedQuery, window.Seconds())
+	if err != nil {
+		return 0, errors.Wrap(err, "cartRecoveryRepo.MarkConverted.ExecContext")
+	}
+
+	converted, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "cartRecoveryRepo.MarkConverted.RowsAffected")
+	}
+
+	return converted, nil
+}
+
+// End open recoveries started before startedBefore
+func (r *cartRecoveryRepo) Expire(ctx context.Context, startedBefore time.Time) (int64, error) {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, expireCartRecoveriesQuery, startedBefore)
+	if err != nil {
+		return 0, errors.Wrap(err, "cartRecoveryRepo.Expire.ExecContext")
+	}
+
+	expired, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "cartRecoveryRepo.Expire.RowsAffected")
+	}
+
+	return expired, nil
+}
+
+// Opt the user out of the email list and end the open reminder sequences
+func (r *cartRecoveryRepo) Unsubscribe(ctx context.Context, userID uuid.UUID, list string) error {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "cartRecoveryRepo.Unsubscribe.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, createEmailUnsubscribeQuery, userID, list); err != nil {
+		return errors.Wrap(err, "cartRecoveryRepo.Unsubscribe.ExecContext.unsubscribe")
+	}
+
+	if _, err = tx.ExecContext(ctx, unsubscribeCartRecoveriesQuery, userID); err != nil {
+		return errors.Wrap(err, "cartRecoveryRepo.Unsubscribe.ExecContext.recoveries")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "cartRecoveryRepo.Unsubscribe.Commit")
+	}
+
+	return nil
+}
+
+// Totals, per step and per currency figures of the recoveries started in [from, to)
+func (r *cartRecoveryRepo) GetStats(ctx context.Context, from time.Time, to time.Time) (*models.CartRecoveryStats, error) {
+	// TODO: Tracing
+
+	stats := &models.CartRecoveryStats{From: from, To: to}
+	if err := r.db.GetContext(ctx, stats, getCartRecoveryStatsQuery, from, to); err != nil {
+		return nil, errors.Wrap(err, "cartRecoveryRepo.GetStats.GetContext")
+	}
+
+	stats.Steps = make([]*models.CartRecoveryStepStats, 0)
+	if err := r.db.SelectContext(ctx, &stats.Steps, getCartRecoveryStepStatsQuery, from, to); err != nil {
+		return nil, errors.Wrap(err, "cartRecoveryRepo.GetStats.SelectContext.steps")
+	}
+
+	stats.Revenue = make([]*models.CartRecoveryRevenue, 0)
+	if err := r.db.SelectContext(ctx, &stats.Revenue, getCartRecoveryRevenueQuery, from, to); err != nil {
+		return nil, errors.Wrap(err, "cartRecoveryRepo.GetStats.SelectContext.revenue")
+	}
+
+	return stats, nil
+}
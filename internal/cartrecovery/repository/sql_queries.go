@@ -0,0 +1,136 @@
+package repository
+
+const (
+	// Idle user carts with something to buy that have no reminder sequence yet. A cart gets a new sequence once
+	// it was changed after the previous one ended, carts idle since before idleAfter are left alone.
+	listAbandonedCartsQuery = `
+		SELECT c.cart_id, c.user_id, u.email, u.first_name, NULL::uuid AS recovery_id, 0 AS step
+		FROM carts c
+		JOIN users u ON u.user_id = c.user_id
+		WHERE c.status = 'active' AND c.updated_at <= $1 AND c.updated_at > $2
+			AND EXISTS (
+				SELECT 1 FROM cart_items i
+				JOIN product_variants v ON v.sku = i.sku
+				JOIN products p ON p.product_id = v.product_id
+				WHERE i.cart_id = c.cart_id AND p.status = 'active' AND v.status = 'active'
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM email_unsubscribes e WHERE e.user_id = c.user_id AND e.list = 'cart_reminders'
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM cart_recoveries r
+				WHERE r.cart_id = c.cart_id AND (r.status = 'open' OR r.created_at >= c.updated_at)
+			)
+		ORDER BY c.updated_at
+		LIMIT $3
+	`
+
+	// Open sequences that sent step reminders and whose cart is still idle and not checked out
+	listDueRecoveriesQuery = `
+		SELECT c.cart_id, c.user_id, u.email, u.first_name, r.recovery_id, r.step
+		FROM cart_recoveries r
+		JOIN carts c ON c.cart_id = r.cart_id
+		JOIN users u ON u.user_id = r.user_id
+		WHERE r.status = 'open' AND r.step = $1 AND r.last_sent_at <= $2
+			AND c.status = 'active' AND c.updated_at <= $3
+			AND EXISTS (
+				SELECT 1 FROM cart_items i
+				JOIN product_variants v ON v.sku = i.sku
+				JOIN products p ON p.product_id = v.product_id
+				WHERE i.cart_id = c.cart_id AND p.status = 'active' AND v.status = 'active'
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM email_unsubscribes e WHERE e.user_id = r.user_id AND e.list = 'cart_reminders'
+			)
+		ORDER BY r.last_sent_at
+		LIMIT $4
+	`
+
+	// Nothing is returned when another instance started the sequence first
+	createCartRecoveryQuery = `
+		INSERT INTO cart_recoveries(cart_id, user_id, status, step, items, currency, cart_total, last_sent_at,
+			created_at, updated_at)
+		VALUES ($1, $2, 'open', 1, $3, $4, $5, now(), now(), now())
+		ON CONFLICT (cart_id) WHERE status = 'open' DO NOTHING
+		RETURNING *
+	`
+
+	// Nothing is returned when another instance sent the reminder first or the sequence ended
+	advanceCartRecoveryQuery = `
+		UPDATE cart_recoveries
+		SET step = step + 1, items = $3, currency = $4, cart_total = $5, last_sent_at = now(), updated_at = now()
+		WHERE recovery_id = $1 AND step = $2 AND status = 'open'
+		RETURNING *
+	`
+
+	getCartRecoveryByIdQuery = `SELECT * FROM cart_recoveries WHERE recovery_id = $1`
+
+	markCartRecoveryRestoredQuery = `
+		UPDATE cart_recoveries
+		SET restored_at = COALESCE(restored_at, now()), updated_at = now()
+		WHERE recovery_id = $1
+	`
+
+	// The first order placed within the window after the sequence started converts it, unpaid and cancelled
+	// orders don't count
+	markCartRecoveriesConvertedQuery = `
+		UPDATE cart_recoveries r
+		SET status = 'converted', order_id = m.order_id, order_total = m.total, converted_at = m.created_at,
+			updated_at = now()
+		FROM (
+			SELECT DISTINCT ON (s.recovery_id) s.recovery_id, o.order_id, o.total, o.created_at
+			FROM cart_recoveries s
+			JOIN orders o ON o.user_id = s.user_id
+				AND o.created_at >= s.created_at AND o.created_at < s.created_at + make_interval(secs => $1)
+			WHERE s.status = 'open' AND o.status NOT IN ('pending', 'cancelled')
+			ORDER BY s.recovery_id, o.created_at
+		) m
+		WHERE r.recovery_id = m.recovery_id
+	`
+
+	expireCartRecoveriesQuery = `
+		UPDATE cart_recoveries SET status = 'expired', updated_at = now()
+		WHERE status = 'open' AND created_at < $1
+	`
+
+	createEmailUnsubscribeQuery = `
+		INSERT INTO email_unsubscribes(user_id, list, created_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (user_id, list) DO NOTHING
+	`
+
+	unsubscribeCartRecoveriesQuery = `
+		UPDATE cart_recoveries SET status = 'unsubscribed', updated_at = now()
+		WHERE user_id = $1 AND status = 'open'
+	`
+
+	getCartRecoveryStatsQuery = `
+		SELECT COUNT(recovery_id) AS carts,
+			COALESCE(SUM(step), 0) AS reminders_sent,
+			COUNT(restored_at) AS restored,
+			COUNT(recovery_id) FILTER (WHERE status = 'converted') AS converted,
+			COUNT(recovery_id) FILTER (WHERE status = 'unsubscribed') AS unsubscribed
+		FROM cart_recoveries
+		WHERE created_at >= $1 AND created_at < $2
+	`
+
+	getCartRecoveryStepStatsQuery = `
+		SELECT step,
+			COUNT(recovery_id) AS carts,
+			COUNT(recovery_id) FILTER (WHERE status = 'converted') AS converted
+		FROM cart_recoveries
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY step
+		ORDER BY step
+	`
+
+	getCartRecoveryRevenueQuery = `
+		SELECT currency,
+			SUM(cart_total) AS abandoned_total,
+			COALESCE(SUM(order_total) FILTER (WHERE status = 'converted'), 0) AS recovered_total
+		FROM cart_recoveries
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY currency
+		ORDER BY currency
+	`
+)
@@ -0,0 +1,15 @@
+package cartrecovery
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+)
+
+// Cart recovery UseCase, the scheduler reminds users of abandoned carts and the email links restore them
+type UseCase interface {
+	RunDue(ctx context.Context) (int, error)
+	Restore(ctx context.Context, token string) (*models.Cart, error)
+	Unsubscribe(ctx context.Context, token string) error
+	GetStats(ctx context.Context, query *models.CartRecoveryStatsQuery) (*models.CartRecoveryStats, error)
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/mailer"
+)
+
+// Reminder about the lines left in the cart, with the restore and unsubscribe links
+func reminderMessage(c *models.AbandonedCart, r *models.CartRecovery, restoreLink string, unsubscribeLink string, last bool) *mailer.Message {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "Hi %s,\n\nYou left these items in your cart:\n\n", c.FirstName)
+	for _, item := range r.Items {
+		title := item.Title
+		if item.VariantTitle != "" {
+			title += " (" + item.VariantTitle + ")"
+		}
+		fmt.Fprintf(b, "  %d x %s  %s\n", item.Quantity, title, formatAmount(item.LineTotal, r.Currency))
+	}
+	fmt.Fprintf(b, "\nSubtotal: %s\n\n", formatAmount(r.CartTotal, r.Currency))
+	fmt.Fprintf(b, "Pick up where you left off, your cart is waiting for you:\n%s\n", restoreLink)
+
+	subject := "You left something in your cart"
+	switch {
+	case last:
+		subject = "Last reminder: your cart is still waiting"
+		b.WriteString("\nThis is the last reminder about this cart, prices and stock may change.\n")
+	case r.Step > 1:
+		subject = "Your cart is still waiting"
+	}
+	fmt.Fprintf(b, "\nDon't want these reminders? Unsubscribe:\n%s\n", unsubscribeLink)
+
+	return &mailer.Message{
+		To:      []string{c.Email},
+		Subject: subject,
+		Body:    b.String(),
+	}
+}
+
+func formatAmount(amount int64, currency string) string {
+	return fmt.Sprintf("%d.%02d %s", amount/100, amount%100, currency)
+}
@@ -0,0 +1,318 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/cart"
+	"github.com/fekuna/go-store/internal/cartrecovery"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/mailer"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Purposes of the signed email link tokens, a token of one is never valid for the other
+const (
+	restoreTokenPurpose     = "cart-recovery"
+	unsubscribeTokenPurpose = "unsubscribe"
+)
+
+const (
+	statsDateLayout  = "2006-01-02"
+	defaultStatsDays = 30
+)
+
+// Cart recovery UseCase
+type cartRecoveryUC struct {
+	cfg              *config.Config
+	logger           logger.Logger
+	cartRecoveryRepo cartrecovery.Repository
+	cartRepo         cart.Repository
+	cartUC           cart.UseCase
+	mailer           mailer.Mailer
+}
+
+// Cart recovery UseCase constructor
+func NewCartRecoveryUseCase(cfg *config.Config, logger logger.Logger, cartRecoveryRepo cartrecovery.Repository, cartRepo cart.Repository, cartUC cart.UseCase, mailer mailer.Mailer) cartrecovery.UseCase {
+	return &cartRecoveryUC{
+		cfg:              cfg,
+		logger:           logger,
+		cartRecoveryRepo: cartRecoveryRepo,
+		cartRepo:         cartRepo,
+		cartUC:           cartUC,
+		mailer:           mailer,
+	}
+}
+
+// Convert recoveries followed by an order, expire the ones past the attribution window and send the reminders
+// that are due. Returns the number of reminders sent.
+func (u *cartRecoveryUC) RunDue(ctx context.Context) (int, error) {
+	// TODO: Tracing
+
+	window := time.Second * u.cfg.CartRecovery.AttributionWindow
+
+	converted, err := u.cartRecoveryRepo.MarkConverted(ctx, window)
+	if err != nil {
+		return 0, err
+	}
+	if converted > 0 {
+		u.logger.Infof("cartRecoveryUC.RunDue: %d carts recovered", converted)
+	}
+
+	now := time.Now()
+	if _, err = u.cartRecoveryRepo.Expire(ctx, now.Add(-window)); err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var previous time.Duration
+	for step, delay := range u.cfg.CartRecovery.Reminders {
+		idle := time.Second * delay
+		n, err := u.remindStep(ctx, step, now.Add(-idle), now.Add(-(idle - previous)), now.Add(-window))
+		sent += n
+		if err != nil {
+			return sent, err
+		}
+		previous = idle
+	}
+
+	return sent, nil
+}
+
+// Restore the cart lines of the reminder into the cart of the user in context. Lines the cart has meanwhile
+// are kept as they are, products no longer sold are left out.
+func (u *cartRecoveryUC) Restore(ctx context.Context, token string) (*models.Cart, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryID, err := u.verifyToken(restoreTokenPurpose, token)
+	if err != nil {
+		return nil, err
+	}
+
+	recovery, err := u.cartRecoveryRepo.GetByID(ctx, recoveryID)
+	if err != nil {
+		return nil, err
+	}
+	if recovery.UserID != user.UserID {
+		return nil, httpErrors.NewNotFoundError(errors.New("cartRecoveryUC.Restore: cart belongs to another user"))
+	}
+
+	c, err := u.cartUC.Get(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	inCart := make(map[string]bool, len(c.Items))
+	for _, item := range c.Items {
+		inCart[item.SKU] = true
+	}
+
+	for _, item := range recovery.Items {
+		if inCart[item.SKU] {
+			continue
+		}
+		if _, err = u.cartUC.AddItem(ctx, "", &models.CartItemInput{SKU: item.SKU, Quantity: item.Quantity}); err != nil {
+			if httpErrors.ParseError(err).Status() >= http.StatusInternalServerError {
+				return nil, err
+			}
+			u.logger.Infof("cartRecoveryUC.Restore: sku %s left out: %v", item.SKU, err)
+		}
+	}
+
+	if err = u.cartRecoveryRepo.MarkRestored(ctx, recovery.RecoveryID); err != nil {
+		return nil, err
+	}
+
+	return u.cartUC.Get(ctx, "")
+}
+
+// Stop cart reminders to the user of token, works without login from the email link
+func (u *cartRecoveryUC) Unsubscribe(ctx context.Context, token string) error {
+	// TODO: Tracing
+
+	userID, err := u.verifyToken(unsubscribeTokenPurpose, token)
+	if err != nil {
+		return err
+	}
+
+	return u.cartRecoveryRepo.Unsubscribe(ctx, userID, models.EmailListCartReminders)
+}
+
+// Conversion of the recoveries started in the given days, the last 30 days by default
+func (u *cartRecoveryUC) GetStats(ctx context.Context, query *models.CartRecoveryStatsQuery) (*models.CartRecoveryStats, error) {
+	// TODO: Tracing
+
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	if query.To != "" {
+		day, err := time.Parse(statsDateLayout, query.To)
+		if err != nil {
+			return nil, httpErrors.NewBadRequestError(errors.Wrap(err, "cartRecoveryUC.GetStats.Parse.to"))
+		}
+		to = day.AddDate(0, 0, 1)
+	}
+
+	from := to.AddDate(0, 0, -defaultStatsDays)
+	if query.From != "" {
+		day, err := time.Parse(statsDateLayout, query.From)
+		if err != nil {
+			return nil, httpErrors.NewBadRequestError(errors.Wrap(err, "cartRecoveryUC.GetStats.Parse.from"))
+		}
+		from = day
+	}
+
+	if !from.Before(to) {
+		return nil, httpErrors.NewBadRequestError(errors.New("cartRecoveryUC.GetStats: from is after to"))
+	}
+
+	stats, err := u.cartRecoveryRepo.GetStats(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.ConversionRate = conversionRate(stats.Converted, stats.Carts)
+	for _, step := range stats.Steps {
+		step.ConversionRate = conversionRate(step.Converted, step.Carts)
+	}
+
+	return stats, nil
+}
+
+// Send the reminders of one step in batches. A batch with failures ends the step, its carts are retried by
+// the next run.
+func (u *cartRecoveryUC) remindStep(ctx context.Context, step int, idleBefore time.Time, sentBefore time.Time, idleAfter time.Time) (int, error) {
+	batchSize := u.cfg.CartRecovery.BatchSize
+
+	sent := 0
+	for {
+		var (
+			carts []*models.AbandonedCart
+			err   error
+		)
+		if step == 0 {
+			carts, err = u.cartRecoveryRepo.ListAbandoned(ctx, idleBefore, idleAfter, batchSize)
+		} else {
+			carts, err = u.cartRecoveryRepo.ListDue(ctx, step, sentBefore, idleBefore, batchSize)
+		}
+		if err != nil {
+			return sent, err
+		}
+
+		failed := false
+		for _, c := range carts {
+			ok, err := u.remind(ctx, c)
+			if err != nil {
+				u.logger.Errorf("cartRecoveryUC.remindStep: cart %s: %v", c.CartID, err)
+				failed = true
+				continue
+			}
+			if ok {
+				sent++
+			}
+		}
+
+		if failed || len(carts) < batchSize {
+			return sent, nil
+		}
+	}
+}
+
+// Record the next reminder of the cart and email it. The reminder is recorded before it is sent so two
+// instances never send it twice, false when another instance got there first.
+func (u *cartRecoveryUC) remind(ctx context.Context, abandoned *models.AbandonedCart) (bool, error) {
+	items, err := u.cartRepo.GetItems(ctx, abandoned.CartID)
+	if err != nil {
+		return false, err
+	}
+	c := &models.Cart{CartID: abandoned.CartID, Items: items}
+	c.CalculateTotals()
+
+	recovery := &models.CartRecovery{
+		CartID:    &abandoned.CartID,
+		UserID:    abandoned.UserID,
+		Step:      abandoned.Step,
+		Items:     models.NewCartRecoveryItems(c),
+		Currency:  c.Totals.Currency,
+		CartTotal: c.Totals.Subtotal,
+	}
+
+	if abandoned.RecoveryID == nil {
+		recovery, err = u.cartRecoveryRepo.Create(ctx, recovery)
+	} else {
+		recovery.RecoveryID = *abandoned.RecoveryID
+		recovery, err = u.cartRecoveryRepo.Advance(ctx, recovery)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	last := recovery.Step >= len(u.cfg.CartRecovery.Reminders)
+	restoreLink := link(u.cfg.CartRecovery.RestoreURL, u.signToken(restoreTokenPurpose, recovery.RecoveryID,
+		time.Now().Add(time.Second*u.cfg.CartRecovery.RestoreLinkTTL)))
+	unsubscribeLink := link(u.cfg.CartRecovery.UnsubscribeURL, u.signToken(unsubscribeTokenPurpose, abandoned.UserID, time.Time{}))
+
+	u.notify(ctx, reminderMessage(abandoned, recovery, restoreLink, unsubscribeLink, last))
+
+	return true, nil
+}
+
+// Send email to the customer, failures are only logged
+func (u *cartRecoveryUC) notify(ctx context.Context, msg *mailer.Message) {
+	if err := u.mailer.Send(ctx, msg); err != nil {
+		u.logger.Errorf("cartRecoveryUC.notify: %v", err)
+	}
+}
+
+// Link tokens are signed with the token secret, unsubscribe links never expire
+func (u *cartRecoveryUC) signToken(purpose string, id uuid.UUID, expiresAt time.Time) string {
+	return utils.SignID(u.cfg.Server.TokenSecretKey, purpose, id, expiresAt)
+}
+
+func (u *cartRecoveryUC) verifyToken(purpose string, token string) (uuid.UUID, error) {
+	id, err := utils.VerifySignedID(u.cfg.Server.TokenSecretKey, purpose, token, time.Now())
+	if err != nil {
+		return uuid.Nil, httpErrors.NewBadRequestError(errors.Wrap(err, "cartRecoveryUC.verifyToken: invalid link token"))
+	}
+
+	return id, nil
+}
+
+// Base URL with the token query param, keeping params the URL already has
+func link(base string, token string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return base + "?token=" + url.QueryEscape(token)
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// Share of converted recoveries rounded to 4 decimals, 0 without recoveries
+func conversionRate(converted int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(converted)/float64(total)*10000) / 10000
+}
@@ -0,0 +1,12 @@
+package catalog
+
+import "github.com/labstack/echo/v4"
+
+// Catalog HTTP Handlers interface
+type Handlers interface {
+	Browse() echo.HandlerFunc
+	CreateImport() echo.HandlerFunc
+	GetImport() echo.HandlerFunc
+	ListImports() echo.HandlerFunc
+	Export() echo.HandlerFunc
+}
@@ -0,0 +1,245 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/catalog"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	optionFilterPrefix    = "opt."
+	attributeFilterPrefix = "attr."
+
+	// Default import upload limit in megabytes
+	defaultImportBodyLimit = 50
+)
+
+// Catalog handlers
+type catalogHandlers struct {
+	cfg       *config.Config
+	logger    logger.Logger
+	catalogUC catalog.UseCase
+}
+
+// Catalog handlers constructor
+func NewCatalogHandlers(cfg *config.Config, logger logger.Logger, catalogUC catalog.UseCase) catalog.Handlers {
+	return &catalogHandlers{
+		cfg:       cfg,
+		logger:    logger,
+		catalogUC: catalogUC,
+	}
+}
+
+// Browse godoc
+// @Summary Browse catalog
+// @Description list active products with combinable filters, facet counts and cursor pagination
+// @Tags Catalog
+// @Produce json
+// @Param category query string false "category id, includes descendants"
+// @Param brand query string false "comma separated brands"
+// @Param min_price query int false "min price in minor units"
+// @Param max_price query int false "max price in minor units"
+// @Param in_stock query bool false "only products with stock"
+// @Param sort query string false "newest, price_asc, price_desc or title_asc"
+// @Param cursor query string false "next_cursor of previous page"
+// @Param limit query int false "page size"
+// @Success 200 {object} models.CatalogPage
+// @Failure 400 {object} httpErrors.RestError
+// @Router /catalog [get]
+func (h *catalogHandlers) Browse() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		query := &models.CatalogQuery{}
+		if err := utils.ReadRequest(c, query); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		page, err := h.catalogUC.Browse(ctx, query, readKeyFilters(c, optionFilterPrefix), readKeyFilters(c, attributeFilterPrefix))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, page)
+	}
+}
+
+// CreateImport godoc
+// @Summary Import catalog
+// @Description upload CSV or JSON Lines file and import products by SKU in background, dry_run only validates rows, admin only
+// @Tags Catalog
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "csv or jsonl file"
+// @Param format query string false "csv or jsonl, derived from file extension by default"
+// @Param dry_run query bool false "validate without writing"
+// @Success 202 {object} models.ImportJob
+// @Failure 400 {object} httpErrors.RestError
+// @Router /catalog/imports [post]
+func (h *catalogHandlers) CreateImport() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		limit := h.cfg.Catalog.ImportBodyLimit
+		if limit == 0 {
+			limit = defaultImportBodyLimit
+		}
+		c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, int64(limit)<<20)
+
+		job := &models.ImportJob{Format: strings.ToLower(c.QueryParam("format"))}
+		if dryRun := c.QueryParam("dry_run"); dryRun != "" {
+			parsed, err := strconv.ParseBool(dryRun)
+			if err != nil {
+				return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(errors.Wrap(err, "dry_run")))
+			}
+			job.DryRun = parsed
+		}
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(errors.WithMessage(err, "ctx.FormFile")))
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		defer file.Close()
+
+		createdJob, err := h.catalogUC.CreateImport(ctx, job, models.UploadInput{
+			File:        file,
+			Name:        fileHeader.Filename,
+			Size:        fileHeader.Size,
+			ContentType: "application/octet-stream",
+		})
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusAccepted, createdJob)
+	}
+}
+
+// GetImport godoc
+// @Summary Get import job
+// @Description get import job progress with the first rejected rows, admin only
+// @Tags Catalog
+// @Produce json
+// @Param job_id path string true "job_id"
+// @Success 200 {object} models.ImportJob
+// @Failure 500 {object} httpErrors.RestError
+// @Router /catalog/imports/{job_id} [get]
+func (h *catalogHandlers) GetImport() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		jobID, err := uuid.Parse(c.Param("job_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		job, err := h.catalogUC.GetImport(ctx, jobID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, job)
+	}
+}
+
+// ListImports godoc
+// @Summary Get import jobs
+// @Description get import jobs, newest first, admin only
+// @Tags Catalog
+// @Produce json
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.ImportJobsList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /catalog/imports [get]
+func (h *catalogHandlers) ListImports() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		jobsList, err := h.catalogUC.ListImports(ctx, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, jobsList)
+	}
+}
+
+// Export godoc
+// @Summary Export catalog
+// @Description stream all products as CSV or JSON Lines in import file format, admin only
+// @Tags Catalog
+// @Produce octet-stream
+// @Param format query string false "csv or jsonl, csv by default"
+// @Success 200 {file} file
+// @Failure 400 {object} httpErrors.RestError
+// @Router /catalog/export [get]
+func (h *catalogHandlers) Export() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		query := &models.ExportQuery{}
+		if err := utils.ReadRequest(c, query); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		format, contentType := models.ImportFormatCSV, "text/csv"
+		if query.Format == models.ImportFormatJSONL {
+			format, contentType = models.ImportFormatJSONL, "application/x-ndjson"
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, contentType)
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"catalog.%s\"", format))
+		c.Response().WriteHeader(http.StatusOK)
+
+		// Headers are already sent, a failure can only cut the stream short
+		if err := h.catalogUC.Export(ctx, format, c.Response()); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+		}
+
+		return nil
+	}
+}
+
+// Read <prefix><name>=value1,value2 query params, e.g. opt.Size=M,L
+func readKeyFilters(c echo.Context, prefix string) map[string][]string {
+	filters := make(map[string][]string)
+	for key, values := range c.QueryParams() {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		name := strings.TrimPrefix(key, prefix)
+		for _, v := range values {
+			filters[name] = append(filters[name], models.SplitQueryList(v)...)
+		}
+	}
+
+	return filters
+}
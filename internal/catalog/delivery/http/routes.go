@@ -0,0 +1,18 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/catalog"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func MapCatalogRoutes(catalogGroup *echo.Group, h catalog.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	catalogGroup.GET("", h.Browse())
+	catalogGroup.POST("/imports", h.CreateImport(), adminOnly...)
+	catalogGroup.GET("/imports", h.ListImports(), adminOnly...)
+	catalogGroup.GET("/imports/:job_id", h.GetImport(), adminOnly...)
+	catalogGroup.GET("/export", h.Export(), adminOnly...)
+}
@@ -0,0 +1,14 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/minio/minio-go/v7"
+)
+
+// Minio S3 interface for import files
+type MinioRepository interface {
+	PutObject(ctx context.Context, input models.UploadInput) (*minio.UploadInfo, error)
+	GetObject(ctx context.Context, bucket string, key string) (*minio.Object, error)
+}
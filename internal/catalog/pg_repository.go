@@ -0,0 +1,22 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Catalog repository
+type Repository interface {
+	Browse(ctx context.Context, filter *models.CatalogFilter) ([]*models.CatalogItem, error)
+	Facets(ctx context.Context, filter *models.CatalogFilter) (*models.CatalogFacets, error)
+	CreateImportJob(ctx context.Context, job *models.ImportJob) (*models.ImportJob, error)
+	UpdateImportJob(ctx context.Context, job *models.ImportJob) error
+	GetImportJob(ctx context.Context, jobID uuid.UUID) (*models.ImportJob, error)
+	ListImportJobs(ctx context.Context, pq *utils.PaginationQuery) (*models.ImportJobsList, error)
+	AddImportErrors(ctx context.Context, rowErrors []*models.ImportRowError) error
+	GetImportErrors(ctx context.Context, jobID uuid.UUID, limit int) ([]*models.ImportRowError, error)
+	ExportProducts(ctx context.Context, fn func(row *models.ImportRow) error) error
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fekuna/go-store/internal/models"
+)
+
+// Facet being counted, its own filter is left out so other values of the facet stay selectable
+const (
+	facetNone            = ""
+	facetCategory        = "category"
+	facetBrand           = "brand"
+	facetPrice           = "price"
+	facetInStock         = "in_stock"
+	facetOptionPrefix    = "option:"
+	facetAttributePrefix = "attribute:"
+)
+
+// Positional arguments of a dynamically built query
+type queryArgs struct {
+	args []interface{}
+}
+
+func (q *queryArgs) add(v interface{}) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+func (q *queryArgs) addList(values []string) string {
+	placeholders := make([]string, 0, len(values))
+	for _, v := range values {
+		placeholders = append(placeholders, q.add(v))
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// Conditions on products p
+func productConditions(f *models.CatalogFilter, q *queryArgs, exclude string) string {
+	conds := []string{"p.status = 'active'"}
+
+	if f.CategoryID != nil && exclude != facetCategory {
+		conds = append(conds, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM product_categories pc
+			JOIN categories c ON c.category_id = pc.category_id
+			WHERE pc.product_id = p.product_id
+				AND c.path LIKE (SELECT path FROM categories WHERE category_id = %s) || '%%'
+		)`, q.add(*f.CategoryID)))
+	}
+
+	if len(f.Brands) > 0 && exclude != facetBrand {
+		conds = append(conds, fmt.Sprintf("p.brand IN (%s)", q.addList(f.Brands)))
+	}
+
+	return strings.Join(conds, " AND ")
+}
+
+// Conditions on product_variants v of product p
+func variantConditions(f *models.CatalogFilter, q *queryArgs, exclude string) string {
+	conds := []string{"v.product_id = p.product_id", "v.status = 'active'"}
+
+	if exclude != facetPrice {
+		if f.MinPrice > 0 {
+			conds = append(conds, fmt.Sprintf("COALESCE(v.price_override, p.price) >= %s", q.add(f.MinPrice)))
+		}
+		if f.MaxPrice > 0 {
+			conds = append(conds, fmt.Sprintf("COALESCE(v.price_override, p.price) <= %s", q.add(f.MaxPrice)))
+		}
+	}
+
+	for _, name := range sortedKeys(f.Options) {
+		if exclude == facetOptionPrefix+name {
+			continue
+		}
+		conds = append(conds, fmt.Sprintf("v.options ->> %s IN (%s)", q.add(name), q.addList(f.Options[name])))
+	}
+
+	for _, code := range sortedKeys(f.Attributes) {
+		if exclude == facetAttributePrefix+code {
+			continue
+		}
+		conds = append(conds, attributeCondition(code, f.Attributes[code], q))
+	}
+
+	if f.InStock && exclude != facetInStock {
+		conds = append(conds, inStockCondition)
+	}
+
+	return strings.Join(conds, " AND ")
+}
+
+// Active products with at least one matching variant, with the cheapest matching price
+func filteredProducts(f *models.CatalogFilter, q *queryArgs, exclude string) string {
+	return fmt.Sprintf(`
+		products p
+		JOIN LATERAL (
+			SELECT MIN(COALESCE(v.price_override, p.price)) AS min_price,
+				COALESCE(BOOL_OR(%s), FALSE) AS in_stock
+			FROM product_variants v
+			WHERE %s
+		) m ON m.min_price IS NOT NULL
+		WHERE %s
+	`, inStockCondition, variantConditions(f, q, exclude), productConditions(f, q, exclude))
+}
+
+// Keyset condition and order for sort, the product id breaks ties so pages never overlap
+func sortClause(f *models.CatalogFilter, q *queryArgs) (string, string) {
+	var keyset, order string
+
+	switch f.Sort {
+	case models.CatalogSortPriceAsc:
+		keyset, order = "(m.min_price, p.product_id) > (%s::bigint, %s::uuid)", "m.min_price ASC, p.product_id ASC"
+	case models.CatalogSortPriceDesc:
+		keyset, order = "(m.min_price, p.product_id) < (%s::bigint, %s::uuid)", "m.min_price DESC, p.product_id DESC"
+	case models.CatalogSortTitleAsc:
+		keyset, order = "(p.title, p.product_id) > (%s::text, %s::uuid)", "p.title ASC, p.product_id ASC"
+	default:
+		keyset, order = "(p.created_at, p.product_id) < (%s::timestamptz, %s::uuid)", "p.created_at DESC, p.product_id DESC"
+	}
+
+	if f.Cursor == nil {
+		return "TRUE", order
+	}
+
+	return fmt.Sprintf(keyset, q.add(f.Cursor.Value), q.add(f.Cursor.ProductID)), order
+}
+
+// Containment checks served by the GIN index on attributes, query values are untyped
+// so numbers and booleans are matched both as typed JSON and as text
+func attributeCondition(code string, values []string, q *queryArgs) string {
+	candidates := make([]string, 0, len(values))
+	for _, v := range values {
+		typed := []interface{}{v}
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			typed = append(typed, n)
+		}
+		if b, err := strconv.ParseBool(v); err == nil {
+			typed = append(typed, b)
+		}
+
+		for _, t := range typed {
+			doc, _ := json.Marshal(map[string]interface{}{code: t})
+			candidates = append(candidates, fmt.Sprintf("v.attributes @> %s::jsonb", q.add(string(doc))))
+		}
+	}
+
+	return "(" + strings.Join(candidates, " OR ") + ")"
+}
+
+func sortedKeys(filters map[string][]string) []string {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/catalog"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+)
+
+// Catalog Minio S3 repository
+type catalogMinioRepository struct {
+	client *minio.Client
+}
+
+// Catalog Minio S3 repository constructor
+func NewCatalogMinioRepository(minioClient *minio.Client) catalog.MinioRepository {
+	return &catalogMinioRepository{client: minioClient}
+}
+
+// Upload file to Minio, input name is used as object key
+func (r *catalogMinioRepository) PutObject(ctx context.Context, input models.UploadInput) (*minio.UploadInfo, error) {
+	// TODO: Tracing
+
+	uploadInfo, err := r.client.PutObject(ctx, input.BucketName, input.Name, input.File, input.Size, minio.PutObjectOptions{ContentType: input.ContentType})
+	if err != nil {
+		return nil, errors.Wrap(err, "catalogMinioRepository.PutObject")
+	}
+
+	return &uploadInfo, nil
+}
+
+// Download file from Minio
+func (r *catalogMinioRepository) GetObject(ctx context.Context, bucket string, key string) (*minio.Object, error) {
+	// TODO: Tracing
+
+	object, err := r.client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "catalogMinioRepository.GetObject")
+	}
+
+	return object, nil
+}
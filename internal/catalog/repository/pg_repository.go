@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fekuna/go-store/internal/catalog"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Lower bounds of price facet buckets in minor units
+var priceBucketBounds = []int64{0, 1000, 2500, 5000, 10000, 25000, 50000}
+
+// Catalog repository
+type catalogRepo struct {
+	db *sqlx.DB
+}
+
+// Catalog repository constructor
+func NewCatalogRepository(db *sqlx.DB) catalog.Repository {
+	return &catalogRepo{db: db}
+}
+
+// Browse active products, fetches one extra item so the caller knows if there is a next page
+func (r *catalogRepo) Browse(ctx context.Context, f *models.CatalogFilter) ([]*models.CatalogItem, error) {
+	// TODO: Tracing
+
+	q := &queryArgs{}
+	from := filteredProducts(f, q, facetNone)
+	keyset, order := sortClause(f, q)
+	query := fmt.Sprintf(browseQuery, from, keyset, order, q.add(f.Limit+1))
+
+	items := make([]*models.CatalogItem, 0, f.Limit+1)
+	if err := r.db.SelectContext(ctx, &items, query, q.args...); err != nil {
+		return nil, errors.Wrap(err, "catalogRepo.Browse.SelectContext")
+	}
+
+	return items, nil
+}
+
+// Count facet values, every facet ignores its own filter
+func (r *catalogRepo) Facets(ctx context.Context, f *models.CatalogFilter) (*models.CatalogFacets, error) {
+	// TODO: Tracing
+
+	facets := &models.CatalogFacets{}
+
+	q := &queryArgs{}
+	facets.Categories = make([]*models.FacetCount, 0)
+	if err := r.db.SelectContext(ctx, &facets.Categories, fmt.Sprintf(categoryFacetQuery, filteredProducts(f, q, facetCategory)), q.args...); err != nil {
+		return nil, errors.Wrap(err, "catalogRepo.Facets.SelectContext.categories")
+	}
+
+	q = &queryArgs{}
+	facets.Brands = make([]*models.FacetCount, 0)
+	if err := r.db.SelectContext(ctx, &facets.Brands, fmt.Sprintf(brandFacetQuery, filteredProducts(f, q, facetBrand)), q.args...); err != nil {
+		return nil, errors.Wrap(err, "catalogRepo.Facets.SelectContext.brands")
+	}
+
+	priceBuckets, err := r.priceFacet(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	facets.PriceBuckets = priceBuckets
+
+	q = &queryArgs{}
+	if err = r.db.GetContext(ctx, &facets.InStock, fmt.Sprintf(inStockFacetQuery, filteredProducts(f, q, facetInStock)), q.args...); err != nil {
+		return nil, errors.Wrap(err, "catalogRepo.Facets.GetContext.inStock")
+	}
+
+	options, err := r.keyFacets(ctx, f, "options", f.Options, facetOptionPrefix)
+	if err != nil {
+		return nil, err
+	}
+	facets.Options = options
+
+	attributes, err := r.keyFacets(ctx, f, "attributes", f.Attributes, facetAttributePrefix)
+	if err != nil {
+		return nil, err
+	}
+	facets.Attributes = attributes
+
+	return facets, nil
+}
+
+func (r *catalogRepo) priceFacet(ctx context.Context, f *models.CatalogFilter) ([]*models.PriceBucketCount, error) {
+	bucketExpr := strings.Builder{}
+	bucketExpr.WriteString("CASE")
+	for i := len(priceBucketBounds) - 1; i > 0; i-- {
+		bucketExpr.WriteString(fmt.Sprintf(" WHEN min_price >= %d THEN %d", priceBucketBounds[i], i))
+	}
+	bucketExpr.WriteString(" ELSE 0 END")
+
+	q := &queryArgs{}
+	query := fmt.Sprintf(priceFacetQuery, filteredProducts(f, q, facetPrice), bucketExpr.String())
+
+	rows := make([]struct {
+		Bucket int `db:"bucket"`
+		Count  int `db:"count"`
+	}, 0)
+	if err := r.db.SelectContext(ctx, &rows, query, q.args...); err != nil {
+		return nil, errors.Wrap(err, "catalogRepo.priceFacet.SelectContext")
+	}
+
+	buckets := make([]*models.PriceBucketCount, 0, len(rows))
+	for _, row := range rows {
+		bucket := &models.PriceBucketCount{Min: priceBucketBounds[row.Bucket], Count: row.Count}
+		if row.Bucket+1 < len(priceBucketBounds) {
+			max := priceBucketBounds[row.Bucket+1]
+			bucket.Max = &max
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets, nil
+}
+
+// Values are counted per key, a selected key ignores its own filter
+func (r *catalogRepo) keyFacets(ctx context.Context, f *models.CatalogFilter, column string, filters map[string][]string, excludePrefix string) ([]*models.OptionFacet, error) {
+	type keyRow struct {
+		Name  string `db:"name"`
+		Value string `db:"value"`
+		Count int    `db:"count"`
+	}
+
+	selected := sortedKeys(filters)
+	rows := make([]keyRow, 0)
+
+	for _, name := range selected {
+		exclude := excludePrefix + name
+		q := &queryArgs{}
+		from := filteredProducts(f, q, exclude)
+		query := fmt.Sprintf(keyFacetQuery, from, column, variantConditions(f, q, exclude), "o.key = "+q.add(name))
+
+		selectedRows := make([]keyRow, 0)
+		if err := r.db.SelectContext(ctx, &selectedRows, query, q.args...); err != nil {
+			return nil, errors.Wrapf(err, "catalogRepo.keyFacets.SelectContext.selected(%s)", column)
+		}
+		rows = append(rows, selectedRows...)
+	}
+
+	q := &queryArgs{}
+	from := filteredProducts(f, q, facetNone)
+	keyCond := "TRUE"
+	if len(selected) > 0 {
+		keyCond = fmt.Sprintf("o.key NOT IN (%s)", q.addList(selected))
+	}
+	query := fmt.Sprintf(keyFacetQuery, from, column, variantConditions(f, q, facetNone), keyCond)
+
+	otherRows := make([]keyRow, 0)
+	if err := r.db.SelectContext(ctx, &otherRows, query, q.args...); err != nil {
+		return nil, errors.Wrapf(err, "catalogRepo.keyFacets.SelectContext.others(%s)", column)
+	}
+	rows = append(rows, otherRows...)
+
+	facets := make([]*models.OptionFacet, 0)
+	byName := make(map[string]*models.OptionFacet)
+	for _, row := range rows {
+		facet, ok := byName[row.Name]
+		if !ok {
+			facet = &models.OptionFacet{Name: row.Name, Values: make([]*models.FacetCount, 0)}
+			byName[row.Name] = facet
+			facets = append(facets, facet)
+		}
+		facet.Values = append(facet.Values, &models.FacetCount{Value: row.Value, Count: row.Count})
+	}
+
+	return facets, nil
+}
+
+func (r *catalogRepo) CreateImportJob(ctx context.Context, job *models.ImportJob) (*models.ImportJob, error) {
+	// TODO: Tracing
+
+	created := &models.ImportJob{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		createImportJobQuery,
+		&job.Format,
+		&job.DryRun,
+		&job.Status,
+		&job.FileName,
+		&job.ObjectKey,
+		job.CreatedBy,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "catalogRepo.CreateImportJob.StructScan")
+	}
+
+	return created, nil
+}
+
+// Save job status, counters and timestamps
+func (r *catalogRepo) UpdateImportJob(ctx context.Context, job *models.ImportJob) error {
+	// TODO: Tracing
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		updateImportJobQuery,
+		&job.Status,
+		&job.ObjectKey,
+		&job.TotalRows,
+		&job.CreatedRows,
+		&job.UpdatedRows,
+		&job.FailedRows,
+		&job.Error,
+		job.StartedAt,
+		job.FinishedAt,
+		&job.JobID,
+	); err != nil {
+		return errors.Wrap(err, "catalogRepo.UpdateImportJob.ExecContext")
+	}
+
+	return nil
+}
+
+func (r *catalogRepo) GetImportJob(ctx context.Context, jobID uuid.UUID) (*models.ImportJob, error) {
+	// TODO: Tracing
+
+	job := &models.ImportJob{}
+	if err := r.db.GetContext(ctx, job, getImportJobQuery, jobID); err != nil {
+		return nil, errors.Wrap(err, "catalogRepo.GetImportJob.GetContext")
+	}
+
+	return job, nil
+}
+
+func (r *catalogRepo) ListImportJobs(ctx context.Context, pq *utils.PaginationQuery) (*models.ImportJobsList, error) {
+	// TODO: Tracing
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalImportJobsQuery); err != nil {
+		return nil, errors.Wrap(err, "catalogRepo.ListImportJobs.GetContext.totalCount")
+	}
+
+	jobs := make([]*models.ImportJob, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &jobs, listImportJobsQuery, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "catalogRepo.ListImportJobs.SelectContext")
+		}
+	}
+
+	return &models.ImportJobsList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Jobs:       jobs,
+	}, nil
+}
+
+// Store a batch of row errors in one transaction
+func (r *catalogRepo) AddImportErrors(ctx context.Context, rowErrors []*models.ImportRowError) error {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "catalogRepo.AddImportErrors.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	for _, e := range rowErrors {
+		if _, err = tx.ExecContext(ctx, createImportErrorQuery, &e.JobID, &e.RowNumber, &e.SKU, &e.Message); err != nil {
+			return errors.Wrap(err, "catalogRepo.AddImportErrors.ExecContext")
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "catalogRepo.AddImportErrors.Commit")
+	}
+
+	return nil
+}
+
+func (r *catalogRepo) GetImportErrors(ctx context.Context, jobID uuid.UUID, limit int) ([]*models.ImportRowError, error) {
+	// TODO: Tracing
+
+	rowErrors := make([]*models.ImportRowError, 0)
+	if err := r.db.SelectContext(ctx, &rowErrors, getImportErrorsQuery, jobID, limit); err != nil {
+		return nil, errors.Wrap(err, "catalogRepo.GetImportErrors.SelectContext")
+	}
+
+	return rowErrors, nil
+}
+
+// Stream every product to fn without loading the whole catalog in memory
+func (r *catalogRepo) ExportProducts(ctx context.Context, fn func(row *models.ImportRow) error) error {
+	// TODO: Tracing
+
+	rows, err := r.db.QueryxContext(ctx, exportProductsQuery)
+	if err != nil {
+		return errors.Wrap(err, "catalogRepo.ExportProducts.QueryxContext")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := &models.ImportRow{}
+		if err = rows.StructScan(row); err != nil {
+			return errors.Wrap(err, "catalogRepo.ExportProducts.StructScan")
+		}
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return errors.Wrap(err, "catalogRepo.ExportProducts.rows.Err")
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+const (
+	inStockCondition = `EXISTS (SELECT 1 FROM stock_levels s WHERE s.sku = v.sku AND s.on_hand > s.reserved)`
+
+	browseQuery = `
+		SELECT p.*, m.min_price, m.in_stock
+		FROM %s AND %s
+		ORDER BY %s
+		LIMIT %s
+	`
+
+	filteredCTE = `WITH filtered AS (SELECT p.product_id, p.brand, m.min_price, m.in_stock FROM %s)`
+
+	categoryFacetQuery = filteredCTE + `
+		SELECT c.category_id::text AS value, c.name AS label, COUNT(*) AS count
+		FROM filtered f
+		JOIN product_categories pc ON pc.product_id = f.product_id
+		JOIN categories c ON c.category_id = pc.category_id
+		GROUP BY c.category_id, c.name
+		ORDER BY count DESC, c.name
+		LIMIT 50
+	`
+
+	brandFacetQuery = filteredCTE + `
+		SELECT brand AS value, COUNT(*) AS count
+		FROM filtered
+		WHERE brand <> ''
+		GROUP BY brand
+		ORDER BY count DESC, brand
+		LIMIT 50
+	`
+
+	priceFacetQuery = filteredCTE + `
+		SELECT %s AS bucket, COUNT(*) AS count
+		FROM filtered
+		GROUP BY bucket
+		ORDER BY bucket
+	`
+
+	inStockFacetQuery = filteredCTE + `SELECT COUNT(*) FILTER (WHERE in_stock) FROM filtered`
+
+	// Counts values of a JSONB object column of product_variants, options or attributes
+	keyFacetQuery = filteredCTE + `
+		SELECT o.key AS name, o.value AS value, COUNT(DISTINCT p.product_id) AS count
+		FROM filtered f
+		JOIN products p ON p.product_id = f.product_id
+		JOIN product_variants v ON v.product_id = p.product_id
+		CROSS JOIN LATERAL jsonb_each_text(v.%s) o
+		WHERE %s AND %s
+		GROUP BY o.key, o.value
+		ORDER BY o.key, count DESC, o.value
+	`
+)
+
+const (
+	createImportJobQuery = `
+		INSERT INTO import_jobs(format, dry_run, status, file_name, object_key, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, now(), now())
+		RETURNING *
+	`
+
+	updateImportJobQuery = `
+		UPDATE import_jobs
+		SET status = $1, object_key = $2, total_rows = $3, created_rows = $4, updated_rows = $5, failed_rows = $6,
+			error = $7, started_at = $8, finished_at = $9, updated_at = now()
+		WHERE job_id = $10
+	`
+
+	getImportJobQuery = `SELECT * FROM import_jobs WHERE job_id = $1`
+
+	getTotalImportJobsQuery = `SELECT COUNT(*) FROM import_jobs`
+
+	listImportJobsQuery = `
+		SELECT * FROM import_jobs
+		ORDER BY created_at DESC, job_id
+		OFFSET $1 LIMIT $2
+	`
+
+	createImportErrorQuery = `
+		INSERT INTO import_job_errors(job_id, row_number, sku, message)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+	`
+
+	getImportErrorsQuery = `
+		SELECT * FROM import_job_errors
+		WHERE job_id = $1
+		ORDER BY row_number
+		LIMIT $2
+	`
+
+	exportProductsQuery = `
+		SELECT sku, title, slug, description, brand, price, currency, status
+		FROM products
+		ORDER BY sku
+	`
+)
@@ -0,0 +1,19 @@
+package catalog
+
+import (
+	"context"
+	"io"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Catalog UseCase
+type UseCase interface {
+	Browse(ctx context.Context, query *models.CatalogQuery, options map[string][]string, attributes map[string][]string) (*models.CatalogPage, error)
+	CreateImport(ctx context.Context, job *models.ImportJob, file models.UploadInput) (*models.ImportJob, error)
+	GetImport(ctx context.Context, jobID uuid.UUID) (*models.ImportJob, error)
+	ListImports(ctx context.Context, pq *utils.PaginationQuery) (*models.ImportJobsList, error)
+	Export(ctx context.Context, format string, w io.Writer) error
+}
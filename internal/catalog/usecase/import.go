@@ -0,0 +1,433 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultImportTimeout  = 30 * time.Minute
+	defaultImportMaxRows  = 50000
+	maxStoredImportErrors = 1000
+	importErrorsBatch     = 100
+	importProgressEvery   = 500
+	maxJSONLLineSize      = 1 << 20
+)
+
+// Store uploaded file and start import in background, job progress is polled with GetImport
+func (u *catalogUC) CreateImport(ctx context.Context, job *models.ImportJob, file models.UploadInput) (*models.ImportJob, error) {
+	// TODO: Tracing
+
+	if job.Format == "" {
+		job.Format = importFormatFromName(file.Name)
+	}
+	if job.Format != models.ImportFormatCSV && job.Format != models.ImportFormatJSONL {
+		return nil, httpErrors.NewBadRequestError(errors.New("catalogUC.CreateImport: format must be csv or jsonl"))
+	}
+
+	if user, err := middleware.GetUserFromCtx(ctx); err == nil {
+		job.CreatedBy = &user.UserID
+	}
+	job.FileName = path.Base(file.Name)
+	job.Status = models.ImportStatusPending
+
+	createdJob, err := u.catalogRepo.CreateImportJob(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	file.Name = fmt.Sprintf("%s.%s", createdJob.JobID, createdJob.Format)
+	file.BucketName = u.cfg.Catalog.ImportBucket
+	if _, err = u.minioRepo.PutObject(ctx, file); err != nil {
+		createdJob.Status = models.ImportStatusFailed
+		createdJob.Error = "upload failed"
+		if updateErr := u.catalogRepo.UpdateImportJob(ctx, createdJob); updateErr != nil {
+			u.logger.Errorf("catalogUC.CreateImport.UpdateImportJob: %v", updateErr)
+		}
+		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "catalogUC.CreateImport.PutObject"))
+	}
+
+	createdJob.ObjectKey = file.Name
+	if err = u.catalogRepo.UpdateImportJob(ctx, createdJob); err != nil {
+		return nil, err
+	}
+
+	go u.runImport(createdJob)
+
+	return createdJob, nil
+}
+
+// Get import job with the first stored row errors
+func (u *catalogUC) GetImport(ctx context.Context, jobID uuid.UUID) (*models.ImportJob, error) {
+	// TODO: Tracing
+
+	job, err := u.catalogRepo.GetImportJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Errors, err = u.catalogRepo.GetImportErrors(ctx, jobID, maxStoredImportErrors)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (u *catalogUC) ListImports(ctx context.Context, pq *utils.PaginationQuery) (*models.ImportJobsList, error) {
+	// TODO: Tracing
+
+	return u.catalogRepo.ListImportJobs(ctx, pq)
+}
+
+// Stream whole catalog in import file format, so an export can be edited and imported back
+func (u *catalogUC) Export(ctx context.Context, format string, w io.Writer) error {
+	// TODO: Tracing
+
+	switch format {
+	case models.ImportFormatJSONL:
+		enc := json.NewEncoder(w)
+		return u.catalogRepo.ExportProducts(ctx, func(row *models.ImportRow) error {
+			return errors.Wrap(enc.Encode(row), "catalogUC.Export.Encode")
+		})
+	case models.ImportFormatCSV, "":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(models.ImportColumns); err != nil {
+			return errors.Wrap(err, "catalogUC.Export.Write.header")
+		}
+
+		err := u.catalogRepo.ExportProducts(ctx, func(row *models.ImportRow) error {
+			return errors.Wrap(cw.Write(row.Record()), "catalogUC.Export.Write")
+		})
+		cw.Flush()
+		if err != nil {
+			return err
+		}
+
+		return errors.Wrap(cw.Error(), "catalogUC.Export.Flush")
+	default:
+		return httpErrors.NewBadRequestError(errors.New("catalogUC.Export: format must be csv or jsonl"))
+	}
+}
+
+// Import runs detached from the request that created it
+func (u *catalogUC) runImport(job *models.ImportJob) {
+	timeout := time.Second * u.cfg.Catalog.ImportTimeout
+	if timeout == 0 {
+		timeout = defaultImportTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	startedAt := time.Now()
+	job.Status = models.ImportStatusRunning
+	job.StartedAt = &startedAt
+	if err := u.catalogRepo.UpdateImportJob(ctx, job); err != nil {
+		u.logger.Errorf("catalogUC.runImport.UpdateImportJob: %v", err)
+		return
+	}
+
+	err := u.processImport(ctx, job)
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	job.Status = models.ImportStatusCompleted
+	if err != nil {
+		job.Status = models.ImportStatusFailed
+		job.Error = err.Error()
+		u.logger.Errorf("catalogUC.runImport: job %s failed: %v", job.JobID, err)
+	}
+
+	// Job context may be expired already, final status must still be saved
+	saveCtx, saveCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer saveCancel()
+	if err = u.catalogRepo.UpdateImportJob(saveCtx, job); err != nil {
+		u.logger.Errorf("catalogUC.runImport.UpdateImportJob: %v", err)
+	}
+
+	u.logger.Infof("catalogUC.runImport: job %s %s, rows: %d, created: %d, updated: %d, failed: %d, dry run: %v",
+		job.JobID, job.Status, job.TotalRows, job.CreatedRows, job.UpdatedRows, job.FailedRows, job.DryRun)
+}
+
+func (u *catalogUC) processImport(ctx context.Context, job *models.ImportJob) error {
+	object, err := u.minioRepo.GetObject(ctx, u.cfg.Catalog.ImportBucket, job.ObjectKey)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	reader, err := newImportReader(job.Format, object)
+	if err != nil {
+		return err
+	}
+
+	maxRows := u.cfg.Catalog.ImportMaxRows
+	if maxRows == 0 {
+		maxRows = defaultImportMaxRows
+	}
+
+	seen := make(map[string]int)
+	pending := make([]*models.ImportRowError, 0, importErrorsBatch)
+	stored := 0
+
+	flushErrors := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		if err := u.catalogRepo.AddImportErrors(ctx, pending); err != nil {
+			return err
+		}
+		stored += len(pending)
+		pending = pending[:0]
+		return nil
+	}
+
+	for {
+		row, rowNumber, err := reader.next()
+		if err == io.EOF {
+			break
+		}
+		var fileErr *importFileError
+		if errors.As(err, &fileErr) {
+			return err
+		}
+
+		job.TotalRows++
+		if job.TotalRows > maxRows {
+			return errors.Errorf("file has more than %d rows", maxRows)
+		}
+
+		if err == nil {
+			err = u.importRow(ctx, job, row, rowNumber, seen)
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			job.FailedRows++
+			if stored+len(pending) < maxStoredImportErrors {
+				sku := ""
+				if row != nil {
+					sku = row.SKU
+				}
+				pending = append(pending, &models.ImportRowError{JobID: job.JobID, RowNumber: rowNumber, SKU: sku, Message: err.Error()})
+			}
+			if len(pending) >= importErrorsBatch {
+				if err = flushErrors(); err != nil {
+					return err
+				}
+			}
+		}
+
+		if job.TotalRows%importProgressEvery == 0 {
+			if err = u.catalogRepo.UpdateImportJob(ctx, job); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flushErrors()
+}
+
+// Validate row and create or update product by SKU, dry run only counts what would happen
+func (u *catalogUC) importRow(ctx context.Context, job *models.ImportJob, row *models.ImportRow, rowNumber int, seen map[string]int) error {
+	p := row.ToProduct()
+	p.PrepareCreate()
+	if p.Slug == "" {
+		return errors.New("slug can't be derived from title")
+	}
+	if err := utils.ValidateStruct(ctx, p); err != nil {
+		return err
+	}
+
+	if firstRow, ok := seen[p.SKU]; ok {
+		return errors.Errorf("duplicate sku, first seen on row %d", firstRow)
+	}
+	seen[p.SKU] = rowNumber
+
+	existing, err := u.productRepo.GetBySKU(ctx, p.SKU)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if existing == nil {
+		if row.Price == nil {
+			return errors.New("price is required for new products")
+		}
+		if !job.DryRun {
+			if _, err = u.productRepo.Create(ctx, p); err != nil {
+				return err
+			}
+		}
+		job.CreatedRows++
+		return nil
+	}
+
+	// Empty optional columns keep current values, same as a product update request
+	update := row.ToProductUpdate()
+	update.PrepareUpdate()
+	if !job.DryRun {
+		if _, err = u.productRepo.Update(ctx, existing.ProductID, update); err != nil {
+			return err
+		}
+	}
+	job.UpdatedRows++
+
+	return nil
+}
+
+// Sequential reader of import rows, row number is the line number in the file.
+// Errors of a single row are returned as is, unreadable files as importFileError.
+type importReader interface {
+	next() (*models.ImportRow, int, error)
+}
+
+// File can't be read any further, import stops
+type importFileError struct {
+	err error
+}
+
+func (e *importFileError) Error() string {
+	return e.err.Error()
+}
+
+func newImportReader(format string, r io.Reader) (importReader, error) {
+	switch format {
+	case models.ImportFormatCSV:
+		return newCSVImportReader(r)
+	case models.ImportFormatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLLineSize)
+		return &jsonlImportReader{scanner: scanner}, nil
+	default:
+		return nil, errors.Errorf("unknown import format %q", format)
+	}
+}
+
+type csvImportReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVImportReader(r io.Reader) (*csvImportReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "csv header")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"sku", "title"} {
+		if _, ok := columns[required]; !ok {
+			return nil, errors.Errorf("csv header has no %q column", required)
+		}
+	}
+
+	return &csvImportReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvImportReader) next() (*models.ImportRow, int, error) {
+	record, err := r.reader.Read()
+	if err == io.EOF {
+		return nil, 0, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, parseErr.StartLine, err
+	}
+	if err != nil {
+		return nil, 0, &importFileError{err: errors.Wrap(err, "csv read")}
+	}
+	line, _ := r.reader.FieldPos(0)
+
+	get := func(column string) string {
+		if i, ok := r.columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	row := &models.ImportRow{
+		SKU:         get("sku"),
+		Title:       get("title"),
+		Slug:        get("slug"),
+		Description: get("description"),
+		Brand:       get("brand"),
+		Currency:    get("currency"),
+		Status:      get("status"),
+	}
+
+	if price := get("price"); price != "" {
+		amount, err := strconv.ParseInt(price, 10, 64)
+		if err != nil {
+			return row, line, errors.Errorf("price %q is not an integer amount in minor units", price)
+		}
+		row.Price = &amount
+	}
+
+	return row, line, nil
+}
+
+type jsonlImportReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *jsonlImportReader) next() (*models.ImportRow, int, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		row := &models.ImportRow{}
+		if err := json.Unmarshal([]byte(line), row); err != nil {
+			return nil, r.line, errors.Wrap(err, "invalid json")
+		}
+
+		return row, r.line, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, r.line + 1, &importFileError{err: errors.Wrapf(err, "line %d", r.line+1)}
+	}
+
+	return nil, 0, io.EOF
+}
+
+func importFormatFromName(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".csv":
+		return models.ImportFormatCSV
+	case ".jsonl", ".ndjson":
+		return models.ImportFormatJSONL
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/product"
+	"github.com/google/uuid"
+)
+
+// Product repository that only knows products by SKU, other methods are not used by the import tests
+type importProductRepo struct {
+	product.Repository
+	products map[string]*models.Product
+	created  []*models.Product
+	updates  map[uuid.UUID]*models.ProductUpdateInput
+}
+
+func (r *importProductRepo) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	p, ok := r.products[sku]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return p, nil
+}
+
+func (r *importProductRepo) Create(ctx context.Context, p *models.Product) (*models.Product, error) {
+	r.created = append(r.created, p)
+	return p, nil
+}
+
+func (r *importProductRepo) Update(ctx context.Context, productID uuid.UUID, in *models.ProductUpdateInput) (*models.Product, error) {
+	r.updates[productID] = in
+	return r.products[*in.SKU], nil
+}
+
+func TestImportRowPrice(t *testing.T) {
+	mug := &models.Product{ProductID: uuid.New(), SKU: "MUG", Title: "Mug", Price: 1500, Currency: "USD", Status: "active"}
+
+	tests := []struct {
+		name        string
+		file        string
+		wantErr     string
+		wantCreated bool
+		wantPrice   *int64
+	}{
+		{
+			name: "blank price keeps the price of an existing product",
+			file: "sku,title,price\nMUG,Mug,\n",
+		},
+		{
+			name: "missing price column keeps the price of an existing product",
+			file: "sku,title\nMUG,Mug\n",
+		},
+		{
+			name:      "zero price is set",
+			file:      "sku,title,price\nMUG,Mug,0\n",
+			wantPrice: new(int64),
+		},
+		{
+			name:    "new product needs a price",
+			file:    "sku,title,price\nPOSTER,Poster,\n",
+			wantErr: "price is required for new products",
+		},
+		{
+			name:        "new product with a price",
+			file:        "sku,title,price\nPOSTER,Poster,500\n",
+			wantCreated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &importProductRepo{
+				products: map[string]*models.Product{mug.SKU: mug},
+				updates:  map[uuid.UUID]*models.ProductUpdateInput{},
+			}
+			u := &catalogUC{productRepo: repo}
+
+			reader, err := newImportReader(models.ImportFormatCSV, strings.NewReader(tt.file))
+			if err != nil {
+				t.Fatalf("newImportReader() error = %v", err)
+			}
+			row, rowNumber, err := reader.next()
+			if err != nil {
+				t.Fatalf("next() error = %v", err)
+			}
+			if _, _, err = reader.next(); err != io.EOF {
+				t.Fatalf("next() after the only row = %v, want EOF", err)
+			}
+
+			err = u.importRow(context.Background(), &models.ImportJob{}, row, rowNumber, map[string]int{})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("importRow() error = %v, want %q", err, tt.wantErr)
+				}
+				if len(repo.created) != 0 || len(repo.updates) != 0 {
+					t.Fatalf("rejected row wrote %d products and %d updates", len(repo.created), len(repo.updates))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("importRow() error = %v", err)
+			}
+
+			if tt.wantCreated {
+				if len(repo.created) != 1 || repo.created[0].Price != *row.Price {
+					t.Fatalf("created %+v, want one product priced %d", repo.created, *row.Price)
+				}
+				return
+			}
+
+			update, ok := repo.updates[mug.ProductID]
+			if !ok {
+				t.Fatalf("existing product was not updated")
+			}
+			switch {
+			case tt.wantPrice == nil && update.Price != nil:
+				t.Fatalf("update price = %d, want the current price kept", *update.Price)
+			case tt.wantPrice != nil && (update.Price == nil || *update.Price != *tt.wantPrice):
+				t.Fatalf("update price = %v, want %d", update.Price, *tt.wantPrice)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/catalog"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/product"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultCatalogLimit = 24
+	maxKeyFilters       = 5
+	maxFilterValues     = 20
+	maxFilterValueLen   = 50
+)
+
+// Catalog UseCase
+type catalogUC struct {
+	cfg         *config.Config
+	logger      logger.Logger
+	catalogRepo catalog.Repository
+	productRepo product.Repository
+	minioRepo   catalog.MinioRepository
+}
+
+// Catalog UseCase constructor
+func NewCatalogUseCase(cfg *config.Config, logger logger.Logger, catalogRepo catalog.Repository, productRepo product.Repository, minioRepo catalog.MinioRepository) catalog.UseCase {
+	return &catalogUC{
+		cfg:         cfg,
+		logger:      logger,
+		catalogRepo: catalogRepo,
+		productRepo: productRepo,
+		minioRepo:   minioRepo,
+	}
+}
+
+// Browse catalog page, facets are only computed for the first page
+func (u *catalogUC) Browse(ctx context.Context, query *models.CatalogQuery, options map[string][]string, attributes map[string][]string) (*models.CatalogPage, error) {
+	// TODO: Tracing
+
+	filter, err := u.buildFilter(query, options, attributes)
+	if err != nil {
+		return nil, httpErrors.NewBadRequestError(err)
+	}
+
+	items, err := u.catalogRepo.Browse(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.CatalogPage{Items: items}
+	if len(items) > filter.Limit {
+		page.Items = items[:filter.Limit]
+		page.HasMore = true
+		page.NextCursor = models.NewCatalogCursor(filter.Sort, page.Items[filter.Limit-1]).Encode()
+	}
+
+	if filter.Cursor == nil {
+		facets, err := u.catalogRepo.Facets(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		page.Facets = facets
+	}
+
+	return page, nil
+}
+
+func (u *catalogUC) buildFilter(query *models.CatalogQuery, options map[string][]string, attributes map[string][]string) (*models.CatalogFilter, error) {
+	filter := &models.CatalogFilter{
+		Brands:     models.SplitQueryList(query.Brand),
+		MinPrice:   query.MinPrice,
+		MaxPrice:   query.MaxPrice,
+		InStock:    query.InStock,
+		Options:    options,
+		Attributes: attributes,
+		Sort:       query.Sort,
+		Limit:      query.Limit,
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = models.CatalogSortNewest
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultCatalogLimit
+	}
+
+	if filter.MaxPrice > 0 && filter.MinPrice > filter.MaxPrice {
+		return nil, errors.New("catalogUC.buildFilter: min_price is greater than max_price")
+	}
+
+	if len(filter.Brands) > maxFilterValues {
+		return nil, errors.Errorf("catalogUC.buildFilter: too many brands, max %d", maxFilterValues)
+	}
+
+	if err := checkKeyFilters("option", options); err != nil {
+		return nil, err
+	}
+
+	if err := checkKeyFilters("attribute", attributes); err != nil {
+		return nil, err
+	}
+
+	if query.Category != "" {
+		categoryID, err := uuid.Parse(query.Category)
+		if err != nil {
+			return nil, errors.Wrap(err, "catalogUC.buildFilter.Parse.category")
+		}
+		filter.CategoryID = &categoryID
+	}
+
+	if query.Cursor != "" {
+		cursor, err := models.DecodeCatalogCursor(query.Cursor, filter.Sort)
+		if err != nil {
+			return nil, err
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
+
+// Check option or attribute filters keyed by name
+func checkKeyFilters(kind string, filters map[string][]string) error {
+	if len(filters) > maxKeyFilters {
+		return errors.Errorf("catalogUC.checkKeyFilters: too many %s filters, max %d", kind, maxKeyFilters)
+	}
+
+	for name, values := range filters {
+		if name == "" || len(name) > maxFilterValueLen || len(values) == 0 || len(values) > maxFilterValues {
+			return errors.Errorf("catalogUC.checkKeyFilters: invalid filter for %s %q", kind, name)
+		}
+		for _, v := range values {
+			if len(v) > maxFilterValueLen {
+				return errors.Errorf("catalogUC.checkKeyFilters: invalid value for %s %q", kind, name)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package category
+
+import "github.com/labstack/echo/v4"
+
+// Category HTTP Handlers interface
+type Handlers interface {
+	Create() echo.HandlerFunc
+	Update() echo.HandlerFunc
+	Delete() echo.HandlerFunc
+	Move() echo.HandlerFunc
+	GetTree() echo.HandlerFunc
+	GetBreadcrumbs() echo.HandlerFunc
+	ListProducts() echo.HandlerFunc
+	AssignProduct() echo.HandlerFunc
+	UnassignProduct() echo.HandlerFunc
+}
@@ -0,0 +1,302 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/category"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Category handlers
+type categoryHandlers struct {
+	cfg        *config.Config
+	logger     logger.Logger
+	categoryUC category.UseCase
+}
+
+// Category handlers constructor
+func NewCategoryHandlers(cfg *config.Config, logger logger.Logger, categoryUC category.UseCase) category.Handlers {
+	return &categoryHandlers{
+		cfg:        cfg,
+		logger:     logger,
+		categoryUC: categoryUC,
+	}
+}
+
+// Create godoc
+// @Summary Create category
+// @Description create category, appended as last child of parent_id or as root, admin only
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Category
+// @Failure 500 {object} httpErrors.RestError
+// @Router /categories [post]
+func (h *categoryHandlers) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		cat := &models.Category{}
+		if err := utils.ReadRequest(c, cat); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		createdCategory, err := h.categoryUC.Create(ctx, cat)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, createdCategory)
+	}
+}
+
+// Update godoc
+// @Summary Update category
+// @Description update category name and slug, admin only
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param category_id path string true "category_id"
+// @Success 200 {object} models.Category
+// @Failure 500 {object} httpErrors.RestError
+// @Router /categories/{category_id} [put]
+func (h *categoryHandlers) Update() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		categoryID, err := uuid.Parse(c.Param("category_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		cat := &models.Category{}
+		if err = utils.ReadRequest(c, cat); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		cat.CategoryID = categoryID
+
+		updatedCategory, err := h.categoryUC.Update(ctx, cat)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, updatedCategory)
+	}
+}
+
+// Delete godoc
+// @Summary Delete category
+// @Description delete category without children, admin only
+// @Tags Categories
+// @Param category_id path string true "category_id"
+// @Success 204
+// @Failure 500 {object} httpErrors.RestError
+// @Router /categories/{category_id} [delete]
+func (h *categoryHandlers) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		categoryID, err := uuid.Parse(c.Param("category_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.categoryUC.Delete(ctx, categoryID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// Move godoc
+// @Summary Move category
+// @Description move category with its subtree under parent_id (null for root) at sibling position, admin only
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param category_id path string true "category_id"
+// @Success 200 {object} models.Category
+// @Failure 500 {object} httpErrors.RestError
+// @Router /categories/{category_id}/move [post]
+func (h *categoryHandlers) Move() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		categoryID, err := uuid.Parse(c.Param("category_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		move := &models.CategoryMove{}
+		if err = utils.ReadRequest(c, move); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		movedCategory, err := h.categoryUC.Move(ctx, categoryID, move)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, movedCategory)
+	}
+}
+
+// GetTree godoc
+// @Summary Get category tree
+// @Description get all categories as nested tree ordered by position
+// @Tags Categories
+// @Produce json
+// @Success 200 {array} models.CategoryTree
+// @Failure 500 {object} httpErrors.RestError
+// @Router /categories/tree [get]
+func (h *categoryHandlers) GetTree() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		tree, err := h.categoryUC.GetTree(ctx)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, tree)
+	}
+}
+
+// GetBreadcrumbs godoc
+// @Summary Get category breadcrumbs
+// @Description get category ancestors from root down to the category
+// @Tags Categories
+// @Produce json
+// @Param category_id path string true "category_id"
+// @Success 200 {array} models.Category
+// @Failure 404 {object} httpErrors.RestError
+// @Router /categories/{category_id}/breadcrumbs [get]
+func (h *categoryHandlers) GetBreadcrumbs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		categoryID, err := uuid.Parse(c.Param("category_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		breadcrumbs, err := h.categoryUC.GetBreadcrumbs(ctx, categoryID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, breadcrumbs)
+	}
+}
+
+// ListProducts godoc
+// @Summary Get category products
+// @Description get active products of category including its descendants
+// @Tags Categories
+// @Produce json
+// @Param category_id path string true "category_id"
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.ProductsList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /categories/{category_id}/products [get]
+func (h *categoryHandlers) ListProducts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		categoryID, err := uuid.Parse(c.Param("category_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		productsList, err := h.categoryUC.ListProducts(ctx, categoryID, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, productsList)
+	}
+}
+
+// AssignProduct godoc
+// @Summary Assign product to category
+// @Description assign product to category, admin only
+// @Tags Categories
+// @Param category_id path string true "category_id"
+// @Param product_id path string true "product_id"
+// @Success 204
+// @Failure 500 {object} httpErrors.RestError
+// @Router /categories/{category_id}/products/{product_id} [put]
+func (h *categoryHandlers) AssignProduct() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		categoryID, err := uuid.Parse(c.Param("category_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		productID, err := uuid.Parse(c.Param("product_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.categoryUC.AssignProduct(ctx, categoryID, productID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// UnassignProduct godoc
+// @Summary Remove product from category
+// @Description remove product from category, admin only
+// @Tags Categories
+// @Param category_id path string true "category_id"
+// @Param product_id path string true "product_id"
+// @Success 204
+// @Failure 500 {object} httpErrors.RestError
+// @Router /categories/{category_id}/products/{product_id} [delete]
+func (h *categoryHandlers) UnassignProduct() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		categoryID, err := uuid.Parse(c.Param("category_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		productID, err := uuid.Parse(c.Param("product_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.categoryUC.UnassignProduct(ctx, categoryID, productID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
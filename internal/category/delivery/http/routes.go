@@ -0,0 +1,22 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/category"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func MapCategoryRoutes(categoryGroup *echo.Group, h category.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	categoryGroup.GET("/tree", h.GetTree())
+	categoryGroup.GET("/:category_id/breadcrumbs", h.GetBreadcrumbs())
+	categoryGroup.GET("/:category_id/products", h.ListProducts())
+	categoryGroup.POST("", h.Create(), adminOnly...)
+	categoryGroup.PUT("/:category_id", h.Update(), adminOnly...)
+	categoryGroup.DELETE("/:category_id", h.Delete(), adminOnly...)
+	categoryGroup.POST("/:category_id/move", h.Move(), adminOnly...)
+	categoryGroup.PUT("/:category_id/products/:product_id", h.AssignProduct(), adminOnly...)
+	categoryGroup.DELETE("/:category_id/products/:product_id", h.UnassignProduct(), adminOnly...)
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/fekuna/go-store/internal/category"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Category repository
+type categoryRepo struct {
+	db *sqlx.DB
+}
+
+// Category repository constructor
+func NewCategoryRepository(db *sqlx.DB) category.Repository {
+	return &categoryRepo{db: db}
+}
+
+func (r *categoryRepo) Create(ctx context.Context, c *models.Category) (*models.Category, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.Create.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	// Same lock as Move, the parent path and the next sibling position can't change until the insert commits
+	if _, err = tx.ExecContext(ctx, lockCategoriesQuery); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.Create.LockTable")
+	}
+
+	categoryID := uuid.New()
+	path := fmt.Sprintf("/%s/", categoryID)
+	depth := 0
+
+	if c.ParentID != nil {
+		parent := &models.Category{}
+		if err = tx.GetContext(ctx, parent, getCategoryByIdQuery, *c.ParentID); err != nil {
+			return nil, errors.Wrap(err, "categoryRepo.Create.GetContext.parent")
+		}
+		path = fmt.Sprintf("%s%s/", parent.Path, categoryID)
+		depth = parent.Depth + 1
+	}
+
+	created := &models.Category{}
+	if err = tx.QueryRowxContext(
+		ctx, createCategoryQuery, categoryID, c.ParentID, &c.Name, &c.Slug, path, depth,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.Create.StructScan")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.Create.Commit")
+	}
+
+	return created, nil
+}
+
+func (r *categoryRepo) Update(ctx context.Context, c *models.Category) (*models.Category, error) {
+	// TODO: Tracing
+
+	updated := &models.Category{}
+	if err := r.db.GetContext(ctx, updated, updateCategoryQuery, &c.Name, &c.Slug, &c.CategoryID); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.Update.GetContext")
+	}
+
+	return updated, nil
+}
+
+func (r *categoryRepo) Delete(ctx context.Context, categoryID uuid.UUID) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, deleteCategoryQuery, categoryID)
+	if err != nil {
+		return errors.Wrap(err, "categoryRepo.Delete.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "categoryRepo.Delete.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "categoryRepo.Delete.rowsAffected")
+	}
+
+	return nil
+}
+
+// Move category with its whole subtree under a new parent and reorder siblings
+func (r *categoryRepo) Move(ctx context.Context, categoryID uuid.UUID, move *models.CategoryMove) (*models.Category, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.Move.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	// Sibling positions and paths are rewritten in bulk, serialize concurrent moves
+	if _, err = tx.ExecContext(ctx, lockCategoriesQuery); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.Move.LockTable")
+	}
+
+	c := &models.Category{}
+	if err = tx.GetContext(ctx, c, getCategoryForUpdateQuery, categoryID); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.Move.GetContext.category")
+	}
+
+	newPath := fmt.Sprintf("/%s/", c.CategoryID)
+	newDepth := 0
+	if move.ParentID != nil {
+		parent := &models.Category{}
+		if err = tx.GetContext(ctx, parent, getCategoryForUpdateQuery, *move.ParentID); err != nil {
+			return nil, errors.Wrap(err, "categoryRepo.Move.GetContext.parent")
+		}
+		if strings.HasPrefix(parent.Path, c.Path) {
+			return nil, httpErrors.NewBadRequestError(errors.New("categoryRepo.Move: can't move category under itself or its descendant"))
+		}
+		newPath = fmt.Sprintf("%s%s/", parent.Path, c.CategoryID)
+		newDepth = parent.Depth + 1
+	}
+
+	if _, err = tx.ExecContext(ctx, closeSiblingsGapQuery, c.ParentID, c.Position); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.Move.ExecContext.closeSiblingsGap")
+	}
+
+	var siblings int
+	if err = tx.GetContext(ctx, &siblings, countSiblingsQuery, move.ParentID, c.CategoryID); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.Move.GetContext.countSiblings")
+	}
+
+	position := move.Position
+	if position > siblings {
+		position = siblings
+	}
+
+	if _, err = tx.ExecContext(ctx, openSiblingsGapQuery, move.ParentID, position, c.CategoryID); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.Move.ExecContext.openSiblingsGap")
+	}
+
+	if _, err = tx.ExecContext(ctx, setCategoryParentQuery, move.ParentID, position, c.CategoryID); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.Move.ExecContext.setCategoryParent")
+	}
+
+	if newPath != c.Path {
+		if _, err = tx.ExecContext(ctx, moveSubtreeQuery, c.Path, newPath, newDepth-c.Depth); err != nil {
+			return nil, errors.Wrap(err, "categoryRepo.Move.ExecContext.moveSubtree")
+		}
+	}
+
+	moved := &models.Category{}
+	if err = tx.GetContext(ctx, moved, getCategoryByIdQuery, c.CategoryID); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.Move.GetContext.moved")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.Move.Commit")
+	}
+
+	return moved, nil
+}
+
+func (r *categoryRepo) GetByID(ctx context.Context, categoryID uuid.UUID) (*models.Category, error) {
+	c := &models.Category{}
+	if err := r.db.GetContext(ctx, c, getCategoryByIdQuery, categoryID); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.GetByID.GetContext")
+	}
+
+	return c, nil
+}
+
+func (r *categoryRepo) GetAll(ctx context.Context) ([]*models.Category, error) {
+	categories := make([]*models.Category, 0)
+	if err := r.db.SelectContext(ctx, &categories, getAllCategoriesQuery); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.GetAll.SelectContext")
+	}
+
+	return categories, nil
+}
+
+// Get category ancestors from root down to the category itself
+func (r *categoryRepo) GetAncestors(ctx context.Context, c *models.Category) ([]*models.Category, error) {
+	categories := make([]*models.Category, 0, c.Depth+1)
+	if err := r.db.SelectContext(ctx, &categories, getAncestorsQuery, c.Path); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.GetAncestors.SelectContext")
+	}
+
+	return categories, nil
+}
+
+// List active products assigned to the category or any of its descendants
+func (r *categoryRepo) ListProducts(ctx context.Context, c *models.Category, pq *utils.PaginationQuery) (*models.ProductsList, error) {
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalCategoryProductsQuery, c.Path); err != nil {
+		return nil, errors.Wrap(err, "categoryRepo.ListProducts.GetContext.totalCount")
+	}
+
+	products := make([]*models.Product, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &products, listCategoryProductsQuery, c.Path, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "categoryRepo.ListProducts.SelectContext")
+		}
+	}
+
+	return &models.ProductsList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Products:   products,
+	}, nil
+}
+
+func (r *categoryRepo) AssignProduct(ctx context.Context, categoryID uuid.UUID, productID uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx, assignProductQuery, productID, categoryID); err != nil {
+		return errors.Wrap(err, "categoryRepo.AssignProduct.ExecContext")
+	}
+
+	return nil
+}
+
+func (r *categoryRepo) UnassignProduct(ctx context.Context, categoryID uuid.UUID, productID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, unassignProductQuery, productID, categoryID)
+	if err != nil {
+		return errors.Wrap(err, "categoryRepo.UnassignProduct.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "categoryRepo.UnassignProduct.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "categoryRepo.UnassignProduct.rowsAffected")
+	}
+
+	return nil
+}
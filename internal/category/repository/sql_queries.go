@@ -0,0 +1,86 @@
+package repository
+
+const (
+	createCategoryQuery = `
+		INSERT INTO categories(category_id, parent_id, name, slug, path, depth, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6,
+			COALESCE((SELECT MAX(position) + 1 FROM categories WHERE parent_id IS NOT DISTINCT FROM $2), 0),
+			now(), now())
+		RETURNING *
+	`
+
+	updateCategoryQuery = `
+		UPDATE categories
+		SET name = COALESCE(NULLIF($1, ''), name),
+			slug = COALESCE(NULLIF($2, ''), slug),
+			updated_at = now()
+		WHERE category_id = $3
+		RETURNING *
+	`
+
+	deleteCategoryQuery = `DELETE FROM categories WHERE category_id = $1`
+
+	getCategoryByIdQuery = `SELECT * FROM categories WHERE category_id = $1`
+
+	getCategoryForUpdateQuery = `SELECT * FROM categories WHERE category_id = $1 FOR UPDATE`
+
+	getAllCategoriesQuery = `SELECT * FROM categories ORDER BY depth, position, name`
+
+	getAncestorsQuery = `SELECT * FROM categories WHERE $1 LIKE path || '%' ORDER BY depth`
+
+	lockCategoriesQuery = `LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE`
+
+	closeSiblingsGapQuery = `
+		UPDATE categories SET position = position - 1
+		WHERE parent_id IS NOT DISTINCT FROM $1 AND position > $2
+	`
+
+	countSiblingsQuery = `
+		SELECT COUNT(*) FROM categories
+		WHERE parent_id IS NOT DISTINCT FROM $1 AND category_id <> $2
+	`
+
+	openSiblingsGapQuery = `
+		UPDATE categories SET position = position + 1
+		WHERE parent_id IS NOT DISTINCT FROM $1 AND position >= $2 AND category_id <> $3
+	`
+
+	setCategoryParentQuery = `
+		UPDATE categories SET parent_id = $1, position = $2, updated_at = now()
+		WHERE category_id = $3
+	`
+
+	moveSubtreeQuery = `
+		UPDATE categories
+		SET path = $2 || substring(path FROM char_length($1) + 1),
+			depth = depth + $3
+		WHERE path LIKE $1 || '%'
+	`
+
+	getTotalCategoryProductsQuery = `
+		SELECT COUNT(p.product_id) FROM products p
+		WHERE p.status = 'active' AND EXISTS (
+			SELECT 1 FROM product_categories pc
+			JOIN categories c ON c.category_id = pc.category_id
+			WHERE pc.product_id = p.product_id AND c.path LIKE $1 || '%'
+		)
+	`
+
+	listCategoryProductsQuery = `
+		SELECT p.* FROM products p
+		WHERE p.status = 'active' AND EXISTS (
+			SELECT 1 FROM product_categories pc
+			JOIN categories c ON c.category_id = pc.category_id
+			WHERE pc.product_id = p.product_id AND c.path LIKE $1 || '%'
+		)
+		ORDER BY p.created_at DESC, p.product_id
+		OFFSET $2 LIMIT $3
+	`
+
+	assignProductQuery = `
+		INSERT INTO product_categories(product_id, category_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	unassignProductQuery = `DELETE FROM product_categories WHERE product_id = $1 AND category_id = $2`
+)
@@ -0,0 +1,22 @@
+package category
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Category UseCase
+type UseCase interface {
+	Create(ctx context.Context, category *models.Category) (*models.Category, error)
+	Update(ctx context.Context, category *models.Category) (*models.Category, error)
+	Delete(ctx context.Context, categoryID uuid.UUID) error
+	Move(ctx context.Context, categoryID uuid.UUID, move *models.CategoryMove) (*models.Category, error)
+	GetTree(ctx context.Context) ([]*models.CategoryTree, error)
+	GetBreadcrumbs(ctx context.Context, categoryID uuid.UUID) ([]*models.Category, error)
+	ListProducts(ctx context.Context, categoryID uuid.UUID, pq *utils.PaginationQuery) (*models.ProductsList, error)
+	AssignProduct(ctx context.Context, categoryID uuid.UUID, productID uuid.UUID) error
+	UnassignProduct(ctx context.Context, categoryID uuid.UUID, productID uuid.UUID) error
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/category"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Category UseCase
+type categoryUC struct {
+	cfg          *config.Config
+	logger       logger.Logger
+	categoryRepo category.Repository
+}
+
+// Category UseCase constructor
+func NewCategoryUseCase(cfg *config.Config, logger logger.Logger, categoryRepo category.Repository) category.UseCase {
+	return &categoryUC{
+		cfg:          cfg,
+		logger:       logger,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (u *categoryUC) Create(ctx context.Context, c *models.Category) (*models.Category, error) {
+	// TODO: Tracing
+
+	c.PrepareCreate()
+	if c.Slug == "" {
+		return nil, httpErrors.NewBadRequestError(errors.New("categoryUC.Create: slug can't be derived from name"))
+	}
+
+	return u.categoryRepo.Create(ctx, c)
+}
+
+func (u *categoryUC) Update(ctx context.Context, c *models.Category) (*models.Category, error) {
+	// TODO: Tracing
+
+	if c.Slug != "" {
+		c.Slug = models.Slugify(c.Slug)
+	}
+
+	return u.categoryRepo.Update(ctx, c)
+}
+
+func (u *categoryUC) Delete(ctx context.Context, categoryID uuid.UUID) error {
+	// TODO: Tracing
+
+	return u.categoryRepo.Delete(ctx, categoryID)
+}
+
+func (u *categoryUC) Move(ctx context.Context, categoryID uuid.UUID, move *models.CategoryMove) (*models.Category, error) {
+	// TODO: Tracing
+
+	if move.ParentID != nil && *move.ParentID == categoryID {
+		return nil, httpErrors.NewBadRequestError(errors.New("categoryUC.Move: category can't be its own parent"))
+	}
+
+	return u.categoryRepo.Move(ctx, categoryID, move)
+}
+
+func (u *categoryUC) GetTree(ctx context.Context) ([]*models.CategoryTree, error) {
+	// TODO: Tracing
+
+	categories, err := u.categoryRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.BuildCategoryTree(categories), nil
+}
+
+func (u *categoryUC) GetBreadcrumbs(ctx context.Context, categoryID uuid.UUID) ([]*models.Category, error) {
+	// TODO: Tracing
+
+	c, err := u.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.categoryRepo.GetAncestors(ctx, c)
+}
+
+func (u *categoryUC) ListProducts(ctx context.Context, categoryID uuid.UUID, pq *utils.PaginationQuery) (*models.ProductsList, error) {
+	// TODO: Tracing
+
+	c, err := u.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.categoryRepo.ListProducts(ctx, c, pq)
+}
+
+func (u *categoryUC) AssignProduct(ctx context.Context, categoryID uuid.UUID, productID uuid.UUID) error {
+	// TODO: Tracing
+
+	return u.categoryRepo.AssignProduct(ctx, categoryID, productID)
+}
+
+func (u *categoryUC) UnassignProduct(ctx context.Context, categoryID uuid.UUID, productID uuid.UUID) error {
+	// TODO: Tracing
+
+	return u.categoryRepo.UnassignProduct(ctx, categoryID, productID)
+}
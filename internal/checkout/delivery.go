@@ -0,0 +1,8 @@
+package checkout
+
+import "github.com/labstack/echo/v4"
+
+// Checkout HTTP Handlers interface
+type Handlers interface {
+	Checkout() echo.HandlerFunc
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/checkout"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// Checkout handlers
+type checkoutHandlers struct {
+	cfg        *config.Config
+	logger     logger.Logger
+	checkoutUC checkout.UseCase
+}
+
+// Checkout handlers constructor
+func NewCheckoutHandlers(cfg *config.Config, logger logger.Logger, checkoutUC checkout.UseCase) checkout.Handlers {
+	return &checkoutHandlers{
+		cfg:        cfg,
+		logger:     logger,
+		checkoutUC: checkoutUC,
+	}
+}
+
+// Checkout godoc
+// @Summary Checkout
+// @Description place pending order from own cart with optional promo codes, stock is reserved until the payment window ends
+// @Tags Checkout
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Order
+// @Failure 409 {object} httpErrors.RestError
+// @Router /checkout [post]
+func (h *checkoutHandlers) Checkout() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		input := &models.CheckoutInput{}
+		if err := utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		o, err := h.checkoutUC.Checkout(ctx, input)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, o)
+	}
+}
@@ -0,0 +1,11 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/checkout"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func MapCheckoutRoutes(checkoutGroup *echo.Group, h checkout.Handlers, mw *middleware.MiddlewareManager) {
+	checkoutGroup.POST("", h.Checkout(), mw.AuthJWTMiddleware)
+}
@@ -0,0 +1,19 @@
+package checkout
+
+import (
+	"context"
+	"time"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Checkout repository. Orders are priced from lines read before the checkout transaction, placing them fails
+// with CartChanged when the locked lines no longer match.
+type Repository interface {
+	GetCartItems(ctx context.Context, cartID uuid.UUID) ([]*models.CartItem, error)
+	GetSubscriptionItems(ctx context.Context, subscriptionID uuid.UUID) ([]*models.CartItem, error)
+	GetSubscriptionRunOrder(ctx context.Context, subscriptionID uuid.UUID, runAt time.Time) (*models.Order, error)
+	PlaceOrder(ctx context.Context, cartID uuid.UUID, o *models.Order, expiresAt time.Time) (*models.Order, error)
+	PlaceSubscriptionOrder(ctx context.Context, subscriptionID uuid.UUID, runAt time.Time, o *models.Order, expiresAt time.Time) (*models.Order, error)
+}
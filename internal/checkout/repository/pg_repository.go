@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/fekuna/go-store/internal/checkout"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Checkout repository
+type checkoutRepo struct {
+	db *sqlx.DB
+}
+
+// Checkout repository constructor
+func NewCheckoutRepository(db *sqlx.DB) checkout.Repository {
+	return &checkoutRepo{db: db}
+}
+
+// Checkout lines of the cart, ordered by SKU
+func (r *checkoutRepo) GetCartItems(ctx context.Context, cartID uuid.UUID) ([]*models.CartItem, error) {
+	// TODO: Tracing
+
+	items := make([]*models.CartItem, 0)
+	if err := r.db.SelectContext(ctx, &items, getCheckoutItemsQuery, cartID); err != nil {
+		return nil, errors.Wrap(err, "checkoutRepo.GetCartItems.SelectContext")
+	}
+
+	return items, nil
+}
+
+// Lines of a subscription run, ordered by SKU like a cart checkout
+func (r *checkoutRepo) GetSubscriptionItems(ctx context.Context, subscriptionID uuid.UUID) ([]*models.CartItem, error) {
+	// TODO: Tracing
+
+	items := make([]*models.CartItem, 0)
+	if err := r.db.SelectContext(ctx, &items, getSubscriptionItemsQuery, subscriptionID); err != nil {
+		return nil, errors.Wrap(err, "checkoutRepo.GetSubscriptionItems.SelectContext")
+	}
+
+	return items, nil
+}
+
+// Order placed for the subscription run at runAt that was not cancelled
+func (r *checkoutRepo) GetSubscriptionRunOrder(ctx context.Context, subscriptionID uuid.UUID, runAt time.Time) (*models.Order, error) {
+	// TODO: Tracing
+
+	o := &models.Order{}
+	if err := r.db.GetContext(ctx, o, getSubscriptionRunOrderQuery, subscriptionID, runAt); err != nil {
+		return nil, errors.Wrap(err, "checkoutRepo.GetSubscriptionRunOrder.GetContext")
+	}
+
+	return o, nil
+}
+
+// Turn the cart into the priced pending order o in one transaction. The cart row lock stops a second checkout
+// of the same cart, lines changed since o was priced fail with CartChanged. Stock rows are locked in SKU order
+// and reserved until expiresAt. Promotion usage, gift card and store credit redemptions are recorded in the
+// same transaction so limits and balances hold under concurrent checkouts.
+func (r *checkoutRepo) PlaceOrder(ctx context.Context, cartID uuid.UUID, o *models.Order, expiresAt time.Time) (*models.Order, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "checkoutRepo.PlaceOrder.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	c := &models.Cart{}
+	if err = tx.GetContext(ctx, c, lockCartQuery, cartID); err != nil {
+		return nil, errors.Wrap(err, "checkoutRepo.PlaceOrder.GetContext.cart")
+	}
+
+	items := make([]*models.CartItem, 0)
+	if err = tx.SelectContext(ctx, &items, getCheckoutItemsQuery, cartID); err != nil {
+		return nil, errors.Wrap(err, "checkoutRepo.PlaceOrder.SelectContext.items")
+	}
+
+	created, err := placeOrder(ctx, tx, items, o, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = tx.ExecContext(ctx, convertCartQuery, cartID); err != nil {
+		return nil, errors.Wrap(err, "checkoutRepo.PlaceOrder.ExecContext.cart")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "checkoutRepo.PlaceOrder.Commit")
+	}
+
+	return created, nil
+}
+
+// Place the priced order of a subscription run, same as a cart checkout. The subscription row lock stops
+// a second run of the same subscription, a subscription that is no longer active or past due fails.
+// A run retried after a crash gets the order its first attempt placed for runAt, unless it was cancelled.
+func (r *checkoutRepo) PlaceSubscriptionOrder(ctx context.Context, subscriptionID uuid.UUID, runAt time.Time, o *models.Order, expiresAt time.Time) (*models.Order, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "checkoutRepo.PlaceSubscriptionOrder.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	var lockedID uuid.UUID
+	if err = tx.GetContext(ctx, &lockedID, lockSubscriptionQuery, subscriptionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrap(httpErrors.InvalidStateTransition, "checkoutRepo.PlaceSubscriptionOrder: subscription is not active")
+		}
+		return nil, errors.Wrap(err, "checkoutRepo.PlaceSubscriptionOrder.GetContext.subscription")
+	}
+
+	existing := &models.Order{}
+	err = tx.GetContext(ctx, existing, getSubscriptionRunOrderQuery, subscriptionID, runAt)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "checkoutRepo.PlaceSubscriptionOrder.GetContext.runOrder")
+	}
+
+	items := make([]*models.CartItem, 0)
+	if err = tx.SelectContext(ctx, &items, getSubscriptionItemsQuery, subscriptionID); err != nil {
+		return nil, errors.Wrap(err, "checkoutRepo.PlaceSubscriptionOrder.SelectContext.items")
+	}
+
+	created, err := placeOrder(ctx, tx, items, o, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "checkoutRepo.PlaceSubscriptionOrder.Commit")
+	}
+
+	return created, nil
+}
+
+// Check the priced order against the locked lines, reserve its stock and save it with its discounts and redemptions
+func placeOrder(ctx context.Context, tx *sqlx.Tx, items []*models.CartItem, o *models.Order, expiresAt time.Time) (*models.Order, error) {
+	err := matchLines(items, o)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		var available int
+		if err = tx.GetContext(ctx, &available, lockStockQuery, item.SKU); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrap(err, "checkoutRepo.placeOrder.GetContext.stock")
+		}
+		if available < item.Quantity {
+			return nil, errors.Wrapf(httpErrors.InsufficientStock, "checkoutRepo.placeOrder: sku %s", item.SKU)
+		}
+
+		if _, err = tx.ExecContext(ctx, reserveStockQuery, item.SKU, item.Quantity); err != nil {
+			return nil, errors.Wrap(err, "checkoutRepo.placeOrder.ExecContext.reserve")
+		}
+	}
+
+	created := &models.Order{}
+	if err = tx.QueryRowxContext(
+		ctx,
+		createOrderQuery,
+		o.UserID,
+		&o.Email,
+		&o.Status,
+		&o.Currency,
+		&o.ItemCount,
+		&o.Subtotal,
+		&o.DiscountTotal,
+		&o.TaxTotal,
+		&o.PricesIncludeTax,
+		o.ShippingMethodID,
+		&o.ShippingMethod,
+		&o.ShippingTotal,
+		o.ShippingAddress,
+		&o.Total,
+		&o.GiftCardTotal,
+		&o.StoreCreditTotal,
+		o.SubscriptionID,
+		o.SubscriptionRunAt,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "checkoutRepo.placeOrder.StructScan.order")
+	}
+
+	created.Items = make([]*models.OrderItem, 0, len(o.Items))
+	for _, item := range o.Items {
+		createdItem := &models.OrderItem{}
+		if err = tx.QueryRowxContext(
+			ctx,
+			createOrderItemQuery,
+			created.OrderID,
+			item.VariantID,
+			item.ProductID,
+			&item.SKU,
+			&item.Title,
+			&item.VariantTitle,
+			&item.UnitPrice,
+			&item.Quantity,
+			&item.LineTotal,
+			&item.TaxClass,
+			&item.TaxRatePPM,
+			&item.TaxAmount,
+		).StructScan(createdItem); err != nil {
+			return nil, errors.Wrap(err, "checkoutRepo.placeOrder.StructScan.item")
+		}
+		created.Items = append(created.Items, createdItem)
+
+		if _, err = tx.ExecContext(ctx, createReservationQuery, created.OrderID, &item.SKU, &item.Quantity, expiresAt); err != nil {
+			return nil, errors.Wrap(err, "checkoutRepo.placeOrder.ExecContext.reservation")
+		}
+	}
+
+	created.Discounts = make([]*models.OrderDiscount, 0, len(o.Discounts))
+	for _, discount := range o.Discounts {
+		if err = usePromotion(ctx, tx, discount); err != nil {
+			return nil, err
+		}
+
+		createdDiscount := &models.OrderDiscount{}
+		if err = tx.QueryRowxContext(
+			ctx,
+			createOrderDiscountQuery,
+			created.OrderID,
+			discount.PromotionID,
+			discount.UserID,
+			discount.Code,
+			&discount.Description,
+			&discount.Amount,
+			&discount.FreeShipping,
+		).StructScan(createdDiscount); err != nil {
+			return nil, errors.Wrap(err, "checkoutRepo.placeOrder.StructScan.discount")
+		}
+		created.Discounts = append(created.Discounts, createdDiscount)
+	}
+
+	if err = redeemGiftCards(ctx, tx, created.OrderID, o.UserID, o.GiftCards); err != nil {
+		return nil, err
+	}
+	created.GiftCards = o.GiftCards
+
+	if o.StoreCreditTotal > 0 {
+		if err = redeemStoreCredit(ctx, tx, created); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, createOrderEventQuery, created.OrderID, &created.Status, "order placed", o.UserID); err != nil {
+		return nil, errors.Wrap(err, "checkoutRepo.placeOrder.ExecContext.event")
+	}
+
+	return created, nil
+}
+
+// The order has to be priced from the same lines, prices and tax classes as the locked ones
+func matchLines(items []*models.CartItem, o *models.Order) error {
+	if len(items) != len(o.Items) {
+		return errors.Wrapf(httpErrors.CartChanged, "checkoutRepo.matchLines: %d lines, priced %d", len(items), len(o.Items))
+	}
+
+	for i, item := range items {
+		priced := o.Items[i]
+		if !item.Available || item.Currency != o.Currency || item.SKU != priced.SKU || item.Quantity != priced.Quantity ||
+			item.UnitPrice != priced.UnitPrice || models.NormalizeTaxClass(item.TaxClass) != priced.TaxClass {
+			return errors.Wrapf(httpErrors.CartChanged, "checkoutRepo.matchLines: sku %s", item.SKU)
+		}
+	}
+
+	return nil
+}
+
+// Count the promotion as used, failing when it ran out or the customer reached the limit since it was evaluated
+func usePromotion(ctx context.Context, tx *sqlx.Tx, discount *models.OrderDiscount) error {
+	if discount.PromotionID == nil {
+		return nil
+	}
+
+	var perCustomerLimit *int
+	if err := tx.GetContext(ctx, &perCustomerLimit, usePromotionQuery, discount.PromotionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.Wrapf(httpErrors.PromotionUnavailable, "checkoutRepo.usePromotion: %s", discount.Description)
+		}
+		return errors.Wrap(err, "checkoutRepo.usePromotion.GetContext")
+	}
+
+	if perCustomerLimit == nil || discount.UserID == nil {
+		return nil
+	}
+
+	var used int
+	if err := tx.GetContext(ctx, &used, countCustomerUsageQuery, discount.PromotionID, discount.UserID); err != nil {
+		return errors.Wrap(err, "checkoutRepo.usePromotion.GetContext.usage")
+	}
+	if used >= *perCustomerLimit {
+		return errors.Wrapf(httpErrors.PromotionUnavailable, "checkoutRepo.usePromotion: %s", discount.Description)
+	}
+
+	return nil
+}
+
+// Take the redeemed amounts off the gift cards, locked in id order so concurrent checkouts don't deadlock
+func redeemGiftCards(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID, userID *uuid.UUID, cards []*models.OrderGiftCard) error {
+	sorted := make([]*models.OrderGiftCard, len(cards))
+	copy(sorted, cards)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GiftCardID.String() < sorted[j].GiftCardID.String()
+	})
+
+	for _, card := range sorted {
+		var balance int64
+		if err := tx.GetContext(ctx, &balance, redeemGiftCardQuery, card.GiftCardID, card.Amount); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.Wrapf(httpErrors.GiftCardUnavailable, "checkoutRepo.redeemGiftCards: %s", card.Code)
+			}
+			return errors.Wrap(err, "checkoutRepo.redeemGiftCards.GetContext")
+		}
+
+		if _, err := tx.ExecContext(ctx, createGiftCardTransactionQuery, card.GiftCardID, -card.Amount, balance, orderID, userID); err != nil {
+			return errors.Wrap(err, "checkoutRepo.redeemGiftCards.ExecContext")
+		}
+	}
+
+	return nil
+}
+
+// Debit store credit of the customer, failing when the balance dropped since checkout read it
+func redeemStoreCredit(ctx context.Context, tx *sqlx.Tx, o *models.Order) error {
+	var lockedID uuid.UUID
+	if err := tx.GetContext(ctx, &lockedID, lockUserQuery, o.UserID); err != nil {
+		return errors.Wrap(err, "checkoutRepo.redeemStoreCredit.GetContext.lockUser")
+	}
+
+	var balance int64
+	if err := tx.GetContext(ctx, &balance, getStoreCreditBalanceQuery, o.UserID, &o.Currency); err != nil {
+		return errors.Wrap(err, "checkoutRepo.redeemStoreCredit.GetContext.balance")
+	}
+	if balance < o.StoreCreditTotal {
+		return errors.Wrapf(httpErrors.InsufficientCredit, "checkoutRepo.redeemStoreCredit: balance is %d", balance)
+	}
+
+	if _, err := tx.ExecContext(ctx, createStoreCreditRedemptionQuery, o.UserID, &o.Currency, -o.StoreCreditTotal, o.OrderID); err != nil {
+		return errors.Wrap(err, "checkoutRepo.redeemStoreCredit.ExecContext")
+	}
+
+	return nil
+}
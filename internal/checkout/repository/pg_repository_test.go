@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fekuna/go-store/internal/checkout"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	_ "github.com/jackc/pgx/stdlib"
+)
+
+// Connection string of a migrated database the checkout tests may write to
+const testDSNEnv = "POSTGRES_TEST_DSN"
+
+func TestPlaceOrderDoesNotOversell(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	const (
+		stock  = 5
+		buyers = 25
+	)
+
+	sku := seedVariant(t, db, stock)
+	cartIDs := make([]uuid.UUID, buyers)
+	for i := range cartIDs {
+		cartIDs[i] = seedCart(t, db, sku, 1)
+	}
+
+	repo := NewCheckoutRepository(db)
+	expiresAt := time.Now().Add(time.Hour)
+
+	orders := make([]*models.Order, buyers)
+	for i, cartID := range cartIDs {
+		orders[i] = testOrder(t, repo, cartID)
+	}
+
+	errs := make([]error, buyers)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range cartIDs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = repo.PlaceOrder(ctx, cartIDs[i], orders[i], expiresAt)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	placed := 0
+	for i, err := range errs {
+		if err == nil {
+			placed++
+			continue
+		}
+		if !errors.Is(err, httpErrors.InsufficientStock) {
+			t.Errorf("checkout %d: unexpected error: %v", i, err)
+		}
+	}
+	if placed != stock {
+		t.Errorf("placed %d orders, want %d", placed, stock)
+	}
+
+	var level struct {
+		OnHand   int `db:"on_hand"`
+		Reserved int `db:"reserved"`
+	}
+	if err := db.GetContext(ctx, &level, `SELECT on_hand, reserved FROM stock_levels WHERE sku = $1`, sku); err != nil {
+		t.Fatal(err)
+	}
+	if level.Reserved > level.OnHand {
+		t.Errorf("reserved %d is more than on hand %d", level.Reserved, level.OnHand)
+	}
+	if level.Reserved != placed {
+		t.Errorf("reserved %d, want %d", level.Reserved, placed)
+	}
+
+	var held int
+	if err := db.GetContext(ctx, &held,
+		`SELECT COALESCE(SUM(quantity), 0) FROM stock_reservations WHERE sku = $1 AND status = 'active'`, sku); err != nil {
+		t.Fatal(err)
+	}
+	if held != level.Reserved {
+		t.Errorf("reservations hold %d, stock level has %d reserved", held, level.Reserved)
+	}
+}
+
+func TestPlaceOrderConvertsCartOnce(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	sku := seedVariant(t, db, 10)
+	cartID := seedCart(t, db, sku, 2)
+	repo := NewCheckoutRepository(db)
+	o := testOrder(t, repo, cartID)
+
+	errs := make([]error, 5)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = repo.PlaceOrder(ctx, cartID, o, time.Now().Add(time.Hour))
+		}(i)
+	}
+	wg.Wait()
+
+	placed := 0
+	for _, err := range errs {
+		if err == nil {
+			placed++
+		}
+	}
+	if placed != 1 {
+		t.Errorf("cart was checked out %d times, want 1", placed)
+	}
+}
+
+func TestPlaceOrderRejectsChangedCart(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	sku := seedVariant(t, db, 10)
+	cartID := seedCart(t, db, sku, 1)
+	repo := NewCheckoutRepository(db)
+	o := testOrder(t, repo, cartID)
+
+	if _, err := db.ExecContext(ctx, `UPDATE cart_items SET quantity = 3 WHERE cart_id = $1`, cartID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.PlaceOrder(ctx, cartID, o, time.Now().Add(time.Hour)); !errors.Is(err, httpErrors.CartChanged) {
+		t.Fatalf("PlaceOrder() error = %v, want %v", err, httpErrors.CartChanged)
+	}
+
+	var reserved int
+	if err := db.GetContext(ctx, &reserved, `SELECT reserved FROM stock_levels WHERE sku = $1`, sku); err != nil {
+		t.Fatal(err)
+	}
+	if reserved != 0 {
+		t.Errorf("reserved %d for a rejected checkout", reserved)
+	}
+}
+
+func testDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	db, err := sqlx.Connect("pgx", dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// Active product with one variant and its stock, removed with its orders and carts after the test
+func seedVariant(t *testing.T, db *sqlx.DB, onHand int) string {
+	t.Helper()
+	ctx := context.Background()
+
+	suffix := uuid.New().String()[:8]
+	sku := "TEST-" + suffix
+
+	var productID uuid.UUID
+	if err := db.GetContext(ctx, &productID, `
+		INSERT INTO products(sku, slug, title, price, currency, status)
+		VALUES ($1, $2, $3, 1000, 'USD', 'active')
+		RETURNING product_id
+	`, sku, "test-"+suffix, "Test "+suffix); err != nil {
+		t.Fatalf("seed product: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO product_variants(product_id, sku, title, is_default) VALUES ($1, $2, 'Default', TRUE)
+	`, productID, sku); err != nil {
+		t.Fatalf("seed variant: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO stock_levels(sku, on_hand) VALUES ($1, $2)`, sku, onHand); err != nil {
+		t.Fatalf("seed stock: %v", err)
+	}
+
+	t.Cleanup(func() {
+		ctx := context.Background()
+		db.ExecContext(ctx, `DELETE FROM orders WHERE order_id IN (SELECT order_id FROM order_items WHERE sku = $1)`, sku)
+		db.ExecContext(ctx, `DELETE FROM carts WHERE cart_id IN (SELECT cart_id FROM cart_items WHERE sku = $1)`, sku)
+		db.ExecContext(ctx, `DELETE FROM products WHERE product_id = $1`, productID)
+	})
+
+	return sku
+}
+
+func seedCart(t *testing.T, db *sqlx.DB, sku string, quantity int) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+
+	var cartID uuid.UUID
+	if err := db.GetContext(ctx, &cartID, `INSERT INTO carts(status) VALUES ('active') RETURNING cart_id`); err != nil {
+		t.Fatalf("seed cart: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO cart_items(cart_id, sku, quantity) VALUES ($1, $2, $3)`, cartID, sku, quantity); err != nil {
+		t.Fatalf("seed cart item: %v", err)
+	}
+
+	return cartID
+}
+
+// Plain order of the cart lines, pricing is covered by the usecase tests
+func testOrder(t *testing.T, repo checkout.Repository, cartID uuid.UUID) *models.Order {
+	t.Helper()
+
+	items, err := repo.GetCartItems(context.Background(), cartID)
+	if err != nil {
+		t.Fatalf("cart items: %v", err)
+	}
+
+	o := &models.Order{
+		Email:    "checkout-test@example.com",
+		Status:   models.OrderStatusPending,
+		Currency: "USD",
+		Items:    make([]*models.OrderItem, 0, len(items)),
+	}
+	for _, item := range items {
+		variantID, productID := item.VariantID, item.ProductID
+		lineTotal := item.UnitPrice * int64(item.Quantity)
+		o.ItemCount += item.Quantity
+		o.Subtotal += lineTotal
+		o.Items = append(o.Items, &models.OrderItem{
+			VariantID: &variantID,
+			ProductID: &productID,
+			SKU:       item.SKU,
+			Title:     item.Title,
+			UnitPrice: item.UnitPrice,
+			Quantity:  item.Quantity,
+			LineTotal: lineTotal,
+			TaxClass:  models.NormalizeTaxClass(item.TaxClass),
+		})
+	}
+	o.Total = o.Subtotal
+
+	return o
+}
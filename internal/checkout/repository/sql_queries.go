@@ -0,0 +1,125 @@
+package repository
+
+const (
+	lockCartQuery = `SELECT * FROM carts WHERE cart_id = $1 AND status = 'active' FOR UPDATE`
+
+	// Ordered by SKU so concurrent checkouts lock stock rows in the same order
+	getCheckoutItemsQuery = `
+		SELECT i.cart_id, i.sku, i.quantity, i.added_at,
+			v.variant_id, p.product_id, p.title, v.title AS variant_title,
+			COALESCE(v.price_override, p.price) AS unit_price, p.currency, v.weight_grams, p.tax_class,
+			p.status = 'active' AND v.status = 'active' AS available,
+			FALSE AS in_stock
+		FROM cart_items i
+		JOIN product_variants v ON v.sku = i.sku
+		JOIN products p ON p.product_id = v.product_id
+		WHERE i.cart_id = $1
+		ORDER BY i.sku
+	`
+
+	lockSubscriptionQuery = `
+		SELECT subscription_id FROM subscriptions
+		WHERE subscription_id = $1 AND status IN ('active', 'past_due')
+		FOR UPDATE
+	`
+
+	// Order of an earlier attempt of the same subscription run
+	getSubscriptionRunOrderQuery = `
+		SELECT * FROM orders
+		WHERE subscription_id = $1 AND subscription_run_at = $2 AND status <> 'cancelled'
+	`
+
+	// Same lines as a cart checkout, ordered by SKU
+	getSubscriptionItemsQuery = `
+		SELECT i.subscription_id AS cart_id, i.sku, i.quantity, now() AS added_at,
+			v.variant_id, p.product_id, p.title, v.title AS variant_title,
+			COALESCE(v.price_override, p.price) AS unit_price, p.currency, v.weight_grams, p.tax_class,
+			p.status = 'active' AND v.status = 'active' AS available,
+			FALSE AS in_stock
+		FROM subscription_items i
+		JOIN product_variants v ON v.sku = i.sku
+		JOIN products p ON p.product_id = v.product_id
+		WHERE i.subscription_id = $1
+		ORDER BY i.sku
+	`
+
+	lockStockQuery = `SELECT on_hand - reserved FROM stock_levels WHERE sku = $1 FOR UPDATE`
+
+	reserveStockQuery = `UPDATE stock_levels SET reserved = reserved + $2, updated_at = now() WHERE sku = $1`
+
+	createReservationQuery = `
+		INSERT INTO stock_reservations(order_id, sku, quantity, status, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, 'active', $4, now(), now())
+	`
+
+	createOrderQuery = `
+		INSERT INTO orders(user_id, email, status, currency, item_count, subtotal, discount_total, tax_total,
+			prices_include_tax, shipping_method_id, shipping_method, shipping_total, shipping_address, total,
+			gift_card_total, store_credit_total, subscription_id, subscription_run_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, now(), now())
+		RETURNING *
+	`
+
+	createOrderItemQuery = `
+		INSERT INTO order_items(order_id, variant_id, product_id, sku, title, variant_title, unit_price, quantity, line_total,
+			tax_class, tax_rate_ppm, tax_amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING *
+	`
+
+	// The row lock also serializes the per customer usage check of concurrent checkouts
+	usePromotionQuery = `
+		UPDATE promotions
+		SET used_count = used_count + 1, updated_at = now()
+		WHERE promotion_id = $1 AND active
+			AND (usage_limit IS NULL OR used_count < usage_limit)
+			AND (starts_at IS NULL OR starts_at <= now())
+			AND (ends_at IS NULL OR ends_at > now())
+		RETURNING per_customer_limit
+	`
+
+	countCustomerUsageQuery = `
+		SELECT COUNT(d.order_discount_id)
+		FROM order_discounts d
+		JOIN orders o ON o.order_id = d.order_id
+		WHERE d.promotion_id = $1 AND d.user_id = $2 AND o.status <> 'cancelled'
+	`
+
+	createOrderDiscountQuery = `
+		INSERT INTO order_discounts(order_id, promotion_id, user_id, code, description, amount, free_shipping, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, now())
+		RETURNING *
+	`
+
+	// Fails when the card was disabled, expired or spent since checkout read it
+	redeemGiftCardQuery = `
+		UPDATE gift_cards
+		SET balance = balance - $2, updated_at = now()
+		WHERE gift_card_id = $1 AND status = 'active' AND (expires_at IS NULL OR expires_at > now()) AND balance >= $2
+		RETURNING balance
+	`
+
+	createGiftCardTransactionQuery = `
+		INSERT INTO gift_card_transactions(gift_card_id, kind, amount, balance_after, order_id, note, actor_id, created_at)
+		VALUES ($1, 'redeem', $2, $3, $4, 'order payment', $5, now())
+	`
+
+	// Serializes store credit ledger writes of the customer, see the store credit repository
+	lockUserQuery = `SELECT user_id FROM users WHERE user_id = $1 FOR NO KEY UPDATE`
+
+	getStoreCreditBalanceQuery = `
+		SELECT COALESCE(SUM(amount), 0) FROM store_credit_ledger WHERE user_id = $1 AND currency = $2
+	`
+
+	createStoreCreditRedemptionQuery = `
+		INSERT INTO store_credit_ledger(user_id, currency, amount, reason, order_id, note, actor_id, created_at)
+		VALUES ($1, $2, $3, 'redemption', $4, 'order payment', $1, now())
+	`
+
+	createOrderEventQuery = `
+		INSERT INTO order_events(order_id, from_status, to_status, note, actor_id, created_at)
+		VALUES ($1, NULL, $2, $3, $4, now())
+	`
+
+	convertCartQuery = `UPDATE carts SET status = 'converted', updated_at = now() WHERE cart_id = $1`
+)
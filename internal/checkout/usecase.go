@@ -0,0 +1,14 @@
+package checkout
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+)
+
+// Checkout UseCase
+type UseCase interface {
+	Checkout(ctx context.Context, input *models.CheckoutInput) (*models.Order, error)
+	PlaceSubscriptionOrder(ctx context.Context, s *models.Subscription) (*models.Order, error)
+	ExpireReservations(ctx context.Context) (int, error)
+}
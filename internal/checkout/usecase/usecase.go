@@ -0,0 +1,518 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/address"
+	"github.com/fekuna/go-store/internal/cart"
+	"github.com/fekuna/go-store/internal/checkout"
+	"github.com/fekuna/go-store/internal/giftcard"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/order"
+	"github.com/fekuna/go-store/internal/promotion"
+	"github.com/fekuna/go-store/internal/shipping"
+	"github.com/fekuna/go-store/internal/storecredit"
+	"github.com/fekuna/go-store/internal/tax"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Expired orders handled per batch
+const expireBatchSize = 100
+
+// Checkout UseCase
+type checkoutUC struct {
+	cfg          *config.Config
+	logger       logger.Logger
+	checkoutRepo checkout.Repository
+	cartRepo     cart.Repository
+	orderRepo    order.Repository
+	promotionUC  promotion.UseCase
+	taxCalc      tax.Calculator
+	shippingUC   shipping.UseCase
+	addressRepo  address.Repository
+	giftCardRepo giftcard.Repository
+	creditRepo   storecredit.Repository
+}
+
+// Checkout UseCase constructor
+func NewCheckoutUseCase(cfg *config.Config, logger logger.Logger, checkoutRepo checkout.Repository, cartRepo cart.Repository, orderRepo order.Repository, promotionUC promotion.UseCase, taxCalc tax.Calculator, shippingUC shipping.UseCase, addressRepo address.Repository, giftCardRepo giftcard.Repository, creditRepo storecredit.Repository) checkout.UseCase {
+	return &checkoutUC{
+		cfg:          cfg,
+		logger:       logger,
+		checkoutRepo: checkoutRepo,
+		cartRepo:     cartRepo,
+		orderRepo:    orderRepo,
+		promotionUC:  promotionUC,
+		taxCalc:      taxCalc,
+		shippingUC:   shippingUC,
+		addressRepo:  addressRepo,
+		giftCardRepo: giftCardRepo,
+		creditRepo:   creditRepo,
+	}
+}
+
+// Place pending order from the cart of the user in context, stock is held until the payment window ends.
+// The order is priced before the checkout transaction: promotions are evaluated on the cart lines, the order
+// ships to the given address, a saved one or the default shipping address of the user, taxes are calculated
+// for that address on the discounted lines. Placing it fails when the cart changed meanwhile. Promotion usage,
+// gift cards and store credit are redeemed with the order, an order they pay in full is paid right away.
+func (u *checkoutUC) Checkout(ctx context.Context, input *models.CheckoutInput) (*models.Order, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := u.cartRepo.GetActiveByUser(ctx, user.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, httpErrors.NewBadRequestError(errors.New("checkoutUC.Checkout: cart is empty"))
+		}
+		return nil, err
+	}
+
+	address, err := u.shippingAddress(ctx, user, input)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := u.checkoutRepo.GetCartItems(ctx, c.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := buildOrder(user, items)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.applyPromotions(ctx, o, items, input.PromoCodes); err != nil {
+		return nil, err
+	}
+	if err = u.applyShipping(ctx, o, items, address, input.ShippingMethodID); err != nil {
+		return nil, err
+	}
+	if err = u.applyTax(ctx, o, address.TaxAddress()); err != nil {
+		return nil, err
+	}
+	if err = u.applyGiftCards(ctx, o, input.GiftCardCodes); err != nil {
+		return nil, err
+	}
+	if input.UseStoreCredit {
+		if err = u.applyStoreCredit(ctx, o); err != nil {
+			return nil, err
+		}
+	}
+
+	expiresAt := time.Now().Add(time.Second * u.cfg.Checkout.ReservationTTL)
+
+	o, err = u.checkoutRepo.PlaceOrder(ctx, c.CartID, o, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if o.AmountDue() > 0 {
+		return o, nil
+	}
+
+	event := &models.OrderEvent{ToStatus: models.OrderStatusPaid, Note: "paid with gift cards and store credit", ActorID: &user.UserID}
+	paid, err := u.orderRepo.UpdateStatus(ctx, o.OrderID, models.OrderStatusPending, event)
+	if err != nil {
+		return nil, err
+	}
+	paid.Items = o.Items
+	paid.Discounts = o.Discounts
+	paid.GiftCards = o.GiftCards
+
+	return paid, nil
+}
+
+// Place pending order of a subscription run for its customer, priced like a checkout of the subscription items
+// with automatic promotions. It ships to the subscription address or the default shipping address. A run
+// retried for the same delivery date gets the order placed by its earlier attempt.
+func (u *checkoutUC) PlaceSubscriptionOrder(ctx context.Context, s *models.Subscription) (*models.Order, error) {
+	// TODO: Tracing
+
+	existing, err := u.checkoutRepo.GetSubscriptionRunOrder(ctx, s.SubscriptionID, s.NextRunAt)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	user := &models.User{UserID: s.UserID, Email: s.Email}
+
+	address, err := u.shippingAddress(ctx, user, &models.CheckoutInput{ShippingAddressID: s.ShippingAddressID})
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := u.checkoutRepo.GetSubscriptionItems(ctx, s.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := buildOrder(user, items)
+	if err != nil {
+		return nil, err
+	}
+	o.SubscriptionID = &s.SubscriptionID
+	o.SubscriptionRunAt = &s.NextRunAt
+	if err = u.applyPromotions(ctx, o, items, nil); err != nil {
+		return nil, err
+	}
+	if err = u.applyShipping(ctx, o, items, address, s.ShippingMethodID); err != nil {
+		return nil, err
+	}
+	if err = u.applyTax(ctx, o, address.TaxAddress()); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(time.Second * u.cfg.Checkout.ReservationTTL)
+
+	return u.checkoutRepo.PlaceSubscriptionOrder(ctx, s.SubscriptionID, s.NextRunAt, o, expiresAt)
+}
+
+// Cancel pending orders whose payment window ended, their reservations are released with the cancellation
+func (u *checkoutUC) ExpireReservations(ctx context.Context) (int, error) {
+	// TODO: Tracing
+
+	expired := 0
+	for {
+		orderIDs, err := u.orderRepo.ListExpiredPending(ctx, time.Now(), expireBatchSize)
+		if err != nil {
+			return expired, err
+		}
+
+		for _, orderID := range orderIDs {
+			event := &models.OrderEvent{ToStatus: models.OrderStatusCancelled, Note: "payment window expired"}
+			if _, err = u.orderRepo.UpdateStatus(ctx, orderID, models.OrderStatusPending, event); err != nil {
+				// Paid or cancelled meanwhile
+				if errors.Is(err, httpErrors.InvalidStateTransition) {
+					continue
+				}
+				return expired, err
+			}
+			expired++
+		}
+
+		if len(orderIDs) < expireBatchSize {
+			return expired, nil
+		}
+	}
+}
+
+// Add discounts to the order, a promo code that doesn't apply fails the checkout
+func (u *checkoutUC) applyPromotions(ctx context.Context, o *models.Order, items []*models.CartItem, codes []string) error {
+	c := &models.Cart{UserID: o.UserID, Items: items}
+	c.CalculateTotals()
+
+	result, err := u.promotionUC.Evaluate(ctx, c, codes)
+	if err != nil {
+		return err
+	}
+	if len(result.Rejected) > 0 {
+		rejected := result.Rejected[0]
+		return httpErrors.NewRestErrorWithMessage(
+			http.StatusBadRequest,
+			fmt.Sprintf("Promo code %s: %s", rejected.Code, rejected.Reason),
+			errors.New("checkoutUC.applyPromotions: promo code rejected"),
+		)
+	}
+
+	o.Discounts = result.Discounts
+	o.DiscountTotal = result.DiscountTotal
+	o.Total = o.Subtotal - o.DiscountTotal
+
+	return nil
+}
+
+// Add the chosen shipping method to the order, a method has to be chosen when the address has any
+func (u *checkoutUC) applyShipping(ctx context.Context, o *models.Order, items []*models.CartItem, address *models.ShippingAddress, methodID *uuid.UUID) error {
+	if address.Country != "" {
+		o.ShippingAddress = address
+	}
+
+	quote, err := u.shippingUC.Quote(ctx, &models.Cart{UserID: o.UserID, Items: items}, address)
+	if err != nil {
+		return err
+	}
+
+	if methodID == nil {
+		if len(quote.Rates) > 0 {
+			return httpErrors.NewRestErrorWithMessage(
+				http.StatusBadRequest,
+				"Choose a shipping method",
+				errors.New("checkoutUC.applyShipping: shipping method is required"),
+			)
+		}
+		return nil
+	}
+
+	var rate *models.ShippingRate
+	for _, r := range quote.Rates {
+		if r.MethodID == *methodID {
+			rate = r
+			break
+		}
+	}
+	if rate == nil {
+		return httpErrors.NewRestErrorWithMessage(
+			http.StatusBadRequest,
+			"Shipping method is not available for this cart and address",
+			errors.New("checkoutUC.applyShipping: shipping method not available"),
+		)
+	}
+
+	o.ShippingMethodID = &rate.MethodID
+	o.ShippingMethod = rate.Name
+	o.ShippingTotal = rate.Price
+	for _, d := range o.Discounts {
+		if d.FreeShipping {
+			o.ShippingTotal = 0
+		}
+	}
+	o.Total += o.ShippingTotal
+
+	return nil
+}
+
+// Add taxes to order lines, exclusive taxes are added to the total
+func (u *checkoutUC) applyTax(ctx context.Context, o *models.Order, address models.TaxAddress) error {
+	req := &models.TaxRequest{
+		Address:  address,
+		Currency: o.Currency,
+		Lines:    make([]*models.TaxLine, 0, len(o.Items)),
+	}
+
+	amounts := make([]int64, 0, len(o.Items))
+	for _, item := range o.Items {
+		amounts = append(amounts, item.LineTotal)
+	}
+	discounted := allocateDiscount(amounts, o.DiscountTotal)
+	for i, item := range o.Items {
+		req.Lines = append(req.Lines, &models.TaxLine{Ref: item.SKU, TaxClass: item.TaxClass, Amount: discounted[i]})
+	}
+
+	result, err := u.taxCalc.Calculate(ctx, req)
+	if err != nil {
+		return err
+	}
+	if len(result.Lines) != len(o.Items) {
+		return errors.New("checkoutUC.applyTax: tax result doesn't match order lines")
+	}
+
+	for i, line := range result.Lines {
+		o.Items[i].TaxRatePPM = line.RatePPM
+		o.Items[i].TaxAmount = line.Tax
+	}
+	o.TaxTotal = result.TaxTotal
+	o.PricesIncludeTax = result.PricesIncludeTax
+	if !result.PricesIncludeTax {
+		o.Total += o.TaxTotal
+	}
+
+	return nil
+}
+
+// Pay the order with gift cards in the given order, a card that can't be used fails the checkout. Cards are
+// redeemed with the order and fail it when another order used their balance meanwhile.
+func (u *checkoutUC) applyGiftCards(ctx context.Context, o *models.Order, codes []string) error {
+	used := make(map[string]bool, len(codes))
+	now := time.Now()
+
+	for _, code := range codes {
+		code = models.NormalizeGiftCardCode(code)
+		if used[code] || o.AmountDue() == 0 {
+			continue
+		}
+		used[code] = true
+
+		card, err := u.giftCardRepo.GetByCode(ctx, code)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return giftCardRejected(&models.GiftCard{Code: code}, "gift card not found")
+			}
+			return err
+		}
+		if reason := card.Unusable(now); reason != "" {
+			return giftCardRejected(card, reason)
+		}
+		if card.Currency != o.Currency {
+			return giftCardRejected(card, "gift card is in "+card.Currency)
+		}
+
+		amount := card.Balance
+		if due := o.AmountDue(); amount > due {
+			amount = due
+		}
+		o.GiftCards = append(o.GiftCards, &models.OrderGiftCard{GiftCardID: card.GiftCardID, Code: card.MaskedCode(), Amount: amount})
+		o.GiftCardTotal += amount
+	}
+
+	return nil
+}
+
+// Pay what gift cards left with store credit of the customer in the order currency
+func (u *checkoutUC) applyStoreCredit(ctx context.Context, o *models.Order) error {
+	if o.UserID == nil || o.AmountDue() == 0 {
+		return nil
+	}
+
+	balance, err := u.creditRepo.Balance(ctx, *o.UserID, o.Currency)
+	if err != nil {
+		return err
+	}
+
+	amount := balance
+	if due := o.AmountDue(); amount > due {
+		amount = due
+	}
+	if amount > 0 {
+		o.StoreCreditTotal = amount
+	}
+
+	return nil
+}
+
+func giftCardRejected(card *models.GiftCard, reason string) error {
+	return httpErrors.NewRestErrorWithMessage(
+		http.StatusBadRequest,
+		fmt.Sprintf("Gift card %s: %s", card.MaskedCode(), reason),
+		errors.New("checkoutUC.applyGiftCards: gift card rejected"),
+	)
+}
+
+// Spread order discount over line amounts by their share of the subtotal. Lines get the floor of their share
+// and the units left go to the largest remainders, so no line gets more discount than its amount.
+func allocateDiscount(amounts []int64, discount int64) []int64 {
+	var subtotal int64
+	for _, amount := range amounts {
+		subtotal += amount
+	}
+
+	discounted := make([]int64, len(amounts))
+	copy(discounted, amounts)
+	if discount <= 0 || subtotal == 0 {
+		return discounted
+	}
+	if discount > subtotal {
+		discount = subtotal
+	}
+
+	remainders := make([]int64, len(amounts))
+	left := discount
+	for i, amount := range amounts {
+		share := discount * amount / subtotal
+		remainders[i] = discount * amount % subtotal
+		discounted[i] -= share
+		left -= share
+	}
+
+	order := make([]int, len(amounts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+	for _, i := range order {
+		if left == 0 {
+			break
+		}
+		if discounted[i] > 0 {
+			discounted[i]--
+			left--
+		}
+	}
+
+	return discounted
+}
+
+// Destination given with the checkout, a saved address or the default shipping address, an empty address when
+// the user has none
+func (u *checkoutUC) shippingAddress(ctx context.Context, user *models.User, input *models.CheckoutInput) (*models.ShippingAddress, error) {
+	if input.ShippingAddress != nil {
+		return input.ShippingAddress, nil
+	}
+
+	if input.ShippingAddressID != nil {
+		a, err := u.addressRepo.GetByID(ctx, user.UserID, *input.ShippingAddressID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, httpErrors.NewRestErrorWithMessage(
+					http.StatusBadRequest,
+					"Shipping address not found",
+					errors.Wrap(err, "checkoutUC.shippingAddress"),
+				)
+			}
+			return nil, err
+		}
+		return a.ShippingAddress(), nil
+	}
+
+	a, err := u.addressRepo.GetDefaultShipping(ctx, user.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &models.ShippingAddress{}, nil
+		}
+		return nil, err
+	}
+
+	return a.ShippingAddress(), nil
+}
+
+// Snapshot cart lines into order lines, the repository checks them against the locked cart
+func buildOrder(user *models.User, items []*models.CartItem) (*models.Order, error) {
+	if len(items) == 0 {
+		return nil, httpErrors.NewBadRequestError(errors.New("checkoutUC.buildOrder: cart is empty"))
+	}
+
+	c := &models.Cart{Items: items}
+	c.CalculateTotals()
+
+	o := &models.Order{
+		UserID:    &user.UserID,
+		Email:     user.Email,
+		Status:    models.OrderStatusPending,
+		Currency:  c.Totals.Currency,
+		ItemCount: c.Totals.ItemCount,
+		Subtotal:  c.Totals.Subtotal,
+		Total:     c.Totals.Subtotal,
+		Items:     make([]*models.OrderItem, 0, len(items)),
+	}
+
+	for _, item := range items {
+		if !item.Available {
+			return nil, httpErrors.NewBadRequestError(errors.Errorf("checkoutUC.buildOrder: sku %s is not available", item.SKU))
+		}
+		if item.Currency != o.Currency {
+			return nil, httpErrors.NewBadRequestError(errors.New("checkoutUC.buildOrder: cart has items in more than one currency"))
+		}
+
+		variantID, productID := item.VariantID, item.ProductID
+		o.Items = append(o.Items, &models.OrderItem{
+			VariantID:    &variantID,
+			ProductID:    &productID,
+			SKU:          item.SKU,
+			Title:        item.Title,
+			VariantTitle: item.VariantTitle,
+			UnitPrice:    item.UnitPrice,
+			Quantity:     item.Quantity,
+			LineTotal:    item.LineTotal,
+			TaxClass:     models.NormalizeTaxClass(item.TaxClass),
+		})
+	}
+
+	return o, nil
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestAllocateDiscount(t *testing.T) {
+	tests := []struct {
+		name     string
+		amounts  []int64
+		discount int64
+		want     []int64
+	}{
+		{name: "no discount", amounts: []int64{100, 200}, discount: 0, want: []int64{100, 200}},
+		{name: "no lines", amounts: []int64{}, discount: 50, want: []int64{}},
+		{name: "free lines", amounts: []int64{0, 0}, discount: 50, want: []int64{0, 0}},
+		{name: "single line", amounts: []int64{1000}, discount: 250, want: []int64{750}},
+		{name: "even shares", amounts: []int64{100, 200, 300}, discount: 60, want: []int64{90, 180, 270}},
+		{name: "rounding to largest remainder", amounts: []int64{100, 100, 100}, discount: 100, want: []int64{66, 67, 67}},
+		{name: "small lines never go negative", amounts: []int64{1, 1, 1}, discount: 2, want: []int64{0, 0, 1}},
+		{name: "discount larger than subtotal", amounts: []int64{300, 200}, discount: 900, want: []int64{0, 0}},
+		{name: "whole subtotal", amounts: []int64{333, 667}, discount: 1000, want: []int64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := allocateDiscount(tt.amounts, tt.discount)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("allocateDiscount(%v, %d) = %v, want %v", tt.amounts, tt.discount, got, tt.want)
+			}
+
+			var before, after int64
+			for i := range got {
+				before += tt.amounts[i]
+				after += got[i]
+				if got[i] < 0 {
+					t.Errorf("line %d is negative: %d", i, got[i])
+				}
+			}
+			if before > 0 && tt.discount > 0 {
+				applied := tt.discount
+				if applied > before {
+					applied = before
+				}
+				if before-after != applied {
+					t.Errorf("allocated %d, want %d", before-after, applied)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildOrder(t *testing.T) {
+	user := &models.User{UserID: uuid.New(), Email: "buyer@example.com"}
+
+	line := func(sku string, quantity int, unitPrice int64, currency string, available bool) *models.CartItem {
+		return &models.CartItem{
+			SKU:       sku,
+			Quantity:  quantity,
+			VariantID: uuid.New(),
+			ProductID: uuid.New(),
+			Title:     "Product " + sku,
+			UnitPrice: unitPrice,
+			Currency:  currency,
+			Available: available,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		items     []*models.CartItem
+		wantErr   bool
+		itemCount int
+		subtotal  int64
+	}{
+		{name: "empty cart", items: nil, wantErr: true},
+		{name: "unavailable line", items: []*models.CartItem{line("A", 1, 500, "USD", true), line("B", 1, 500, "USD", false)}, wantErr: true},
+		{name: "mixed currencies", items: []*models.CartItem{line("A", 1, 500, "USD", true), line("B", 1, 500, "EUR", true)}, wantErr: true},
+		{name: "one line", items: []*models.CartItem{line("A", 3, 250, "USD", true)}, itemCount: 3, subtotal: 750},
+		{name: "several lines", items: []*models.CartItem{line("A", 2, 1000, "EUR", true), line("B", 1, 499, "EUR", true)}, itemCount: 3, subtotal: 2499},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := buildOrder(user, tt.items)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("buildOrder() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildOrder() error = %v", err)
+			}
+
+			if o.Status != models.OrderStatusPending {
+				t.Errorf("status = %s, want %s", o.Status, models.OrderStatusPending)
+			}
+			if o.UserID == nil || *o.UserID != user.UserID || o.Email != user.Email {
+				t.Errorf("order belongs to %v %s, want %s %s", o.UserID, o.Email, user.UserID, user.Email)
+			}
+			if o.Currency != tt.items[0].Currency {
+				t.Errorf("currency = %s, want %s", o.Currency, tt.items[0].Currency)
+			}
+			if o.ItemCount != tt.itemCount || o.Subtotal != tt.subtotal || o.Total != tt.subtotal {
+				t.Errorf("item count %d, subtotal %d, total %d, want %d, %d, %d",
+					o.ItemCount, o.Subtotal, o.Total, tt.itemCount, tt.subtotal, tt.subtotal)
+			}
+			if len(o.Items) != len(tt.items) {
+				t.Fatalf("%d order lines, want %d", len(o.Items), len(tt.items))
+			}
+			for i, item := range o.Items {
+				cartItem := tt.items[i]
+				if item.SKU != cartItem.SKU || item.Quantity != cartItem.Quantity || item.UnitPrice != cartItem.UnitPrice {
+					t.Errorf("line %d = %s x%d at %d, want %s x%d at %d",
+						i, item.SKU, item.Quantity, item.UnitPrice, cartItem.SKU, cartItem.Quantity, cartItem.UnitPrice)
+				}
+				if item.LineTotal != cartItem.UnitPrice*int64(cartItem.Quantity) {
+					t.Errorf("line %d total = %d, want %d", i, item.LineTotal, cartItem.UnitPrice*int64(cartItem.Quantity))
+				}
+				if item.TaxClass != models.TaxClassStandard {
+					t.Errorf("line %d tax class = %q, want %q", i, item.TaxClass, models.TaxClassStandard)
+				}
+				if *item.VariantID != cartItem.VariantID || *item.ProductID != cartItem.ProductID {
+					t.Errorf("line %d has variant %s product %s, want %s %s",
+						i, item.VariantID, item.ProductID, cartItem.VariantID, cartItem.ProductID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package giftcard
+
+import "github.com/labstack/echo/v4"
+
+// Gift card HTTP Handlers interface
+type Handlers interface {
+	Issue() echo.HandlerFunc
+	GetByID() echo.HandlerFunc
+	List() echo.HandlerFunc
+	Update() echo.HandlerFunc
+	Balance() echo.HandlerFunc
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/giftcard"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Gift card handlers
+type giftCardHandlers struct {
+	cfg        *config.Config
+	logger     logger.Logger
+	giftCardUC giftcard.UseCase
+}
+
+// Gift card handlers constructor
+func NewGiftCardHandlers(cfg *config.Config, logger logger.Logger, giftCardUC giftcard.UseCase) giftcard.Handlers {
+	return &giftCardHandlers{
+		cfg:        cfg,
+		logger:     logger,
+		giftCardUC: giftCardUC,
+	}
+}
+
+// Issue godoc
+// @Summary Issue gift card
+// @Description issue gift card with a generated code and the given balance, admin only
+// @Tags GiftCards
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.GiftCard
+// @Failure 400 {object} httpErrors.RestError
+// @Router /gift-cards [post]
+func (h *giftCardHandlers) Issue() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		input := &models.GiftCardInput{}
+		if err := utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		card, err := h.giftCardUC.Issue(ctx, input)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, card)
+	}
+}
+
+// GetByID godoc
+// @Summary Get gift card by id
+// @Description get gift card with its transactions, admin only
+// @Tags GiftCards
+// @Produce json
+// @Param gift_card_id path string true "gift_card_id"
+// @Success 200 {object} models.GiftCard
+// @Failure 404 {object} httpErrors.RestError
+// @Router /gift-cards/{gift_card_id} [get]
+func (h *giftCardHandlers) GetByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		giftCardID, err := uuid.Parse(c.Param("gift_card_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		card, err := h.giftCardUC.GetByID(ctx, giftCardID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, card)
+	}
+}
+
+// List godoc
+// @Summary Get gift cards
+// @Description get gift cards, newest first, optionally by status, admin only
+// @Tags GiftCards
+// @Produce json
+// @Param status query string false "gift card status"
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.GiftCardsList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /gift-cards [get]
+func (h *giftCardHandlers) List() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		query := &models.GiftCardsQuery{}
+		if err := utils.ReadRequest(c, query); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		cardsList, err := h.giftCardUC.List(ctx, query, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, cardsList)
+	}
+}
+
+// Update godoc
+// @Summary Update gift card
+// @Description disable or enable gift card and change its expiry, admin only
+// @Tags GiftCards
+// @Accept json
+// @Produce json
+// @Param gift_card_id path string true "gift_card_id"
+// @Success 200 {object} models.GiftCard
+// @Failure 404 {object} httpErrors.RestError
+// @Router /gift-cards/{gift_card_id} [put]
+func (h *giftCardHandlers) Update() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		giftCardID, err := uuid.Parse(c.Param("gift_card_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		update := &models.GiftCardUpdate{}
+		if err = utils.ReadRequest(c, update); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		card, err := h.giftCardUC.Update(ctx, giftCardID, update)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, card)
+	}
+}
+
+// Balance godoc
+// @Summary Check gift card balance
+// @Description get balance and expiry of the gift card with the code, the code is sent in the body so it stays out of logs
+// @Tags GiftCards
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.GiftCardBalance
+// @Failure 404 {object} httpErrors.RestError
+// @Router /gift-cards/balance [post]
+func (h *giftCardHandlers) Balance() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		input := &models.GiftCardBalanceInput{}
+		if err := utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		balance, err := h.giftCardUC.Balance(ctx, input)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, balance)
+	}
+}
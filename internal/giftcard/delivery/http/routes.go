@@ -0,0 +1,18 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/giftcard"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func MapGiftCardRoutes(giftCardGroup *echo.Group, h giftcard.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	giftCardGroup.POST("/balance", h.Balance(), mw.AuthJWTMiddleware)
+	giftCardGroup.POST("", h.Issue(), adminOnly...)
+	giftCardGroup.GET("", h.List(), adminOnly...)
+	giftCardGroup.GET("/:gift_card_id", h.GetByID(), adminOnly...)
+	giftCardGroup.PUT("/:gift_card_id", h.Update(), adminOnly...)
+}
@@ -0,0 +1,20 @@
+package giftcard
+
+import (
+	"context"
+	"time"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Gift card repository, balances only change through redemptions at checkout and their reversals
+type Repository interface {
+	Create(ctx context.Context, card *models.GiftCard) (*models.GiftCard, error)
+	GetByID(ctx context.Context, giftCardID uuid.UUID) (*models.GiftCard, error)
+	GetByCode(ctx context.Context, code string) (*models.GiftCard, error)
+	GetTransactions(ctx context.Context, giftCardID uuid.UUID) ([]*models.GiftCardTransaction, error)
+	List(ctx context.Context, status string, pq *utils.PaginationQuery) (*models.GiftCardsList, error)
+	Update(ctx context.Context, giftCardID uuid.UUID, status string, expiresAt *time.Time) (*models.GiftCard, error)
+}
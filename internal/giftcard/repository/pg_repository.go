@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fekuna/go-store/internal/giftcard"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Gift card repository
+type giftCardRepo struct {
+	db *sqlx.DB
+}
+
+// Gift card repository constructor
+func NewGiftCardRepository(db *sqlx.DB) giftcard.Repository {
+	return &giftCardRepo{db: db}
+}
+
+// Create card with its full balance and record the issue
+func (r *giftCardRepo) Create(ctx context.Context, card *models.GiftCard) (*models.GiftCard, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "giftCardRepo.Create.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	created := &models.GiftCard{}
+	if err = tx.QueryRowxContext(
+		ctx,
+		createGiftCardQuery,
+		&card.Code,
+		&card.Currency,
+		&card.InitialAmount,
+		card.ExpiresAt,
+		card.RecipientEmail,
+		&card.Note,
+		card.OrderID,
+		card.IssuedBy,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "giftCardRepo.Create.StructScan")
+	}
+
+	if _, err = tx.ExecContext(
+		ctx,
+		createGiftCardTransactionQuery,
+		created.GiftCardID,
+		models.GiftCardTxIssue,
+		created.InitialAmount,
+		created.Balance,
+		created.OrderID,
+		&created.Note,
+		created.IssuedBy,
+	); err != nil {
+		return nil, errors.Wrap(err, "giftCardRepo.Create.ExecContext.transaction")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "giftCardRepo.Create.Commit")
+	}
+
+	return created, nil
+}
+
+func (r *giftCardRepo) GetByID(ctx context.Context, giftCardID uuid.UUID) (*models.GiftCard, error) {
+	// TODO: Tracing
+
+	card := &models.GiftCard{}
+	if err := r.db.GetContext(ctx, card, getGiftCardByIdQuery, giftCardID); err != nil {
+		return nil, errors.Wrap(err, "giftCardRepo.GetByID.GetContext")
+	}
+
+	return card, nil
+}
+
+// Card of a normalized code
+func (r *giftCardRepo) GetByCode(ctx context.Context, code string) (*models.GiftCard, error) {
+	// TODO: Tracing
+
+	card := &models.GiftCard{}
+	if err := r.db.GetContext(ctx, card, getGiftCardByCodeQuery, code); err != nil {
+		return nil, errors.Wrap(err, "giftCardRepo.GetByCode.GetContext")
+	}
+
+	return card, nil
+}
+
+func (r *giftCardRepo) GetTransactions(ctx context.Context, giftCardID uuid.UUID) ([]*models.GiftCardTransaction, error) {
+	// TODO: Tracing
+
+	transactions := make([]*models.GiftCardTransaction, 0)
+	if err := r.db.SelectContext(ctx, &transactions, getGiftCardTransactionsQuery, giftCardID); err != nil {
+		return nil, errors.Wrap(err, "giftCardRepo.GetTransactions.SelectContext")
+	}
+
+	return transactions, nil
+}
+
+func (r *giftCardRepo) List(ctx context.Context, status string, pq *utils.PaginationQuery) (*models.GiftCardsList, error) {
+	// TODO: Tracing
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalGiftCardsQuery, status); err != nil {
+		return nil, errors.Wrap(err, "giftCardRepo.List.GetContext.totalCount")
+	}
+
+	list := make([]*models.GiftCard, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &list, listGiftCardsQuery, status, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "giftCardRepo.List.SelectContext")
+		}
+	}
+
+	return &models.GiftCardsList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		GiftCards:  list,
+	}, nil
+}
+
+// Change status and expiry, the balance is left alone
+func (r *giftCardRepo) Update(ctx context.Context, giftCardID uuid.UUID, status string, expiresAt *time.Time) (*models.GiftCard, error) {
+	// TODO: Tracing
+
+	card := &models.GiftCard{}
+	if err := r.db.GetContext(ctx, card, updateGiftCardQuery, status, expiresAt, giftCardID); err != nil {
+		return nil, errors.Wrap(err, "giftCardRepo.Update.GetContext")
+	}
+
+	return card, nil
+}
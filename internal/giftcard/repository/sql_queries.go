@@ -0,0 +1,41 @@
+package repository
+
+const (
+	createGiftCardQuery = `
+		INSERT INTO gift_cards(code, currency, initial_amount, balance, status, expires_at, recipient_email, note,
+			order_id, issued_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $3, 'active', $4, $5, $6, $7, $8, now(), now())
+		RETURNING *
+	`
+
+	createGiftCardTransactionQuery = `
+		INSERT INTO gift_card_transactions(gift_card_id, kind, amount, balance_after, order_id, note, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, now())
+	`
+
+	getGiftCardByIdQuery = `SELECT * FROM gift_cards WHERE gift_card_id = $1`
+
+	getGiftCardByCodeQuery = `SELECT * FROM gift_cards WHERE code = $1`
+
+	getGiftCardTransactionsQuery = `
+		SELECT * FROM gift_card_transactions
+		WHERE gift_card_id = $1
+		ORDER BY created_at, transaction_id
+	`
+
+	getTotalGiftCardsQuery = `SELECT COUNT(gift_card_id) FROM gift_cards WHERE ($1 = '' OR status = $1)`
+
+	listGiftCardsQuery = `
+		SELECT * FROM gift_cards
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC, gift_card_id
+		OFFSET $2 LIMIT $3
+	`
+
+	updateGiftCardQuery = `
+		UPDATE gift_cards
+		SET status = $1, expires_at = $2, updated_at = now()
+		WHERE gift_card_id = $3
+		RETURNING *
+	`
+)
@@ -0,0 +1,18 @@
+package giftcard
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Gift card UseCase, admins issue and manage cards and anyone signed in can check a balance by code
+type UseCase interface {
+	Issue(ctx context.Context, input *models.GiftCardInput) (*models.GiftCard, error)
+	GetByID(ctx context.Context, giftCardID uuid.UUID) (*models.GiftCard, error)
+	List(ctx context.Context, query *models.GiftCardsQuery, pq *utils.PaginationQuery) (*models.GiftCardsList, error)
+	Update(ctx context.Context, giftCardID uuid.UUID, update *models.GiftCardUpdate) (*models.GiftCard, error)
+	Balance(ctx context.Context, input *models.GiftCardBalanceInput) (*models.GiftCardBalance, error)
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/giftcard"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Upper case letters and digits without 0, 1, I and O that are easily mistaken. 32 characters so a random
+	// byte maps to one without bias.
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	codeLength   = 16
+	// Attempts to generate a code that isn't taken yet
+	codeAttempts = 3
+)
+
+// Gift card UseCase
+type giftCardUC struct {
+	cfg          *config.Config
+	logger       logger.Logger
+	giftCardRepo giftcard.Repository
+}
+
+// Gift card UseCase constructor
+func NewGiftCardUseCase(cfg *config.Config, logger logger.Logger, giftCardRepo giftcard.Repository) giftcard.UseCase {
+	return &giftCardUC{cfg: cfg, logger: logger, giftCardRepo: giftCardRepo}
+}
+
+// Issue gift card with a generated code, admin only
+func (u *giftCardUC) Issue(ctx context.Context, input *models.GiftCardInput) (*models.GiftCard, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Prepare()
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, httpErrors.NewRestErrorWithMessage(
+			http.StatusBadRequest,
+			"Expiry must be in the future",
+			errors.New("giftCardUC.Issue: expiry in the past"),
+		)
+	}
+
+	card := &models.GiftCard{
+		Currency:       input.Currency,
+		InitialAmount:  input.Amount,
+		ExpiresAt:      input.ExpiresAt,
+		RecipientEmail: input.RecipientEmail,
+		Note:           input.Note,
+		OrderID:        input.OrderID,
+		IssuedBy:       &user.UserID,
+	}
+
+	for attempt := 1; ; attempt++ {
+		if card.Code, err = generateCode(); err != nil {
+			return nil, err
+		}
+
+		created, err := u.giftCardRepo.Create(ctx, card)
+		if err == nil {
+			return created, nil
+		}
+		if !strings.Contains(err.Error(), "gift_cards_code_key") || attempt == codeAttempts {
+			return nil, err
+		}
+	}
+}
+
+// Gift card with its transactions, admin only
+func (u *giftCardUC) GetByID(ctx context.Context, giftCardID uuid.UUID) (*models.GiftCard, error) {
+	// TODO: Tracing
+
+	card, err := u.giftCardRepo.GetByID(ctx, giftCardID)
+	if err != nil {
+		return nil, err
+	}
+
+	card.Transactions, err = u.giftCardRepo.GetTransactions(ctx, giftCardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return card, nil
+}
+
+// Gift cards, newest first, admin only
+func (u *giftCardUC) List(ctx context.Context, query *models.GiftCardsQuery, pq *utils.PaginationQuery) (*models.GiftCardsList, error) {
+	// TODO: Tracing
+
+	return u.giftCardRepo.List(ctx, query.Status, pq)
+}
+
+// Disable or enable gift card and change its expiry, admin only
+func (u *giftCardUC) Update(ctx context.Context, giftCardID uuid.UUID, update *models.GiftCardUpdate) (*models.GiftCard, error) {
+	// TODO: Tracing
+
+	return u.giftCardRepo.Update(ctx, giftCardID, update.Status, update.ExpiresAt)
+}
+
+// Balance of the card with the code, unknown codes are not found
+func (u *giftCardUC) Balance(ctx context.Context, input *models.GiftCardBalanceInput) (*models.GiftCardBalance, error) {
+	// TODO: Tracing
+
+	card, err := u.giftCardRepo.GetByCode(ctx, models.NormalizeGiftCardCode(input.Code))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.GiftCardBalance{
+		Code:      card.MaskedCode(),
+		Currency:  card.Currency,
+		Balance:   card.Balance,
+		ExpiresAt: card.ExpiresAt,
+		Usable:    card.Unusable(time.Now()) == "",
+	}, nil
+}
+
+// Random gift card code from crypto/rand
+func generateCode() (string, error) {
+	b := make([]byte, codeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "giftCardUC.generateCode")
+	}
+
+	for i := range b {
+		b[i] = codeAlphabet[int(b[i])%len(codeAlphabet)]
+	}
+
+	return string(b), nil
+}
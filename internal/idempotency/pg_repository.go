@@ -0,0 +1,18 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/fekuna/go-store/internal/models"
+)
+
+// Idempotency key repository
+type Repository interface {
+	Create(ctx context.Context, record *models.IdempotencyKey) (bool, error)
+	Get(ctx context.Context, scope string, key string) (*models.IdempotencyKey, error)
+	Relock(ctx context.Context, record *models.IdempotencyKey, lockedBefore time.Time) (bool, error)
+	Complete(ctx context.Context, record *models.IdempotencyKey) error
+	Delete(ctx context.Context, scope string, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
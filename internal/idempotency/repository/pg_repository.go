@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fekuna/go-store/internal/idempotency"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Idempotency key repository
+type idempotencyRepo struct {
+	db *sqlx.DB
+}
+
+// Idempotency key repository constructor
+func NewIdempotencyRepository(db *sqlx.DB) idempotency.Repository {
+	return &idempotencyRepo{db: db}
+}
+
+// Insert in progress key, false if the key already exists
+func (r *idempotencyRepo) Create(ctx context.Context, rec *models.IdempotencyKey) (bool, error) {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, createIdempotencyKeyQuery, &rec.Scope, &rec.Key, &rec.Fingerprint, &rec.ExpiresAt)
+	if err != nil {
+		return false, errors.Wrap(err, "idempotencyRepo.Create.ExecContext")
+	}
+
+	return rowsAffected(result, "idempotencyRepo.Create.RowsAffected")
+}
+
+func (r *idempotencyRepo) Get(ctx context.Context, scope string, key string) (*models.IdempotencyKey, error) {
+	// TODO: Tracing
+
+	rec := &models.IdempotencyKey{}
+	if err := r.db.GetContext(ctx, rec, getIdempotencyKeyQuery, scope, key); err != nil {
+		return nil, errors.Wrap(err, "idempotencyRepo.Get.GetContext")
+	}
+
+	return rec, nil
+}
+
+// Take over an expired key or a lock left behind before lockedBefore
+func (r *idempotencyRepo) Relock(ctx context.Context, rec *models.IdempotencyKey, lockedBefore time.Time) (bool, error) {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, relockIdempotencyKeyQuery, &rec.Scope, &rec.Key, &rec.Fingerprint, &rec.ExpiresAt, lockedBefore)
+	if err != nil {
+		return false, errors.Wrap(err, "idempotencyRepo.Relock.ExecContext")
+	}
+
+	return rowsAffected(result, "idempotencyRepo.Relock.RowsAffected")
+}
+
+func (r *idempotencyRepo) Complete(ctx context.Context, rec *models.IdempotencyKey) error {
+	// TODO: Tracing
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		completeIdempotencyKeyQuery,
+		&rec.Scope,
+		&rec.Key,
+		rec.ResponseStatus,
+		rec.ResponseContentType,
+		rec.ResponseHeaders,
+		rec.ResponseBody,
+	); err != nil {
+		return errors.Wrap(err, "idempotencyRepo.Complete.ExecContext")
+	}
+
+	return nil
+}
+
+func (r *idempotencyRepo) Delete(ctx context.Context, scope string, key string) error {
+	// TODO: Tracing
+
+	if _, err := r.db.ExecContext(ctx, deleteIdempotencyKeyQuery, scope, key); err != nil {
+		return errors.Wrap(err, "idempotencyRepo.Delete.ExecContext")
+	}
+
+	return nil
+}
+
+func (r *idempotencyRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, deleteExpiredIdempotencyKeysQuery)
+	if err != nil {
+		return 0, errors.Wrap(err, "idempotencyRepo.DeleteExpired.ExecContext")
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "idempotencyRepo.DeleteExpired.RowsAffected")
+	}
+
+	return n, nil
+}
+
+func rowsAffected(result sql.Result, op string) (bool, error) {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, op)
+	}
+
+	return n > 0, nil
+}
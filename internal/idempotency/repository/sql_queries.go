@@ -0,0 +1,31 @@
+package repository
+
+const (
+	createIdempotencyKeyQuery = `
+		INSERT INTO idempotency_keys(scope, idempotency_key, fingerprint, status, created_at, locked_at, expires_at)
+		VALUES ($1, $2, $3, 'in_progress', now(), now(), $4)
+		ON CONFLICT (scope, idempotency_key) DO NOTHING
+	`
+
+	getIdempotencyKeyQuery = `SELECT * FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2`
+
+	// Expired keys are free for any request, stale locks only for the same request
+	relockIdempotencyKeyQuery = `
+		UPDATE idempotency_keys
+		SET fingerprint = $3, status = 'in_progress', response_status = NULL, response_content_type = NULL,
+			response_headers = NULL, response_body = NULL, created_at = now(), locked_at = now(), expires_at = $4
+		WHERE scope = $1 AND idempotency_key = $2
+			AND (expires_at < now() OR (status = 'in_progress' AND locked_at < $5 AND fingerprint = $3))
+	`
+
+	completeIdempotencyKeyQuery = `
+		UPDATE idempotency_keys
+		SET status = 'completed', response_status = $3, response_content_type = $4, response_headers = $5,
+			response_body = $6
+		WHERE scope = $1 AND idempotency_key = $2
+	`
+
+	deleteIdempotencyKeyQuery = `DELETE FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2`
+
+	deleteExpiredIdempotencyKeysQuery = `DELETE FROM idempotency_keys WHERE expires_at < now()`
+)
@@ -0,0 +1,15 @@
+package idempotency
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+)
+
+// Idempotency key UseCase
+type UseCase interface {
+	Begin(ctx context.Context, scope string, key string, fingerprint string) (*models.IdempotencyKey, error)
+	Complete(ctx context.Context, record *models.IdempotencyKey) error
+	Release(ctx context.Context, scope string, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
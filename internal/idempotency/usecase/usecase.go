@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/idempotency"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+// Idempotency key UseCase
+type idempotencyUC struct {
+	cfg             *config.Config
+	logger          logger.Logger
+	idempotencyRepo idempotency.Repository
+}
+
+// Idempotency key UseCase constructor
+func NewIdempotencyUseCase(cfg *config.Config, logger logger.Logger, idempotencyRepo idempotency.Repository) idempotency.UseCase {
+	return &idempotencyUC{
+		cfg:             cfg,
+		logger:          logger,
+		idempotencyRepo: idempotencyRepo,
+	}
+}
+
+// Lock key for a new request. Returns nil when the caller should run the request,
+// or the completed record whose response must be replayed.
+func (u *idempotencyUC) Begin(ctx context.Context, scope string, key string, fingerprint string) (*models.IdempotencyKey, error) {
+	// TODO: Tracing
+
+	now := time.Now()
+	rec := &models.IdempotencyKey{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(time.Second * u.cfg.Idempotency.TTL),
+	}
+
+	created, err := u.idempotencyRepo.Create(ctx, rec)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		return nil, nil
+	}
+
+	relocked, err := u.idempotencyRepo.Relock(ctx, rec, now.Add(-time.Second*u.cfg.Idempotency.LockTimeout))
+	if err != nil {
+		return nil, err
+	}
+	if relocked {
+		return nil, nil
+	}
+
+	existing, err := u.idempotencyRepo.Get(ctx, scope, key)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case existing.Fingerprint != fingerprint:
+		return nil, errors.Wrapf(httpErrors.IdempotencyKeyReused, "idempotencyUC.Begin: key %s", key)
+	case existing.Status != models.IdempotencyCompleted:
+		return nil, errors.Wrapf(httpErrors.IdempotencyKeyInUse, "idempotencyUC.Begin: key %s", key)
+	}
+
+	return existing, nil
+}
+
+// Store response of the request that holds the key
+func (u *idempotencyUC) Complete(ctx context.Context, rec *models.IdempotencyKey) error {
+	// TODO: Tracing
+
+	return u.idempotencyRepo.Complete(ctx, rec)
+}
+
+// Drop key of a request that did not produce a response worth replaying, so it can be retried
+func (u *idempotencyUC) Release(ctx context.Context, scope string, key string) error {
+	// TODO: Tracing
+
+	return u.idempotencyRepo.Delete(ctx, scope, key)
+}
+
+func (u *idempotencyUC) DeleteExpired(ctx context.Context) (int64, error) {
+	// TODO: Tracing
+
+	return u.idempotencyRepo.DeleteExpired(ctx)
+}
@@ -0,0 +1,12 @@
+package inventory
+
+import "github.com/labstack/echo/v4"
+
+// Inventory HTTP Handlers interface
+type Handlers interface {
+	GetStock() echo.HandlerFunc
+	RecordMovement() echo.HandlerFunc
+	ListMovements() echo.HandlerFunc
+	SetThreshold() echo.HandlerFunc
+	ListLowStock() echo.HandlerFunc
+}
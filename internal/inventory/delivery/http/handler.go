@@ -0,0 +1,164 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/inventory"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// Inventory handlers
+type inventoryHandlers struct {
+	cfg         *config.Config
+	logger      logger.Logger
+	inventoryUC inventory.UseCase
+}
+
+// Inventory handlers constructor
+func NewInventoryHandlers(cfg *config.Config, logger logger.Logger, inventoryUC inventory.UseCase) inventory.Handlers {
+	return &inventoryHandlers{
+		cfg:         cfg,
+		logger:      logger,
+		inventoryUC: inventoryUC,
+	}
+}
+
+// GetStock godoc
+// @Summary Get stock level
+// @Description get on hand quantity and low stock threshold of SKU, admin only
+// @Tags Inventory
+// @Produce json
+// @Param sku path string true "sku"
+// @Success 200 {object} models.StockLevel
+// @Failure 404 {object} httpErrors.RestError
+// @Router /inventory/{sku} [get]
+func (h *inventoryHandlers) GetStock() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		stock, err := h.inventoryUC.GetStock(ctx, c.Param("sku"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, stock)
+	}
+}
+
+// RecordMovement godoc
+// @Summary Record stock movement
+// @Description receive, sell, return or adjust stock of SKU, quantity is a signed delta, admin only
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param sku path string true "sku"
+// @Success 201 {object} models.StockMovement
+// @Failure 409 {object} httpErrors.RestError
+// @Router /inventory/{sku}/movements [post]
+func (h *inventoryHandlers) RecordMovement() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		movement := &models.StockMovement{}
+		if err := utils.ReadRequest(c, movement); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		movement.SKU = c.Param("sku")
+		movement.ActorUserID = nil
+
+		createdMovement, err := h.inventoryUC.RecordMovement(ctx, movement)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, createdMovement)
+	}
+}
+
+// ListMovements godoc
+// @Summary Get stock movements
+// @Description get stock movement history of SKU, newest first, admin only
+// @Tags Inventory
+// @Produce json
+// @Param sku path string true "sku"
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.StockMovementsList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /inventory/{sku}/movements [get]
+func (h *inventoryHandlers) ListMovements() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		movementsList, err := h.inventoryUC.ListMovements(ctx, c.Param("sku"), pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, movementsList)
+	}
+}
+
+// SetThreshold godoc
+// @Summary Set low stock threshold
+// @Description set low stock threshold of SKU, admin only
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param sku path string true "sku"
+// @Success 200 {object} models.StockLevel
+// @Failure 500 {object} httpErrors.RestError
+// @Router /inventory/{sku}/threshold [put]
+func (h *inventoryHandlers) SetThreshold() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		threshold := &models.StockThreshold{}
+		if err := utils.ReadRequest(c, threshold); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		stock, err := h.inventoryUC.SetThreshold(ctx, c.Param("sku"), threshold.LowStockThreshold)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, stock)
+	}
+}
+
+// ListLowStock godoc
+// @Summary Get low stock SKUs
+// @Description get SKUs at or below their low stock threshold, admin only
+// @Tags Inventory
+// @Produce json
+// @Success 200 {array} models.StockLevel
+// @Failure 500 {object} httpErrors.RestError
+// @Router /inventory/low-stock [get]
+func (h *inventoryHandlers) ListLowStock() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		levels, err := h.inventoryUC.ListLowStock(ctx)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, levels)
+	}
+}
@@ -0,0 +1,18 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/inventory"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func MapInventoryRoutes(inventoryGroup *echo.Group, h inventory.Handlers, mw *middleware.MiddlewareManager) {
+	inventoryGroup.Use(mw.AuthJWTMiddleware)
+	inventoryGroup.Use(mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin}))
+	inventoryGroup.GET("/low-stock", h.ListLowStock())
+	inventoryGroup.GET("/:sku", h.GetStock())
+	inventoryGroup.GET("/:sku/movements", h.ListMovements())
+	inventoryGroup.POST("/:sku/movements", h.RecordMovement())
+	inventoryGroup.PUT("/:sku/threshold", h.SetThreshold())
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fekuna/go-store/internal/inventory"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Inventory repository
+type inventoryRepo struct {
+	db *sqlx.DB
+}
+
+// Inventory repository constructor
+func NewInventoryRepository(db *sqlx.DB) inventory.Repository {
+	return &inventoryRepo{db: db}
+}
+
+func (r *inventoryRepo) GetStock(ctx context.Context, sku string) (*models.StockLevel, error) {
+	stock := &models.StockLevel{}
+	if err := r.db.GetContext(ctx, stock, getStockQuery, sku); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.GetStock.GetContext")
+	}
+
+	return stock, nil
+}
+
+// Apply movement to on hand stock and append it to the ledger in one transaction
+func (r *inventoryRepo) RecordMovement(ctx context.Context, m *models.StockMovement) (*models.StockMovement, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.RecordMovement.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, ensureStockLevelQuery, &m.SKU); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.RecordMovement.ExecContext.ensureStockLevel")
+	}
+
+	var onHand int
+	if err = tx.GetContext(ctx, &onHand, applyMovementQuery, &m.SKU, &m.Quantity); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(httpErrors.InsufficientStock, "inventoryRepo.RecordMovement: sku %s", m.SKU)
+		}
+		return nil, errors.Wrap(err, "inventoryRepo.RecordMovement.GetContext.applyMovement")
+	}
+
+	created := &models.StockMovement{}
+	if err = tx.QueryRowxContext(ctx, createMovementQuery, &m.SKU, &m.MovementType, &m.Quantity, onHand,
+		&m.Reason, m.ActorUserID, m.ReferenceID,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.RecordMovement.StructScan")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.RecordMovement.Commit")
+	}
+
+	return created, nil
+}
+
+func (r *inventoryRepo) ListMovements(ctx context.Context, sku string, pq *utils.PaginationQuery) (*models.StockMovementsList, error) {
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalMovementsQuery, sku); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.ListMovements.GetContext.totalCount")
+	}
+
+	movements := make([]*models.StockMovement, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &movements, listMovementsQuery, sku, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "inventoryRepo.ListMovements.SelectContext")
+		}
+	}
+
+	return &models.StockMovementsList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Movements:  movements,
+	}, nil
+}
+
+func (r *inventoryRepo) SetThreshold(ctx context.Context, sku string, threshold int) (*models.StockLevel, error) {
+	stock := &models.StockLevel{}
+	if err := r.db.GetContext(ctx, stock, setThresholdQuery, sku, threshold); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.SetThreshold.GetContext")
+	}
+
+	return stock, nil
+}
+
+func (r *inventoryRepo) ListLowStock(ctx context.Context) ([]*models.StockLevel, error) {
+	levels := make([]*models.StockLevel, 0)
+	if err := r.db.SelectContext(ctx, &levels, listLowStockQuery); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.ListLowStock.SelectContext")
+	}
+
+	return levels, nil
+}
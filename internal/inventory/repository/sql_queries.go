@@ -0,0 +1,56 @@
+package repository
+
+const (
+	stockLevelColumns = `sku, on_hand, reserved, on_hand - reserved AS available, low_stock_threshold, on_hand <= low_stock_threshold AS is_low_stock, updated_at`
+
+	getStockQuery = `
+		SELECT v.sku,
+			COALESCE(s.on_hand, 0) AS on_hand,
+			COALESCE(s.reserved, 0) AS reserved,
+			COALESCE(s.on_hand - s.reserved, 0) AS available,
+			COALESCE(s.low_stock_threshold, 0) AS low_stock_threshold,
+			COALESCE(s.on_hand, 0) <= COALESCE(s.low_stock_threshold, 0) AS is_low_stock,
+			COALESCE(s.updated_at, v.updated_at) AS updated_at
+		FROM product_variants v
+		LEFT JOIN stock_levels s ON s.sku = v.sku
+		WHERE v.sku = $1
+	`
+
+	ensureStockLevelQuery = `INSERT INTO stock_levels(sku) VALUES ($1) ON CONFLICT (sku) DO NOTHING`
+
+	// Row lock on the stock level serializes concurrent movements, the guard is re-checked after the lock is acquired.
+	// Stock reserved by pending orders can not be taken out.
+	applyMovementQuery = `
+		UPDATE stock_levels
+		SET on_hand = on_hand + $2, updated_at = now()
+		WHERE sku = $1 AND on_hand + $2 >= reserved
+		RETURNING on_hand
+	`
+
+	createMovementQuery = `
+		INSERT INTO stock_movements(sku, movement_type, quantity, on_hand_after, reason, actor_user_id, reference_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, now())
+		RETURNING *
+	`
+
+	getTotalMovementsQuery = `SELECT COUNT(movement_id) FROM stock_movements WHERE sku = $1`
+
+	listMovementsQuery = `
+		SELECT * FROM stock_movements
+		WHERE sku = $1
+		ORDER BY created_at DESC, movement_id
+		OFFSET $2 LIMIT $3
+	`
+
+	setThresholdQuery = `
+		INSERT INTO stock_levels(sku, low_stock_threshold) VALUES ($1, $2)
+		ON CONFLICT (sku) DO UPDATE SET low_stock_threshold = EXCLUDED.low_stock_threshold, updated_at = now()
+		RETURNING ` + stockLevelColumns
+
+	listLowStockQuery = `
+		SELECT ` + stockLevelColumns + `
+		FROM stock_levels
+		WHERE on_hand <= low_stock_threshold
+		ORDER BY on_hand - low_stock_threshold, sku
+	`
+)
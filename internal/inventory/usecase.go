@@ -0,0 +1,17 @@
+package inventory
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+)
+
+// Inventory UseCase
+type UseCase interface {
+	GetStock(ctx context.Context, sku string) (*models.StockLevel, error)
+	RecordMovement(ctx context.Context, movement *models.StockMovement) (*models.StockMovement, error)
+	ListMovements(ctx context.Context, sku string, pq *utils.PaginationQuery) (*models.StockMovementsList, error)
+	SetThreshold(ctx context.Context, sku string, threshold int) (*models.StockLevel, error)
+	ListLowStock(ctx context.Context) ([]*models.StockLevel, error)
+}
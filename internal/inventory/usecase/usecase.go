@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/inventory"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+// Inventory UseCase
+type inventoryUC struct {
+	cfg           *config.Config
+	logger        logger.Logger
+	inventoryRepo inventory.Repository
+}
+
+// Inventory UseCase constructor
+func NewInventoryUseCase(cfg *config.Config, logger logger.Logger, inventoryRepo inventory.Repository) inventory.UseCase {
+	return &inventoryUC{
+		cfg:           cfg,
+		logger:        logger,
+		inventoryRepo: inventoryRepo,
+	}
+}
+
+func (u *inventoryUC) GetStock(ctx context.Context, sku string) (*models.StockLevel, error) {
+	// TODO: Tracing
+
+	return u.inventoryRepo.GetStock(ctx, normalizeSKU(sku))
+}
+
+// Record stock movement on behalf of the user in context, if any
+func (u *inventoryUC) RecordMovement(ctx context.Context, m *models.StockMovement) (*models.StockMovement, error) {
+	// TODO: Tracing
+
+	m.SKU = normalizeSKU(m.SKU)
+	m.Reason = strings.TrimSpace(m.Reason)
+
+	if !m.IsValidDirection() {
+		return nil, httpErrors.NewBadRequestError(errors.Errorf("inventoryUC.RecordMovement: quantity %d is not valid for %s", m.Quantity, m.MovementType))
+	}
+
+	if m.ActorUserID == nil {
+		if user, err := middleware.GetUserFromCtx(ctx); err == nil {
+			m.ActorUserID = &user.UserID
+		}
+	}
+
+	// Make sure SKU exists before the ledger is touched
+	if _, err := u.inventoryRepo.GetStock(ctx, m.SKU); err != nil {
+		return nil, err
+	}
+
+	movement, err := u.inventoryRepo.RecordMovement(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	if stock, err := u.inventoryRepo.GetStock(ctx, m.SKU); err == nil && stock.IsLowStock {
+		u.logger.Warnf("inventoryUC.RecordMovement: low stock, SKU: %s, OnHand: %d, Threshold: %d", stock.SKU, stock.OnHand, stock.LowStockThreshold)
+	}
+
+	return movement, nil
+}
+
+func (u *inventoryUC) ListMovements(ctx context.Context, sku string, pq *utils.PaginationQuery) (*models.StockMovementsList, error) {
+	// TODO: Tracing
+
+	return u.inventoryRepo.ListMovements(ctx, normalizeSKU(sku), pq)
+}
+
+func (u *inventoryUC) SetThreshold(ctx context.Context, sku string, threshold int) (*models.StockLevel, error) {
+	// TODO: Tracing
+
+	sku = normalizeSKU(sku)
+	if _, err := u.inventoryRepo.GetStock(ctx, sku); err != nil {
+		return nil, err
+	}
+
+	return u.inventoryRepo.SetThreshold(ctx, sku, threshold)
+}
+
+func (u *inventoryUC) ListLowStock(ctx context.Context) ([]*models.StockLevel, error) {
+	// TODO: Tracing
+
+	return u.inventoryRepo.ListLowStock(ctx)
+}
+
+func normalizeSKU(sku string) string {
+	return strings.ToUpper(strings.TrimSpace(sku))
+}
@@ -0,0 +1,12 @@
+package invoice
+
+import "github.com/labstack/echo/v4"
+
+// Invoice HTTP Handlers interface
+type Handlers interface {
+	IssueForOrder() echo.HandlerFunc
+	IssueCreditNote() echo.HandlerFunc
+	GetByID() echo.HandlerFunc
+	ListByOrder() echo.HandlerFunc
+	Download() echo.HandlerFunc
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/invoice"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Invoice handlers
+type invoiceHandlers struct {
+	cfg       *config.Config
+	logger    logger.Logger
+	invoiceUC invoice.UseCase
+}
+
+// Invoice handlers constructor
+func NewInvoiceHandlers(cfg *config.Config, logger logger.Logger, invoiceUC invoice.UseCase) invoice.Handlers {
+	return &invoiceHandlers{
+		cfg:       cfg,
+		logger:    logger,
+		invoiceUC: invoiceUC,
+	}
+}
+
+// IssueForOrder godoc
+// @Summary Issue order invoice
+// @Description issue invoice of own paid order with the next invoice number, returns the existing invoice when the order has one
+// @Tags Invoices
+// @Produce json
+// @Param order_id path string true "order_id"
+// @Success 200 {object} models.Invoice
+// @Failure 409 {object} httpErrors.RestError
+// @Router /invoices/orders/{order_id} [post]
+func (h *invoiceHandlers) IssueForOrder() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		orderID, err := uuid.Parse(c.Param("order_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		inv, err := h.invoiceUC.IssueForOrder(ctx, orderID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, inv)
+	}
+}
+
+// IssueCreditNote godoc
+// @Summary Issue return credit note
+// @Description issue credit note of refunded return with the next credit note number, returns the existing credit note when the return has one, admin only
+// @Tags Invoices
+// @Produce json
+// @Param return_id path string true "return_id"
+// @Success 200 {object} models.Invoice
+// @Failure 409 {object} httpErrors.RestError
+// @Router /invoices/returns/{return_id} [post]
+func (h *invoiceHandlers) IssueCreditNote() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		returnID, err := uuid.Parse(c.Param("return_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		inv, err := h.invoiceUC.IssueCreditNote(ctx, returnID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, inv)
+	}
+}
+
+// GetByID godoc
+// @Summary Get invoice by id
+// @Description get own invoice or credit note with its lines, admins can get any document
+// @Tags Invoices
+// @Produce json
+// @Param invoice_id path string true "invoice_id"
+// @Success 200 {object} models.Invoice
+// @Failure 404 {object} httpErrors.RestError
+// @Router /invoices/{invoice_id} [get]
+func (h *invoiceHandlers) GetByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		invoiceID, err := uuid.Parse(c.Param("invoice_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		inv, err := h.invoiceUC.GetByID(ctx, invoiceID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, inv)
+	}
+}
+
+// ListByOrder godoc
+// @Summary Get order invoices
+// @Description get invoice and credit notes of own order, admins can get any order
+// @Tags Invoices
+// @Produce json
+// @Param order_id path string true "order_id"
+// @Success 200 {array} models.Invoice
+// @Failure 404 {object} httpErrors.RestError
+// @Router /invoices/orders/{order_id} [get]
+func (h *invoiceHandlers) ListByOrder() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		orderID, err := uuid.Parse(c.Param("order_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		invoices, err := h.invoiceUC.ListByOrder(ctx, orderID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, invoices)
+	}
+}
+
+// Download godoc
+// @Summary Download invoice
+// @Description get short lived presigned link to the PDF of own invoice or credit note, admins can get any document
+// @Tags Invoices
+// @Produce json
+// @Param invoice_id path string true "invoice_id"
+// @Success 200 {object} models.InvoiceDownload
+// @Failure 404 {object} httpErrors.RestError
+// @Router /invoices/{invoice_id}/download [get]
+func (h *invoiceHandlers) Download() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		invoiceID, err := uuid.Parse(c.Param("invoice_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		download, err := h.invoiceUC.Download(ctx, invoiceID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, download)
+	}
+}
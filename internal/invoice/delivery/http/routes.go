@@ -0,0 +1,18 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/invoice"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func MapInvoiceRoutes(invoiceGroup *echo.Group, h invoice.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	invoiceGroup.POST("/orders/:order_id", h.IssueForOrder(), mw.AuthJWTMiddleware)
+	invoiceGroup.GET("/orders/:order_id", h.ListByOrder(), mw.AuthJWTMiddleware)
+	invoiceGroup.POST("/returns/:return_id", h.IssueCreditNote(), adminOnly...)
+	invoiceGroup.GET("/:invoice_id", h.GetByID(), mw.AuthJWTMiddleware)
+	invoiceGroup.GET("/:invoice_id/download", h.Download(), mw.AuthJWTMiddleware)
+}
@@ -0,0 +1,16 @@
+package invoice
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/minio/minio-go/v7"
+)
+
+// Minio S3 interface for invoice files
+type MinioRepository interface {
+	PutObject(ctx context.Context, input models.UploadInput) (*minio.UploadInfo, error)
+	GetObjectUrl(ctx context.Context, bucket string, key string, expires time.Duration) (*url.URL, error)
+}
@@ -0,0 +1,19 @@
+package invoice
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Invoice repository
+type Repository interface {
+	Issue(ctx context.Context, inv *models.Invoice) (*models.Invoice, error)
+	GetByID(ctx context.Context, invoiceID uuid.UUID) (*models.Invoice, error)
+	GetByOrder(ctx context.Context, orderID uuid.UUID) (*models.Invoice, error)
+	GetByReturn(ctx context.Context, returnID uuid.UUID) (*models.Invoice, error)
+	ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.Invoice, error)
+	GetLines(ctx context.Context, invoiceID uuid.UUID) ([]*models.InvoiceLine, error)
+	SetObjectKey(ctx context.Context, invoiceID uuid.UUID, objectKey string) error
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/fekuna/go-store/internal/invoice"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+)
+
+// Invoice Minio S3 repository
+type invoiceMinioRepository struct {
+	client *minio.Client
+}
+
+// Invoice Minio S3 repository constructor
+func NewInvoiceMinioRepository(minioClient *minio.Client) invoice.MinioRepository {
+	return &invoiceMinioRepository{client: minioClient}
+}
+
+// Upload file to Minio, input name is used as object key
+func (r *invoiceMinioRepository) PutObject(ctx context.Context, input models.UploadInput) (*minio.UploadInfo, error) {
+	// TODO: Tracing
+
+	uploadInfo, err := r.client.PutObject(ctx, input.BucketName, input.Name, input.File, input.Size, minio.PutObjectOptions{ContentType: input.ContentType})
+	if err != nil {
+		return nil, errors.Wrap(err, "invoiceMinioRepository.PutObject")
+	}
+
+	return &uploadInfo, nil
+}
+
+// Presigned download url of object, the file is saved under the last part of its key
+func (r *invoiceMinioRepository) GetObjectUrl(ctx context.Context, bucket string, key string, expires time.Duration) (*url.URL, error) {
+	// TODO: Tracing
+
+	reqParams := url.Values{}
+	reqParams.Set("response-content-disposition", fmt.Sprintf("attachment; filename=%q", path.Base(key)))
+
+	objectUrl, err := r.client.PresignedGetObject(ctx, bucket, key, expires, reqParams)
+	if err != nil {
+		return nil, errors.Wrap(err, "invoiceMinioRepository.GetObjectUrl")
+	}
+
+	return objectUrl, nil
+}
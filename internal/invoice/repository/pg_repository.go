@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fekuna/go-store/internal/invoice"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Invoice repository
+type invoiceRepo struct {
+	db *sqlx.DB
+}
+
+// Invoice repository constructor
+func NewInvoiceRepository(db *sqlx.DB) invoice.Repository {
+	return &invoiceRepo{db: db}
+}
+
+// Issue document with the next number of its store and type while its order is locked. An order has one invoice
+// and a return one credit note, when the document was already issued that one is returned unchanged.
+func (r *invoiceRepo) Issue(ctx context.Context, inv *models.Invoice) (*models.Invoice, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "invoiceRepo.Issue.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	var lockedID uuid.UUID
+	if err = tx.GetContext(ctx, &lockedID, lockOrderQuery, inv.OrderID); err != nil {
+		return nil, errors.Wrap(err, "invoiceRepo.Issue.GetContext.lockOrder")
+	}
+
+	existing := &models.Invoice{}
+	if inv.Type == models.InvoiceTypeCreditNote {
+		err = tx.GetContext(ctx, existing, getCreditNoteByReturnQuery, inv.ReturnID)
+	} else {
+		err = tx.GetContext(ctx, existing, getInvoiceByOrderQuery, inv.OrderID)
+	}
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "invoiceRepo.Issue.GetContext.existing")
+	}
+
+	var sequenceNumber int64
+	if err = tx.GetContext(ctx, &sequenceNumber, nextSequenceNumberQuery, &inv.StoreCode, &inv.Type); err != nil {
+		return nil, errors.Wrap(err, "invoiceRepo.Issue.GetContext.nextSequenceNumber")
+	}
+
+	created := &models.Invoice{}
+	if err = tx.QueryRowxContext(
+		ctx,
+		createInvoiceQuery,
+		&inv.StoreCode,
+		&inv.Type,
+		sequenceNumber,
+		models.FormatInvoiceNumber(inv.StoreCode, inv.Type, sequenceNumber),
+		&inv.OrderID,
+		inv.ReturnID,
+		inv.OriginalInvoiceID,
+		inv.UserID,
+		&inv.Email,
+		inv.BillTo,
+		&inv.Currency,
+		&inv.PricesIncludeTax,
+		&inv.Subtotal,
+		&inv.DiscountTotal,
+		&inv.ShippingTotal,
+		&inv.TaxTotal,
+		&inv.Total,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "invoiceRepo.Issue.StructScan.invoice")
+	}
+
+	created.Lines = make([]*models.InvoiceLine, 0, len(inv.Lines))
+	for i, line := range inv.Lines {
+		createdLine := &models.InvoiceLine{}
+		if err = tx.QueryRowxContext(
+			ctx,
+			createInvoiceLineQuery,
+			created.InvoiceID,
+			i+1,
+			&line.SKU,
+			&line.Description,
+			&line.Quantity,
+			&line.UnitPrice,
+			&line.TaxRatePPM,
+			&line.TaxAmount,
+			&line.LineTotal,
+		).StructScan(createdLine); err != nil {
+			return nil, errors.Wrap(err, "invoiceRepo.Issue.StructScan.line")
+		}
+		created.Lines = append(created.Lines, createdLine)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "invoiceRepo.Issue.Commit")
+	}
+
+	return created, nil
+}
+
+func (r *invoiceRepo) GetByID(ctx context.Context, invoiceID uuid.UUID) (*models.Invoice, error) {
+	// TODO: Tracing
+
+	inv := &models.Invoice{}
+	if err := r.db.GetContext(ctx, inv, getInvoiceByIdQuery, invoiceID); err != nil {
+		return nil, errors.Wrap(err, "invoiceRepo.GetByID.GetContext")
+	}
+
+	return inv, nil
+}
+
+// Invoice of the order, not its credit notes
+func (r *invoiceRepo) GetByOrder(ctx context.Context, orderID uuid.UUID) (*models.Invoice, error) {
+	// TODO: Tracing
+
+	inv := &models.Invoice{}
+	if err := r.db.GetContext(ctx, inv, getInvoiceByOrderQuery, orderID); err != nil {
+		return nil, errors.Wrap(err, "invoiceRepo.GetByOrder.GetContext")
+	}
+
+	return inv, nil
+}
+
+// Credit note of the return
+func (r *invoiceRepo) GetByReturn(ctx context.Context, returnID uuid.UUID) (*models.Invoice, error) {
+	// TODO: Tracing
+
+	inv := &models.Invoice{}
+	if err := r.db.GetContext(ctx, inv, getCreditNoteByReturnQuery, returnID); err != nil {
+		return nil, errors.Wrap(err, "invoiceRepo.GetByReturn.GetContext")
+	}
+
+	return inv, nil
+}
+
+// Invoice and credit notes of the order in issue order
+func (r *invoiceRepo) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.Invoice, error) {
+	// TODO: Tracing
+
+	invoices := make([]*models.Invoice, 0)
+	if err := r.db.SelectContext(ctx, &invoices, listInvoicesByOrderQuery, orderID); err != nil {
+		return nil, errors.Wrap(err, "invoiceRepo.ListByOrder.SelectContext")
+	}
+
+	return invoices, nil
+}
+
+func (r *invoiceRepo) GetLines(ctx context.Context, invoiceID uuid.UUID) ([]*models.InvoiceLine, error) {
+	// TODO: Tracing
+
+	lines := make([]*models.InvoiceLine, 0)
+	if err := r.db.SelectContext(ctx, &lines, getInvoiceLinesQuery, invoiceID); err != nil {
+		return nil, errors.Wrap(err, "invoiceRepo.GetLines.SelectContext")
+	}
+
+	return lines, nil
+}
+
+// Record key of the stored PDF
+func (r *invoiceRepo) SetObjectKey(ctx context.Context, invoiceID uuid.UUID, objectKey string) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, setInvoiceObjectKeyQuery, objectKey, invoiceID)
+	if err != nil {
+		return errors.Wrap(err, "invoiceRepo.SetObjectKey.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "invoiceRepo.SetObjectKey.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "invoiceRepo.SetObjectKey.rowsAffected")
+	}
+
+	return nil
+}
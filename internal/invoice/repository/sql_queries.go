@@ -0,0 +1,41 @@
+package repository
+
+const (
+	// Serializes issuing documents of the same order
+	lockOrderQuery = `SELECT order_id FROM orders WHERE order_id = $1 FOR UPDATE`
+
+	// Takes the next number, the row stays locked until the document is committed
+	nextSequenceNumberQuery = `
+		INSERT INTO invoice_sequences(store_code, type, last_number)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (store_code, type) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+		RETURNING last_number
+	`
+
+	createInvoiceQuery = `
+		INSERT INTO invoices(store_code, type, sequence_number, number, order_id, return_id, original_invoice_id,
+			user_id, email, bill_to, currency, prices_include_tax, subtotal, discount_total, shipping_total, tax_total,
+			total, issued_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, now())
+		RETURNING *
+	`
+
+	createInvoiceLineQuery = `
+		INSERT INTO invoice_lines(invoice_id, position, sku, description, quantity, unit_price, tax_rate_ppm,
+			tax_amount, line_total)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING *
+	`
+
+	getInvoiceByIdQuery = `SELECT * FROM invoices WHERE invoice_id = $1`
+
+	getInvoiceByOrderQuery = `SELECT * FROM invoices WHERE order_id = $1 AND type = 'invoice'`
+
+	getCreditNoteByReturnQuery = `SELECT * FROM invoices WHERE return_id = $1 AND type = 'credit_note'`
+
+	listInvoicesByOrderQuery = `SELECT * FROM invoices WHERE order_id = $1 ORDER BY issued_at, number`
+
+	getInvoiceLinesQuery = `SELECT * FROM invoice_lines WHERE invoice_id = $1 ORDER BY position`
+
+	setInvoiceObjectKeyQuery = `UPDATE invoices SET object_key = $1 WHERE invoice_id = $2`
+)
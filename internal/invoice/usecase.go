@@ -0,0 +1,17 @@
+package invoice
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Invoice UseCase, customers see documents of their own orders and admins see every document
+type UseCase interface {
+	IssueForOrder(ctx context.Context, orderID uuid.UUID) (*models.Invoice, error)
+	IssueCreditNote(ctx context.Context, returnID uuid.UUID) (*models.Invoice, error)
+	GetByID(ctx context.Context, invoiceID uuid.UUID) (*models.Invoice, error)
+	ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.Invoice, error)
+	Download(ctx context.Context, invoiceID uuid.UUID) (*models.InvoiceDownload, error)
+}
@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/pdf"
+	"github.com/google/uuid"
+)
+
+// Table of invoices and credit notes, 495 points wide
+var documentColumns = []pdf.Column{
+	{Title: "Description", Width: 215},
+	{Title: "Qty", Width: 40, AlignRight: true},
+	{Title: "Unit price", Width: 80, AlignRight: true},
+	{Title: "Tax", Width: 60, AlignRight: true},
+	{Title: "Amount", Width: 100, AlignRight: true},
+}
+
+var invoiceTemplate = &pdf.Template{
+	Title:      "Invoice",
+	SellerHead: "From",
+	PartyHead:  "Bill to",
+	Columns:    documentColumns,
+}
+
+var creditNoteTemplate = &pdf.Template{
+	Title:      "Credit note",
+	SellerHead: "From",
+	PartyHead:  "Credit to",
+	Columns:    documentColumns,
+}
+
+// Invoice of the order with a line per item, the order discount and shipping
+func orderInvoice(storeCode string, o *models.Order, items []*models.OrderItem, discounts []*models.OrderDiscount) *models.Invoice {
+	inv := &models.Invoice{
+		StoreCode:        storeCode,
+		Type:             models.InvoiceTypeInvoice,
+		OrderID:          o.OrderID,
+		UserID:           o.UserID,
+		Email:            o.Email,
+		Currency:         o.Currency,
+		PricesIncludeTax: o.PricesIncludeTax,
+		Subtotal:         o.Subtotal,
+		DiscountTotal:    o.DiscountTotal,
+		ShippingTotal:    o.ShippingTotal,
+		TaxTotal:         o.TaxTotal,
+		Total:            o.Total,
+		Lines:            make([]*models.InvoiceLine, 0, len(items)+2),
+	}
+
+	for _, item := range items {
+		inv.Lines = append(inv.Lines, &models.InvoiceLine{
+			SKU:         item.SKU,
+			Description: itemDescription(item),
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			TaxRatePPM:  item.TaxRatePPM,
+			TaxAmount:   item.TaxAmount,
+			LineTotal:   item.LineTotal,
+		})
+	}
+
+	if o.DiscountTotal > 0 {
+		names := make([]string, 0, len(discounts))
+		for _, d := range discounts {
+			if d.Amount > 0 {
+				names = append(names, d.Description)
+			}
+		}
+
+		description := "Discount"
+		if len(names) > 0 {
+			description += ": " + strings.Join(names, ", ")
+		}
+		inv.Lines = append(inv.Lines, &models.InvoiceLine{
+			Description: description,
+			Quantity:    1,
+			UnitPrice:   -o.DiscountTotal,
+			LineTotal:   -o.DiscountTotal,
+		})
+	}
+
+	if o.ShippingMethod != "" || o.ShippingTotal > 0 {
+		description := "Shipping"
+		if o.ShippingMethod != "" {
+			description += ": " + o.ShippingMethod
+		}
+		inv.Lines = append(inv.Lines, &models.InvoiceLine{
+			Description: description,
+			Quantity:    1,
+			UnitPrice:   o.ShippingTotal,
+			LineTotal:   o.ShippingTotal,
+		})
+	}
+
+	return inv
+}
+
+// Credit note of the refunded return with a line per refunded item. Refunds are paid prices so tax is included,
+// a refund amount set by hand that differs from the items gets an adjustment line.
+func creditNote(original *models.Invoice, ret *models.Return, items []*models.ReturnItem, orderItems []*models.OrderItem) *models.Invoice {
+	byID := make(map[uuid.UUID]*models.OrderItem, len(orderItems))
+	for _, item := range orderItems {
+		byID[item.OrderItemID] = item
+	}
+
+	cn := &models.Invoice{
+		StoreCode:         original.StoreCode,
+		Type:              models.InvoiceTypeCreditNote,
+		OrderID:           original.OrderID,
+		ReturnID:          &ret.ReturnID,
+		OriginalInvoiceID: &original.InvoiceID,
+		UserID:            original.UserID,
+		Email:             original.Email,
+		BillTo:            original.BillTo,
+		Currency:          ret.Currency,
+		PricesIncludeTax:  true,
+		Subtotal:          ret.RefundAmount,
+		Total:             ret.RefundAmount,
+		Lines:             make([]*models.InvoiceLine, 0, len(items)+1),
+	}
+
+	var credited int64
+	for _, item := range items {
+		if item.ReceivedQuantity == 0 || item.RefundAmount == 0 {
+			continue
+		}
+
+		line := &models.InvoiceLine{
+			SKU:         item.SKU,
+			Description: item.SKU,
+			Quantity:    item.ReceivedQuantity,
+			UnitPrice:   item.RefundAmount / int64(item.ReceivedQuantity),
+			LineTotal:   item.RefundAmount,
+		}
+		if orderItem, ok := byID[item.OrderItemID]; ok && orderItem.Quantity > 0 {
+			line.Description = itemDescription(orderItem)
+			line.TaxRatePPM = orderItem.TaxRatePPM
+			line.TaxAmount = orderItem.TaxAmount * int64(item.ReceivedQuantity) / int64(orderItem.Quantity)
+		}
+
+		cn.Lines = append(cn.Lines, line)
+		cn.TaxTotal += line.TaxAmount
+		credited += line.LineTotal
+	}
+
+	if adjustment := ret.RefundAmount - credited; adjustment != 0 {
+		cn.Lines = append(cn.Lines, &models.InvoiceLine{
+			Description: "Refund adjustment",
+			Quantity:    1,
+			UnitPrice:   adjustment,
+			LineTotal:   adjustment,
+		})
+	}
+
+	return cn
+}
+
+// PDF of an invoice, credit notes also get the invoice they correct
+func render(cfg *config.Config, inv *models.Invoice, original *models.Invoice) ([]byte, error) {
+	tpl := invoiceTemplate
+	details := []pdf.Field{
+		{Label: "Number", Value: inv.Number},
+		{Label: "Date", Value: inv.IssuedAt.Format("2006-01-02")},
+		{Label: "Order", Value: inv.OrderID.String()},
+	}
+	if original != nil {
+		tpl = creditNoteTemplate
+		details = append(details, pdf.Field{Label: "Credits invoice", Value: original.Number})
+	}
+
+	seller := append([]string{cfg.Invoice.SellerName}, cfg.Invoice.SellerAddress...)
+	if cfg.Invoice.SellerTaxID != "" {
+		seller = append(seller, "Tax ID: "+cfg.Invoice.SellerTaxID)
+	}
+
+	rows := make([][]string, 0, len(inv.Lines))
+	for _, line := range inv.Lines {
+		tax := ""
+		if line.TaxRatePPM > 0 {
+			tax = formatRate(line.TaxRatePPM)
+		}
+		rows = append(rows, []string{
+			line.Description,
+			strconv.Itoa(line.Quantity),
+			formatAmount(line.UnitPrice, inv.Currency),
+			tax,
+			formatAmount(line.LineTotal, inv.Currency),
+		})
+	}
+
+	return tpl.Render(&pdf.Data{
+		Details: details,
+		Seller:  seller,
+		Party:   partyLines(inv),
+		Rows:    rows,
+		Totals:  totals(inv),
+		Notes:   notes(inv, original),
+	})
+}
+
+func totals(inv *models.Invoice) []pdf.Field {
+	taxLabel := "Tax"
+	if inv.PricesIncludeTax {
+		taxLabel = "Included tax"
+	}
+
+	if inv.Type == models.InvoiceTypeCreditNote {
+		return []pdf.Field{
+			{Label: taxLabel, Value: formatAmount(inv.TaxTotal, inv.Currency)},
+			{Label: "Total credited", Value: formatAmount(inv.Total, inv.Currency)},
+		}
+	}
+
+	fields := []pdf.Field{{Label: "Subtotal", Value: formatAmount(inv.Subtotal, inv.Currency)}}
+	if inv.DiscountTotal > 0 {
+		fields = append(fields, pdf.Field{Label: "Discount", Value: formatAmount(-inv.DiscountTotal, inv.Currency)})
+	}
+	if inv.ShippingTotal > 0 {
+		fields = append(fields, pdf.Field{Label: "Shipping", Value: formatAmount(inv.ShippingTotal, inv.Currency)})
+	}
+
+	return append(fields,
+		pdf.Field{Label: taxLabel, Value: formatAmount(inv.TaxTotal, inv.Currency)},
+		pdf.Field{Label: "Total", Value: formatAmount(inv.Total, inv.Currency)},
+	)
+}
+
+func notes(inv *models.Invoice, original *models.Invoice) []string {
+	if original != nil {
+		return []string{fmt.Sprintf("This credit note corrects invoice %s of %s.", original.Number, original.IssuedAt.Format("2006-01-02"))}
+	}
+	if inv.PricesIncludeTax {
+		return []string{"Prices include tax."}
+	}
+
+	return nil
+}
+
+// Address block of the customer, the email alone for orders without address. Region is left out when it is the
+// city, like on addresses saved without region.
+func partyLines(inv *models.Invoice) []string {
+	lines := make([]string, 0, 7)
+	if a := inv.BillTo; a != nil {
+		if a.Name != "" {
+			lines = append(lines, a.Name)
+		}
+		if a.Company != nil {
+			lines = append(lines, *a.Company)
+		}
+		if a.Line1 != "" {
+			lines = append(lines, a.Line1)
+		}
+		if a.Line2 != nil {
+			lines = append(lines, *a.Line2)
+		}
+		place := make([]string, 0, 4)
+		for _, part := range []string{a.Postcode, a.City, a.Region, a.Country} {
+			if part != "" && (len(place) == 0 || place[len(place)-1] != part) {
+				place = append(place, part)
+			}
+		}
+		lines = append(lines, strings.Join(place, " "))
+	}
+
+	return append(lines, inv.Email)
+}
+
+func itemDescription(item *models.OrderItem) string {
+	if item.VariantTitle == "" {
+		return item.Title
+	}
+
+	return item.Title + " - " + item.VariantTitle
+}
+
+// Minor units as a decimal amount with currency
+func formatAmount(amount int64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	return fmt.Sprintf("%s%d.%02d %s", sign, amount/100, amount%100, currency)
+}
+
+// Rate in parts per million as percent, like 11%
+func formatRate(ppm int64) string {
+	return strconv.FormatFloat(float64(ppm)/10000, 'f', -1, 64) + "%"
+}
@@ -0,0 +1,296 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/address"
+	"github.com/fekuna/go-store/internal/invoice"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/order"
+	"github.com/fekuna/go-store/internal/returns"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Order statuses that have been paid for and can be invoiced
+var invoiceableStatuses = map[string]bool{
+	models.OrderStatusPaid:      true,
+	models.OrderStatusFulfilled: true,
+	models.OrderStatusShipped:   true,
+	models.OrderStatusDelivered: true,
+	models.OrderStatusRefunded:  true,
+}
+
+// Invoice UseCase
+type invoiceUC struct {
+	cfg         *config.Config
+	logger      logger.Logger
+	invoiceRepo invoice.Repository
+	minioRepo   invoice.MinioRepository
+	orderRepo   order.Repository
+	orderUC     order.UseCase
+	returnRepo  returns.Repository
+	addressRepo address.Repository
+}
+
+// Invoice UseCase constructor
+func NewInvoiceUseCase(cfg *config.Config, logger logger.Logger, invoiceRepo invoice.Repository, minioRepo invoice.MinioRepository, orderRepo order.Repository, orderUC order.UseCase, returnRepo returns.Repository, addressRepo address.Repository) invoice.UseCase {
+	return &invoiceUC{
+		cfg:         cfg,
+		logger:      logger,
+		invoiceRepo: invoiceRepo,
+		minioRepo:   minioRepo,
+		orderRepo:   orderRepo,
+		orderUC:     orderUC,
+		returnRepo:  returnRepo,
+		addressRepo: addressRepo,
+	}
+}
+
+// Invoice of own paid order, issued on the first request
+func (u *invoiceUC) IssueForOrder(ctx context.Context, orderID uuid.UUID) (*models.Invoice, error) {
+	// TODO: Tracing
+
+	o, err := u.orderUC.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.issueInvoice(ctx, o)
+}
+
+// Credit note of refunded return, the invoice it corrects is issued first when the order has none. Admin only.
+func (u *invoiceUC) IssueCreditNote(ctx context.Context, returnID uuid.UUID) (*models.Invoice, error) {
+	// TODO: Tracing
+
+	ret, err := u.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+	if ret.Status != models.ReturnStatusRefunded {
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "invoiceUC.IssueCreditNote: return is %s", ret.Status)
+	}
+
+	existing, err := u.invoiceRepo.GetByReturn(ctx, returnID)
+	if err == nil {
+		return u.withFile(ctx, existing)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	o, err := u.orderRepo.GetByID(ctx, ret.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	original, err := u.issueInvoice(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := u.returnRepo.GetItems(ctx, ret.ReturnID)
+	if err != nil {
+		return nil, err
+	}
+	orderItems, err := u.orderRepo.GetItems(ctx, o.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	cn := creditNote(original, ret, items, orderItems)
+	issued, err := u.invoiceRepo.Issue(ctx, cn)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.withFile(ctx, issued)
+}
+
+// Invoice or credit note with its lines, customers only see their own
+func (u *invoiceUC) GetByID(ctx context.Context, invoiceID uuid.UUID) (*models.Invoice, error) {
+	// TODO: Tracing
+
+	inv, err := u.getVisible(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	inv.Lines, err = u.invoiceRepo.GetLines(ctx, inv.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return inv, nil
+}
+
+// Invoice and credit notes of own order, admins see every order
+func (u *invoiceUC) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.Invoice, error) {
+	// TODO: Tracing
+
+	if _, err := u.orderUC.GetByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	return u.invoiceRepo.ListByOrder(ctx, orderID)
+}
+
+// Short lived presigned link to the PDF, the file is rendered again when storing it failed at issue
+func (u *invoiceUC) Download(ctx context.Context, invoiceID uuid.UUID) (*models.InvoiceDownload, error) {
+	// TODO: Tracing
+
+	inv, err := u.getVisible(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if inv.ObjectKey == nil {
+		if inv.Lines, err = u.invoiceRepo.GetLines(ctx, inv.InvoiceID); err != nil {
+			return nil, err
+		}
+		if err = u.store(ctx, inv); err != nil {
+			return nil, err
+		}
+	}
+
+	ttl := time.Second * u.cfg.Invoice.LinkTTL
+	objectUrl, err := u.minioRepo.GetObjectUrl(ctx, u.cfg.Invoice.Bucket, *inv.ObjectKey, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.InvoiceDownload{URL: objectUrl.String(), ExpiresAt: time.Now().Add(ttl)}, nil
+}
+
+// Issue invoice of the order unless it has one
+func (u *invoiceUC) issueInvoice(ctx context.Context, o *models.Order) (*models.Invoice, error) {
+	if !invoiceableStatuses[o.Status] {
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "invoiceUC.issueInvoice: order is %s", o.Status)
+	}
+
+	existing, err := u.invoiceRepo.GetByOrder(ctx, o.OrderID)
+	if err == nil {
+		return u.withFile(ctx, existing)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	items, err := u.orderRepo.GetItems(ctx, o.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	discounts, err := u.orderRepo.GetDiscounts(ctx, o.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	billTo, err := u.billTo(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	inv := orderInvoice(u.cfg.Invoice.StoreCode, o, items, discounts)
+	inv.BillTo = billTo
+
+	issued, err := u.invoiceRepo.Issue(ctx, inv)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.withFile(ctx, issued)
+}
+
+// Default billing address of the customer, else where the order shipped to
+func (u *invoiceUC) billTo(ctx context.Context, o *models.Order) (*models.ShippingAddress, error) {
+	if o.UserID != nil {
+		a, err := u.addressRepo.GetDefaultBilling(ctx, *o.UserID)
+		if err == nil {
+			return a.ShippingAddress(), nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	return o.ShippingAddress, nil
+}
+
+// Document with lines and its file stored. The document is already issued, so a storage failure is only logged
+// and the file is stored again on download.
+func (u *invoiceUC) withFile(ctx context.Context, inv *models.Invoice) (*models.Invoice, error) {
+	if inv.Lines == nil {
+		lines, err := u.invoiceRepo.GetLines(ctx, inv.InvoiceID)
+		if err != nil {
+			return nil, err
+		}
+		inv.Lines = lines
+	}
+
+	if inv.ObjectKey == nil {
+		if err := u.store(ctx, inv); err != nil {
+			u.logger.Errorf("invoiceUC.withFile: storing %s failed: %v", inv.Number, err)
+		}
+	}
+
+	return inv, nil
+}
+
+// Render PDF and save it in the invoice bucket
+func (u *invoiceUC) store(ctx context.Context, inv *models.Invoice) error {
+	var original *models.Invoice
+	if inv.OriginalInvoiceID != nil {
+		var err error
+		if original, err = u.invoiceRepo.GetByID(ctx, *inv.OriginalInvoiceID); err != nil {
+			return err
+		}
+	}
+
+	file, err := render(u.cfg, inv, original)
+	if err != nil {
+		return errors.Wrap(err, "invoiceUC.store.render")
+	}
+
+	key := fmt.Sprintf("%s/%s.pdf", inv.StoreCode, inv.Number)
+	if _, err = u.minioRepo.PutObject(ctx, models.UploadInput{
+		File:        bytes.NewReader(file),
+		Name:        key,
+		Size:        int64(len(file)),
+		ContentType: "application/pdf",
+		BucketName:  u.cfg.Invoice.Bucket,
+	}); err != nil {
+		return err
+	}
+
+	if err = u.invoiceRepo.SetObjectKey(ctx, inv.InvoiceID, key); err != nil {
+		return err
+	}
+	inv.ObjectKey = &key
+
+	return nil
+}
+
+// Document of the user in context, admins see every document
+func (u *invoiceUC) getVisible(ctx context.Context, invoiceID uuid.UUID) (*models.Invoice, error) {
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := u.invoiceRepo.GetByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsAdmin() && (inv.UserID == nil || *inv.UserID != user.UserID) {
+		return nil, httpErrors.NewNotFoundError(errors.New("invoiceUC.getVisible: invoice belongs to another user"))
+	}
+
+	return inv, nil
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/fekuna/go-store/internal/models"
 	"github.com/fekuna/go-store/pkg/httpErrors"
 	"github.com/fekuna/go-store/pkg/utils"
 	"github.com/golang-jwt/jwt"
@@ -51,9 +52,69 @@ func (mw *MiddlewareManager) AuthJWTMiddleware(next echo.HandlerFunc) echo.Handl
 	}
 }
 
+// Same as AuthJWTMiddleware when a token is sent, requests without one continue as guest
+func (mw *MiddlewareManager) OptionalAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	authNext := mw.AuthJWTMiddleware(next)
+	return func(c echo.Context) error {
+		if c.Request().Header.Get("Authorization") != "" {
+			return authNext(c)
+		}
+		if _, err := c.Cookie("jwt-token"); err == nil {
+			return authNext(c)
+		}
+		return next(c)
+	}
+}
+
+// Role based auth middleware, must run after AuthJWTMiddleware
+func (mw *MiddlewareManager) RoleBasedAuthMiddleware(roles []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, ok := c.Get("user").(*models.User)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, httpErrors.NewUnauthorizedError(httpErrors.Unauthorized))
+			}
+
+			if user.Role != nil {
+				for _, role := range roles {
+					if *user.Role == role {
+						return next(c)
+					}
+				}
+			}
+
+			mw.logger.Errorf("RoleBasedAuthMiddleware, RequestID: %s, UserID: %s, Error: %s",
+				utils.GetRequestID(c), user.UserID.String(), httpErrors.PermissionDenied)
+
+			return c.JSON(http.StatusForbidden, httpErrors.NewForbiddenError(httpErrors.PermissionDenied))
+		}
+	}
+}
+
 func (mw *MiddlewareManager) validateJWTToken(c echo.Context, tokenString string) error {
+	userUUID, err := mw.parseJWTUserID(tokenString)
+	if err != nil {
+		return err
+	}
+
+	u, err := mw.authUC.GetByID(c.Request().Context(), userUUID)
+	if err != nil {
+		return err
+	}
+
+	c.Set("user", u)
+
+	ctx := context.WithValue(c.Request().Context(), utils.UserCtxKey{}, u)
+	// req := c.Request().WithContext(ctx)
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	return nil
+}
+
+// Verifies the token signature and returns the user id it was issued to, the user is not loaded
+func (mw *MiddlewareManager) parseJWTUserID(tokenString string) (uuid.UUID, error) {
 	if tokenString == "" {
-		return httpErrors.InvalidJWTToken
+		return uuid.Nil, httpErrors.InvalidJWTToken
 	}
 
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
@@ -64,35 +125,28 @@ func (mw *MiddlewareManager) validateJWTToken(c echo.Context, tokenString string
 		return secret, nil
 	})
 	if err != nil {
-		return err
+		return uuid.Nil, err
 	}
 
-	if !token.Valid {
-		return httpErrors.InvalidJWTClaims
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return uuid.Nil, httpErrors.InvalidJWTClaims
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID, ok := claims["id"].(string)
-		if !ok {
-			return httpErrors.InvalidJWTClaims
-		}
-
-		userUUID, err := uuid.Parse(userID)
-		if err != nil {
-			return err
-		}
-
-		u, err := mw.authUC.GetByID(c.Request().Context(), userUUID)
-		if err != nil {
-			return err
-		}
+	userID, ok := claims["id"].(string)
+	if !ok {
+		return uuid.Nil, httpErrors.InvalidJWTClaims
+	}
 
-		c.Set("user", u)
+	return uuid.Parse(userID)
+}
 
-		ctx := context.WithValue(c.Request().Context(), utils.UserCtxKey{}, u)
-		// req := c.Request().WithContext(ctx)
-		c.SetRequest(c.Request().WithContext(ctx))
+// Get user from context, set by auth middleware
+func GetUserFromCtx(ctx context.Context) (*models.User, error) {
+	user, ok := ctx.Value(utils.UserCtxKey{}).(*models.User)
+	if !ok {
+		return nil, httpErrors.NewUnauthorizedError(httpErrors.Unauthorized)
 	}
 
-	return nil
+	return user, nil
 }
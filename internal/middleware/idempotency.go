@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+	maxIdempotentBodySize   = 2 << 20
+	idempotentReplayHeader  = "Idempotent-Replayed"
+)
+
+// Keeps a copy of everything the handler writes
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Idempotency-Key support for unsafe requests. The first request with a key runs and its response is stored,
+// retries with the same request get the stored response and headers back. Keys are scoped to the signed in user,
+// the guest cart or, for anonymous requests, the request itself, so callers never share keys. A retry while the first one still runs gets 409,
+// reusing the key for a different request gets 422. Server errors are not stored so they can be retried.
+func (mw *MiddlewareManager) IdempotencyMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(models.IdempotencyKeyHeader)
+		if key == "" || !isUnsafeMethod(c.Request().Method) {
+			return next(c)
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return c.JSON(http.StatusBadRequest, httpErrors.NewBadRequestError(errors.New("Idempotency-Key is too long")))
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxIdempotentBodySize+1))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, mw.logger, err)
+		}
+		if len(body) > maxIdempotentBodySize {
+			return c.JSON(http.StatusRequestEntityTooLarge, httpErrors.NewRestError(http.StatusRequestEntityTooLarge,
+				"Idempotency-Key is not supported for bodies over 2MB", nil))
+		}
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := requestFingerprint(c, body)
+		scope := mw.idempotencyScope(c, fingerprint)
+		ctx := c.Request().Context()
+
+		stored, err := mw.idempotencyUC.Begin(ctx, scope, key, fingerprint)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, mw.logger, err)
+		}
+		if stored != nil {
+			contentType := echo.MIMEApplicationJSONCharsetUTF8
+			if stored.ResponseContentType != nil {
+				contentType = *stored.ResponseContentType
+			}
+			header := c.Response().Header()
+			for name, values := range stored.ResponseHeaders {
+				if header.Get(name) == "" {
+					header[name] = values
+				}
+			}
+			header.Set(idempotentReplayHeader, "true")
+			return c.Blob(*stored.ResponseStatus, contentType, stored.ResponseBody)
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = recorder
+		err = next(c)
+		c.Response().Writer = recorder.ResponseWriter
+
+		status := c.Response().Status
+		if !c.Response().Committed || status >= http.StatusInternalServerError {
+			if releaseErr := mw.idempotencyUC.Release(ctx, scope, key); releaseErr != nil {
+				mw.logger.Errorf("IdempotencyMiddleware.Release: %v", releaseErr)
+			}
+			return err
+		}
+
+		contentType := c.Response().Header().Get(echo.HeaderContentType)
+		if completeErr := mw.idempotencyUC.Complete(ctx, &models.IdempotencyKey{
+			Scope:               scope,
+			Key:                 key,
+			ResponseStatus:      &status,
+			ResponseContentType: &contentType,
+			ResponseHeaders:     replayedHeaders(c.Response().Header()),
+			ResponseBody:        recorder.body.Bytes(),
+		}); completeErr != nil {
+			mw.logger.Errorf("IdempotencyMiddleware.Complete: %v", completeErr)
+		}
+
+		return err
+	}
+}
+
+func isUnsafeMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// Keys belong to the signed in user or the guest cart they were sent with. Anonymous requests share one space,
+// keyed by the request itself, so a key only ever replays the very request that stored it.
+func (mw *MiddlewareManager) idempotencyScope(c echo.Context, fingerprint string) string {
+	tokenString := ""
+	if headerParts := strings.Split(c.Request().Header.Get(echo.HeaderAuthorization), " "); len(headerParts) == 2 {
+		tokenString = headerParts[1]
+	} else if cookie, err := c.Cookie("jwt-token"); err == nil {
+		tokenString = cookie.Value
+	}
+	if userID, err := mw.parseJWTUserID(tokenString); err == nil {
+		return scopeHash("user", userID.String())
+	}
+
+	if cartToken := c.Request().Header.Get(models.CartTokenHeader); cartToken != "" {
+		return scopeHash("cart", cartToken)
+	}
+
+	return scopeHash("anonymous", fingerprint)
+}
+
+func scopeHash(kind string, value string) string {
+	h := sha256.New()
+	h.Write([]byte(kind))
+	h.Write([]byte{0})
+	h.Write([]byte(value))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Headers worth replaying, the ones describing this very response are set again when it is replayed
+func replayedHeaders(header http.Header) models.HeaderMap {
+	headers := models.HeaderMap{}
+	for name, values := range header {
+		switch name {
+		case echo.HeaderContentType, echo.HeaderContentLength, echo.HeaderXRequestID, "Date":
+			continue
+		}
+		headers[name] = values
+	}
+	return headers
+}
+
+func requestFingerprint(c echo.Context, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(c.Request().Method))
+	h.Write([]byte{0})
+	h.Write([]byte(c.Request().URL.RequestURI()))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
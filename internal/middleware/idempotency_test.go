@@ -0,0 +1,282 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/idempotency/usecase"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// In memory idempotency key repository with the same locking rules as the Postgres one
+type memIdempotencyRepo struct {
+	mu   sync.Mutex
+	keys map[string]models.IdempotencyKey
+}
+
+func (r *memIdempotencyRepo) Create(ctx context.Context, rec *models.IdempotencyKey) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := rec.Scope + "/" + rec.Key
+	if _, ok := r.keys[id]; ok {
+		return false, nil
+	}
+	r.keys[id] = models.IdempotencyKey{
+		Scope:       rec.Scope,
+		Key:         rec.Key,
+		Fingerprint: rec.Fingerprint,
+		Status:      models.IdempotencyInProgress,
+		LockedAt:    time.Now(),
+		ExpiresAt:   rec.ExpiresAt,
+	}
+	return true, nil
+}
+
+func (r *memIdempotencyRepo) Get(ctx context.Context, scope string, key string) (*models.IdempotencyKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, ok := r.keys[scope+"/"+key]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &rec, nil
+}
+
+func (r *memIdempotencyRepo) Relock(ctx context.Context, rec *models.IdempotencyKey, lockedBefore time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := rec.Scope + "/" + rec.Key
+	existing := r.keys[id]
+	stale := existing.Status == models.IdempotencyInProgress && existing.LockedAt.Before(lockedBefore) &&
+		existing.Fingerprint == rec.Fingerprint
+	if !existing.ExpiresAt.Before(time.Now()) && !stale {
+		return false, nil
+	}
+	r.keys[id] = models.IdempotencyKey{
+		Scope:       rec.Scope,
+		Key:         rec.Key,
+		Fingerprint: rec.Fingerprint,
+		Status:      models.IdempotencyInProgress,
+		LockedAt:    time.Now(),
+		ExpiresAt:   rec.ExpiresAt,
+	}
+	return true, nil
+}
+
+func (r *memIdempotencyRepo) Complete(ctx context.Context, rec *models.IdempotencyKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := rec.Scope + "/" + rec.Key
+	existing := r.keys[id]
+	existing.Status = models.IdempotencyCompleted
+	existing.ResponseStatus = rec.ResponseStatus
+	existing.ResponseContentType = rec.ResponseContentType
+	existing.ResponseHeaders = rec.ResponseHeaders
+	existing.ResponseBody = rec.ResponseBody
+	r.keys[id] = existing
+	return nil
+}
+
+func (r *memIdempotencyRepo) Delete(ctx context.Context, scope string, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.keys, scope+"/"+key)
+	return nil
+}
+
+func (r *memIdempotencyRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func newIdempotencyTestManager() *MiddlewareManager {
+	cfg := &config.Config{
+		Server:      config.ServerConfig{JwtSecretKey: "secret"},
+		Logger:      config.LoggerConfig{Level: "fatal"},
+		Idempotency: config.IdempotencyConfig{TTL: 3600, LockTimeout: 60},
+	}
+	apiLogger := logger.NewApiLogger(cfg)
+	apiLogger.InitLogger()
+
+	repo := &memIdempotencyRepo{keys: map[string]models.IdempotencyKey{}}
+	return NewMiddlewareManager(cfg, apiLogger, nil, nil, usecase.NewIdempotencyUseCase(cfg, apiLogger, repo))
+}
+
+// Bearer header of a token signed for the user, iat tells two tokens of the same user apart
+func bearerFor(t *testing.T, userID uuid.UUID, issuedAt int64) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"id": userID.String(), "iat": issuedAt}).
+		SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + token
+}
+
+func serveIdempotent(h echo.HandlerFunc, body string, headers map[string]string) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/cart/items", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	if err := h(e.NewContext(req, rec)); err != nil {
+		e.HTTPErrorHandler(err, e.NewContext(req, rec))
+	}
+	return rec
+}
+
+func TestIdempotencyMiddlewareReplay(t *testing.T) {
+	mw := newIdempotencyTestManager()
+
+	calls := 0
+	h := mw.IdempotencyMiddleware(func(c echo.Context) error {
+		calls++
+		c.Response().Header().Set(echo.HeaderLocation, "/api/v1/cart")
+		c.Response().Header().Set(models.CartTokenHeader, "issued-token")
+		return c.JSON(http.StatusCreated, map[string]int{"call": calls})
+	})
+	headers := map[string]string{models.IdempotencyKeyHeader: "key-1", models.CartTokenHeader: "guest-token"}
+
+	first := serveIdempotent(h, `{"sku":"A","quantity":1}`, headers)
+	second := serveIdempotent(h, `{"sku":"A","quantity":1}`, headers)
+
+	if calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Fatalf("replay = %d %q, want %d %q", second.Code, second.Body.String(), first.Code, first.Body.String())
+	}
+	if got := second.Header().Get(idempotentReplayHeader); got != "true" {
+		t.Fatalf("%s = %q, want true", idempotentReplayHeader, got)
+	}
+	for _, name := range []string{echo.HeaderLocation, models.CartTokenHeader} {
+		if got, want := second.Header().Get(name), first.Header().Get(name); got != want {
+			t.Fatalf("replayed %s = %q, want %q", name, got, want)
+		}
+	}
+	if got := first.Header().Get(idempotentReplayHeader); got != "" {
+		t.Fatalf("first response has %s = %q", idempotentReplayHeader, got)
+	}
+}
+
+func TestIdempotencyMiddlewareInFlight(t *testing.T) {
+	mw := newIdempotencyTestManager()
+	headers := map[string]string{models.IdempotencyKeyHeader: "key-1", echo.HeaderAuthorization: bearerFor(t, uuid.New(), 1)}
+
+	var retry *httptest.ResponseRecorder
+	var h echo.HandlerFunc
+	h = mw.IdempotencyMiddleware(func(c echo.Context) error {
+		if retry == nil {
+			// Retry arrives while the first request still holds the key
+			retry = serveIdempotent(h, `{}`, headers)
+		}
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	first := serveIdempotent(h, `{}`, headers)
+
+	if first.Code != http.StatusNoContent {
+		t.Fatalf("first request = %d, want %d", first.Code, http.StatusNoContent)
+	}
+	if retry.Code != http.StatusConflict {
+		t.Fatalf("retry while in flight = %d, want %d", retry.Code, http.StatusConflict)
+	}
+}
+
+func TestIdempotencyMiddlewareFingerprintMismatch(t *testing.T) {
+	mw := newIdempotencyTestManager()
+	headers := map[string]string{models.IdempotencyKeyHeader: "key-1", echo.HeaderAuthorization: bearerFor(t, uuid.New(), 1)}
+
+	calls := 0
+	h := mw.IdempotencyMiddleware(func(c echo.Context) error {
+		calls++
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	serveIdempotent(h, `{"quantity":1}`, headers)
+	reused := serveIdempotent(h, `{"quantity":2}`, headers)
+
+	if reused.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("key reused for another body = %d, want %d", reused.Code, http.StatusUnprocessableEntity)
+	}
+	if calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls)
+	}
+}
+
+func TestIdempotencyMiddlewareScope(t *testing.T) {
+	mw := newIdempotencyTestManager()
+
+	calls := 0
+	h := mw.IdempotencyMiddleware(func(c echo.Context) error {
+		calls++
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	// Anonymous retries of the same request are replayed, another request with the key runs on its own
+	serveIdempotent(h, `{"email":"a@example.com"}`, map[string]string{models.IdempotencyKeyHeader: "key-1"})
+	serveIdempotent(h, `{"email":"a@example.com"}`, map[string]string{models.IdempotencyKeyHeader: "key-1"})
+	other := serveIdempotent(h, `{"email":"b@example.com"}`, map[string]string{models.IdempotencyKeyHeader: "key-1"})
+	if calls != 2 || other.Code != http.StatusNoContent {
+		t.Fatalf("anonymous requests = %d after %d calls, want %d after 2", other.Code, calls, http.StatusNoContent)
+	}
+
+	// A refreshed token of the same user keeps the key, another user gets a key of their own
+	userID := uuid.New()
+	serveIdempotent(h, `{}`, map[string]string{models.IdempotencyKeyHeader: "key-2",
+		echo.HeaderAuthorization: bearerFor(t, userID, 1)})
+	serveIdempotent(h, `{}`, map[string]string{models.IdempotencyKeyHeader: "key-2",
+		echo.HeaderAuthorization: bearerFor(t, userID, 2)})
+	if calls != 3 {
+		t.Fatalf("handler ran %d times for two tokens of one user, want 3", calls)
+	}
+	serveIdempotent(h, `{}`, map[string]string{models.IdempotencyKeyHeader: "key-2",
+		echo.HeaderAuthorization: bearerFor(t, uuid.New(), 1)})
+	if calls != 4 {
+		t.Fatalf("handler ran %d times for another user, want 4", calls)
+	}
+
+	// Same key from two guest carts runs twice
+	serveIdempotent(h, `{}`, map[string]string{models.IdempotencyKeyHeader: "key-1", models.CartTokenHeader: "cart-a"})
+	serveIdempotent(h, `{}`, map[string]string{models.IdempotencyKeyHeader: "key-1", models.CartTokenHeader: "cart-b"})
+	if calls != 6 {
+		t.Fatalf("handler ran %d times for two guest carts, want 6", calls)
+	}
+}
+
+func TestIdempotencyMiddlewareReleasesServerErrors(t *testing.T) {
+	mw := newIdempotencyTestManager()
+	headers := map[string]string{models.IdempotencyKeyHeader: "key-1", echo.HeaderAuthorization: bearerFor(t, uuid.New(), 1)}
+
+	calls := 0
+	h := mw.IdempotencyMiddleware(func(c echo.Context) error {
+		calls++
+		if calls == 1 {
+			return c.NoContent(http.StatusBadGateway)
+		}
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	serveIdempotent(h, `{}`, headers)
+	retry := serveIdempotent(h, `{}`, headers)
+
+	if calls != 2 || retry.Code != http.StatusNoContent {
+		t.Fatalf("retry after server error = %d after %d calls, want %d after 2", retry.Code, calls, http.StatusNoContent)
+	}
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"github.com/fekuna/go-store/config"
 	"github.com/fekuna/go-store/internal/auth"
+	"github.com/fekuna/go-store/internal/idempotency"
 	"github.com/fekuna/go-store/internal/session"
 	"github.com/fekuna/go-store/pkg/logger"
 )
@@ -13,6 +14,8 @@ type MiddlewareManager struct {
 	logger logger.Logger
 	sessUC session.UseCase
 	authUC auth.UseCase
+
+	idempotencyUC idempotency.UseCase
 }
 
 // Middleware manager constructor
@@ -21,11 +24,14 @@ func NewMiddlewareManager(
 	logger logger.Logger,
 	sessUC session.UseCase,
 	authUC auth.UseCase,
+	idempotencyUC idempotency.UseCase,
 ) *MiddlewareManager {
 	return &MiddlewareManager{
 		cfg:    cfg,
 		logger: logger,
 		sessUC: sessUC,
 		authUC: authUC,
+
+		idempotencyUC: idempotencyUC,
 	}
 }
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Saved address of a user, a user has at most one default shipping and one default billing address
+type Address struct {
+	AddressID         uuid.UUID `json:"address_id" db:"address_id"`
+	UserID            uuid.UUID `json:"user_id" db:"user_id"`
+	Label             string    `json:"label" db:"label" validate:"lte=50"`
+	FirstName         string    `json:"first_name" db:"first_name" validate:"required,lte=30"`
+	LastName          string    `json:"last_name" db:"last_name" validate:"required,lte=30"`
+	Company           *string   `json:"company,omitempty" db:"company" validate:"omitempty,lte=100"`
+	Line1             string    `json:"line1" db:"line1" validate:"required,lte=250"`
+	Line2             *string   `json:"line2,omitempty" db:"line2" validate:"omitempty,lte=250"`
+	City              string    `json:"city" db:"city" validate:"required,lte=50"`
+	Region            *string   `json:"region,omitempty" db:"region" validate:"omitempty,lte=50"`
+	Postcode          *string   `json:"postcode,omitempty" db:"postcode" validate:"omitempty,lte=20"`
+	Country           string    `json:"country" db:"country" validate:"required,len=2,alpha"`
+	PhoneNumber       *string   `json:"phone_number,omitempty" db:"phone_number" validate:"omitempty,lte=20"`
+	IsDefaultShipping bool      `json:"is_default_shipping" db:"is_default_shipping"`
+	IsDefaultBilling  bool      `json:"is_default_billing" db:"is_default_billing"`
+	CreatedAt         time.Time `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// Address rules of a country, postcodes are matched after Prepare upper cased them
+type addressFormat struct {
+	postcode       *regexp.Regexp
+	regionRequired bool
+}
+
+// Countries without an entry accept any postcode and region
+var addressFormats = map[string]addressFormat{
+	"AU": {postcode: regexp.MustCompile(`^\d{4}$`), regionRequired: true},
+	"CA": {postcode: regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`), regionRequired: true},
+	"DE": {postcode: regexp.MustCompile(`^\d{5}$`)},
+	"FR": {postcode: regexp.MustCompile(`^\d{5}$`)},
+	"GB": {postcode: regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`)},
+	"ID": {postcode: regexp.MustCompile(`^\d{5}$`)},
+	"IN": {postcode: regexp.MustCompile(`^\d{6}$`), regionRequired: true},
+	"JP": {postcode: regexp.MustCompile(`^\d{3}-?\d{4}$`), regionRequired: true},
+	"MY": {postcode: regexp.MustCompile(`^\d{5}$`), regionRequired: true},
+	"NL": {postcode: regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`)},
+	"SG": {postcode: regexp.MustCompile(`^\d{6}$`)},
+	"US": {postcode: regexp.MustCompile(`^\d{5}(-\d{4})?$`), regionRequired: true},
+}
+
+// Prepare address for create and update
+func (a *Address) Prepare() {
+	a.Label = strings.TrimSpace(a.Label)
+	a.FirstName = strings.TrimSpace(a.FirstName)
+	a.LastName = strings.TrimSpace(a.LastName)
+	a.Company = trimOptional(a.Company)
+	a.Line1 = strings.TrimSpace(a.Line1)
+	a.Line2 = trimOptional(a.Line2)
+	a.City = strings.TrimSpace(a.City)
+	a.Region = trimOptional(a.Region)
+	a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+	a.PhoneNumber = trimOptional(a.PhoneNumber)
+
+	a.Postcode = trimOptional(a.Postcode)
+	if a.Postcode != nil {
+		*a.Postcode = strings.ToUpper(*a.Postcode)
+	}
+}
+
+// Check postcode and region against the rules of the country, the error reads well for the customer
+func (a *Address) Validate() error {
+	format, ok := addressFormats[a.Country]
+	if !ok {
+		return nil
+	}
+
+	if format.regionRequired && a.Region == nil {
+		return errors.Errorf("region is required for %s addresses", a.Country)
+	}
+	if format.postcode != nil && (a.Postcode == nil || !format.postcode.MatchString(*a.Postcode)) {
+		return errors.Errorf("invalid postcode for %s addresses", a.Country)
+	}
+
+	return nil
+}
+
+// Shipping destination of the address, without region the city is matched as region like profile addresses were
+func (a *Address) ShippingAddress() *ShippingAddress {
+	s := &ShippingAddress{
+		Country: a.Country,
+		Region:  a.City,
+		Name:    strings.TrimSpace(a.FirstName + " " + a.LastName),
+		Company: a.Company,
+		Line1:   a.Line1,
+		Line2:   a.Line2,
+		City:    a.City,
+		Phone:   a.PhoneNumber,
+	}
+	if a.Region != nil {
+		s.Region = *a.Region
+	}
+	if a.Postcode != nil {
+		s.Postcode = *a.Postcode
+	}
+
+	return s
+}
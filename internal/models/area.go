@@ -0,0 +1,38 @@
+package models
+
+import (
+	"path"
+	"strings"
+)
+
+// Match an area narrowed by optional region and postcode glob against an address. The score grows with
+// specificity, a postcode pattern counts more than a region. Regions are matched against the city of users.
+func MatchArea(region *string, postcodePattern *string, addressRegion string, addressPostcode string) (int, bool) {
+	score := 0
+
+	if region != nil {
+		if !strings.EqualFold(*region, strings.TrimSpace(addressRegion)) {
+			return 0, false
+		}
+		score++
+	}
+
+	if postcodePattern != nil {
+		postcode := strings.ToUpper(strings.TrimSpace(addressPostcode))
+		if postcode == "" {
+			return 0, false
+		}
+		if ok, err := path.Match(strings.ToUpper(*postcodePattern), postcode); err != nil || !ok {
+			return 0, false
+		}
+		score += 2
+	}
+
+	return score, true
+}
+
+// Check postcode glob syntax
+func ValidPostcodePattern(pattern string) bool {
+	_, err := path.Match(pattern, "")
+	return err == nil
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attribute types
+const (
+	AttributeText    = "text"
+	AttributeNumber  = "number"
+	AttributeEnum    = "enum"
+	AttributeBoolean = "boolean"
+)
+
+// Attribute set groups the attributes of one product type, e.g. books or shoes
+type AttributeSet struct {
+	AttributeSetID uuid.UUID    `json:"attribute_set_id" db:"attribute_set_id" validate:"omitempty"`
+	Name           string       `json:"name" db:"name" validate:"required,lte=100"`
+	CreatedAt      time.Time    `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at,omitempty" db:"updated_at"`
+	Attributes     []*Attribute `json:"attributes,omitempty" db:"-"`
+}
+
+// Typed attribute, rules are extra validator tags applied to the value, e.g. "gte=0,lte=500"
+type Attribute struct {
+	AttributeID    uuid.UUID  `json:"attribute_id" db:"attribute_id" validate:"omitempty"`
+	AttributeSetID uuid.UUID  `json:"attribute_set_id" db:"attribute_set_id" validate:"omitempty"`
+	Code           string     `json:"code" db:"code" validate:"required,lte=50"`
+	Label          string     `json:"label" db:"label" validate:"required,lte=100"`
+	Type           string     `json:"type" db:"type" validate:"required,oneof=text number enum boolean"`
+	Required       bool       `json:"required" db:"required"`
+	EnumValues     StringList `json:"enum_values" db:"enum_values" validate:"required_if=Type enum,unique,dive,required,lte=100"`
+	Rules          string     `json:"rules" db:"rules" validate:"omitempty,lte=250"`
+	Position       int        `json:"position" db:"position" validate:"gte=0"`
+	CreatedAt      time.Time  `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// Assign attribute set to product, nil removes it
+type ProductAttributeSet struct {
+	AttributeSetID *uuid.UUID `json:"attribute_set_id" validate:"omitempty"`
+}
+
+// Variant attribute values keyed by attribute code
+type VariantAttributes struct {
+	Attributes JSONMap `json:"attributes" validate:"required"`
+}
+
+// Prepare attribute for create or update
+func (a *Attribute) Prepare() {
+	a.Code = strings.ReplaceAll(Slugify(a.Code), "-", "_")
+	a.Label = strings.TrimSpace(a.Label)
+	a.Rules = strings.TrimSpace(a.Rules)
+
+	if a.Type != AttributeEnum {
+		a.EnumValues = StringList{}
+	}
+	if a.EnumValues == nil {
+		a.EnumValues = StringList{}
+	}
+}
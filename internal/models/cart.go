@@ -0,0 +1,91 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cart statuses
+const (
+	CartStatusActive    = "active"
+	CartStatusConverted = "converted"
+)
+
+// Request header carrying the guest cart token
+const CartTokenHeader = "X-Cart-Token"
+
+// Max quantity of one cart line
+const MaxCartItemQuantity = 99
+
+// Shopping cart, guest carts have no user and are addressed by signed token
+type Cart struct {
+	CartID    uuid.UUID   `json:"cart_id" db:"cart_id"`
+	UserID    *uuid.UUID  `json:"user_id,omitempty" db:"user_id"`
+	Status    string      `json:"status" db:"status"`
+	CreatedAt time.Time   `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at,omitempty" db:"updated_at"`
+	Token     string      `json:"cart_token,omitempty" db:"-"`
+	Items     []*CartItem `json:"items" db:"-"`
+	Totals    *CartTotals `json:"totals" db:"-"`
+}
+
+// Cart line with current variant data, prices are never taken from the client
+type CartItem struct {
+	CartID       uuid.UUID `json:"-" db:"cart_id"`
+	SKU          string    `json:"sku" db:"sku"`
+	Quantity     int       `json:"quantity" db:"quantity"`
+	VariantID    uuid.UUID `json:"variant_id" db:"variant_id"`
+	ProductID    uuid.UUID `json:"product_id" db:"product_id"`
+	Title        string    `json:"title" db:"title"`
+	VariantTitle string    `json:"variant_title" db:"variant_title"`
+	UnitPrice    int64     `json:"unit_price" db:"unit_price"`
+	Currency     string    `json:"currency" db:"currency"`
+	WeightGrams  int       `json:"weight_grams" db:"weight_grams"`
+	TaxClass     string    `json:"-" db:"tax_class"`
+	Available    bool      `json:"available" db:"available"`
+	InStock      bool      `json:"in_stock" db:"in_stock"`
+	LineTotal    int64     `json:"line_total" db:"-"`
+	AddedAt      time.Time `json:"added_at" db:"added_at"`
+}
+
+// Cart totals in minor units, unavailable lines are excluded
+type CartTotals struct {
+	ItemCount int    `json:"item_count"`
+	Subtotal  int64  `json:"subtotal"`
+	Currency  string `json:"currency"`
+}
+
+// Add item request
+type CartItemInput struct {
+	SKU      string `json:"sku" validate:"required,lte=64"`
+	Quantity int    `json:"quantity" validate:"required,gte=1,lte=99"`
+}
+
+// Change quantity request, zero removes the line
+type CartQuantityInput struct {
+	Quantity int `json:"quantity" validate:"gte=0,lte=99"`
+}
+
+// Recompute line and cart totals from current prices
+func (c *Cart) CalculateTotals() {
+	totals := &CartTotals{Currency: DefaultCurrency}
+
+	for _, item := range c.Items {
+		item.LineTotal = item.UnitPrice * int64(item.Quantity)
+		if !item.Available {
+			continue
+		}
+		totals.ItemCount += item.Quantity
+		totals.Subtotal += item.LineTotal
+		totals.Currency = item.Currency
+	}
+
+	c.Totals = totals
+}
+
+// Normalize SKU the same way products store it
+func NormalizeSKU(sku string) string {
+	return strings.ToUpper(strings.TrimSpace(sku))
+}
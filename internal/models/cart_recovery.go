@@ -0,0 +1,132 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cart recovery statuses, open recoveries get the next reminder
+const (
+	CartRecoveryStatusOpen         = "open"
+	CartRecoveryStatusConverted    = "converted"
+	CartRecoveryStatusExpired      = "expired"
+	CartRecoveryStatusUnsubscribed = "unsubscribed"
+)
+
+// Email list of abandoned cart reminders
+const EmailListCartReminders = "cart_reminders"
+
+// Reminder sequence of an abandoned cart. Step is the number of reminders sent, Items the cart lines when the
+// last one was sent so the restore link can bring them back.
+type CartRecovery struct {
+	RecoveryID  uuid.UUID         `json:"recovery_id" db:"recovery_id"`
+	CartID      *uuid.UUID        `json:"cart_id,omitempty" db:"cart_id"`
+	UserID      uuid.UUID         `json:"user_id" db:"user_id"`
+	Status      string            `json:"status" db:"status"`
+	Step        int               `json:"step" db:"step"`
+	Items       CartRecoveryItems `json:"items" db:"items"`
+	Currency    string            `json:"currency" db:"currency"`
+	CartTotal   int64             `json:"cart_total" db:"cart_total"`
+	LastSentAt  time.Time         `json:"last_sent_at" db:"last_sent_at"`
+	RestoredAt  *time.Time        `json:"restored_at,omitempty" db:"restored_at"`
+	OrderID     *uuid.UUID        `json:"order_id,omitempty" db:"order_id"`
+	OrderTotal  *int64            `json:"order_total,omitempty" db:"order_total"`
+	ConvertedAt *time.Time        `json:"converted_at,omitempty" db:"converted_at"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// Cart line of a reminder
+type CartRecoveryItem struct {
+	SKU          string `json:"sku"`
+	Title        string `json:"title"`
+	VariantTitle string `json:"variant_title"`
+	Quantity     int    `json:"quantity"`
+	LineTotal    int64  `json:"line_total"`
+}
+
+// Cart lines stored as JSONB array
+type CartRecoveryItems []*CartRecoveryItem
+
+// Idle cart of a user due for its next reminder, RecoveryID is nil before the first one
+type AbandonedCart struct {
+	CartID     uuid.UUID  `db:"cart_id"`
+	UserID     uuid.UUID  `db:"user_id"`
+	Email      string     `db:"email"`
+	FirstName  string     `db:"first_name"`
+	RecoveryID *uuid.UUID `db:"recovery_id"`
+	Step       int        `db:"step"`
+}
+
+// Restore and unsubscribe request, the token comes from the reminder email link
+type CartRecoveryTokenInput struct {
+	Token string `json:"token" validate:"required,lte=200"`
+}
+
+// Cart recovery stats query params, dates are inclusive and default to the last 30 days
+type CartRecoveryStatsQuery struct {
+	From string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To   string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// Reminder conversion of the recoveries started from From until before To. Carts got at least one reminder,
+// converted ones were followed by an order within the attribution window.
+type CartRecoveryStats struct {
+	From           time.Time                `json:"from" db:"-"`
+	To             time.Time                `json:"to" db:"-"`
+	Carts          int                      `json:"carts" db:"carts"`
+	RemindersSent  int                      `json:"reminders_sent" db:"reminders_sent"`
+	Restored       int                      `json:"restored" db:"restored"`
+	Converted      int                      `json:"converted" db:"converted"`
+	Unsubscribed   int                      `json:"unsubscribed" db:"unsubscribed"`
+	ConversionRate float64                  `json:"conversion_rate" db:"-"`
+	Steps          []*CartRecoveryStepStats `json:"steps" db:"-"`
+	Revenue        []*CartRecoveryRevenue   `json:"revenue" db:"-"`
+}
+
+// Recoveries whose last reminder was the given step
+type CartRecoveryStepStats struct {
+	Step           int     `json:"step" db:"step"`
+	Carts          int     `json:"carts" db:"carts"`
+	Converted      int     `json:"converted" db:"converted"`
+	ConversionRate float64 `json:"conversion_rate" db:"-"`
+}
+
+// Abandoned and recovered value of one currency in minor units
+type CartRecoveryRevenue struct {
+	Currency       string `json:"currency" db:"currency"`
+	AbandonedTotal int64  `json:"abandoned_total" db:"abandoned_total"`
+	RecoveredTotal int64  `json:"recovered_total" db:"recovered_total"`
+}
+
+// Snapshot available cart lines, the cart totals have to be calculated
+func NewCartRecoveryItems(c *Cart) CartRecoveryItems {
+	items := make(CartRecoveryItems, 0, len(c.Items))
+	for _, item := range c.Items {
+		if !item.Available {
+			continue
+		}
+		items = append(items, &CartRecoveryItem{
+			SKU:          item.SKU,
+			Title:        item.Title,
+			VariantTitle: item.VariantTitle,
+			Quantity:     item.Quantity,
+			LineTotal:    item.LineTotal,
+		})
+	}
+	return items
+}
+
+func (l CartRecoveryItems) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+func (l *CartRecoveryItems) Scan(src interface{}) error {
+	return scanJSONB(src, l)
+}
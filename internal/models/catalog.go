@@ -0,0 +1,152 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Catalog sort orders
+const (
+	CatalogSortNewest    = "newest"
+	CatalogSortPriceAsc  = "price_asc"
+	CatalogSortPriceDesc = "price_desc"
+	CatalogSortTitleAsc  = "title_asc"
+)
+
+// Catalog listing query params, option filters are passed as opt.<Name>=value1,value2
+// and attribute filters as attr.<code>=value1,value2
+type CatalogQuery struct {
+	Category string `query:"category" validate:"omitempty,uuid"`
+	Brand    string `query:"brand" validate:"omitempty,lte=500"`
+	MinPrice int64  `query:"min_price" validate:"gte=0"`
+	MaxPrice int64  `query:"max_price" validate:"gte=0"`
+	InStock  bool   `query:"in_stock"`
+	Sort     string `query:"sort" validate:"omitempty,oneof=newest price_asc price_desc title_asc"`
+	Cursor   string `query:"cursor" validate:"omitempty,lte=500"`
+	Limit    int    `query:"limit" validate:"omitempty,gte=1,lte=100"`
+}
+
+// Parsed catalog filters
+type CatalogFilter struct {
+	CategoryID *uuid.UUID
+	Brands     []string
+	MinPrice   int64
+	MaxPrice   int64
+	InStock    bool
+	Options    map[string][]string
+	Attributes map[string][]string
+	Sort       string
+	Cursor     *CatalogCursor
+	Limit      int
+}
+
+// Keyset pagination cursor, position after the last item of the previous page
+type CatalogCursor struct {
+	Sort      string    `json:"s"`
+	Value     string    `json:"v"`
+	ProductID uuid.UUID `json:"id"`
+}
+
+// Product in catalog listing with its cheapest matching variant price
+type CatalogItem struct {
+	Product
+	MinPrice int64 `json:"min_price" db:"min_price"`
+	InStock  bool  `json:"in_stock" db:"in_stock"`
+}
+
+// Facet value with number of matching products
+type FacetCount struct {
+	Value string `json:"value" db:"value"`
+	Label string `json:"label,omitempty" db:"label"`
+	Count int    `json:"count" db:"count"`
+}
+
+// Price range facet, max is exclusive and nil for the last bucket
+type PriceBucketCount struct {
+	Min   int64  `json:"min" db:"min"`
+	Max   *int64 `json:"max,omitempty" db:"max"`
+	Count int    `json:"count" db:"count"`
+}
+
+// Values of one option or attribute, e.g. Size
+type OptionFacet struct {
+	Name   string        `json:"name"`
+	Values []*FacetCount `json:"values"`
+}
+
+// Catalog facets
+type CatalogFacets struct {
+	Categories   []*FacetCount       `json:"categories"`
+	Brands       []*FacetCount       `json:"brands"`
+	PriceBuckets []*PriceBucketCount `json:"price_buckets"`
+	Options      []*OptionFacet      `json:"options"`
+	Attributes   []*OptionFacet      `json:"attributes"`
+	InStock      int                 `json:"in_stock"`
+}
+
+// Catalog listing page, facets are returned with the first page only
+type CatalogPage struct {
+	Items      []*CatalogItem `json:"items"`
+	HasMore    bool           `json:"has_more"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	Facets     *CatalogFacets `json:"facets,omitempty"`
+}
+
+// Cursor pointing after the given item
+func NewCatalogCursor(sort string, item *CatalogItem) *CatalogCursor {
+	c := &CatalogCursor{Sort: sort, ProductID: item.ProductID}
+
+	switch sort {
+	case CatalogSortPriceAsc, CatalogSortPriceDesc:
+		c.Value = strconv.FormatInt(item.MinPrice, 10)
+	case CatalogSortTitleAsc:
+		c.Value = item.Title
+	default:
+		c.Value = item.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	return c
+}
+
+// Encode cursor as opaque url safe string
+func (c *CatalogCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decode cursor, it must belong to the requested sort order
+func DecodeCatalogCursor(s string, sort string) (*CatalogCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "DecodeCatalogCursor.DecodeString")
+	}
+
+	c := &CatalogCursor{}
+	if err = json.Unmarshal(b, c); err != nil {
+		return nil, errors.Wrap(err, "DecodeCatalogCursor.Unmarshal")
+	}
+
+	if c.Sort != sort {
+		return nil, errors.New("DecodeCatalogCursor: cursor belongs to another sort order")
+	}
+
+	return c, nil
+}
+
+// Split comma separated query value
+func SplitQueryList(s string) []string {
+	values := make([]string, 0)
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
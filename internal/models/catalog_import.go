@@ -0,0 +1,137 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Import file formats
+const (
+	ImportFormatCSV   = "csv"
+	ImportFormatJSONL = "jsonl"
+)
+
+// Import job statuses
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// Column order of CSV import and export files
+var ImportColumns = []string{"sku", "title", "slug", "description", "brand", "price", "currency", "status"}
+
+// Background catalog import, dry run validates every row without writing
+type ImportJob struct {
+	JobID       uuid.UUID         `json:"job_id" db:"job_id"`
+	Format      string            `json:"format" db:"format"`
+	DryRun      bool              `json:"dry_run" db:"dry_run"`
+	Status      string            `json:"status" db:"status"`
+	FileName    string            `json:"file_name" db:"file_name"`
+	ObjectKey   string            `json:"-" db:"object_key"`
+	TotalRows   int               `json:"total_rows" db:"total_rows"`
+	CreatedRows int               `json:"created_rows" db:"created_rows"`
+	UpdatedRows int               `json:"updated_rows" db:"updated_rows"`
+	FailedRows  int               `json:"failed_rows" db:"failed_rows"`
+	Error       string            `json:"error,omitempty" db:"error"`
+	CreatedBy   *uuid.UUID        `json:"created_by,omitempty" db:"created_by"`
+	StartedAt   *time.Time        `json:"started_at,omitempty" db:"started_at"`
+	FinishedAt  *time.Time        `json:"finished_at,omitempty" db:"finished_at"`
+	CreatedAt   time.Time         `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at,omitempty" db:"updated_at"`
+	Errors      []*ImportRowError `json:"errors,omitempty" db:"-"`
+}
+
+// Rejected row, row number is the line in the uploaded file
+type ImportRowError struct {
+	JobID     uuid.UUID `json:"-" db:"job_id"`
+	RowNumber int       `json:"row_number" db:"row_number"`
+	SKU       string    `json:"sku,omitempty" db:"sku"`
+	Message   string    `json:"message" db:"message"`
+}
+
+// One product in import and export files, price is nil when the column is empty
+type ImportRow struct {
+	SKU         string `json:"sku" db:"sku"`
+	Title       string `json:"title" db:"title"`
+	Slug        string `json:"slug" db:"slug"`
+	Description string `json:"description" db:"description"`
+	Brand       string `json:"brand" db:"brand"`
+	Price       *int64 `json:"price" db:"price"`
+	Currency    string `json:"currency" db:"currency"`
+	Status      string `json:"status" db:"status"`
+}
+
+// Catalog export query params
+type ExportQuery struct {
+	Format string `query:"format" validate:"omitempty,oneof=csv jsonl"`
+}
+
+// All import jobs response
+type ImportJobsList struct {
+	TotalCount int          `json:"total_count"`
+	TotalPages int          `json:"total_pages"`
+	Page       int          `json:"page"`
+	Size       int          `json:"size"`
+	HasMore    bool         `json:"has_more"`
+	Jobs       []*ImportJob `json:"jobs"`
+}
+
+// Product to create from row
+func (r *ImportRow) ToProduct() *Product {
+	var price int64
+	if r.Price != nil {
+		price = *r.Price
+	}
+
+	return &Product{
+		SKU:         r.SKU,
+		Title:       r.Title,
+		Slug:        r.Slug,
+		Description: r.Description,
+		Brand:       r.Brand,
+		Price:       price,
+		Currency:    r.Currency,
+		Status:      r.Status,
+	}
+}
+
+// Update of an existing product, empty optional columns keep current values
+func (r *ImportRow) ToProductUpdate() *ProductUpdateInput {
+	optional := func(s string) *string {
+		if s == "" {
+			return nil
+		}
+		return &s
+	}
+	sku, title := r.SKU, r.Title
+	var price *int64
+	if r.Price != nil {
+		value := *r.Price
+		price = &value
+	}
+
+	return &ProductUpdateInput{
+		SKU:         &sku,
+		Title:       &title,
+		Slug:        optional(r.Slug),
+		Description: optional(r.Description),
+		Brand:       optional(r.Brand),
+		Price:       price,
+		Currency:    optional(r.Currency),
+		Status:      optional(r.Status),
+	}
+}
+
+// CSV record in ImportColumns order
+func (r *ImportRow) Record() []string {
+	price := ""
+	if r.Price != nil {
+		price = strconv.FormatInt(*r.Price, 10)
+	}
+
+	return []string{r.SKU, r.Title, r.Slug, r.Description, r.Brand, price, r.Currency, r.Status}
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category model, path is the materialized path of ancestor ids: /root_id/.../category_id/
+type Category struct {
+	CategoryID uuid.UUID  `json:"category_id" db:"category_id" validate:"omitempty"`
+	ParentID   *uuid.UUID `json:"parent_id,omitempty" db:"parent_id" validate:"omitempty"`
+	Name       string     `json:"name" db:"name" validate:"required,lte=100"`
+	Slug       string     `json:"slug" db:"slug" validate:"omitempty,lte=128"`
+	Path       string     `json:"path" db:"path"`
+	Depth      int        `json:"depth" db:"depth"`
+	Position   int        `json:"position" db:"position"`
+	CreatedAt  time.Time  `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// Category node with nested children
+type CategoryTree struct {
+	*Category
+	Children []*CategoryTree `json:"children"`
+}
+
+// Move category under new parent (nil for root) at given sibling position
+type CategoryMove struct {
+	ParentID *uuid.UUID `json:"parent_id" validate:"omitempty"`
+	Position int        `json:"position" validate:"gte=0"`
+}
+
+// Prepare category for create
+func (c *Category) PrepareCreate() {
+	c.Name = strings.TrimSpace(c.Name)
+
+	if c.Slug == "" {
+		c.Slug = Slugify(c.Name)
+	} else {
+		c.Slug = Slugify(c.Slug)
+	}
+}
+
+// Build ordered category tree from categories sorted by depth and position
+func BuildCategoryTree(categories []*Category) []*CategoryTree {
+	nodes := make(map[uuid.UUID]*CategoryTree, len(categories))
+	roots := make([]*CategoryTree, 0)
+
+	for _, c := range categories {
+		node := &CategoryTree{Category: c, Children: make([]*CategoryTree, 0)}
+		nodes[c.CategoryID] = node
+
+		if c.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+
+		if parent, ok := nodes[*c.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	return roots
+}
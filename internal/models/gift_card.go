@@ -0,0 +1,149 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Gift card statuses
+const (
+	GiftCardStatusActive   = "active"
+	GiftCardStatusDisabled = "disabled"
+)
+
+// Gift card transaction kinds, redemptions are negative
+const (
+	GiftCardTxIssue    = "issue"
+	GiftCardTxRedeem   = "redeem"
+	GiftCardTxReversal = "reversal"
+)
+
+// Gift card with its remaining balance in minor units. Order is the order the card was sold with, if any.
+type GiftCard struct {
+	GiftCardID     uuid.UUID              `json:"gift_card_id" db:"gift_card_id"`
+	Code           string                 `json:"code" db:"code"`
+	Currency       string                 `json:"currency" db:"currency"`
+	InitialAmount  int64                  `json:"initial_amount" db:"initial_amount"`
+	Balance        int64                  `json:"balance" db:"balance"`
+	Status         string                 `json:"status" db:"status"`
+	ExpiresAt      *time.Time             `json:"expires_at,omitempty" db:"expires_at"`
+	RecipientEmail *string                `json:"recipient_email,omitempty" db:"recipient_email"`
+	Note           string                 `json:"note" db:"note"`
+	OrderID        *uuid.UUID             `json:"order_id,omitempty" db:"order_id"`
+	IssuedBy       *uuid.UUID             `json:"issued_by,omitempty" db:"issued_by"`
+	CreatedAt      time.Time              `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at,omitempty" db:"updated_at"`
+	Transactions   []*GiftCardTransaction `json:"transactions,omitempty" db:"-"`
+}
+
+// Change of a gift card balance
+type GiftCardTransaction struct {
+	TransactionID uuid.UUID  `json:"transaction_id" db:"transaction_id"`
+	GiftCardID    uuid.UUID  `json:"-" db:"gift_card_id"`
+	Kind          string     `json:"kind" db:"kind"`
+	Amount        int64      `json:"amount" db:"amount"`
+	BalanceAfter  int64      `json:"balance_after" db:"balance_after"`
+	OrderID       *uuid.UUID `json:"order_id,omitempty" db:"order_id"`
+	Note          string     `json:"note" db:"note"`
+	ActorID       *uuid.UUID `json:"actor_id,omitempty" db:"actor_id"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Gift card issue request, the code is generated
+type GiftCardInput struct {
+	Amount         int64      `json:"amount" validate:"required,gt=0"`
+	Currency       string     `json:"currency" validate:"omitempty,len=3,alpha"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	RecipientEmail *string    `json:"recipient_email" validate:"omitempty,lte=64,email"`
+	Note           string     `json:"note" validate:"omitempty,lte=500"`
+	OrderID        *uuid.UUID `json:"order_id"`
+}
+
+// Gift card change request, expiry is replaced as given
+type GiftCardUpdate struct {
+	Status    string     `json:"status" validate:"required,oneof=active disabled"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Balance lookup by code
+type GiftCardBalanceInput struct {
+	Code string `json:"code" validate:"required,lte=64"`
+}
+
+// Balance of a gift card as shown to customers
+type GiftCardBalance struct {
+	Code      string     `json:"code"`
+	Currency  string     `json:"currency"`
+	Balance   int64      `json:"balance"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Usable    bool       `json:"usable"`
+}
+
+// Gift card redeemed at checkout
+type OrderGiftCard struct {
+	GiftCardID uuid.UUID `json:"gift_card_id"`
+	Code       string    `json:"code"`
+	Amount     int64     `json:"amount"`
+}
+
+// Gift cards list query params
+type GiftCardsQuery struct {
+	Status string `query:"status" validate:"omitempty,oneof=active disabled"`
+}
+
+// All gift cards response
+type GiftCardsList struct {
+	TotalCount int         `json:"total_count"`
+	TotalPages int         `json:"total_pages"`
+	Page       int         `json:"page"`
+	Size       int         `json:"size"`
+	HasMore    bool        `json:"has_more"`
+	GiftCards  []*GiftCard `json:"gift_cards"`
+}
+
+// Reason a gift card can't be redeemed now, empty when it can
+func (g *GiftCard) Unusable(now time.Time) string {
+	switch {
+	case g.Status != GiftCardStatusActive:
+		return "gift card is disabled"
+	case g.ExpiresAt != nil && !g.ExpiresAt.After(now):
+		return "gift card has expired"
+	case g.Balance == 0:
+		return "gift card has no balance left"
+	}
+	return ""
+}
+
+// Code with all but the last four characters hidden
+func (g *GiftCard) MaskedCode() string {
+	if len(g.Code) <= 4 {
+		return g.Code
+	}
+	return strings.Repeat("*", len(g.Code)-4) + g.Code[len(g.Code)-4:]
+}
+
+// Prepare gift card issue request
+func (i *GiftCardInput) Prepare() {
+	i.Currency = strings.ToUpper(strings.TrimSpace(i.Currency))
+	if i.Currency == "" {
+		i.Currency = DefaultCurrency
+	}
+	i.RecipientEmail = trimOptional(i.RecipientEmail)
+	if i.RecipientEmail != nil {
+		*i.RecipientEmail = strings.ToLower(*i.RecipientEmail)
+	}
+	i.Note = strings.TrimSpace(i.Note)
+}
+
+// Gift card code as stored, upper case without spaces and dashes so codes can be typed as printed
+func NormalizeGiftCardCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, code)
+}
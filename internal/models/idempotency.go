@@ -0,0 +1,45 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// Idempotency key statuses
+const (
+	IdempotencyInProgress = "in_progress"
+	IdempotencyCompleted  = "completed"
+)
+
+// Request header carrying the idempotency key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Stored outcome of a request sent with an idempotency key
+type IdempotencyKey struct {
+	Scope               string    `json:"-" db:"scope"`
+	Key                 string    `json:"key" db:"idempotency_key"`
+	Fingerprint         string    `json:"-" db:"fingerprint"`
+	Status              string    `json:"status" db:"status"`
+	ResponseStatus      *int      `json:"-" db:"response_status"`
+	ResponseContentType *string   `json:"-" db:"response_content_type"`
+	ResponseHeaders     HeaderMap `json:"-" db:"response_headers"`
+	ResponseBody        []byte    `json:"-" db:"response_body"`
+	CreatedAt           time.Time `json:"created_at" db:"created_at"`
+	LockedAt            time.Time `json:"locked_at" db:"locked_at"`
+	ExpiresAt           time.Time `json:"expires_at" db:"expires_at"`
+}
+
+// Response headers stored as JSONB object
+type HeaderMap map[string][]string
+
+func (m HeaderMap) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
+func (m *HeaderMap) Scan(src interface{}) error {
+	return scanJSONB(src, m)
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Stock movement types
+const (
+	MovementReceipt    = "receipt"
+	MovementSale       = "sale"
+	MovementReturn     = "return"
+	MovementAdjustment = "adjustment"
+)
+
+// Current stock of a SKU, maintained from the stock movements ledger
+type StockLevel struct {
+	SKU               string    `json:"sku" db:"sku"`
+	OnHand            int       `json:"on_hand" db:"on_hand"`
+	Reserved          int       `json:"reserved" db:"reserved"`
+	Available         int       `json:"available" db:"available"`
+	LowStockThreshold int       `json:"low_stock_threshold" db:"low_stock_threshold"`
+	IsLowStock        bool      `json:"is_low_stock" db:"is_low_stock"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Append only stock ledger entry, quantity is a signed delta of on hand stock
+type StockMovement struct {
+	MovementID   uuid.UUID  `json:"movement_id" db:"movement_id" validate:"omitempty"`
+	SKU          string     `json:"sku" db:"sku" validate:"omitempty,lte=64"`
+	MovementType string     `json:"movement_type" db:"movement_type" validate:"required,oneof=receipt sale return adjustment"`
+	Quantity     int        `json:"quantity" db:"quantity" validate:"required,ne=0"`
+	OnHandAfter  int        `json:"on_hand_after" db:"on_hand_after"`
+	Reason       string     `json:"reason" db:"reason" validate:"omitempty,lte=250"`
+	ActorUserID  *uuid.UUID `json:"actor_user_id,omitempty" db:"actor_user_id"`
+	ReferenceID  *uuid.UUID `json:"reference_id,omitempty" db:"reference_id" validate:"omitempty"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Stock threshold input
+type StockThreshold struct {
+	LowStockThreshold int `json:"low_stock_threshold" validate:"gte=0"`
+}
+
+// All stock movements response
+type StockMovementsList struct {
+	TotalCount int              `json:"total_count"`
+	TotalPages int              `json:"total_pages"`
+	Page       int              `json:"page"`
+	Size       int              `json:"size"`
+	HasMore    bool             `json:"has_more"`
+	Movements  []*StockMovement `json:"movements"`
+}
+
+// Check that quantity sign matches movement type
+func (m *StockMovement) IsValidDirection() bool {
+	switch m.MovementType {
+	case MovementReceipt, MovementReturn:
+		return m.Quantity > 0
+	case MovementSale:
+		return m.Quantity < 0
+	case MovementAdjustment:
+		return m.Quantity != 0
+	default:
+		return false
+	}
+}
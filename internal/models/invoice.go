@@ -0,0 +1,70 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invoice document types
+const (
+	InvoiceTypeInvoice    = "invoice"
+	InvoiceTypeCreditNote = "credit_note"
+)
+
+// Issued invoice of an order or credit note of a refunded return, amounts are in minor units and positive on both
+type Invoice struct {
+	InvoiceID         uuid.UUID        `json:"invoice_id" db:"invoice_id"`
+	StoreCode         string           `json:"store_code" db:"store_code"`
+	Type              string           `json:"type" db:"type"`
+	SequenceNumber    int64            `json:"sequence_number" db:"sequence_number"`
+	Number            string           `json:"number" db:"number"`
+	OrderID           uuid.UUID        `json:"order_id" db:"order_id"`
+	ReturnID          *uuid.UUID       `json:"return_id,omitempty" db:"return_id"`
+	OriginalInvoiceID *uuid.UUID       `json:"original_invoice_id,omitempty" db:"original_invoice_id"`
+	UserID            *uuid.UUID       `json:"user_id,omitempty" db:"user_id"`
+	Email             string           `json:"email" db:"email"`
+	BillTo            *ShippingAddress `json:"bill_to,omitempty" db:"bill_to"`
+	Currency          string           `json:"currency" db:"currency"`
+	PricesIncludeTax  bool             `json:"prices_include_tax" db:"prices_include_tax"`
+	Subtotal          int64            `json:"subtotal" db:"subtotal"`
+	DiscountTotal     int64            `json:"discount_total" db:"discount_total"`
+	ShippingTotal     int64            `json:"shipping_total" db:"shipping_total"`
+	TaxTotal          int64            `json:"tax_total" db:"tax_total"`
+	Total             int64            `json:"total" db:"total"`
+	ObjectKey         *string          `json:"-" db:"object_key"`
+	IssuedAt          time.Time        `json:"issued_at" db:"issued_at"`
+	Lines             []*InvoiceLine   `json:"lines,omitempty" db:"-"`
+}
+
+// Printed invoice line, discounts are negative lines
+type InvoiceLine struct {
+	LineID      uuid.UUID `json:"line_id" db:"line_id"`
+	InvoiceID   uuid.UUID `json:"-" db:"invoice_id"`
+	Position    int       `json:"position" db:"position"`
+	SKU         string    `json:"sku" db:"sku"`
+	Description string    `json:"description" db:"description"`
+	Quantity    int       `json:"quantity" db:"quantity"`
+	UnitPrice   int64     `json:"unit_price" db:"unit_price"`
+	TaxRatePPM  int64     `json:"tax_rate_ppm" db:"tax_rate_ppm"`
+	TaxAmount   int64     `json:"tax_amount" db:"tax_amount"`
+	LineTotal   int64     `json:"line_total" db:"line_total"`
+}
+
+// Presigned link to the PDF of an invoice
+type InvoiceDownload struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Number prefix of every document type
+var invoiceNumberPrefixes = map[string]string{
+	InvoiceTypeInvoice:    "INV",
+	InvoiceTypeCreditNote: "CN",
+}
+
+// Printed document number, like MAIN-INV-000042
+func FormatInvoiceNumber(storeCode string, invoiceType string, sequenceNumber int64) string {
+	return fmt.Sprintf("%s-%s-%06d", storeCode, invoiceNumberPrefixes[invoiceType], sequenceNumber)
+}
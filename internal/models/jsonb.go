@@ -0,0 +1,63 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// String list stored as JSONB array
+type StringList []string
+
+// String map stored as JSONB object
+type StringMap map[string]string
+
+// Arbitrary JSONB object
+type JSONMap map[string]interface{}
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+func (l *StringList) Scan(src interface{}) error {
+	return scanJSONB(src, l)
+}
+
+func (m StringMap) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(m)
+}
+
+func (m *StringMap) Scan(src interface{}) error {
+	return scanJSONB(src, m)
+}
+
+func (m JSONMap) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(m)
+}
+
+func (m *JSONMap) Scan(src interface{}) error {
+	return scanJSONB(src, m)
+}
+
+func scanJSONB(src interface{}, dest interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	default:
+		return errors.Errorf("scanJSONB: unsupported type %T", src)
+	}
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Order statuses
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusPaid      = "paid"
+	OrderStatusFulfilled = "fulfilled"
+	OrderStatusShipped   = "shipped"
+	OrderStatusDelivered = "delivered"
+	OrderStatusCancelled = "cancelled"
+	OrderStatusRefunded  = "refunded"
+)
+
+// Allowed order status transitions, cancelled and refunded are final
+var orderTransitions = map[string][]string{
+	OrderStatusPending:   {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusFulfilled, OrderStatusRefunded},
+	OrderStatusFulfilled: {OrderStatusShipped, OrderStatusRefunded},
+	OrderStatusShipped:   {OrderStatusDelivered, OrderStatusRefunded},
+	OrderStatusDelivered: {OrderStatusRefunded},
+}
+
+// Order placed from a cart
+type Order struct {
+	OrderID           uuid.UUID        `json:"order_id" db:"order_id"`
+	UserID            *uuid.UUID       `json:"user_id,omitempty" db:"user_id"`
+	Email             string           `json:"email" db:"email"`
+	Status            string           `json:"status" db:"status"`
+	Currency          string           `json:"currency" db:"currency"`
+	ItemCount         int              `json:"item_count" db:"item_count"`
+	Subtotal          int64            `json:"subtotal" db:"subtotal"`
+	DiscountTotal     int64            `json:"discount_total" db:"discount_total"`
+	TaxTotal          int64            `json:"tax_total" db:"tax_total"`
+	PricesIncludeTax  bool             `json:"prices_include_tax" db:"prices_include_tax"`
+	ShippingMethodID  *uuid.UUID       `json:"shipping_method_id,omitempty" db:"shipping_method_id"`
+	ShippingMethod    string           `json:"shipping_method" db:"shipping_method"`
+	ShippingTotal     int64            `json:"shipping_total" db:"shipping_total"`
+	ShippingAddress   *ShippingAddress `json:"shipping_address,omitempty" db:"shipping_address"`
+	Total             int64            `json:"total" db:"total"`
+	GiftCardTotal     int64            `json:"gift_card_total" db:"gift_card_total"`
+	StoreCreditTotal  int64            `json:"store_credit_total" db:"store_credit_total"`
+	SubscriptionID    *uuid.UUID       `json:"subscription_id,omitempty" db:"subscription_id"`
+	SubscriptionRunAt *time.Time       `json:"subscription_run_at,omitempty" db:"subscription_run_at"`
+	CreatedAt         time.Time        `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at,omitempty" db:"updated_at"`
+	Items             []*OrderItem     `json:"items,omitempty" db:"-"`
+	Discounts         []*OrderDiscount `json:"discounts,omitempty" db:"-"`
+	GiftCards         []*OrderGiftCard `json:"gift_cards,omitempty" db:"-"`
+	Events            []*OrderEvent    `json:"events,omitempty" db:"-"`
+}
+
+// Order line, a snapshot of the variant at purchase time
+type OrderItem struct {
+	OrderItemID  uuid.UUID  `json:"order_item_id" db:"order_item_id"`
+	OrderID      uuid.UUID  `json:"-" db:"order_id"`
+	VariantID    *uuid.UUID `json:"variant_id,omitempty" db:"variant_id"`
+	ProductID    *uuid.UUID `json:"product_id,omitempty" db:"product_id"`
+	SKU          string     `json:"sku" db:"sku"`
+	Title        string     `json:"title" db:"title"`
+	VariantTitle string     `json:"variant_title" db:"variant_title"`
+	UnitPrice    int64      `json:"unit_price" db:"unit_price"`
+	Quantity     int        `json:"quantity" db:"quantity"`
+	LineTotal    int64      `json:"line_total" db:"line_total"`
+	TaxClass     string     `json:"tax_class" db:"tax_class"`
+	TaxRatePPM   int64      `json:"tax_rate_ppm" db:"tax_rate_ppm"`
+	TaxAmount    int64      `json:"tax_amount" db:"tax_amount"`
+}
+
+// Order status history entry
+type OrderEvent struct {
+	EventID    uuid.UUID  `json:"event_id" db:"event_id"`
+	OrderID    uuid.UUID  `json:"-" db:"order_id"`
+	FromStatus *string    `json:"from_status,omitempty" db:"from_status"`
+	ToStatus   string     `json:"to_status" db:"to_status"`
+	Note       string     `json:"note" db:"note"`
+	ActorID    *uuid.UUID `json:"actor_id,omitempty" db:"actor_id"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Checkout request, every promo code has to apply or checkout fails. The order ships to shipping_address,
+// else to the saved address shipping_address_id, else to the default shipping address. A shipping method
+// is required when the address has any. Gift cards pay in the given order, then store credit pays what is left
+// when use_store_credit is set.
+type CheckoutInput struct {
+	PromoCodes        []string         `json:"promo_codes" validate:"omitempty,max=5,dive,required,lte=64"`
+	ShippingMethodID  *uuid.UUID       `json:"shipping_method_id"`
+	ShippingAddressID *uuid.UUID       `json:"shipping_address_id"`
+	ShippingAddress   *ShippingAddress `json:"shipping_address"`
+	GiftCardCodes     []string         `json:"gift_card_codes" validate:"omitempty,max=5,dive,required,lte=64"`
+	UseStoreCredit    bool             `json:"use_store_credit"`
+}
+
+// Order status change request
+type OrderTransition struct {
+	Status string `json:"status" validate:"required,oneof=pending paid fulfilled shipped delivered cancelled refunded"`
+	Note   string `json:"note" validate:"omitempty,lte=500"`
+}
+
+// Order list query params
+type OrdersQuery struct {
+	Status string `query:"status" validate:"omitempty,oneof=pending paid fulfilled shipped delivered cancelled refunded"`
+}
+
+// All orders response
+type OrdersList struct {
+	TotalCount int      `json:"total_count"`
+	TotalPages int      `json:"total_pages"`
+	Page       int      `json:"page"`
+	Size       int      `json:"size"`
+	HasMore    bool     `json:"has_more"`
+	Orders     []*Order `json:"orders"`
+}
+
+// Part of the total left to pay through the payment provider
+func (o *Order) AmountDue() int64 {
+	return o.Total - o.GiftCardTotal - o.StoreCreditTotal
+}
+
+// Check if the order can move from one status to another
+func CanTransitionOrder(from string, to string) bool {
+	for _, status := range orderTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Prepare transition request
+func (t *OrderTransition) Prepare() {
+	t.Note = strings.TrimSpace(t.Note)
+}
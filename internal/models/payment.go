@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payment statuses
+const (
+	PaymentStatusPending   = "pending"
+	PaymentStatusSucceeded = "succeeded"
+	PaymentStatusFailed    = "failed"
+	PaymentStatusRefunded  = "refunded"
+)
+
+// Saved payment method statuses, a method is pending until its setup succeeded at the provider
+const (
+	PaymentMethodStatusPending = "pending"
+	PaymentMethodStatusActive  = "active"
+)
+
+// Payment attempt of an order at the payment provider, IntentID is nil until the provider answered
+type Payment struct {
+	PaymentID      uuid.UUID `json:"payment_id" db:"payment_id"`
+	OrderID        uuid.UUID `json:"order_id" db:"order_id"`
+	Provider       string    `json:"provider" db:"provider"`
+	IntentID       *string   `json:"intent_id,omitempty" db:"intent_id"`
+	Status         string    `json:"status" db:"status"`
+	Amount         int64     `json:"amount" db:"amount"`
+	RefundedAmount int64     `json:"refunded_amount" db:"refunded_amount"`
+	Currency       string    `json:"currency" db:"currency"`
+	CreatedAt      time.Time `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// Created payment with the secret the client needs to confirm it at the provider
+type PaymentIntent struct {
+	Payment      *Payment `json:"payment"`
+	ClientSecret string   `json:"client_secret"`
+}
+
+// Refund request, zero refunds what is left
+type PaymentRefund struct {
+	Amount int64 `json:"amount" validate:"gte=0"`
+}
+
+// Payment method of a user saved at the provider for off session charges. The provider ids stay on the server,
+// clients only know PaymentMethodID.
+type PaymentMethod struct {
+	PaymentMethodID  uuid.UUID `json:"payment_method_id" db:"payment_method_id"`
+	UserID           uuid.UUID `json:"-" db:"user_id"`
+	Provider         string    `json:"provider" db:"provider"`
+	Status           string    `json:"status" db:"status"`
+	SetupID          string    `json:"-" db:"setup_id"`
+	CustomerID       string    `json:"-" db:"customer_id"`
+	ProviderMethodID *string   `json:"-" db:"provider_method_id"`
+	CreatedAt        time.Time `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// Started payment method setup with the secret the client needs to confirm it at the provider
+type PaymentMethodSetup struct {
+	PaymentMethod *PaymentMethod `json:"payment_method"`
+	ClientSecret  string         `json:"client_secret"`
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Product statuses
+const (
+	ProductStatusDraft    = "draft"
+	ProductStatusActive   = "active"
+	ProductStatusArchived = "archived"
+)
+
+const DefaultCurrency = "USD"
+
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Product full model, price is stored in minor units (cents)
+type Product struct {
+	ProductID      uuid.UUID  `json:"product_id" db:"product_id" validate:"omitempty"`
+	SKU            string     `json:"sku" db:"sku" validate:"required,lte=64"`
+	Slug           string     `json:"slug" db:"slug" validate:"omitempty,lte=128"`
+	Title          string     `json:"title" db:"title" validate:"required,lte=250"`
+	Description    string     `json:"description" db:"description" validate:"omitempty,lte=10000"`
+	Brand          string     `json:"brand" db:"brand" validate:"omitempty,lte=100"`
+	AttributeSetID *uuid.UUID `json:"attribute_set_id,omitempty" db:"attribute_set_id"`
+	Price          int64      `json:"price" db:"price" validate:"gte=0"`
+	Currency       string     `json:"currency" db:"currency" validate:"omitempty,len=3,alpha"`
+	Status         string     `json:"status" db:"status" validate:"omitempty,oneof=draft active archived"`
+	TaxClass       string     `json:"tax_class" db:"tax_class" validate:"omitempty,lte=32"`
+	RatingAvg      float64    `json:"rating_avg" db:"rating_avg"`
+	RatingCount    int        `json:"rating_count" db:"rating_count"`
+	CreatedAt      time.Time  `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at,omitempty" db:"updated_at"`
+
+	Options  []*ProductOption  `json:"options,omitempty" db:"-"`
+	Variants []*ProductVariant `json:"variants,omitempty" db:"-"`
+}
+
+// All products response
+type ProductsList struct {
+	TotalCount int        `json:"total_count"`
+	TotalPages int        `json:"total_pages"`
+	Page       int        `json:"page"`
+	Size       int        `json:"size"`
+	HasMore    bool       `json:"has_more"`
+	Products   []*Product `json:"products"`
+}
+
+// Prepare product for create
+func (p *Product) PrepareCreate() {
+	p.SKU = strings.ToUpper(strings.TrimSpace(p.SKU))
+	p.Title = strings.TrimSpace(p.Title)
+	p.Brand = strings.TrimSpace(p.Brand)
+	p.Currency = strings.ToUpper(strings.TrimSpace(p.Currency))
+
+	if p.Currency == "" {
+		p.Currency = DefaultCurrency
+	}
+
+	if p.Status == "" {
+		p.Status = ProductStatusDraft
+	}
+
+	p.TaxClass = NormalizeTaxClass(p.TaxClass)
+
+	if p.Slug == "" {
+		p.Slug = Slugify(p.Title)
+	} else {
+		p.Slug = Slugify(p.Slug)
+	}
+}
+
+// Product update request, fields left out are not changed
+type ProductUpdateInput struct {
+	SKU         *string `json:"sku,omitempty" validate:"omitempty,min=1,lte=64"`
+	Slug        *string `json:"slug,omitempty" validate:"omitempty,min=1,lte=128"`
+	Title       *string `json:"title,omitempty" validate:"omitempty,min=1,lte=250"`
+	Description *string `json:"description,omitempty" validate:"omitempty,lte=10000"`
+	Brand       *string `json:"brand,omitempty" validate:"omitempty,lte=100"`
+	Price       *int64  `json:"price,omitempty" validate:"omitempty,gte=0"`
+	Currency    *string `json:"currency,omitempty" validate:"omitempty,len=3,alpha"`
+	Status      *string `json:"status,omitempty" validate:"omitempty,oneof=draft active archived"`
+	TaxClass    *string `json:"tax_class,omitempty" validate:"omitempty,min=1,lte=32"`
+}
+
+// Prepare product update, normalizes the fields that are set
+func (in *ProductUpdateInput) PrepareUpdate() {
+	trim := func(s *string, normalize func(string) string) {
+		if s != nil {
+			*s = normalize(strings.TrimSpace(*s))
+		}
+	}
+	trim(in.SKU, strings.ToUpper)
+	trim(in.Title, strings.TrimSpace)
+	trim(in.Brand, strings.TrimSpace)
+	trim(in.Currency, strings.ToUpper)
+	trim(in.TaxClass, strings.ToLower)
+	trim(in.Slug, Slugify)
+}
+
+// Make url friendly slug from text
+func Slugify(s string) string {
+	return strings.Trim(slugInvalidChars.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
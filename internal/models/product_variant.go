@@ -0,0 +1,118 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Product variant statuses, archived variants are no longer sold but stay on past orders and stock history
+const (
+	VariantStatusActive   = "active"
+	VariantStatusArchived = "archived"
+)
+
+// Product option, e.g. Size with values S, M, L
+type ProductOption struct {
+	OptionID  uuid.UUID  `json:"option_id" db:"option_id"`
+	ProductID uuid.UUID  `json:"product_id" db:"product_id"`
+	Name      string     `json:"name" db:"name" validate:"required,lte=50"`
+	Position  int        `json:"position" db:"position"`
+	Values    StringList `json:"values" db:"option_values" validate:"required,min=1,unique,dive,required,lte=50"`
+}
+
+// Replace product options input
+type ProductOptionsInput struct {
+	Options []*ProductOption `json:"options" validate:"lte=3,dive"`
+}
+
+// Product variant is the sellable item: one combination of option values with its own SKU.
+// Cart and order lines reference the variant, never the parent product.
+type ProductVariant struct {
+	VariantID     uuid.UUID  `json:"variant_id" db:"variant_id" validate:"omitempty"`
+	ProductID     uuid.UUID  `json:"product_id" db:"product_id" validate:"omitempty"`
+	SKU           string     `json:"sku" db:"sku" validate:"omitempty,lte=64"`
+	Title         string     `json:"title" db:"title" validate:"omitempty,lte=250"`
+	Options       StringMap  `json:"options" db:"options"`
+	PriceOverride *int64     `json:"price_override,omitempty" db:"price_override" validate:"omitempty,gte=0"`
+	Price         int64      `json:"price" db:"price"`
+	Currency      string     `json:"currency" db:"currency"`
+	Barcode       *string    `json:"barcode,omitempty" db:"barcode" validate:"omitempty,lte=32"`
+	WeightGrams   int        `json:"weight_grams" db:"weight_grams" validate:"gte=0"`
+	Images        StringList `json:"images" db:"images" validate:"lte=20,dive,url"`
+	Attributes    JSONMap    `json:"attributes" db:"attributes"`
+	IsDefault     bool       `json:"is_default" db:"is_default"`
+	Status        string     `json:"status" db:"status"`
+	CreatedAt     time.Time  `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// Default variant for products without options
+func NewDefaultVariant(p *Product) *ProductVariant {
+	return &ProductVariant{
+		ProductID:  p.ProductID,
+		SKU:        p.SKU,
+		Title:      p.Title,
+		Options:    StringMap{},
+		Images:     StringList{},
+		Attributes: JSONMap{},
+		IsDefault:  true,
+		Status:     VariantStatusActive,
+	}
+}
+
+// Build every variant of the option value matrix, in option and value order
+func BuildVariantMatrix(p *Product, options []*ProductOption) []*ProductVariant {
+	if len(options) == 0 {
+		return nil
+	}
+
+	combinations := []StringMap{{}}
+	for _, opt := range options {
+		next := make([]StringMap, 0, len(combinations)*len(opt.Values))
+		for _, combination := range combinations {
+			for _, value := range opt.Values {
+				m := make(StringMap, len(combination)+1)
+				for k, v := range combination {
+					m[k] = v
+				}
+				m[opt.Name] = value
+				next = append(next, m)
+			}
+		}
+		combinations = next
+	}
+
+	variants := make([]*ProductVariant, 0, len(combinations))
+	for _, combination := range combinations {
+		skuParts := []string{p.SKU}
+		titleParts := make([]string, 0, len(options))
+		for _, opt := range options {
+			skuParts = append(skuParts, strings.ToUpper(Slugify(combination[opt.Name])))
+			titleParts = append(titleParts, combination[opt.Name])
+		}
+
+		variants = append(variants, &ProductVariant{
+			ProductID:  p.ProductID,
+			SKU:        strings.Join(skuParts, "-"),
+			Title:      strings.Join(titleParts, " / "),
+			Options:    combination,
+			Images:     StringList{},
+			Attributes: JSONMap{},
+			Status:     VariantStatusActive,
+		})
+	}
+
+	return variants
+}
+
+// Prepare variant for update
+func (v *ProductVariant) PrepareUpdate() {
+	v.SKU = strings.ToUpper(strings.TrimSpace(v.SKU))
+	v.Title = strings.TrimSpace(v.Title)
+
+	if v.Barcode != nil {
+		*v.Barcode = strings.TrimSpace(*v.Barcode)
+	}
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildVariantMatrix(t *testing.T) {
+	product := &Product{ProductID: uuid.New(), SKU: "TEE"}
+
+	type variant struct {
+		SKU     string
+		Title   string
+		Options StringMap
+	}
+
+	tests := []struct {
+		name    string
+		options []*ProductOption
+		want    []variant
+	}{
+		{name: "no options", options: nil, want: nil},
+		{
+			name:    "single option",
+			options: []*ProductOption{{Name: "Size", Values: StringList{"S", "M"}}},
+			want: []variant{
+				{SKU: "TEE-S", Title: "S", Options: StringMap{"Size": "S"}},
+				{SKU: "TEE-M", Title: "M", Options: StringMap{"Size": "M"}},
+			},
+		},
+		{
+			name: "option and value order",
+			options: []*ProductOption{
+				{Name: "Color", Values: StringList{"Navy Blue", "Red"}},
+				{Name: "Size", Values: StringList{"S", "XL"}},
+			},
+			want: []variant{
+				{SKU: "TEE-NAVY-BLUE-S", Title: "Navy Blue / S", Options: StringMap{"Color": "Navy Blue", "Size": "S"}},
+				{SKU: "TEE-NAVY-BLUE-XL", Title: "Navy Blue / XL", Options: StringMap{"Color": "Navy Blue", "Size": "XL"}},
+				{SKU: "TEE-RED-S", Title: "Red / S", Options: StringMap{"Color": "Red", "Size": "S"}},
+				{SKU: "TEE-RED-XL", Title: "Red / XL", Options: StringMap{"Color": "Red", "Size": "XL"}},
+			},
+		},
+		{
+			name: "three options",
+			options: []*ProductOption{
+				{Name: "Color", Values: StringList{"Red"}},
+				{Name: "Size", Values: StringList{"S", "M"}},
+				{Name: "Fit", Values: StringList{"Slim", "Regular", "Loose"}},
+			},
+			want: []variant{
+				{SKU: "TEE-RED-S-SLIM", Title: "Red / S / Slim", Options: StringMap{"Color": "Red", "Size": "S", "Fit": "Slim"}},
+				{SKU: "TEE-RED-S-REGULAR", Title: "Red / S / Regular", Options: StringMap{"Color": "Red", "Size": "S", "Fit": "Regular"}},
+				{SKU: "TEE-RED-S-LOOSE", Title: "Red / S / Loose", Options: StringMap{"Color": "Red", "Size": "S", "Fit": "Loose"}},
+				{SKU: "TEE-RED-M-SLIM", Title: "Red / M / Slim", Options: StringMap{"Color": "Red", "Size": "M", "Fit": "Slim"}},
+				{SKU: "TEE-RED-M-REGULAR", Title: "Red / M / Regular", Options: StringMap{"Color": "Red", "Size": "M", "Fit": "Regular"}},
+				{SKU: "TEE-RED-M-LOOSE", Title: "Red / M / Loose", Options: StringMap{"Color": "Red", "Size": "M", "Fit": "Loose"}},
+			},
+		},
+		{
+			name: "option without values gives no variants",
+			options: []*ProductOption{
+				{Name: "Color", Values: StringList{"Red"}},
+				{Name: "Size", Values: StringList{}},
+			},
+			want: []variant{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variants := BuildVariantMatrix(product, tt.options)
+			if tt.want == nil {
+				if variants != nil {
+					t.Fatalf("BuildVariantMatrix() = %d variants, want nil", len(variants))
+				}
+				return
+			}
+
+			got := make([]variant, 0, len(variants))
+			for _, v := range variants {
+				if v.ProductID != product.ProductID || v.IsDefault || v.Images == nil || v.Attributes == nil {
+					t.Fatalf("variant %s = %+v, want a non default variant of the product with empty images and attributes", v.SKU, v)
+				}
+				got = append(got, variant{SKU: v.SKU, Title: v.Title, Options: v.Options})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("BuildVariantMatrix() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
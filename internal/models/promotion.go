@@ -0,0 +1,104 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Promotion kinds
+const (
+	PromotionKindPercentage   = "percentage"
+	PromotionKindFixed        = "fixed"
+	PromotionKindFreeShipping = "free_shipping"
+	PromotionKindBuyXGetY     = "buy_x_get_y"
+)
+
+// Discount code or automatic promotion when code is empty. Value is a percent for percentage promotions
+// and an amount in minor units for fixed ones, buy_x_get_y gives get_quantity of every buy_quantity + get_quantity
+// eligible units for free, cheapest first. Product limits the promotion to lines of one product.
+type Promotion struct {
+	PromotionID      uuid.UUID  `json:"promotion_id" db:"promotion_id"`
+	Name             string     `json:"name" db:"name" validate:"required,lte=250"`
+	Description      string     `json:"description" db:"description" validate:"omitempty,lte=1000"`
+	Code             *string    `json:"code,omitempty" db:"code" validate:"omitempty,lte=64"`
+	Kind             string     `json:"kind" db:"kind" validate:"required,oneof=percentage fixed free_shipping buy_x_get_y"`
+	Value            int64      `json:"value" db:"value" validate:"gte=0"`
+	Currency         string     `json:"currency" db:"currency" validate:"omitempty,len=3,alpha"`
+	ProductID        *uuid.UUID `json:"product_id,omitempty" db:"product_id"`
+	MinSubtotal      int64      `json:"min_subtotal" db:"min_subtotal" validate:"gte=0"`
+	BuyQuantity      int        `json:"buy_quantity" db:"buy_quantity" validate:"gte=0,lte=99"`
+	GetQuantity      int        `json:"get_quantity" db:"get_quantity" validate:"gte=0,lte=99"`
+	Stackable        bool       `json:"stackable" db:"stackable"`
+	Priority         int        `json:"priority" db:"priority"`
+	UsageLimit       *int       `json:"usage_limit,omitempty" db:"usage_limit" validate:"omitempty,gte=1"`
+	PerCustomerLimit *int       `json:"per_customer_limit,omitempty" db:"per_customer_limit" validate:"omitempty,gte=1"`
+	UsedCount        int        `json:"used_count" db:"used_count"`
+	Active           bool       `json:"active" db:"active"`
+	StartsAt         *time.Time `json:"starts_at,omitempty" db:"starts_at"`
+	EndsAt           *time.Time `json:"ends_at,omitempty" db:"ends_at"`
+	CreatedAt        time.Time  `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// All promotions response
+type PromotionsList struct {
+	TotalCount int          `json:"total_count"`
+	TotalPages int          `json:"total_pages"`
+	Page       int          `json:"page"`
+	Size       int          `json:"size"`
+	HasMore    bool         `json:"has_more"`
+	Promotions []*Promotion `json:"promotions"`
+}
+
+// Discount applied to a cart or an order, with the explanation shown to the customer
+type OrderDiscount struct {
+	OrderDiscountID uuid.UUID  `json:"order_discount_id,omitempty" db:"order_discount_id"`
+	OrderID         uuid.UUID  `json:"-" db:"order_id"`
+	PromotionID     *uuid.UUID `json:"promotion_id,omitempty" db:"promotion_id"`
+	UserID          *uuid.UUID `json:"-" db:"user_id"`
+	Code            *string    `json:"code,omitempty" db:"code"`
+	Description     string     `json:"description" db:"description"`
+	Amount          int64      `json:"amount" db:"amount"`
+	FreeShipping    bool       `json:"free_shipping" db:"free_shipping"`
+	CreatedAt       time.Time  `json:"created_at,omitempty" db:"created_at"`
+}
+
+// Code that was entered but doesn't apply, with the reason
+type RejectedPromotion struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// Promotions evaluated against a cart
+type PromotionResult struct {
+	Discounts     []*OrderDiscount     `json:"discounts"`
+	Rejected      []*RejectedPromotion `json:"rejected"`
+	DiscountTotal int64                `json:"discount_total"`
+	FreeShipping  bool                 `json:"free_shipping"`
+}
+
+// Prepare promotion for create and update
+func (p *Promotion) Prepare() {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Description = strings.TrimSpace(p.Description)
+	p.Currency = strings.ToUpper(strings.TrimSpace(p.Currency))
+
+	if p.Currency == "" {
+		p.Currency = DefaultCurrency
+	}
+
+	if p.Code != nil {
+		code := NormalizePromoCode(*p.Code)
+		p.Code = &code
+		if code == "" {
+			p.Code = nil
+		}
+	}
+}
+
+// Promo codes are case insensitive
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
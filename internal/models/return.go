@@ -0,0 +1,204 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Return statuses
+const (
+	ReturnStatusRequested    = "requested"
+	ReturnStatusApproved     = "approved"
+	ReturnStatusRejected     = "rejected"
+	ReturnStatusCancelled    = "cancelled"
+	ReturnStatusReceived     = "received"
+	ReturnStatusRefunded     = "refunded"
+	ReturnStatusRefundFailed = "refund_failed"
+	ReturnStatusClosed       = "closed"
+)
+
+// Return reasons
+const (
+	ReturnReasonDamaged        = "damaged"
+	ReturnReasonDefective      = "defective"
+	ReturnReasonWrongItem      = "wrong_item"
+	ReturnReasonNotAsDescribed = "not_as_described"
+	ReturnReasonNoLongerNeeded = "no_longer_needed"
+	ReturnReasonOther          = "other"
+)
+
+// Where a return refund goes
+const (
+	ReturnRefundOriginal    = "original"
+	ReturnRefundStoreCredit = "store_credit"
+)
+
+// Allowed return status transitions, rejected, cancelled and closed are final. Refunded only moves on
+// to refund_failed when its refund fails, a failed refund is retried or closed.
+var returnTransitions = map[string][]string{
+	ReturnStatusRequested:    {ReturnStatusApproved, ReturnStatusRejected, ReturnStatusCancelled},
+	ReturnStatusApproved:     {ReturnStatusReceived, ReturnStatusCancelled},
+	ReturnStatusReceived:     {ReturnStatusRefunded, ReturnStatusClosed},
+	ReturnStatusRefunded:     {ReturnStatusRefundFailed},
+	ReturnStatusRefundFailed: {ReturnStatusRefunded, ReturnStatusClosed},
+}
+
+// Return request (RMA) of delivered order items
+type Return struct {
+	ReturnID       uuid.UUID      `json:"return_id" db:"return_id"`
+	OrderID        uuid.UUID      `json:"order_id" db:"order_id"`
+	UserID         *uuid.UUID     `json:"user_id,omitempty" db:"user_id"`
+	Status         string         `json:"status" db:"status"`
+	Note           string         `json:"note" db:"note"`
+	LabelCarrier   *string        `json:"label_carrier,omitempty" db:"label_carrier"`
+	LabelTracking  *string        `json:"label_tracking,omitempty" db:"label_tracking"`
+	LabelURL       *string        `json:"label_url,omitempty" db:"label_url"`
+	Currency       string         `json:"currency" db:"currency"`
+	RefundAmount   int64          `json:"refund_amount" db:"refund_amount"`
+	RefundMethod   string         `json:"refund_method" db:"refund_method"`
+	RefundedAmount int64          `json:"refunded_amount" db:"refunded_amount"`
+	CreatedAt      time.Time      `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at,omitempty" db:"updated_at"`
+	Items          []*ReturnItem  `json:"items,omitempty" db:"-"`
+	Events         []*ReturnEvent `json:"events,omitempty" db:"-"`
+}
+
+// Returned quantity of an order line with the outcome of its inspection
+type ReturnItem struct {
+	ReturnItemID      uuid.UUID `json:"return_item_id" db:"return_item_id"`
+	ReturnID          uuid.UUID `json:"-" db:"return_id"`
+	OrderItemID       uuid.UUID `json:"order_item_id" db:"order_item_id"`
+	SKU               string    `json:"sku" db:"sku"`
+	Quantity          int       `json:"quantity" db:"quantity"`
+	Reason            string    `json:"reason" db:"reason"`
+	Note              string    `json:"note" db:"note"`
+	ReceivedQuantity  int       `json:"received_quantity" db:"received_quantity"`
+	RestockedQuantity int       `json:"restocked_quantity" db:"restocked_quantity"`
+	InspectionNote    string    `json:"inspection_note" db:"inspection_note"`
+	RefundAmount      int64     `json:"refund_amount" db:"refund_amount"`
+}
+
+// Return history entry
+type ReturnEvent struct {
+	EventID    uuid.UUID  `json:"event_id" db:"event_id"`
+	ReturnID   uuid.UUID  `json:"-" db:"return_id"`
+	FromStatus *string    `json:"from_status,omitempty" db:"from_status"`
+	ToStatus   string     `json:"to_status" db:"to_status"`
+	Note       string     `json:"note" db:"note"`
+	ActorID    *uuid.UUID `json:"actor_id,omitempty" db:"actor_id"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Return request of the customer, an order line may only appear once
+type ReturnInput struct {
+	OrderID uuid.UUID          `json:"order_id" validate:"required"`
+	Note    string             `json:"note" validate:"omitempty,lte=1000"`
+	Items   []*ReturnItemInput `json:"items" validate:"required,min=1,max=100,dive,required"`
+}
+
+// Requested quantity of an order line
+type ReturnItemInput struct {
+	OrderItemID uuid.UUID `json:"order_item_id" validate:"required"`
+	Quantity    int       `json:"quantity" validate:"gte=1"`
+	Reason      string    `json:"reason" validate:"required,oneof=damaged defective wrong_item not_as_described no_longer_needed other"`
+	Note        string    `json:"note" validate:"omitempty,lte=500"`
+}
+
+// Approval with the return shipping label, the label is a placeholder filled in by hand until a carrier issues labels
+type ReturnApproval struct {
+	LabelCarrier  *string `json:"label_carrier" validate:"omitempty,lte=64"`
+	LabelTracking *string `json:"label_tracking" validate:"omitempty,lte=100"`
+	LabelURL      *string `json:"label_url" validate:"omitempty,lte=512,url"`
+	Note          string  `json:"note" validate:"omitempty,lte=500"`
+}
+
+// Reject, cancel or close request
+type ReturnDecision struct {
+	Note string `json:"note" validate:"omitempty,lte=500"`
+}
+
+// Inspection of the received parcel, items left out count as not received
+type ReturnReceipt struct {
+	Items []*ReturnReceiptItem `json:"items" validate:"omitempty,max=100,dive,required"`
+	Note  string               `json:"note" validate:"omitempty,lte=500"`
+}
+
+// Received and restocked quantity of a return item, restocked units go back to stock
+type ReturnReceiptItem struct {
+	ReturnItemID     uuid.UUID `json:"return_item_id" validate:"required"`
+	ReceivedQuantity int       `json:"received_quantity" validate:"gte=0"`
+	RestockQuantity  int       `json:"restock_quantity" validate:"gte=0"`
+	InspectionNote   string    `json:"inspection_note" validate:"omitempty,lte=500"`
+}
+
+// Refund request, zero refunds the paid price of the received items. The refund goes back to the original
+// payment unless method is store_credit, what the order paid with gift cards or store credit goes to store credit. Retrying a failed refund refunds what is still owed with the same method.
+type ReturnRefund struct {
+	Amount int64  `json:"amount" validate:"gte=0"`
+	Method string `json:"method" validate:"omitempty,oneof=original store_credit"`
+	Note   string `json:"note" validate:"omitempty,lte=500"`
+}
+
+// Return list query params
+type ReturnsQuery struct {
+	Status string `query:"status" validate:"omitempty,oneof=requested approved rejected cancelled received refunded refund_failed closed"`
+}
+
+// All returns response
+type ReturnsList struct {
+	TotalCount int       `json:"total_count"`
+	TotalPages int       `json:"total_pages"`
+	Page       int       `json:"page"`
+	Size       int       `json:"size"`
+	HasMore    bool      `json:"has_more"`
+	Returns    []*Return `json:"returns"`
+}
+
+// Check if the return can move from one status to another
+func CanTransitionReturn(from string, to string) bool {
+	for _, status := range returnTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Prepare return request
+func (r *ReturnInput) Prepare() {
+	r.Note = strings.TrimSpace(r.Note)
+	for _, item := range r.Items {
+		item.Note = strings.TrimSpace(item.Note)
+	}
+}
+
+// Prepare approval
+func (a *ReturnApproval) Prepare() {
+	a.LabelCarrier = trimOptional(a.LabelCarrier)
+	a.LabelTracking = trimOptional(a.LabelTracking)
+	a.LabelURL = trimOptional(a.LabelURL)
+	a.Note = strings.TrimSpace(a.Note)
+}
+
+// Prepare decision
+func (d *ReturnDecision) Prepare() {
+	d.Note = strings.TrimSpace(d.Note)
+}
+
+// Prepare receipt
+func (r *ReturnReceipt) Prepare() {
+	r.Note = strings.TrimSpace(r.Note)
+	for _, item := range r.Items {
+		item.InspectionNote = strings.TrimSpace(item.InspectionNote)
+	}
+}
+
+// Prepare refund request
+func (r *ReturnRefund) Prepare() {
+	r.Note = strings.TrimSpace(r.Note)
+	if r.Method == "" {
+		r.Method = ReturnRefundOriginal
+	}
+}
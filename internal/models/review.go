@@ -0,0 +1,61 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Review moderation statuses
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
+// Product review, one per user per product
+type Review struct {
+	ReviewID         uuid.UUID  `json:"review_id" db:"review_id" validate:"omitempty"`
+	ProductID        uuid.UUID  `json:"product_id" db:"product_id" validate:"omitempty"`
+	UserID           uuid.UUID  `json:"user_id" db:"user_id" validate:"omitempty"`
+	Rating           int        `json:"rating" db:"rating" validate:"required,gte=1,lte=5"`
+	Title            string     `json:"title" db:"title" validate:"omitempty,lte=150"`
+	Body             string     `json:"body" db:"body" validate:"omitempty,lte=5000"`
+	Status           string     `json:"status" db:"status"`
+	VerifiedPurchase bool       `json:"verified_purchase" db:"verified_purchase"`
+	HelpfulCount     int        `json:"helpful_count" db:"helpful_count"`
+	ModeratedBy      *uuid.UUID `json:"moderated_by,omitempty" db:"moderated_by"`
+	ModeratedAt      *time.Time `json:"moderated_at,omitempty" db:"moderated_at"`
+	CreatedAt        time.Time  `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at,omitempty" db:"updated_at"`
+	AuthorName       string     `json:"author_name" db:"author_name"`
+}
+
+// Moderation decision
+type ReviewModeration struct {
+	Status string `json:"status" validate:"required,oneof=approved rejected"`
+}
+
+// Review list query params
+type ReviewsQuery struct {
+	Sort   string `query:"sort" validate:"omitempty,oneof=newest helpful rating_desc rating_asc"`
+	Status string `query:"status" validate:"omitempty,oneof=pending approved rejected"`
+}
+
+// All reviews response
+type ReviewsList struct {
+	TotalCount int       `json:"total_count"`
+	TotalPages int       `json:"total_pages"`
+	Page       int       `json:"page"`
+	Size       int       `json:"size"`
+	HasMore    bool      `json:"has_more"`
+	Reviews    []*Review `json:"reviews"`
+}
+
+// Prepare review for create or update, edited reviews go back to moderation
+func (r *Review) Prepare() {
+	r.Title = strings.TrimSpace(r.Title)
+	r.Body = strings.TrimSpace(r.Body)
+	r.Status = ReviewStatusPending
+}
@@ -0,0 +1,34 @@
+package models
+
+import "github.com/google/uuid"
+
+// Search query params
+type SearchQuery struct {
+	Query string `query:"q" validate:"required,lte=100"`
+}
+
+// Sellable item matching a search query, TitleHighlight and Snippet are escaped HTML with matches in <mark>
+type SearchResult struct {
+	VariantID      uuid.UUID `json:"variant_id" db:"variant_id"`
+	ProductID      uuid.UUID `json:"product_id" db:"product_id"`
+	SKU            string    `json:"sku" db:"sku"`
+	Slug           string    `json:"slug" db:"slug"`
+	Title          string    `json:"title" db:"title"`
+	VariantTitle   string    `json:"variant_title" db:"variant_title"`
+	Price          int64     `json:"price" db:"price"`
+	Currency       string    `json:"currency" db:"currency"`
+	Rank           float64   `json:"rank" db:"rank"`
+	TitleHighlight string    `json:"title_highlight" db:"title_highlight"`
+	Snippet        string    `json:"snippet" db:"snippet"`
+}
+
+// Search results response
+type SearchResultsList struct {
+	Query      string          `json:"query"`
+	TotalCount int             `json:"total_count"`
+	TotalPages int             `json:"total_pages"`
+	Page       int             `json:"page"`
+	Size       int             `json:"size"`
+	HasMore    bool            `json:"has_more"`
+	Results    []*SearchResult `json:"results"`
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Shipping method kinds
+const (
+	ShippingKindFlat     = "flat"
+	ShippingKindWeight   = "weight"
+	ShippingKindFreeOver = "free_over"
+	ShippingKindPickup   = "pickup"
+	ShippingKindCarrier  = "carrier"
+)
+
+// Shipping zone of a country, optionally narrowed to a region and postcode pattern. The most specific
+// matching zone offers its methods, priority breaks ties.
+type ShippingZone struct {
+	ZoneID          uuid.UUID         `json:"zone_id" db:"zone_id"`
+	Name            string            `json:"name" db:"name" validate:"required,lte=100"`
+	Country         string            `json:"country" db:"country" validate:"required,lte=30"`
+	Region          *string           `json:"region,omitempty" db:"region" validate:"omitempty,lte=30"`
+	PostcodePattern *string           `json:"postcode_pattern,omitempty" db:"postcode_pattern" validate:"omitempty,lte=30"`
+	Priority        int               `json:"priority" db:"priority"`
+	CreatedAt       time.Time         `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at,omitempty" db:"updated_at"`
+	Methods         []*ShippingMethod `json:"methods,omitempty" db:"-"`
+}
+
+// Shipping method of a zone. Flat and pickup methods cost price, weight methods take the price of the first
+// weight rate the parcel fits in, free_over methods are free from free_over subtotal and cost price below it,
+// carrier methods quote carrier_service of the configured carrier.
+type ShippingMethod struct {
+	MethodID       uuid.UUID   `json:"method_id" db:"method_id"`
+	ZoneID         uuid.UUID   `json:"zone_id" db:"zone_id"`
+	Name           string      `json:"name" db:"name" validate:"required,lte=100"`
+	Kind           string      `json:"kind" db:"kind" validate:"required,oneof=flat weight free_over pickup carrier"`
+	Price          int64       `json:"price" db:"price" validate:"gte=0"`
+	Currency       string      `json:"currency" db:"currency" validate:"omitempty,len=3,alpha"`
+	FreeOver       *int64      `json:"free_over,omitempty" db:"free_over" validate:"omitempty,gte=0"`
+	WeightRates    WeightRates `json:"weight_rates" db:"weight_rates" validate:"omitempty,max=50,dive,required"`
+	CarrierService *string     `json:"carrier_service,omitempty" db:"carrier_service" validate:"omitempty,lte=64"`
+	EstimatedDays  *int        `json:"estimated_days,omitempty" db:"estimated_days" validate:"omitempty,gte=0"`
+	Active         bool        `json:"active" db:"active"`
+	Position       int         `json:"position" db:"position"`
+	CreatedAt      time.Time   `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// Price of parcels up to max grams
+type WeightRate struct {
+	MaxGrams int   `json:"max_grams" validate:"gte=1"`
+	Price    int64 `json:"price" validate:"gte=0"`
+}
+
+// Weight rates stored as JSONB array
+type WeightRates []*WeightRate
+
+// Destination of an order, rate quotes only need country, region and postcode
+type ShippingAddress struct {
+	Country  string  `json:"country" validate:"required,lte=30"`
+	Region   string  `json:"region" validate:"omitempty,lte=50"`
+	Postcode string  `json:"postcode" validate:"omitempty,lte=30"`
+	Name     string  `json:"name,omitempty" validate:"omitempty,lte=70"`
+	Company  *string `json:"company,omitempty" validate:"omitempty,lte=100"`
+	Line1    string  `json:"line1,omitempty" validate:"omitempty,lte=250"`
+	Line2    *string `json:"line2,omitempty" validate:"omitempty,lte=250"`
+	City     string  `json:"city,omitempty" validate:"omitempty,lte=50"`
+	Phone    *string `json:"phone,omitempty" validate:"omitempty,lte=20"`
+}
+
+// Rate quote request for the current cart
+type ShippingQuoteInput struct {
+	Address ShippingAddress `json:"address" validate:"required"`
+}
+
+// Price of one shipping method for a cart
+type ShippingRate struct {
+	MethodID      uuid.UUID `json:"method_id"`
+	Name          string    `json:"name"`
+	Kind          string    `json:"kind"`
+	Price         int64     `json:"price"`
+	Currency      string    `json:"currency"`
+	EstimatedDays *int      `json:"estimated_days,omitempty"`
+}
+
+// Shipping methods available for a cart and destination, cheapest first
+type ShippingQuote struct {
+	ZoneID      *uuid.UUID      `json:"zone_id,omitempty"`
+	WeightGrams int             `json:"weight_grams"`
+	Currency    string          `json:"currency"`
+	Rates       []*ShippingRate `json:"rates"`
+}
+
+// Prepare zone for create and update
+func (z *ShippingZone) Prepare() {
+	z.Name = strings.TrimSpace(z.Name)
+	z.Country = strings.TrimSpace(z.Country)
+	z.Region = trimOptional(z.Region)
+	z.PostcodePattern = trimOptional(z.PostcodePattern)
+}
+
+// Prepare method for create and update
+func (m *ShippingMethod) Prepare() {
+	m.Name = strings.TrimSpace(m.Name)
+	m.Currency = strings.ToUpper(strings.TrimSpace(m.Currency))
+	m.CarrierService = trimOptional(m.CarrierService)
+
+	if m.Currency == "" {
+		m.Currency = DefaultCurrency
+	}
+}
+
+// Tax address of the destination
+func (a *ShippingAddress) TaxAddress() TaxAddress {
+	return TaxAddress{Country: a.Country, Region: a.Region, Postcode: a.Postcode}
+}
+
+func (r WeightRates) Value() (driver.Value, error) {
+	if r == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(r)
+}
+
+func (r *WeightRates) Scan(src interface{}) error {
+	return scanJSONB(src, r)
+}
+
+func (a *ShippingAddress) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	return json.Marshal(a)
+}
+
+func (a *ShippingAddress) Scan(src interface{}) error {
+	return scanJSONB(src, a)
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store credit ledger reasons, redemptions are negative and adjustments may be
+const (
+	StoreCreditRefund     = "refund"
+	StoreCreditGoodwill   = "goodwill"
+	StoreCreditAdjustment = "adjustment"
+	StoreCreditRedemption = "redemption"
+	StoreCreditReversal   = "reversal"
+)
+
+// Store credit ledger entry, entries are never changed
+type StoreCreditEntry struct {
+	EntryID   uuid.UUID  `json:"entry_id" db:"entry_id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	Currency  string     `json:"currency" db:"currency"`
+	Amount    int64      `json:"amount" db:"amount"`
+	Reason    string     `json:"reason" db:"reason"`
+	OrderID   *uuid.UUID `json:"order_id,omitempty" db:"order_id"`
+	ReturnID  *uuid.UUID `json:"return_id,omitempty" db:"return_id"`
+	Note      string     `json:"note" db:"note"`
+	ActorID   *uuid.UUID `json:"actor_id,omitempty" db:"actor_id"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Store credit balance of a user in one currency
+type StoreCreditBalance struct {
+	Currency string `json:"currency" db:"currency"`
+	Balance  int64  `json:"balance" db:"balance"`
+}
+
+// Credit granted by an admin, adjustments may be negative but can't take the balance below zero
+type StoreCreditInput struct {
+	Amount   int64  `json:"amount" validate:"required"`
+	Currency string `json:"currency" validate:"omitempty,len=3,alpha"`
+	Reason   string `json:"reason" validate:"required,oneof=goodwill adjustment"`
+	Note     string `json:"note" validate:"omitempty,lte=500"`
+}
+
+// Store credit ledger response
+type StoreCreditList struct {
+	TotalCount int                   `json:"total_count"`
+	TotalPages int                   `json:"total_pages"`
+	Page       int                   `json:"page"`
+	Size       int                   `json:"size"`
+	HasMore    bool                  `json:"has_more"`
+	Balances   []*StoreCreditBalance `json:"balances"`
+	Entries    []*StoreCreditEntry   `json:"entries"`
+}
+
+// Prepare store credit grant
+func (i *StoreCreditInput) Prepare() {
+	i.Currency = strings.ToUpper(strings.TrimSpace(i.Currency))
+	if i.Currency == "" {
+		i.Currency = DefaultCurrency
+	}
+	i.Note = strings.TrimSpace(i.Note)
+}
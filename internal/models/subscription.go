@@ -0,0 +1,164 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Subscription statuses, past_due subscriptions are retrying a failed charge
+const (
+	SubscriptionStatusActive    = "active"
+	SubscriptionStatusPastDue   = "past_due"
+	SubscriptionStatusPaused    = "paused"
+	SubscriptionStatusSuspended = "suspended"
+	SubscriptionStatusCancelled = "cancelled"
+)
+
+// Subscription interval units
+const (
+	SubscriptionIntervalDay   = "day"
+	SubscriptionIntervalWeek  = "week"
+	SubscriptionIntervalMonth = "month"
+)
+
+// Subscription run outcomes
+const (
+	SubscriptionRunSucceeded = "succeeded"
+	SubscriptionRunFailed    = "failed"
+)
+
+// Allowed subscription status transitions, cancelled is final
+var subscriptionTransitions = map[string][]string{
+	SubscriptionStatusActive:    {SubscriptionStatusPastDue, SubscriptionStatusPaused, SubscriptionStatusCancelled},
+	SubscriptionStatusPastDue:   {SubscriptionStatusActive, SubscriptionStatusPaused, SubscriptionStatusSuspended, SubscriptionStatusCancelled},
+	SubscriptionStatusPaused:    {SubscriptionStatusActive, SubscriptionStatusCancelled},
+	SubscriptionStatusSuspended: {SubscriptionStatusActive, SubscriptionStatusCancelled},
+}
+
+// Recurring order of the same items, charged to a saved payment method of the subscriber.
+// NextRunAt is the next delivery date, RetryAt when the failed charge of that delivery is tried again.
+type Subscription struct {
+	SubscriptionID    uuid.UUID           `json:"subscription_id" db:"subscription_id"`
+	UserID            uuid.UUID           `json:"user_id" db:"user_id"`
+	Email             string              `json:"-" db:"email"`
+	Status            string              `json:"status" db:"status"`
+	IntervalUnit      string              `json:"interval_unit" db:"interval_unit"`
+	IntervalCount     int                 `json:"interval_count" db:"interval_count"`
+	NextRunAt         time.Time           `json:"next_run_at" db:"next_run_at"`
+	RetryAt           *time.Time          `json:"retry_at,omitempty" db:"retry_at"`
+	FailedAttempts    int                 `json:"failed_attempts" db:"failed_attempts"`
+	LastError         string              `json:"last_error,omitempty" db:"last_error"`
+	ShippingAddressID *uuid.UUID          `json:"shipping_address_id,omitempty" db:"shipping_address_id"`
+	ShippingMethodID  *uuid.UUID          `json:"shipping_method_id,omitempty" db:"shipping_method_id"`
+	PaymentMethodID   *uuid.UUID          `json:"payment_method_id,omitempty" db:"payment_method_id"`
+	LastOrderID       *uuid.UUID          `json:"last_order_id,omitempty" db:"last_order_id"`
+	ClaimedUntil      *time.Time          `json:"-" db:"claimed_until"`
+	CancelledAt       *time.Time          `json:"cancelled_at,omitempty" db:"cancelled_at"`
+	CreatedAt         time.Time           `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt         time.Time           `json:"updated_at,omitempty" db:"updated_at"`
+	Items             []*SubscriptionItem `json:"items,omitempty" db:"-"`
+	Runs              []*SubscriptionRun  `json:"runs,omitempty" db:"-"`
+}
+
+// Variant and quantity delivered every run
+type SubscriptionItem struct {
+	SubscriptionID uuid.UUID `json:"-" db:"subscription_id"`
+	SKU            string    `json:"sku" db:"sku"`
+	Quantity       int       `json:"quantity" db:"quantity"`
+}
+
+// Order attempt of a subscription
+type SubscriptionRun struct {
+	RunID          uuid.UUID  `json:"run_id" db:"run_id"`
+	SubscriptionID uuid.UUID  `json:"-" db:"subscription_id"`
+	ScheduledAt    time.Time  `json:"scheduled_at" db:"scheduled_at"`
+	Attempt        int        `json:"attempt" db:"attempt"`
+	Status         string     `json:"status" db:"status"`
+	OrderID        *uuid.UUID `json:"order_id,omitempty" db:"order_id"`
+	PaymentID      *uuid.UUID `json:"payment_id,omitempty" db:"payment_id"`
+	Error          string     `json:"error,omitempty" db:"error"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Subscription create and update request, the payment method is one the subscriber saved before.
+// Without a start the first order of a new subscription is placed right away, a given start also moves the next
+// delivery of an existing subscription.
+type SubscriptionInput struct {
+	Items             []*SubscriptionItemInput `json:"items" validate:"required,min=1,max=20,dive,required"`
+	IntervalUnit      string                   `json:"interval_unit" validate:"required,oneof=day week month"`
+	IntervalCount     int                      `json:"interval_count" validate:"gte=1,lte=12"`
+	StartAt           *time.Time               `json:"start_at"`
+	ShippingAddressID *uuid.UUID               `json:"shipping_address_id"`
+	ShippingMethodID  *uuid.UUID               `json:"shipping_method_id"`
+	PaymentMethodID   uuid.UUID                `json:"payment_method_id" validate:"required"`
+}
+
+// Variant and quantity of a subscription request, a SKU may only appear once
+type SubscriptionItemInput struct {
+	SKU      string `json:"sku" validate:"required,lte=64"`
+	Quantity int    `json:"quantity" validate:"gte=1,lte=99"`
+}
+
+// Subscription list query params
+type SubscriptionsQuery struct {
+	Status string `query:"status" validate:"omitempty,oneof=active past_due paused suspended cancelled"`
+}
+
+// All subscriptions response
+type SubscriptionsList struct {
+	TotalCount    int             `json:"total_count"`
+	TotalPages    int             `json:"total_pages"`
+	Page          int             `json:"page"`
+	Size          int             `json:"size"`
+	HasMore       bool            `json:"has_more"`
+	Subscriptions []*Subscription `json:"subscriptions"`
+}
+
+// Check if the subscription can move from one status to another
+func CanTransitionSubscription(from string, to string) bool {
+	for _, status := range subscriptionTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// One interval after t. Months keep the day of t, clamped to the end of shorter months.
+func (s *Subscription) After(t time.Time) time.Time {
+	switch s.IntervalUnit {
+	case SubscriptionIntervalDay:
+		return t.AddDate(0, 0, s.IntervalCount)
+	case SubscriptionIntervalWeek:
+		return t.AddDate(0, 0, 7*s.IntervalCount)
+	default:
+		return addMonths(t, s.IntervalCount)
+	}
+}
+
+// Next delivery after the current one that is not in the past
+func (s *Subscription) NextAfter(now time.Time) time.Time {
+	next := s.After(s.NextRunAt)
+	for !next.After(now) {
+		next = s.After(next)
+	}
+	return next
+}
+
+// Prepare subscription request
+func (i *SubscriptionInput) Prepare() {
+	for _, item := range i.Items {
+		item.SKU = strings.TrimSpace(item.SKU)
+	}
+}
+
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	lastDay := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month+time.Month(months), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
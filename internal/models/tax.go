@@ -0,0 +1,115 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tax class of products without one
+const TaxClassStandard = "standard"
+
+// Tax rounding modes
+const (
+	TaxRoundingLine  = "line"
+	TaxRoundingOrder = "order"
+)
+
+// One million, rates are stored in millionths of the price
+const TaxRateScale = 1000000
+
+// Tax zone of a country, optionally narrowed to a region and postcode pattern. The most specific
+// matching zone applies, priority breaks ties.
+type TaxZone struct {
+	ZoneID           uuid.UUID  `json:"zone_id" db:"zone_id"`
+	Name             string     `json:"name" db:"name" validate:"required,lte=100"`
+	Country          string     `json:"country" db:"country" validate:"required,lte=30"`
+	Region           *string    `json:"region,omitempty" db:"region" validate:"omitempty,lte=30"`
+	PostcodePattern  *string    `json:"postcode_pattern,omitempty" db:"postcode_pattern" validate:"omitempty,lte=30"`
+	Priority         int        `json:"priority" db:"priority"`
+	PricesIncludeTax bool       `json:"prices_include_tax" db:"prices_include_tax"`
+	Rounding         string     `json:"rounding" db:"rounding" validate:"omitempty,oneof=line order"`
+	CreatedAt        time.Time  `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at,omitempty" db:"updated_at"`
+	Rates            []*TaxRate `json:"rates,omitempty" db:"-"`
+}
+
+// Rate of a tax class in a zone, classes without rate are not taxed
+type TaxRate struct {
+	RateID    uuid.UUID `json:"rate_id" db:"rate_id"`
+	ZoneID    uuid.UUID `json:"zone_id" db:"zone_id"`
+	TaxClass  string    `json:"tax_class" db:"tax_class"`
+	Name      string    `json:"name" db:"name" validate:"required,lte=100"`
+	RatePPM   int64     `json:"rate_ppm" db:"rate_ppm" validate:"gte=0,lte=1000000"`
+	CreatedAt time.Time `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// Address taxes are calculated for
+type TaxAddress struct {
+	Country  string `json:"country" validate:"required,lte=30"`
+	Region   string `json:"region" validate:"omitempty,lte=30"`
+	Postcode string `json:"postcode" validate:"omitempty,lte=30"`
+}
+
+// Taxable line, amount is the line total after discounts
+type TaxLine struct {
+	Ref      string `json:"ref" validate:"required,lte=64"`
+	TaxClass string `json:"tax_class" validate:"omitempty,lte=32"`
+	Amount   int64  `json:"amount" validate:"gte=0"`
+}
+
+// Tax calculation request
+type TaxRequest struct {
+	Address  TaxAddress `json:"address" validate:"required"`
+	Currency string     `json:"currency" validate:"omitempty,len=3,alpha"`
+	Lines    []*TaxLine `json:"lines" validate:"required,min=1,max=500,dive"`
+}
+
+// Tax of one line
+type TaxLineResult struct {
+	Ref     string `json:"ref"`
+	RatePPM int64  `json:"rate_ppm"`
+	Tax     int64  `json:"tax"`
+}
+
+// Calculated taxes, when prices include tax the tax is part of the line amounts
+type TaxResult struct {
+	ZoneID           *uuid.UUID       `json:"zone_id,omitempty"`
+	PricesIncludeTax bool             `json:"prices_include_tax"`
+	Lines            []*TaxLineResult `json:"lines"`
+	TaxTotal         int64            `json:"tax_total"`
+}
+
+// Prepare zone for create and update
+func (z *TaxZone) Prepare() {
+	z.Name = strings.TrimSpace(z.Name)
+	z.Country = strings.TrimSpace(z.Country)
+	z.Region = trimOptional(z.Region)
+	z.PostcodePattern = trimOptional(z.PostcodePattern)
+
+	if z.Rounding == "" {
+		z.Rounding = TaxRoundingLine
+	}
+}
+
+// Tax classes are lower case
+func NormalizeTaxClass(class string) string {
+	class = strings.ToLower(strings.TrimSpace(class))
+	if class == "" {
+		return TaxClassStandard
+	}
+	return class
+}
+
+func trimOptional(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*s)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
@@ -8,6 +8,12 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 // User full model
 type User struct {
 	UserID      uuid.UUID  `json:"user_id" db:"user_id" redis:"user_id" validate:"omitempty"`
@@ -19,11 +25,7 @@ type User struct {
 	About       *string    `json:"about,omitempty" db:"about" redis:"about" validate:"omitempty,lte=1024"`
 	Avatar      *string    `json:"avatar,omitempty" db:"avatar" redis:"avatar" validate:"omitempty,lte=512,url"`
 	PhoneNumber *string    `json:"phone_number,omitempty" db:"phone_number" redis:"phone_number" validate:"omitempty,lte=20"`
-	Address     *string    `json:"address,omitempty" db:"address" redis:"address" validate:"omitempty,lte=250"`
-	City        *string    `json:"city,omitempty" db:"city" redis:"city" validate:"omitempty,lte=24"`
-	Country     *string    `json:"country,omitempty" db:"country" redis:"country" validate:"omitempty,lte=24"`
 	Gender      *string    `json:"gender,omitempty" db:"gender" redis:"gender" validate:"omitempty,lte=10"`
-	Postcode    *int       `json:"postcode,omitempty" db:"postcode" redis:"postcode" validate:"omitempty"`
 	Birthday    *time.Time `json:"birthday,omitempty" db:"birthday" redis:"birthday" validate:"omitempty,lte=10"`
 	CreatedAt   time.Time  `json:"created_at,omitempty" db:"created_at" redis:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at,omitempty" db:"updated_at" redis:"updated_at"`
@@ -85,3 +87,8 @@ func (u *User) PrepareCreate() error {
 func (u *User) SanitizePassword() {
 	u.Password = ""
 }
+
+// Check whether the user has the admin role
+func (u *User) IsAdmin() bool {
+	return u.Role != nil && *u.Role == RoleAdmin
+}
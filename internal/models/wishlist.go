@@ -0,0 +1,64 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Named list of saved items, shared publicly while share token is set
+type Wishlist struct {
+	WishlistID uuid.UUID       `json:"wishlist_id" db:"wishlist_id" validate:"omitempty"`
+	UserID     uuid.UUID       `json:"user_id" db:"user_id" validate:"omitempty"`
+	Name       string          `json:"name" db:"name" validate:"required,lte=100"`
+	ShareToken *string         `json:"share_token,omitempty" db:"share_token"`
+	CreatedAt  time.Time       `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at,omitempty" db:"updated_at"`
+	Items      []*WishlistItem `json:"items,omitempty" db:"-"`
+}
+
+// Saved variant with current product data
+type WishlistItem struct {
+	WishlistID   uuid.UUID `json:"wishlist_id" db:"wishlist_id"`
+	VariantID    uuid.UUID `json:"variant_id" db:"variant_id" validate:"required"`
+	Position     int       `json:"position" db:"position"`
+	AddedAt      time.Time `json:"added_at" db:"added_at"`
+	ProductID    uuid.UUID `json:"product_id" db:"product_id"`
+	SKU          string    `json:"sku" db:"sku"`
+	Slug         string    `json:"slug" db:"slug"`
+	Title        string    `json:"title" db:"title"`
+	VariantTitle string    `json:"variant_title" db:"variant_title"`
+	Price        int64     `json:"price" db:"price"`
+	Currency     string    `json:"currency" db:"currency"`
+	Available    bool      `json:"available" db:"available"`
+}
+
+// New wishlist item order
+type WishlistOrder struct {
+	VariantIDs []uuid.UUID `json:"variant_ids" validate:"required,unique"`
+}
+
+// Shared wishlist as seen by anyone with the link, owner is not exposed
+type SharedWishlist struct {
+	Name  string          `json:"name"`
+	Items []*WishlistItem `json:"items"`
+}
+
+// Prepare wishlist for create or rename
+func (w *Wishlist) Prepare() {
+	w.Name = strings.TrimSpace(w.Name)
+}
+
+// Unguessable url safe token, 256 bits of randomness
+func NewShareToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "NewShareToken.Read")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,12 @@
+package order
+
+import "github.com/labstack/echo/v4"
+
+// Order HTTP Handlers interface
+type Handlers interface {
+	GetByID() echo.HandlerFunc
+	ListMine() echo.HandlerFunc
+	List() echo.HandlerFunc
+	Cancel() echo.HandlerFunc
+	Transition() echo.HandlerFunc
+}
@@ -0,0 +1,184 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/order"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Order handlers
+type orderHandlers struct {
+	cfg     *config.Config
+	logger  logger.Logger
+	orderUC order.UseCase
+}
+
+// Order handlers constructor
+func NewOrderHandlers(cfg *config.Config, logger logger.Logger, orderUC order.UseCase) order.Handlers {
+	return &orderHandlers{
+		cfg:     cfg,
+		logger:  logger,
+		orderUC: orderUC,
+	}
+}
+
+// GetByID godoc
+// @Summary Get order by id
+// @Description get own order with items and status history, admins can get any order
+// @Tags Orders
+// @Produce json
+// @Param order_id path string true "order_id"
+// @Success 200 {object} models.Order
+// @Failure 404 {object} httpErrors.RestError
+// @Router /orders/{order_id} [get]
+func (h *orderHandlers) GetByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		orderID, err := uuid.Parse(c.Param("order_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		o, err := h.orderUC.GetByID(ctx, orderID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, o)
+	}
+}
+
+// ListMine godoc
+// @Summary Get my orders
+// @Description get own orders, newest first
+// @Tags Orders
+// @Produce json
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.OrdersList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /orders/me [get]
+func (h *orderHandlers) ListMine() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		ordersList, err := h.orderUC.ListMine(ctx, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, ordersList)
+	}
+}
+
+// List godoc
+// @Summary Get orders
+// @Description get orders of every user, optionally by status, admin only
+// @Tags Orders
+// @Produce json
+// @Param status query string false "order status"
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.OrdersList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /orders [get]
+func (h *orderHandlers) List() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		query := &models.OrdersQuery{}
+		if err := utils.ReadRequest(c, query); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		ordersList, err := h.orderUC.List(ctx, query, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, ordersList)
+	}
+}
+
+// Cancel godoc
+// @Summary Cancel order
+// @Description cancel own order while it is pending
+// @Tags Orders
+// @Produce json
+// @Param order_id path string true "order_id"
+// @Success 200 {object} models.Order
+// @Failure 409 {object} httpErrors.RestError
+// @Router /orders/{order_id}/cancel [post]
+func (h *orderHandlers) Cancel() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		orderID, err := uuid.Parse(c.Param("order_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		o, err := h.orderUC.Cancel(ctx, orderID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, o)
+	}
+}
+
+// Transition godoc
+// @Summary Change order status
+// @Description move order to next status, pending > paid > fulfilled > shipped > delivered, pending orders can be cancelled and paid ones refunded, admin only
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param order_id path string true "order_id"
+// @Success 200 {object} models.Order
+// @Failure 409 {object} httpErrors.RestError
+// @Router /orders/{order_id}/status [put]
+func (h *orderHandlers) Transition() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		orderID, err := uuid.Parse(c.Param("order_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		transition := &models.OrderTransition{}
+		if err = utils.ReadRequest(c, transition); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		o, err := h.orderUC.Transition(ctx, orderID, transition)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, o)
+	}
+}
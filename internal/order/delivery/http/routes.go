@@ -0,0 +1,18 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/order"
+	"github.com/labstack/echo/v4"
+)
+
+func MapOrderRoutes(orderGroup *echo.Group, h order.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	orderGroup.GET("", h.List(), adminOnly...)
+	orderGroup.GET("/me", h.ListMine(), mw.AuthJWTMiddleware)
+	orderGroup.GET("/:order_id", h.GetByID(), mw.AuthJWTMiddleware)
+	orderGroup.POST("/:order_id/cancel", h.Cancel(), mw.AuthJWTMiddleware)
+	orderGroup.PUT("/:order_id/status", h.Transition(), adminOnly...)
+}
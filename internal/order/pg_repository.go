@@ -0,0 +1,23 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Order repository
+type Repository interface {
+	GetByID(ctx context.Context, orderID uuid.UUID) (*models.Order, error)
+	GetItems(ctx context.Context, orderID uuid.UUID) ([]*models.OrderItem, error)
+	GetDiscounts(ctx context.Context, orderID uuid.UUID) ([]*models.OrderDiscount, error)
+	GetEvents(ctx context.Context, orderID uuid.UUID) ([]*models.OrderEvent, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, pq *utils.PaginationQuery) (*models.OrdersList, error)
+	List(ctx context.Context, status string, pq *utils.PaginationQuery) (*models.OrdersList, error)
+	UpdateStatus(ctx context.Context, orderID uuid.UUID, from string, event *models.OrderEvent) (*models.Order, error)
+	ListExpiredPending(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+	HasPurchased(ctx context.Context, userID uuid.UUID, productID uuid.UUID) (bool, error)
+}
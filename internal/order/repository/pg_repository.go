@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/order"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Order repository
+type orderRepo struct {
+	db *sqlx.DB
+}
+
+// Order repository constructor
+func NewOrderRepository(db *sqlx.DB) order.Repository {
+	return &orderRepo{db: db}
+}
+
+func (r *orderRepo) GetByID(ctx context.Context, orderID uuid.UUID) (*models.Order, error) {
+	// TODO: Tracing
+
+	o := &models.Order{}
+	if err := r.db.GetContext(ctx, o, getOrderByIdQuery, orderID); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.GetByID.GetContext")
+	}
+
+	return o, nil
+}
+
+func (r *orderRepo) GetItems(ctx context.Context, orderID uuid.UUID) ([]*models.OrderItem, error) {
+	// TODO: Tracing
+
+	items := make([]*models.OrderItem, 0)
+	if err := r.db.SelectContext(ctx, &items, getOrderItemsQuery, orderID); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.GetItems.SelectContext")
+	}
+
+	return items, nil
+}
+
+func (r *orderRepo) GetDiscounts(ctx context.Context, orderID uuid.UUID) ([]*models.OrderDiscount, error) {
+	// TODO: Tracing
+
+	discounts := make([]*models.OrderDiscount, 0)
+	if err := r.db.SelectContext(ctx, &discounts, getOrderDiscountsQuery, orderID); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.GetDiscounts.SelectContext")
+	}
+
+	return discounts, nil
+}
+
+func (r *orderRepo) GetEvents(ctx context.Context, orderID uuid.UUID) ([]*models.OrderEvent, error) {
+	// TODO: Tracing
+
+	events := make([]*models.OrderEvent, 0)
+	if err := r.db.SelectContext(ctx, &events, getOrderEventsQuery, orderID); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.GetEvents.SelectContext")
+	}
+
+	return events, nil
+}
+
+func (r *orderRepo) ListByUser(ctx context.Context, userID uuid.UUID, pq *utils.PaginationQuery) (*models.OrdersList, error) {
+	// TODO: Tracing
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalOrdersByUserQuery, userID); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.ListByUser.GetContext.totalCount")
+	}
+
+	orders := make([]*models.Order, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &orders, listOrdersByUserQuery, userID, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "orderRepo.ListByUser.SelectContext")
+		}
+	}
+
+	return newOrdersList(orders, totalCount, pq), nil
+}
+
+// List orders of every user, empty status lists all
+func (r *orderRepo) List(ctx context.Context, status string, pq *utils.PaginationQuery) (*models.OrdersList, error) {
+	// TODO: Tracing
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalOrdersQuery, status); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.List.GetContext.totalCount")
+	}
+
+	orders := make([]*models.Order, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &orders, listOrdersQuery, status, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "orderRepo.List.SelectContext")
+		}
+	}
+
+	return newOrdersList(orders, totalCount, pq), nil
+}
+
+// Move order to event.ToStatus only if it still has status from, and record the event.
+// Stock reservations are turned into sales when the order is paid and released when it is cancelled,
+// cancelled orders also give back their promotion usage, gift card balances and store credit.
+func (r *orderRepo) UpdateStatus(ctx context.Context, orderID uuid.UUID, from string, event *models.OrderEvent) (*models.Order, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "orderRepo.UpdateStatus.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	updated := &models.Order{}
+	if err = tx.GetContext(ctx, updated, updateOrderStatusQuery, &event.ToStatus, orderID, from); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "orderRepo.UpdateStatus: order is no longer %s", from)
+		}
+		return nil, errors.Wrap(err, "orderRepo.UpdateStatus.GetContext")
+	}
+
+	switch event.ToStatus {
+	case models.OrderStatusPaid:
+		if _, err = tx.ExecContext(ctx, commitReservationsQuery, orderID, event.ActorID); err != nil {
+			return nil, errors.Wrap(err, "orderRepo.UpdateStatus.ExecContext.commitReservations")
+		}
+	case models.OrderStatusCancelled:
+		if _, err = tx.ExecContext(ctx, releaseReservationsQuery, orderID); err != nil {
+			return nil, errors.Wrap(err, "orderRepo.UpdateStatus.ExecContext.releaseReservations")
+		}
+		if _, err = tx.ExecContext(ctx, releasePromotionsQuery, orderID); err != nil {
+			return nil, errors.Wrap(err, "orderRepo.UpdateStatus.ExecContext.releasePromotions")
+		}
+		if _, err = tx.ExecContext(ctx, reverseGiftCardsQuery, orderID, event.ActorID); err != nil {
+			return nil, errors.Wrap(err, "orderRepo.UpdateStatus.ExecContext.reverseGiftCards")
+		}
+		if _, err = tx.ExecContext(ctx, reverseStoreCreditQuery, orderID, event.ActorID); err != nil {
+			return nil, errors.Wrap(err, "orderRepo.UpdateStatus.ExecContext.reverseStoreCredit")
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, createOrderEventQuery, orderID, from, &event.ToStatus, &event.Note, event.ActorID); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.UpdateStatus.ExecContext")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.UpdateStatus.Commit")
+	}
+
+	return updated, nil
+}
+
+// Pending orders with reservations expired before the given time
+func (r *orderRepo) ListExpiredPending(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	// TODO: Tracing
+
+	orderIDs := make([]uuid.UUID, 0)
+	if err := r.db.SelectContext(ctx, &orderIDs, listExpiredPendingOrdersQuery, before, limit); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.ListExpiredPending.SelectContext")
+	}
+
+	return orderIDs, nil
+}
+
+// Check if the user has a paid order containing the product
+func (r *orderRepo) HasPurchased(ctx context.Context, userID uuid.UUID, productID uuid.UUID) (bool, error) {
+	// TODO: Tracing
+
+	var purchased bool
+	if err := r.db.GetContext(ctx, &purchased, hasPurchasedQuery, userID, productID); err != nil {
+		return false, errors.Wrap(err, "orderRepo.HasPurchased.GetContext")
+	}
+
+	return purchased, nil
+}
+
+func newOrdersList(orders []*models.Order, totalCount int, pq *utils.PaginationQuery) *models.OrdersList {
+	return &models.OrdersList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Orders:     orders,
+	}
+}
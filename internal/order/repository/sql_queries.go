@@ -0,0 +1,125 @@
+package repository
+
+const (
+	getOrderByIdQuery = `SELECT * FROM orders WHERE order_id = $1`
+
+	getOrderItemsQuery = `SELECT * FROM order_items WHERE order_id = $1 ORDER BY sku`
+
+	getOrderDiscountsQuery = `SELECT * FROM order_discounts WHERE order_id = $1 ORDER BY created_at, order_discount_id`
+
+	getOrderEventsQuery = `SELECT * FROM order_events WHERE order_id = $1 ORDER BY created_at, event_id`
+
+	getTotalOrdersByUserQuery = `SELECT COUNT(order_id) FROM orders WHERE user_id = $1`
+
+	listOrdersByUserQuery = `
+		SELECT * FROM orders
+		WHERE user_id = $1
+		ORDER BY created_at DESC, order_id
+		OFFSET $2 LIMIT $3
+	`
+
+	getTotalOrdersQuery = `SELECT COUNT(order_id) FROM orders WHERE ($1 = '' OR status = $1)`
+
+	listOrdersQuery = `
+		SELECT * FROM orders
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC, order_id
+		OFFSET $2 LIMIT $3
+	`
+
+	updateOrderStatusQuery = `
+		UPDATE orders
+		SET status = $1, updated_at = now()
+		WHERE order_id = $2 AND status = $3
+		RETURNING *
+	`
+
+	createOrderEventQuery = `
+		INSERT INTO order_events(order_id, from_status, to_status, note, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, now())
+	`
+
+	// Paid orders turn their reservations into sales recorded in the stock ledger
+	commitReservationsQuery = `
+		WITH committed AS (
+			UPDATE stock_reservations
+			SET status = 'committed', updated_at = now()
+			WHERE order_id = $1 AND status = 'active'
+			RETURNING sku, quantity
+		), applied AS (
+			UPDATE stock_levels s
+			SET on_hand = s.on_hand - c.quantity, reserved = s.reserved - c.quantity, updated_at = now()
+			FROM committed c
+			WHERE s.sku = c.sku
+			RETURNING s.sku, c.quantity, s.on_hand
+		)
+		INSERT INTO stock_movements(sku, movement_type, quantity, on_hand_after, reason, actor_user_id, reference_id, created_at)
+		SELECT sku, 'sale', -quantity, on_hand, 'order paid', $2, $1, now() FROM applied
+	`
+
+	releaseReservationsQuery = `
+		WITH released AS (
+			UPDATE stock_reservations
+			SET status = 'released', updated_at = now()
+			WHERE order_id = $1 AND status = 'active'
+			RETURNING sku, quantity
+		)
+		UPDATE stock_levels s
+		SET reserved = s.reserved - r.quantity, updated_at = now()
+		FROM released r
+		WHERE s.sku = r.sku
+	`
+
+	// Cancelled orders give their promotion usage back
+	releasePromotionsQuery = `
+		UPDATE promotions p
+		SET used_count = GREATEST(p.used_count - 1, 0), updated_at = now()
+		FROM order_discounts d
+		WHERE d.order_id = $1 AND d.promotion_id = p.promotion_id
+	`
+
+	// Cancelled orders credit back what is still redeemed from each gift card
+	reverseGiftCardsQuery = `
+		WITH redeemed AS (
+			SELECT gift_card_id, -SUM(amount) AS amount
+			FROM gift_card_transactions
+			WHERE order_id = $1 AND kind IN ('redeem', 'reversal')
+			GROUP BY gift_card_id
+			HAVING SUM(amount) < 0
+		), credited AS (
+			UPDATE gift_cards g
+			SET balance = g.balance + r.amount, updated_at = now()
+			FROM redeemed r
+			WHERE g.gift_card_id = r.gift_card_id
+			RETURNING g.gift_card_id, r.amount, g.balance
+		)
+		INSERT INTO gift_card_transactions(gift_card_id, kind, amount, balance_after, order_id, note, actor_id, created_at)
+		SELECT gift_card_id, 'reversal', amount, balance, $1, 'order cancelled', $2, now() FROM credited
+	`
+
+	// Cancelled orders credit back the store credit still redeemed
+	reverseStoreCreditQuery = `
+		INSERT INTO store_credit_ledger(user_id, currency, amount, reason, order_id, note, actor_id, created_at)
+		SELECT user_id, currency, -SUM(amount), 'reversal', $1, 'order cancelled', $2, now()
+		FROM store_credit_ledger
+		WHERE order_id = $1 AND reason IN ('redemption', 'reversal')
+		GROUP BY user_id, currency
+		HAVING SUM(amount) < 0
+	`
+
+	listExpiredPendingOrdersQuery = `
+		SELECT DISTINCT o.order_id
+		FROM orders o
+		JOIN stock_reservations r ON r.order_id = o.order_id
+		WHERE o.status = 'pending' AND r.status = 'active' AND r.expires_at < $1
+		LIMIT $2
+	`
+
+	hasPurchasedQuery = `
+		SELECT EXISTS (
+			SELECT 1 FROM orders o
+			JOIN order_items i ON i.order_id = o.order_id
+			WHERE o.user_id = $1 AND i.product_id = $2 AND o.status IN ('paid', 'fulfilled', 'shipped', 'delivered')
+		)
+	`
+)
@@ -0,0 +1,18 @@
+package order
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Order UseCase, customers see their own orders, admins see every order
+type UseCase interface {
+	GetByID(ctx context.Context, orderID uuid.UUID) (*models.Order, error)
+	ListMine(ctx context.Context, pq *utils.PaginationQuery) (*models.OrdersList, error)
+	List(ctx context.Context, query *models.OrdersQuery, pq *utils.PaginationQuery) (*models.OrdersList, error)
+	Cancel(ctx context.Context, orderID uuid.UUID) (*models.Order, error)
+	Transition(ctx context.Context, orderID uuid.UUID, transition *models.OrderTransition) (*models.Order, error)
+}
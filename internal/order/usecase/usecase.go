@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/order"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Order UseCase
+type orderUC struct {
+	cfg       *config.Config
+	logger    logger.Logger
+	orderRepo order.Repository
+}
+
+// Order UseCase constructor
+func NewOrderUseCase(cfg *config.Config, logger logger.Logger, orderRepo order.Repository) order.UseCase {
+	return &orderUC{
+		cfg:       cfg,
+		logger:    logger,
+		orderRepo: orderRepo,
+	}
+}
+
+// Get order with items, discounts and status history, customers only see their own orders
+func (u *orderUC) GetByID(ctx context.Context, orderID uuid.UUID) (*models.Order, error) {
+	// TODO: Tracing
+
+	o, err := u.getVisible(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := u.orderRepo.GetItems(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	discounts, err := u.orderRepo.GetDiscounts(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := u.orderRepo.GetEvents(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	o.Items = items
+	o.Discounts = discounts
+	o.Events = events
+
+	return o, nil
+}
+
+// Orders of the user in context, newest first
+func (u *orderUC) ListMine(ctx context.Context, pq *utils.PaginationQuery) (*models.OrdersList, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.orderRepo.ListByUser(ctx, user.UserID, pq)
+}
+
+// Orders of every user, optionally by status
+func (u *orderUC) List(ctx context.Context, query *models.OrdersQuery, pq *utils.PaginationQuery) (*models.OrdersList, error) {
+	// TODO: Tracing
+
+	return u.orderRepo.List(ctx, query.Status, pq)
+}
+
+// Cancel own order, only while it is still pending
+func (u *orderUC) Cancel(ctx context.Context, orderID uuid.UUID) (*models.Order, error) {
+	// TODO: Tracing
+
+	o, err := u.getVisible(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Status != models.OrderStatusPending {
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "orderUC.Cancel: order is %s", o.Status)
+	}
+
+	return u.changeStatus(ctx, o, &models.OrderTransition{Status: models.OrderStatusCancelled, Note: "cancelled by customer"})
+}
+
+// Move order along the state machine, admin only
+func (u *orderUC) Transition(ctx context.Context, orderID uuid.UUID, transition *models.OrderTransition) (*models.Order, error) {
+	// TODO: Tracing
+
+	o, err := u.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	transition.Prepare()
+
+	return u.changeStatus(ctx, o, transition)
+}
+
+func (u *orderUC) changeStatus(ctx context.Context, o *models.Order, transition *models.OrderTransition) (*models.Order, error) {
+	if !models.CanTransitionOrder(o.Status, transition.Status) {
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "orderUC.changeStatus: %s to %s", o.Status, transition.Status)
+	}
+
+	event := &models.OrderEvent{ToStatus: transition.Status, Note: transition.Note}
+	if user, err := middleware.GetUserFromCtx(ctx); err == nil {
+		event.ActorID = &user.UserID
+	}
+
+	return u.orderRepo.UpdateStatus(ctx, o.OrderID, o.Status, event)
+}
+
+// Order of the user in context, admins see every order. Others get not found so ids do not leak.
+func (u *orderUC) getVisible(ctx context.Context, orderID uuid.UUID) (*models.Order, error) {
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := u.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsAdmin() && (o.UserID == nil || *o.UserID != user.UserID) {
+		return nil, httpErrors.NewNotFoundError(errors.New("orderUC.getVisible: order belongs to another user"))
+	}
+
+	return o, nil
+}
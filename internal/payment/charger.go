@@ -0,0 +1,17 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Charges a pending order off session to a saved payment method of its customer, used by subscriptions.
+// A retry with the same idempotency key is never charged twice, an order already paid returns its payment.
+// Declined charges return a *payment.DeclineError of the gateway package. CheckMethod returns a bad request
+// unless the method is an active method of the user.
+type Charger interface {
+	ChargeOrder(ctx context.Context, orderID uuid.UUID, paymentMethodID uuid.UUID, idempotencyKey string) (*models.Payment, error)
+	CheckMethod(ctx context.Context, userID uuid.UUID, paymentMethodID uuid.UUID) error
+}
@@ -0,0 +1,15 @@
+package payment
+
+import "github.com/labstack/echo/v4"
+
+// Payment HTTP Handlers interface
+type Handlers interface {
+	CreateIntent() echo.HandlerFunc
+	ListByOrder() echo.HandlerFunc
+	Capture() echo.HandlerFunc
+	Refund() echo.HandlerFunc
+	Webhook() echo.HandlerFunc
+	SetupMethod() echo.HandlerFunc
+	ConfirmMethod() echo.HandlerFunc
+	ListMethods() echo.HandlerFunc
+}
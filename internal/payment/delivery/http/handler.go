@@ -0,0 +1,246 @@
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/payment"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Payment handlers
+type paymentHandlers struct {
+	cfg       *config.Config
+	logger    logger.Logger
+	paymentUC payment.UseCase
+}
+
+// Payment handlers constructor
+func NewPaymentHandlers(cfg *config.Config, logger logger.Logger, paymentUC payment.UseCase) payment.Handlers {
+	return &paymentHandlers{
+		cfg:       cfg,
+		logger:    logger,
+		paymentUC: paymentUC,
+	}
+}
+
+// CreateIntent godoc
+// @Summary Pay order
+// @Description create payment intent for own pending order, the client confirms it at the provider with client_secret
+// @Tags Payments
+// @Produce json
+// @Param order_id path string true "order_id"
+// @Success 201 {object} models.PaymentIntent
+// @Failure 409 {object} httpErrors.RestError
+// @Router /orders/{order_id}/payments [post]
+func (h *paymentHandlers) CreateIntent() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		orderID, err := uuid.Parse(c.Param("order_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		intent, err := h.paymentUC.CreateIntent(ctx, orderID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, intent)
+	}
+}
+
+// ListByOrder godoc
+// @Summary Get order payments
+// @Description get payment attempts of own order, admins can get any order
+// @Tags Payments
+// @Produce json
+// @Param order_id path string true "order_id"
+// @Success 200 {array} models.Payment
+// @Failure 404 {object} httpErrors.RestError
+// @Router /orders/{order_id}/payments [get]
+func (h *paymentHandlers) ListByOrder() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		orderID, err := uuid.Parse(c.Param("order_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		payments, err := h.paymentUC.ListByOrder(ctx, orderID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, payments)
+	}
+}
+
+// Capture godoc
+// @Summary Capture payment
+// @Description capture authorized payment and mark its order paid, admin only
+// @Tags Payments
+// @Produce json
+// @Param payment_id path string true "payment_id"
+// @Success 200 {object} models.Payment
+// @Failure 409 {object} httpErrors.RestError
+// @Router /payments/{payment_id}/capture [post]
+func (h *paymentHandlers) Capture() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		paymentID, err := uuid.Parse(c.Param("payment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		p, err := h.paymentUC.Capture(ctx, paymentID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, p)
+	}
+}
+
+// Refund godoc
+// @Summary Refund payment
+// @Description refund captured payment, zero amount refunds what is left, admin only
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param payment_id path string true "payment_id"
+// @Success 200 {object} models.Payment
+// @Failure 409 {object} httpErrors.RestError
+// @Router /payments/{payment_id}/refund [post]
+func (h *paymentHandlers) Refund() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		paymentID, err := uuid.Parse(c.Param("payment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		refund := &models.PaymentRefund{}
+		if err = utils.ReadRequest(c, refund); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		p, err := h.paymentUC.Refund(ctx, paymentID, refund)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, p)
+	}
+}
+
+// Webhook godoc
+// @Summary Payment provider webhook
+// @Description signed provider event, replayed events are acknowledged without being applied again
+// @Tags Payments
+// @Accept json
+// @Success 204
+// @Failure 400 {object} httpErrors.RestError
+// @Router /payments/webhook [post]
+func (h *paymentHandlers) Webhook() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		payload, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.paymentUC.HandleWebhook(ctx, payload, c.Request().Header); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// SetupMethod godoc
+// @Summary Save payment method
+// @Description start saving a payment method for subscriptions, the client confirms the setup at the provider with client_secret
+// @Tags Payments
+// @Produce json
+// @Success 201 {object} models.PaymentMethodSetup
+// @Failure 401 {object} httpErrors.RestError
+// @Router /payments/methods [post]
+func (h *paymentHandlers) SetupMethod() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		setup, err := h.paymentUC.SetupMethod(ctx)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, setup)
+	}
+}
+
+// ConfirmMethod godoc
+// @Summary Confirm payment method
+// @Description activate own payment method once its setup was confirmed at the provider
+// @Tags Payments
+// @Produce json
+// @Param payment_method_id path string true "payment_method_id"
+// @Success 200 {object} models.PaymentMethod
+// @Failure 409 {object} httpErrors.RestError
+// @Router /payments/methods/{payment_method_id}/confirm [post]
+func (h *paymentHandlers) ConfirmMethod() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		paymentMethodID, err := uuid.Parse(c.Param("payment_method_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		method, err := h.paymentUC.ConfirmMethod(ctx, paymentMethodID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, method)
+	}
+}
+
+// ListMethods godoc
+// @Summary Get payment methods
+// @Description get own saved payment methods
+// @Tags Payments
+// @Produce json
+// @Success 200 {array} models.PaymentMethod
+// @Failure 401 {object} httpErrors.RestError
+// @Router /payments/methods [get]
+func (h *paymentHandlers) ListMethods() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		methods, err := h.paymentUC.ListMethods(ctx)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, methods)
+	}
+}
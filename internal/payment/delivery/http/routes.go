@@ -0,0 +1,22 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/payment"
+	"github.com/labstack/echo/v4"
+)
+
+func MapPaymentRoutes(paymentGroup *echo.Group, orderGroup *echo.Group, h payment.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	orderGroup.POST("/:order_id/payments", h.CreateIntent(), mw.AuthJWTMiddleware)
+	orderGroup.GET("/:order_id/payments", h.ListByOrder(), mw.AuthJWTMiddleware)
+
+	paymentGroup.POST("/webhook", h.Webhook())
+	paymentGroup.POST("/methods", h.SetupMethod(), mw.AuthJWTMiddleware)
+	paymentGroup.GET("/methods", h.ListMethods(), mw.AuthJWTMiddleware)
+	paymentGroup.POST("/methods/:payment_method_id/confirm", h.ConfirmMethod(), mw.AuthJWTMiddleware)
+	paymentGroup.POST("/:payment_id/capture", h.Capture(), adminOnly...)
+	paymentGroup.POST("/:payment_id/refund", h.Refund(), adminOnly...)
+}
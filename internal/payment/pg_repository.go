@@ -0,0 +1,25 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Payment repository
+type Repository interface {
+	Create(ctx context.Context, payment *models.Payment) (*models.Payment, error)
+	GetByID(ctx context.Context, paymentID uuid.UUID) (*models.Payment, error)
+	GetByIntent(ctx context.Context, provider string, intentID string) (*models.Payment, error)
+	ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.Payment, error)
+	SetIntent(ctx context.Context, paymentID uuid.UUID, intentID string, amount int64) (*models.Payment, error)
+	UpdateStatus(ctx context.Context, paymentID uuid.UUID, from string, to string) (*models.Payment, error)
+	SetRefunded(ctx context.Context, paymentID uuid.UUID, refundedAmount int64) (*models.Payment, error)
+	SaveWebhookEvent(ctx context.Context, provider string, eventID string, eventType string, payload []byte) (bool, error)
+	MarkWebhookProcessed(ctx context.Context, provider string, eventID string) error
+	CreateMethod(ctx context.Context, method *models.PaymentMethod) (*models.PaymentMethod, error)
+	GetMethodByID(ctx context.Context, paymentMethodID uuid.UUID) (*models.PaymentMethod, error)
+	ListMethodsByUser(ctx context.Context, userID uuid.UUID) ([]*models.PaymentMethod, error)
+	ActivateMethod(ctx context.Context, paymentMethodID uuid.UUID, providerMethodID string) (*models.PaymentMethod, error)
+}
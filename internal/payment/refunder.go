@@ -0,0 +1,16 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Refunds part of an order over its captured payments, used by returns. Reference identifies the refund
+// at the provider, calls repeating it never refund a payment twice. Returns the amount refunded, also
+// when a later payment failed. Fully refunding the last payment refunds the order. RefundableAmount is
+// what the captured payments can still refund, parts paid with gift cards or store credit are not included.
+type Refunder interface {
+	RefundOrder(ctx context.Context, orderID uuid.UUID, amount int64, reference string) (int64, error)
+	RefundableAmount(ctx context.Context, orderID uuid.UUID) (int64, error)
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/payment"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Payment repository
+type paymentRepo struct {
+	db *sqlx.DB
+}
+
+// Payment repository constructor
+func NewPaymentRepository(db *sqlx.DB) payment.Repository {
+	return &paymentRepo{db: db}
+}
+
+func (r *paymentRepo) Create(ctx context.Context, p *models.Payment) (*models.Payment, error) {
+	// TODO: Tracing
+
+	created := &models.Payment{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		createPaymentQuery,
+		&p.OrderID,
+		&p.Provider,
+		&p.IntentID,
+		&p.Status,
+		&p.Amount,
+		&p.Currency,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "paymentRepo.Create.StructScan")
+	}
+
+	return created, nil
+}
+
+func (r *paymentRepo) GetByID(ctx context.Context, paymentID uuid.UUID) (*models.Payment, error) {
+	// TODO: Tracing
+
+	p := &models.Payment{}
+	if err := r.db.GetContext(ctx, p, getPaymentByIdQuery, paymentID); err != nil {
+		return nil, errors.Wrap(err, "paymentRepo.GetByID.GetContext")
+	}
+
+	return p, nil
+}
+
+func (r *paymentRepo) GetByIntent(ctx context.Context, provider string, intentID string) (*models.Payment, error) {
+	// TODO: Tracing
+
+	p := &models.Payment{}
+	if err := r.db.GetContext(ctx, p, getPaymentByIntentQuery, provider, intentID); err != nil {
+		return nil, errors.Wrap(err, "paymentRepo.GetByIntent.GetContext")
+	}
+
+	return p, nil
+}
+
+func (r *paymentRepo) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.Payment, error) {
+	// TODO: Tracing
+
+	payments := make([]*models.Payment, 0)
+	if err := r.db.SelectContext(ctx, &payments, listPaymentsByOrderQuery, orderID); err != nil {
+		return nil, errors.Wrap(err, "paymentRepo.ListByOrder.SelectContext")
+	}
+
+	return payments, nil
+}
+
+// Change status only if it is still from, sql.ErrNoRows otherwise
+func (r *paymentRepo) SetIntent(ctx context.Context, paymentID uuid.UUID, intentID string, amount int64) (*models.Payment, error) {
+	// TODO: Tracing
+
+	p := &models.Payment{}
+	if err := r.db.GetContext(ctx, p, setPaymentIntentQuery, intentID, amount, paymentID); err != nil {
+		return nil, errors.Wrap(err, "paymentRepo.SetIntent.GetContext")
+	}
+
+	return p, nil
+}
+
+func (r *paymentRepo) UpdateStatus(ctx context.Context, paymentID uuid.UUID, from string, to string) (*models.Payment, error) {
+	// TODO: Tracing
+
+	p := &models.Payment{}
+	if err := r.db.GetContext(ctx, p, updatePaymentStatusQuery, to, paymentID, from); err != nil {
+		return nil, errors.Wrap(err, "paymentRepo.UpdateStatus.GetContext")
+	}
+
+	return p, nil
+}
+
+// Raise refunded total, the payment becomes refunded once all of it is refunded
+func (r *paymentRepo) SetRefunded(ctx context.Context, paymentID uuid.UUID, refundedAmount int64) (*models.Payment, error) {
+	// TODO: Tracing
+
+	p := &models.Payment{}
+	if err := r.db.GetContext(ctx, p, setPaymentRefundedQuery, refundedAmount, paymentID); err != nil {
+		return nil, errors.Wrap(err, "paymentRepo.SetRefunded.GetContext")
+	}
+
+	return p, nil
+}
+
+// Store webhook event once, returns true if it was already processed
+func (r *paymentRepo) SaveWebhookEvent(ctx context.Context, provider string, eventID string, eventType string, payload []byte) (bool, error) {
+	// TODO: Tracing
+
+	if _, err := r.db.ExecContext(ctx, createWebhookEventQuery, provider, eventID, eventType, payload); err != nil {
+		return false, errors.Wrap(err, "paymentRepo.SaveWebhookEvent.ExecContext")
+	}
+
+	var processed bool
+	if err := r.db.GetContext(ctx, &processed, isWebhookEventProcessedQuery, provider, eventID); err != nil {
+		return false, errors.Wrap(err, "paymentRepo.SaveWebhookEvent.GetContext")
+	}
+
+	return processed, nil
+}
+
+func (r *paymentRepo) MarkWebhookProcessed(ctx context.Context, provider string, eventID string) error {
+	// TODO: Tracing
+
+	if _, err := r.db.ExecContext(ctx, markWebhookEventProcessedQuery, provider, eventID); err != nil {
+		return errors.Wrap(err, "paymentRepo.MarkWebhookProcessed.ExecContext")
+	}
+
+	return nil
+}
+
+func (r *paymentRepo) CreateMethod(ctx context.Context, method *models.PaymentMethod) (*models.PaymentMethod, error) {
+	// TODO: Tracing
+
+	created := &models.PaymentMethod{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		createPaymentMethodQuery,
+		&method.UserID,
+		&method.Provider,
+		&method.Status,
+		&method.SetupID,
+		&method.CustomerID,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "paymentRepo.CreateMethod.StructScan")
+	}
+
+	return created, nil
+}
+
+func (r *paymentRepo) GetMethodByID(ctx context.Context, paymentMethodID uuid.UUID) (*models.PaymentMethod, error) {
+	// TODO: Tracing
+
+	method := &models.PaymentMethod{}
+	if err := r.db.GetContext(ctx, method, getPaymentMethodByIdQuery, paymentMethodID); err != nil {
+		return nil, errors.Wrap(err, "paymentRepo.GetMethodByID.GetContext")
+	}
+
+	return method, nil
+}
+
+func (r *paymentRepo) ListMethodsByUser(ctx context.Context, userID uuid.UUID) ([]*models.PaymentMethod, error) {
+	// TODO: Tracing
+
+	methods := make([]*models.PaymentMethod, 0)
+	if err := r.db.SelectContext(ctx, &methods, listPaymentMethodsByUserQuery, userID); err != nil {
+		return nil, errors.Wrap(err, "paymentRepo.ListMethodsByUser.SelectContext")
+	}
+
+	return methods, nil
+}
+
+// Activate method with the provider method its setup saved, sql.ErrNoRows if it has another one
+func (r *paymentRepo) ActivateMethod(ctx context.Context, paymentMethodID uuid.UUID, providerMethodID string) (*models.PaymentMethod, error) {
+	// TODO: Tracing
+
+	method := &models.PaymentMethod{}
+	if err := r.db.GetContext(ctx, method, activatePaymentMethodQuery, providerMethodID, paymentMethodID); err != nil {
+		return nil, errors.Wrap(err, "paymentRepo.ActivateMethod.GetContext")
+	}
+
+	return method, nil
+}
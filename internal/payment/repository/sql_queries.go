@@ -0,0 +1,68 @@
+package repository
+
+const (
+	createPaymentQuery = `
+		INSERT INTO payments(order_id, provider, intent_id, status, amount, currency, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, now(), now())
+		RETURNING *
+	`
+
+	getPaymentByIdQuery = `SELECT * FROM payments WHERE payment_id = $1`
+
+	getPaymentByIntentQuery = `SELECT * FROM payments WHERE provider = $1 AND intent_id = $2`
+
+	listPaymentsByOrderQuery = `SELECT * FROM payments WHERE order_id = $1 ORDER BY created_at, payment_id`
+
+	// An attempt gets its intent once, a replayed answer of the provider sets the same one again
+	setPaymentIntentQuery = `
+		UPDATE payments
+		SET intent_id = $1, amount = $2, updated_at = now()
+		WHERE payment_id = $3 AND (intent_id IS NULL OR intent_id = $1)
+		RETURNING *
+	`
+
+	updatePaymentStatusQuery = `
+		UPDATE payments
+		SET status = $1, updated_at = now()
+		WHERE payment_id = $2 AND status = $3
+		RETURNING *
+	`
+
+	// Refunded total only grows so replayed or reordered events change nothing
+	setPaymentRefundedQuery = `
+		UPDATE payments
+		SET refunded_amount = GREATEST(refunded_amount, LEAST($1, amount)),
+			status = CASE WHEN GREATEST(refunded_amount, LEAST($1, amount)) >= amount THEN 'refunded' ELSE status END,
+			updated_at = now()
+		WHERE payment_id = $2
+		RETURNING *
+	`
+
+	createWebhookEventQuery = `
+		INSERT INTO webhook_events(provider, event_id, event_type, payload, received_at)
+		VALUES ($1, $2, $3, $4, now())
+		ON CONFLICT (provider, event_id) DO NOTHING
+	`
+
+	isWebhookEventProcessedQuery = `SELECT processed_at IS NOT NULL FROM webhook_events WHERE provider = $1 AND event_id = $2`
+
+	markWebhookEventProcessedQuery = `UPDATE webhook_events SET processed_at = now() WHERE provider = $1 AND event_id = $2`
+
+	createPaymentMethodQuery = `
+		INSERT INTO payment_methods(user_id, provider, status, setup_id, customer_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, now(), now())
+		RETURNING *
+	`
+
+	getPaymentMethodByIdQuery = `SELECT * FROM payment_methods WHERE payment_method_id = $1`
+
+	listPaymentMethodsByUserQuery = `SELECT * FROM payment_methods WHERE user_id = $1 ORDER BY created_at, payment_method_id`
+
+	// The provider method of a setup never changes, confirming it again returns the method as it is
+	activatePaymentMethodQuery = `
+		UPDATE payment_methods
+		SET status = 'active', provider_method_id = $1, updated_at = now()
+		WHERE payment_method_id = $2 AND (provider_method_id IS NULL OR provider_method_id = $1)
+		RETURNING *
+	`
+)
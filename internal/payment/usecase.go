@@ -0,0 +1,23 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Payment UseCase
+type UseCase interface {
+	Refunder
+	Charger
+	CreateIntent(ctx context.Context, orderID uuid.UUID) (*models.PaymentIntent, error)
+	ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.Payment, error)
+	Capture(ctx context.Context, paymentID uuid.UUID) (*models.Payment, error)
+	Refund(ctx context.Context, paymentID uuid.UUID, refund *models.PaymentRefund) (*models.Payment, error)
+	HandleWebhook(ctx context.Context, payload []byte, header http.Header) error
+	SetupMethod(ctx context.Context) (*models.PaymentMethodSetup, error)
+	ConfirmMethod(ctx context.Context, paymentMethodID uuid.UUID) (*models.PaymentMethod, error)
+	ListMethods(ctx context.Context) ([]*models.PaymentMethod, error)
+}
@@ -0,0 +1,656 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/order"
+	"github.com/fekuna/go-store/internal/payment"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	gateway "github.com/fekuna/go-store/pkg/payment"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Payment UseCase
+type paymentUC struct {
+	cfg         *config.Config
+	logger      logger.Logger
+	paymentRepo payment.Repository
+	orderRepo   order.Repository
+	orderUC     order.UseCase
+	gateway     gateway.Gateway
+}
+
+// Payment UseCase constructor
+func NewPaymentUseCase(cfg *config.Config, logger logger.Logger, paymentRepo payment.Repository, orderRepo order.Repository, orderUC order.UseCase, gw gateway.Gateway) payment.UseCase {
+	return &paymentUC{
+		cfg:         cfg,
+		logger:      logger,
+		paymentRepo: paymentRepo,
+		orderRepo:   orderRepo,
+		orderUC:     orderUC,
+		gateway:     gw,
+	}
+}
+
+// Start payment of own pending order for the part of its total not paid with gift cards or store credit.
+// An intent started before that can still be paid is returned again, so a double submit is not charged twice.
+func (u *paymentUC) CreateIntent(ctx context.Context, orderID uuid.UUID) (*models.PaymentIntent, error) {
+	// TODO: Tracing
+
+	o, err := u.orderUC.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.Status != models.OrderStatusPending {
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "paymentUC.CreateIntent: order is %s", o.Status)
+	}
+
+	open, err := u.openIntent(ctx, o)
+	if err != nil || open != nil {
+		return open, err
+	}
+
+	attempt, err := u.startAttempt(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	intent, err := u.gateway.CreateIntent(ctx, &gateway.IntentRequest{
+		Amount:         attempt.Amount,
+		Currency:       o.Currency,
+		Reference:      o.OrderID.String(),
+		IdempotencyKey: attemptKey(attempt),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "paymentUC.CreateIntent.gateway")
+	}
+
+	p, err := u.paymentRepo.SetIntent(ctx, attempt.PaymentID, intent.ID, intent.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PaymentIntent{Payment: p, ClientSecret: intent.ClientSecret}, nil
+}
+
+// Payments of own order, admins see every order
+func (u *paymentUC) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.Payment, error) {
+	// TODO: Tracing
+
+	if _, err := u.orderUC.GetByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	return u.paymentRepo.ListByOrder(ctx, orderID)
+}
+
+// Capture authorized payment, admin only
+func (u *paymentUC) Capture(ctx context.Context, paymentID uuid.UUID) (*models.Payment, error) {
+	// TODO: Tracing
+
+	p, err := u.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != models.PaymentStatusPending || p.IntentID == nil {
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "paymentUC.Capture: payment is %s without intent", p.Status)
+	}
+
+	intent, err := u.gateway.Capture(ctx, *p.IntentID, p.Amount)
+	if err != nil {
+		return nil, errors.Wrap(err, "paymentUC.Capture.gateway")
+	}
+	if intent.Status != gateway.IntentSucceeded {
+		return nil, httpErrors.NewBadRequestError(errors.Errorf("paymentUC.Capture: intent is %s", intent.Status))
+	}
+
+	return u.markSucceeded(ctx, p)
+}
+
+// Refund captured payment, zero amount refunds what is left. Fully refunded orders become refunded. Admin only.
+func (u *paymentUC) Refund(ctx context.Context, paymentID uuid.UUID, refund *models.PaymentRefund) (*models.Payment, error) {
+	// TODO: Tracing
+
+	p, err := u.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != models.PaymentStatusSucceeded {
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "paymentUC.Refund: payment is %s", p.Status)
+	}
+
+	left := p.Amount - p.RefundedAmount
+	amount := refund.Amount
+	if amount == 0 {
+		amount = left
+	}
+	if amount > left {
+		return nil, httpErrors.NewBadRequestError(errors.Errorf("paymentUC.Refund: at most %d can be refunded", left))
+	}
+
+	// Keyed by what was refunded before, a retry after a lost response is not refunded again
+	re, err := u.gateway.Refund(ctx, *p.IntentID, amount, fmt.Sprintf("refund_%s_%d", p.PaymentID, p.RefundedAmount))
+	if err != nil {
+		return nil, errors.Wrap(err, "paymentUC.Refund.gateway")
+	}
+
+	return u.markRefunded(ctx, p, p.RefundedAmount+re.Amount)
+}
+
+// Refund amount over the captured payments of the order, oldest payment first. Each payment is refunded with
+// the key reference_<payment id>, so a retry of a failed refund leaves the payments already refunded alone.
+func (u *paymentUC) RefundOrder(ctx context.Context, orderID uuid.UUID, amount int64, reference string) (int64, error) {
+	// TODO: Tracing
+
+	if amount <= 0 {
+		return 0, httpErrors.NewBadRequestError(errors.New("paymentUC.RefundOrder: amount must be positive"))
+	}
+
+	payments, err := u.paymentRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return 0, err
+	}
+
+	if refundable := refundableAmount(payments); amount > refundable {
+		return 0, httpErrors.NewBadRequestError(errors.Errorf("paymentUC.RefundOrder: at most %d can be refunded", refundable))
+	}
+
+	var refunded int64
+	for _, p := range payments {
+		if refunded == amount {
+			break
+		}
+		if p.Status != models.PaymentStatusSucceeded || p.Amount == p.RefundedAmount {
+			continue
+		}
+
+		share := p.Amount - p.RefundedAmount
+		if share > amount-refunded {
+			share = amount - refunded
+		}
+
+		re, err := u.gateway.Refund(ctx, *p.IntentID, share, reference+"_"+p.PaymentID.String())
+		if err != nil {
+			return refunded, errors.Wrap(err, "paymentUC.RefundOrder.gateway")
+		}
+		refunded += re.Amount
+
+		if _, err = u.markRefunded(ctx, p, p.RefundedAmount+re.Amount); err != nil {
+			return refunded, err
+		}
+	}
+
+	return refunded, nil
+}
+
+// What the captured payments of the order can still refund
+func (u *paymentUC) RefundableAmount(ctx context.Context, orderID uuid.UUID) (int64, error) {
+	// TODO: Tracing
+
+	payments, err := u.paymentRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return 0, err
+	}
+
+	return refundableAmount(payments), nil
+}
+
+// Charge what is due on a pending order to a saved payment method of its customer. A charge that needs the
+// customer to confirm it can't complete off session, its payment is recorded as failed and it is returned as a
+// decline, so is a method that can't be charged. Without an idempotency key the payment attempt is the key.
+// An order paid by an earlier call returns its payment.
+func (u *paymentUC) ChargeOrder(ctx context.Context, orderID uuid.UUID, paymentMethodID uuid.UUID, idempotencyKey string) (*models.Payment, error) {
+	// TODO: Tracing
+
+	o, err := u.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.Status != models.OrderStatusPending {
+		if o.Status != models.OrderStatusCancelled {
+			if p, err := u.succeededPayment(ctx, o.OrderID); err == nil {
+				return p, nil
+			}
+		}
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "paymentUC.ChargeOrder: order is %s", o.Status)
+	}
+
+	if o.UserID == nil {
+		return nil, httpErrors.NewBadRequestError(errors.New("paymentUC.ChargeOrder: guest orders have no saved payment methods"))
+	}
+	method, err := u.usableMethod(ctx, *o.UserID, paymentMethodID)
+	if err != nil {
+		if httpErrors.ParseError(err).Status() != http.StatusBadRequest {
+			return nil, err
+		}
+		decline := &gateway.DeclineError{Code: "payment_method_unavailable", Message: "The saved payment method can't be used, please set it up again."}
+		return nil, errors.Wrap(decline, "paymentUC.ChargeOrder: "+err.Error())
+	}
+
+	attempt, err := u.startAttempt(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if idempotencyKey == "" {
+		idempotencyKey = attemptKey(attempt)
+	}
+
+	intent, err := u.gateway.ChargeSaved(ctx, &gateway.ChargeRequest{
+		Amount:          attempt.Amount,
+		Currency:        o.Currency,
+		Reference:       o.OrderID.String(),
+		IdempotencyKey:  idempotencyKey,
+		CustomerID:      method.CustomerID,
+		PaymentMethodID: *method.ProviderMethodID,
+	})
+	if err != nil {
+		var decline *gateway.DeclineError
+		if errors.As(err, &decline) {
+			if _, failErr := u.paymentRepo.UpdateStatus(ctx, attempt.PaymentID, models.PaymentStatusPending, models.PaymentStatusFailed); failErr != nil {
+				return nil, failErr
+			}
+		}
+		return nil, errors.Wrap(err, "paymentUC.ChargeOrder.gateway")
+	}
+
+	p, err := u.paymentRepo.SetIntent(ctx, attempt.PaymentID, intent.ID, intent.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if intent.Status != gateway.IntentSucceeded {
+		if _, err = u.paymentRepo.UpdateStatus(ctx, p.PaymentID, models.PaymentStatusPending, models.PaymentStatusFailed); err != nil {
+			return nil, err
+		}
+		decline := &gateway.DeclineError{Code: intent.Status, Message: "The payment needs to be confirmed by the card holder."}
+		return nil, errors.Wrapf(decline, "paymentUC.ChargeOrder: intent is %s", intent.Status)
+	}
+
+	return u.markSucceeded(ctx, p)
+}
+
+// Bad request unless the payment method is an active method of the user
+func (u *paymentUC) CheckMethod(ctx context.Context, userID uuid.UUID, paymentMethodID uuid.UUID) error {
+	// TODO: Tracing
+
+	_, err := u.usableMethod(ctx, userID, paymentMethodID)
+	return err
+}
+
+// Start saving a payment method of the user in context. The client confirms the setup at the provider with
+// client_secret and then confirms the method here. The provider customer of the user is reused.
+func (u *paymentUC) SetupMethod(ctx context.Context) (*models.PaymentMethodSetup, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	methods, err := u.paymentRepo.ListMethodsByUser(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var customerID string
+	for _, m := range methods {
+		if m.Provider == u.gateway.Name() {
+			customerID = m.CustomerID
+			break
+		}
+	}
+
+	setup, err := u.gateway.CreateSetup(ctx, &gateway.SetupRequest{CustomerID: customerID, Reference: user.UserID.String()})
+	if err != nil {
+		return nil, errors.Wrap(err, "paymentUC.SetupMethod.gateway")
+	}
+
+	method, err := u.paymentRepo.CreateMethod(ctx, &models.PaymentMethod{
+		UserID:     user.UserID,
+		Provider:   u.gateway.Name(),
+		Status:     models.PaymentMethodStatusPending,
+		SetupID:    setup.ID,
+		CustomerID: setup.CustomerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PaymentMethodSetup{PaymentMethod: method, ClientSecret: setup.ClientSecret}, nil
+}
+
+// Activate own payment method once its setup succeeded at the provider, the saved method is read from the provider
+func (u *paymentUC) ConfirmMethod(ctx context.Context, paymentMethodID uuid.UUID) (*models.PaymentMethod, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := u.paymentRepo.GetMethodByID(ctx, paymentMethodID)
+	if err != nil {
+		return nil, err
+	}
+	if method.UserID != user.UserID {
+		return nil, httpErrors.NewNotFoundError(errors.New("paymentUC.ConfirmMethod: payment method belongs to another user"))
+	}
+	if method.Status == models.PaymentMethodStatusActive {
+		return method, nil
+	}
+
+	setup, err := u.gateway.GetSetup(ctx, method.SetupID)
+	if err != nil {
+		return nil, errors.Wrap(err, "paymentUC.ConfirmMethod.gateway")
+	}
+	if setup.Status != gateway.IntentSucceeded || setup.PaymentMethodID == "" {
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "paymentUC.ConfirmMethod: setup is %s", setup.Status)
+	}
+
+	return u.paymentRepo.ActivateMethod(ctx, method.PaymentMethodID, setup.PaymentMethodID)
+}
+
+// Payment methods of the user in context, oldest first
+func (u *paymentUC) ListMethods(ctx context.Context) ([]*models.PaymentMethod, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.paymentRepo.ListMethodsByUser(ctx, user.UserID)
+}
+
+// Verify, deduplicate and apply provider event. Applying is idempotent so a delivery that failed halfway can be retried.
+func (u *paymentUC) HandleWebhook(ctx context.Context, payload []byte, header http.Header) error {
+	// TODO: Tracing
+
+	event, err := u.gateway.VerifyWebhook(payload, header)
+	if err != nil {
+		return httpErrors.NewBadRequestError(errors.Wrap(err, "paymentUC.HandleWebhook.VerifyWebhook"))
+	}
+	if event.ID == "" {
+		return httpErrors.NewBadRequestError(errors.New("paymentUC.HandleWebhook: event without id"))
+	}
+
+	provider := u.gateway.Name()
+	processed, err := u.paymentRepo.SaveWebhookEvent(ctx, provider, event.ID, event.Type, payload)
+	if err != nil {
+		return err
+	}
+	if processed {
+		return nil
+	}
+
+	if err = u.applyEvent(ctx, event); err != nil {
+		return err
+	}
+
+	return u.paymentRepo.MarkWebhookProcessed(ctx, provider, event.ID)
+}
+
+func (u *paymentUC) applyEvent(ctx context.Context, event *gateway.Event) error {
+	if event.Type == gateway.EventUnknown {
+		return nil
+	}
+
+	p, err := u.paymentRepo.GetByIntent(ctx, u.gateway.Name(), event.IntentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			u.logger.Warnf("paymentUC.applyEvent: event %s for unknown intent %s", event.ID, event.IntentID)
+			return nil
+		}
+		return err
+	}
+
+	switch event.Type {
+	case gateway.EventPaymentSucceeded:
+		_, err = u.markSucceeded(ctx, p)
+	case gateway.EventPaymentFailed:
+		_, err = u.paymentRepo.UpdateStatus(ctx, p.PaymentID, models.PaymentStatusPending, models.PaymentStatusFailed)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil
+		}
+	case gateway.EventPaymentRefunded:
+		_, err = u.markRefunded(ctx, p, event.Amount)
+	}
+
+	return err
+}
+
+// Active payment method of the user, a bad request for methods of other users or not set up yet
+func (u *paymentUC) usableMethod(ctx context.Context, userID uuid.UUID, paymentMethodID uuid.UUID) (*models.PaymentMethod, error) {
+	method, err := u.paymentRepo.GetMethodByID(ctx, paymentMethodID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, httpErrors.NewBadRequestError(errors.Wrap(err, "paymentUC.usableMethod: payment method not found"))
+		}
+		return nil, err
+	}
+	if method.UserID != userID {
+		return nil, httpErrors.NewBadRequestError(errors.New("paymentUC.usableMethod: payment method not found"))
+	}
+	if method.Status != models.PaymentMethodStatusActive {
+		return nil, httpErrors.NewBadRequestError(errors.New("paymentUC.usableMethod: payment method is not set up"))
+	}
+
+	return method, nil
+}
+
+// Payment attempt of what is due on the order, recorded before the provider is called. An attempt the provider
+// never answered is reused, so a retry sends the same idempotency key and gets the same intent back.
+func (u *paymentUC) startAttempt(ctx context.Context, o *models.Order) (*models.Payment, error) {
+	existing, err := u.paymentRepo.ListByOrder(ctx, o.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := o.AmountDue()
+	for _, p := range existing {
+		if p.Status == models.PaymentStatusPending && p.IntentID == nil && p.Amount == amount && p.Currency == o.Currency {
+			return p, nil
+		}
+	}
+
+	return u.paymentRepo.Create(ctx, &models.Payment{
+		OrderID:  o.OrderID,
+		Provider: u.gateway.Name(),
+		Status:   models.PaymentStatusPending,
+		Amount:   amount,
+		Currency: o.Currency,
+	})
+}
+
+// Mark payment succeeded and its order paid, both steps are no-ops when already done. A payment of a cancelled
+// order is refunded, so is a payment of an order another payment paid.
+func (u *paymentUC) markSucceeded(ctx context.Context, p *models.Payment) (*models.Payment, error) {
+	for _, from := range []string{models.PaymentStatusPending, models.PaymentStatusFailed} {
+		updated, err := u.paymentRepo.UpdateStatus(ctx, p.PaymentID, from, models.PaymentStatusSucceeded)
+		if err == nil {
+			p = updated
+			break
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	event := &models.OrderEvent{ToStatus: models.OrderStatusPaid, Note: "payment " + *p.IntentID + " succeeded"}
+	if _, err := u.orderRepo.UpdateStatus(ctx, p.OrderID, models.OrderStatusPending, event); err != nil {
+		if !errors.Is(err, httpErrors.InvalidStateTransition) {
+			return nil, err
+		}
+
+		o, err := u.orderRepo.GetByID(ctx, p.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		if o.Status == models.OrderStatusCancelled {
+			return u.refundCancelled(ctx, p)
+		}
+		return u.refundDuplicates(ctx, p)
+	}
+
+	return p, nil
+}
+
+// Intent of what is due on the order the customer can still pay, nil when there is none. An intent the
+// provider already authorized or charged only waits for its webhook, the order is not paid again.
+func (u *paymentUC) openIntent(ctx context.Context, o *models.Order) (*models.PaymentIntent, error) {
+	payments, err := u.paymentRepo.ListByOrder(ctx, o.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := o.AmountDue()
+	for _, p := range payments {
+		if p.Status != models.PaymentStatusPending || p.IntentID == nil || p.Amount != amount || p.Currency != o.Currency {
+			continue
+		}
+
+		intent, err := u.gateway.GetIntent(ctx, *p.IntentID)
+		if err != nil {
+			return nil, errors.Wrap(err, "paymentUC.openIntent.gateway")
+		}
+		switch intent.Status {
+		case gateway.IntentCanceled:
+			continue
+		case gateway.IntentSucceeded, gateway.IntentRequiresCapture:
+			return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "paymentUC.openIntent: payment %s is %s", p.PaymentID, intent.Status)
+		}
+
+		return &models.PaymentIntent{Payment: p, ClientSecret: intent.ClientSecret}, nil
+	}
+
+	return nil, nil
+}
+
+// Refund the payments of an order that succeeded after another one, like two intents paid from two tabs.
+// The oldest payment is kept whichever success arrived first, the key makes repeated deliveries refund once.
+// Refunding a duplicate leaves the order alone.
+func (u *paymentUC) refundDuplicates(ctx context.Context, p *models.Payment) (*models.Payment, error) {
+	payments, err := u.paymentRepo.ListByOrder(ctx, p.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := false
+	for _, other := range payments {
+		if other.Status != models.PaymentStatusSucceeded && other.Status != models.PaymentStatusRefunded {
+			continue
+		}
+		if !kept {
+			kept = true
+			continue
+		}
+
+		left := other.Amount - other.RefundedAmount
+		if other.Status != models.PaymentStatusSucceeded || left <= 0 {
+			continue
+		}
+
+		u.logger.Warnf("paymentUC.refundDuplicates: payment %s paid order %s again, refunding %d", other.PaymentID, other.OrderID, left)
+
+		re, err := u.gateway.Refund(ctx, *other.IntentID, left, "duplicate_"+other.PaymentID.String())
+		if err != nil {
+			return nil, errors.Wrap(err, "paymentUC.refundDuplicates.gateway")
+		}
+		updated, err := u.paymentRepo.SetRefunded(ctx, other.PaymentID, other.RefundedAmount+re.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if updated.PaymentID == p.PaymentID {
+			p = updated
+		}
+	}
+
+	return p, nil
+}
+
+// Refund a payment that succeeded after its order was cancelled, like a reservation that expired while the
+// customer was paying. The key makes repeated deliveries of the success event refund it once.
+func (u *paymentUC) refundCancelled(ctx context.Context, p *models.Payment) (*models.Payment, error) {
+	left := p.Amount - p.RefundedAmount
+	if left <= 0 {
+		return p, nil
+	}
+
+	u.logger.Warnf("paymentUC.refundCancelled: payment %s succeeded for cancelled order %s, refunding %d", p.PaymentID, p.OrderID, left)
+
+	re, err := u.gateway.Refund(ctx, *p.IntentID, left, "cancelled_"+p.PaymentID.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "paymentUC.refundCancelled.gateway")
+	}
+
+	return u.markRefunded(ctx, p, p.RefundedAmount+re.Amount)
+}
+
+// Record refunded total, a fully refunded payment refunds its order
+func (u *paymentUC) markRefunded(ctx context.Context, p *models.Payment, refundedAmount int64) (*models.Payment, error) {
+	updated, err := u.paymentRepo.SetRefunded(ctx, p.PaymentID, refundedAmount)
+	if err != nil {
+		return nil, err
+	}
+	if updated.Status != models.PaymentStatusRefunded {
+		return updated, nil
+	}
+
+	o, err := u.orderRepo.GetByID(ctx, updated.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if !models.CanTransitionOrder(o.Status, models.OrderStatusRefunded) {
+		return updated, nil
+	}
+
+	event := &models.OrderEvent{ToStatus: models.OrderStatusRefunded, Note: "payment " + *updated.IntentID + " refunded"}
+	if user, err := middleware.GetUserFromCtx(ctx); err == nil {
+		event.ActorID = &user.UserID
+	}
+	if _, err = u.orderRepo.UpdateStatus(ctx, o.OrderID, o.Status, event); err != nil && !errors.Is(err, httpErrors.InvalidStateTransition) {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// Latest succeeded payment of the order
+func (u *paymentUC) succeededPayment(ctx context.Context, orderID uuid.UUID) (*models.Payment, error) {
+	payments, err := u.paymentRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(payments) - 1; i >= 0; i-- {
+		if payments[i].Status == models.PaymentStatusSucceeded {
+			return payments[i], nil
+		}
+	}
+
+	return nil, errors.Wrap(sql.ErrNoRows, "paymentUC.succeededPayment")
+}
+
+// Idempotency key of the provider calls of a payment attempt
+func attemptKey(p *models.Payment) string {
+	return "payment_" + p.PaymentID.String()
+}
+
+func refundableAmount(payments []*models.Payment) int64 {
+	var refundable int64
+	for _, p := range payments {
+		if p.Status == models.PaymentStatusSucceeded {
+			refundable += p.Amount - p.RefundedAmount
+		}
+	}
+	return refundable
+}
@@ -0,0 +1,325 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/order"
+	"github.com/fekuna/go-store/internal/payment"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	gateway "github.com/fekuna/go-store/pkg/payment"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// In memory payments with the same conditional updates as the Postgres repository
+type memPaymentRepo struct {
+	payment.Repository
+	payments []*models.Payment
+	methods  []*models.PaymentMethod
+}
+
+func (r *memPaymentRepo) Create(ctx context.Context, p *models.Payment) (*models.Payment, error) {
+	created := *p
+	created.PaymentID = uuid.New()
+	created.CreatedAt = time.Now()
+	r.payments = append(r.payments, &created)
+	copied := created
+	return &copied, nil
+}
+
+func (r *memPaymentRepo) find(paymentID uuid.UUID) *models.Payment {
+	for _, p := range r.payments {
+		if p.PaymentID == paymentID {
+			return p
+		}
+	}
+	return nil
+}
+
+func (r *memPaymentRepo) GetByIntent(ctx context.Context, provider string, intentID string) (*models.Payment, error) {
+	for _, p := range r.payments {
+		if p.IntentID != nil && *p.IntentID == intentID {
+			copied := *p
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *memPaymentRepo) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.Payment, error) {
+	list := make([]*models.Payment, 0)
+	for _, p := range r.payments {
+		if p.OrderID == orderID {
+			copied := *p
+			list = append(list, &copied)
+		}
+	}
+	return list, nil
+}
+
+func (r *memPaymentRepo) SetIntent(ctx context.Context, paymentID uuid.UUID, intentID string, amount int64) (*models.Payment, error) {
+	p := r.find(paymentID)
+	if p.IntentID != nil && *p.IntentID != intentID {
+		return nil, sql.ErrNoRows
+	}
+	p.IntentID, p.Amount = &intentID, amount
+	copied := *p
+	return &copied, nil
+}
+
+func (r *memPaymentRepo) UpdateStatus(ctx context.Context, paymentID uuid.UUID, from string, to string) (*models.Payment, error) {
+	p := r.find(paymentID)
+	if p.Status != from {
+		return nil, sql.ErrNoRows
+	}
+	p.Status = to
+	copied := *p
+	return &copied, nil
+}
+
+func (r *memPaymentRepo) SetRefunded(ctx context.Context, paymentID uuid.UUID, refundedAmount int64) (*models.Payment, error) {
+	p := r.find(paymentID)
+	if refundedAmount > p.RefundedAmount {
+		p.RefundedAmount = refundedAmount
+	}
+	if p.RefundedAmount >= p.Amount {
+		p.Status = models.PaymentStatusRefunded
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (r *memPaymentRepo) CreateMethod(ctx context.Context, method *models.PaymentMethod) (*models.PaymentMethod, error) {
+	created := *method
+	created.PaymentMethodID = uuid.New()
+	r.methods = append(r.methods, &created)
+	copied := created
+	return &copied, nil
+}
+
+func (r *memPaymentRepo) GetMethodByID(ctx context.Context, paymentMethodID uuid.UUID) (*models.PaymentMethod, error) {
+	for _, m := range r.methods {
+		if m.PaymentMethodID == paymentMethodID {
+			copied := *m
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *memPaymentRepo) ListMethodsByUser(ctx context.Context, userID uuid.UUID) ([]*models.PaymentMethod, error) {
+	list := make([]*models.PaymentMethod, 0)
+	for _, m := range r.methods {
+		if m.UserID == userID {
+			copied := *m
+			list = append(list, &copied)
+		}
+	}
+	return list, nil
+}
+
+func (r *memPaymentRepo) ActivateMethod(ctx context.Context, paymentMethodID uuid.UUID, providerMethodID string) (*models.PaymentMethod, error) {
+	for _, m := range r.methods {
+		if m.PaymentMethodID == paymentMethodID {
+			m.Status, m.ProviderMethodID = models.PaymentMethodStatusActive, &providerMethodID
+			copied := *m
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// One order that moves through its statuses
+type memOrders struct {
+	order.Repository
+	o *models.Order
+}
+
+func (m *memOrders) GetByID(ctx context.Context, orderID uuid.UUID) (*models.Order, error) {
+	copied := *m.o
+	return &copied, nil
+}
+
+func (m *memOrders) UpdateStatus(ctx context.Context, orderID uuid.UUID, from string, event *models.OrderEvent) (*models.Order, error) {
+	if m.o.Status != from || !models.CanTransitionOrder(from, event.ToStatus) {
+		return nil, errors.Wrap(httpErrors.InvalidStateTransition, "memOrders.UpdateStatus")
+	}
+	m.o.Status = event.ToStatus
+	return m.GetByID(ctx, orderID)
+}
+
+// Order usecase that shows the order to everyone
+type memOrderUC struct {
+	order.UseCase
+	orders *memOrders
+}
+
+func (m *memOrderUC) GetByID(ctx context.Context, orderID uuid.UUID) (*models.Order, error) {
+	return m.orders.GetByID(ctx, orderID)
+}
+
+func newPaymentTestUC() (*paymentUC, *memPaymentRepo, *memOrders, *gateway.Fake) {
+	cfg := &config.Config{Logger: config.LoggerConfig{Level: "fatal"}}
+	apiLogger := logger.NewApiLogger(cfg)
+	apiLogger.InitLogger()
+
+	orders := &memOrders{o: &models.Order{OrderID: uuid.New(), Status: models.OrderStatusPending, Currency: "USD", Total: 2500}}
+	repo := &memPaymentRepo{}
+	fake := gateway.NewFake("whsec")
+	u := NewPaymentUseCase(cfg, apiLogger, repo, orders, &memOrderUC{orders: orders}, fake).(*paymentUC)
+
+	return u, repo, orders, fake
+}
+
+func TestCreateIntentReusesOpenIntent(t *testing.T) {
+	u, repo, orders, fake := newPaymentTestUC()
+	ctx := context.Background()
+
+	first, err := u.CreateIntent(ctx, orders.o.OrderID)
+	if err != nil {
+		t.Fatalf("CreateIntent() error = %v", err)
+	}
+	second, err := u.CreateIntent(ctx, orders.o.OrderID)
+	if err != nil {
+		t.Fatalf("second CreateIntent() error = %v", err)
+	}
+	if second.Payment.PaymentID != first.Payment.PaymentID || second.ClientSecret != first.ClientSecret {
+		t.Fatalf("second intent = %s %q, want %s %q", second.Payment.PaymentID, second.ClientSecret,
+			first.Payment.PaymentID, first.ClientSecret)
+	}
+	if len(repo.payments) != 1 {
+		t.Fatalf("%d payments, want 1", len(repo.payments))
+	}
+
+	// Charged at the provider before its webhook arrived
+	if _, err = fake.Capture(ctx, *first.Payment.IntentID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = u.CreateIntent(ctx, orders.o.OrderID); !errors.Is(err, httpErrors.InvalidStateTransition) {
+		t.Fatalf("CreateIntent() of a charged intent error = %v, want %v", err, httpErrors.InvalidStateTransition)
+	}
+	if len(repo.payments) != 1 {
+		t.Fatalf("%d payments, want 1", len(repo.payments))
+	}
+}
+
+func TestDuplicatePaymentIsRefunded(t *testing.T) {
+	for _, latestFirst := range []bool{false, true} {
+		u, repo, orders, fake := newPaymentTestUC()
+		ctx := context.Background()
+
+		// Two intents of the same order, like a second tab opened before the first intent existed
+		intents := make([]*gateway.Intent, 2)
+		for i := range intents {
+			attempt, err := repo.Create(ctx, &models.Payment{OrderID: orders.o.OrderID, Provider: fake.Name(),
+				Status: models.PaymentStatusPending, Amount: 2500, Currency: "USD"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			intents[i], _ = fake.CreateIntent(ctx, &gateway.IntentRequest{Amount: 2500, Currency: "USD"})
+			if _, err = repo.SetIntent(ctx, attempt.PaymentID, intents[i].ID, 2500); err != nil {
+				t.Fatal(err)
+			}
+			if _, err = fake.Capture(ctx, intents[i].ID, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		order := []int{0, 1}
+		if latestFirst {
+			order = []int{1, 0}
+		}
+		for _, i := range order {
+			p, _ := repo.GetByIntent(ctx, fake.Name(), intents[i].ID)
+			if _, err := u.markSucceeded(ctx, p); err != nil {
+				t.Fatalf("markSucceeded() error = %v", err)
+			}
+		}
+		// Replayed success of the payment that is kept
+		kept, _ := repo.GetByIntent(ctx, fake.Name(), intents[0].ID)
+		if _, err := u.markSucceeded(ctx, kept); err != nil {
+			t.Fatalf("replayed markSucceeded() error = %v", err)
+		}
+
+		kept, _ = repo.GetByIntent(ctx, fake.Name(), intents[0].ID)
+		duplicate, _ := repo.GetByIntent(ctx, fake.Name(), intents[1].ID)
+		if kept.Status != models.PaymentStatusSucceeded || kept.RefundedAmount != 0 {
+			t.Fatalf("latest first %v: kept payment is %s with %d refunded", latestFirst, kept.Status, kept.RefundedAmount)
+		}
+		if duplicate.Status != models.PaymentStatusRefunded || duplicate.RefundedAmount != 2500 {
+			t.Fatalf("latest first %v: duplicate payment is %s with %d refunded, want refunded", latestFirst,
+				duplicate.Status, duplicate.RefundedAmount)
+		}
+		if orders.o.Status != models.OrderStatusPaid {
+			t.Fatalf("latest first %v: order is %s, want %s", latestFirst, orders.o.Status, models.OrderStatusPaid)
+		}
+	}
+}
+
+func TestSavedPaymentMethod(t *testing.T) {
+	u, repo, orders, fake := newPaymentTestUC()
+	owner, other := &models.User{UserID: uuid.New()}, &models.User{UserID: uuid.New()}
+	ctx := context.WithValue(context.Background(), utils.UserCtxKey{}, owner)
+
+	setup, err := u.SetupMethod(ctx)
+	if err != nil {
+		t.Fatalf("SetupMethod() error = %v", err)
+	}
+	method := setup.PaymentMethod
+	if method.Status != models.PaymentMethodStatusPending || setup.ClientSecret == "" {
+		t.Fatalf("setup = %+v %q, want a pending method with a client secret", method, setup.ClientSecret)
+	}
+	if _, err = u.ConfirmMethod(ctx, method.PaymentMethodID); !errors.Is(err, httpErrors.InvalidStateTransition) {
+		t.Fatalf("ConfirmMethod() before the setup succeeded error = %v, want %v", err, httpErrors.InvalidStateTransition)
+	}
+
+	if err = fake.ConfirmSetup(method.SetupID, "pm_card_visa"); err != nil {
+		t.Fatal(err)
+	}
+	otherCtx := context.WithValue(context.Background(), utils.UserCtxKey{}, other)
+	if _, err = u.ConfirmMethod(otherCtx, method.PaymentMethodID); httpErrors.ParseError(err).Status() != http.StatusNotFound {
+		t.Fatalf("ConfirmMethod() of another user error = %v, want not found", err)
+	}
+	if method, err = u.ConfirmMethod(ctx, method.PaymentMethodID); err != nil || method.Status != models.PaymentMethodStatusActive {
+		t.Fatalf("ConfirmMethod() = %+v, %v, want an active method", method, err)
+	}
+
+	second, err := u.SetupMethod(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.PaymentMethod.CustomerID != method.CustomerID {
+		t.Fatalf("second setup customer = %s, want %s", second.PaymentMethod.CustomerID, method.CustomerID)
+	}
+
+	if err = u.CheckMethod(ctx, other.UserID, method.PaymentMethodID); httpErrors.ParseError(err).Status() != http.StatusBadRequest {
+		t.Fatalf("CheckMethod() of another user error = %v, want bad request", err)
+	}
+	if err = u.CheckMethod(ctx, owner.UserID, second.PaymentMethod.PaymentMethodID); httpErrors.ParseError(err).Status() != http.StatusBadRequest {
+		t.Fatalf("CheckMethod() of a pending method error = %v, want bad request", err)
+	}
+
+	// Order of another customer can't be charged to the method
+	orders.o.UserID = &other.UserID
+	var decline *gateway.DeclineError
+	if _, err = u.ChargeOrder(ctx, orders.o.OrderID, method.PaymentMethodID, ""); !errors.As(err, &decline) {
+		t.Fatalf("ChargeOrder() of another user's order error = %v, want a decline", err)
+	}
+	if len(repo.payments) != 0 {
+		t.Fatalf("%d payments, want none", len(repo.payments))
+	}
+
+	orders.o.UserID = &owner.UserID
+	p, err := u.ChargeOrder(ctx, orders.o.OrderID, method.PaymentMethodID, "")
+	if err != nil || p.Status != models.PaymentStatusSucceeded {
+		t.Fatalf("ChargeOrder() = %+v, %v, want a succeeded payment", p, err)
+	}
+}
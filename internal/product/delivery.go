@@ -0,0 +1,18 @@
+package product
+
+import "github.com/labstack/echo/v4"
+
+// Product HTTP Handlers interface
+type Handlers interface {
+	Create() echo.HandlerFunc
+	Update() echo.HandlerFunc
+	Delete() echo.HandlerFunc
+	GetByID() echo.HandlerFunc
+	GetBySlug() echo.HandlerFunc
+	List() echo.HandlerFunc
+	SetOptions() echo.HandlerFunc
+	GenerateVariants() echo.HandlerFunc
+	GetVariant() echo.HandlerFunc
+	UpdateVariant() echo.HandlerFunc
+	DeleteVariant() echo.HandlerFunc
+}
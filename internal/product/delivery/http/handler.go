@@ -0,0 +1,362 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/product"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Product handlers
+type productHandlers struct {
+	cfg       *config.Config
+	logger    logger.Logger
+	productUC product.UseCase
+}
+
+// Product handlers constructor
+func NewProductHandlers(cfg *config.Config, logger logger.Logger, productUC product.UseCase) product.Handlers {
+	return &productHandlers{
+		cfg:       cfg,
+		logger:    logger,
+		productUC: productUC,
+	}
+}
+
+// Create godoc
+// @Summary Create product
+// @Description create new product, admin only
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Product
+// @Failure 500 {object} httpErrors.RestError
+// @Router /products [post]
+func (h *productHandlers) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		p := &models.Product{}
+		if err := utils.ReadRequest(c, p); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		createdProduct, err := h.productUC.Create(ctx, p)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, createdProduct)
+	}
+}
+
+// Update godoc
+// @Summary Update product
+// @Description update product, fields left out are not changed, admin only
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param product_id path string true "product_id"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} httpErrors.RestError
+// @Router /products/{product_id} [put]
+func (h *productHandlers) Update() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		productID, err := uuid.Parse(c.Param("product_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		input := &models.ProductUpdateInput{}
+		if err = utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		updatedProduct, err := h.productUC.Update(ctx, productID, input)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, updatedProduct)
+	}
+}
+
+// Delete godoc
+// @Summary Delete product
+// @Description archive product, it stays on past orders and stock history, admin only
+// @Tags Products
+// @Param product_id path string true "product_id"
+// @Success 204
+// @Failure 500 {object} httpErrors.RestError
+// @Router /products/{product_id} [delete]
+func (h *productHandlers) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		productID, err := uuid.Parse(c.Param("product_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.productUC.Delete(ctx, productID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetByID godoc
+// @Summary Get product by id
+// @Description get active product by id
+// @Tags Products
+// @Produce json
+// @Param product_id path string true "product_id"
+// @Success 200 {object} models.Product
+// @Failure 404 {object} httpErrors.RestError
+// @Router /products/{product_id} [get]
+func (h *productHandlers) GetByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		productID, err := uuid.Parse(c.Param("product_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		p, err := h.productUC.GetByID(ctx, productID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, p)
+	}
+}
+
+// GetBySlug godoc
+// @Summary Get product by slug
+// @Description get active product by url slug
+// @Tags Products
+// @Produce json
+// @Param slug path string true "slug"
+// @Success 200 {object} models.Product
+// @Failure 404 {object} httpErrors.RestError
+// @Router /products/slug/{slug} [get]
+func (h *productHandlers) GetBySlug() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		p, err := h.productUC.GetBySlug(ctx, c.Param("slug"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, p)
+	}
+}
+
+// List godoc
+// @Summary Get products
+// @Description get active products with pagination
+// @Tags Products
+// @Produce json
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.ProductsList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /products [get]
+func (h *productHandlers) List() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		productsList, err := h.productUC.List(ctx, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, productsList)
+	}
+}
+
+// SetOptions godoc
+// @Summary Set product options
+// @Description replace product options like Size or Color with their values, admin only
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param product_id path string true "product_id"
+// @Success 200 {array} models.ProductOption
+// @Failure 500 {object} httpErrors.RestError
+// @Router /products/{product_id}/options [put]
+func (h *productHandlers) SetOptions() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		productID, err := uuid.Parse(c.Param("product_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		input := &models.ProductOptionsInput{}
+		if err = utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		options, err := h.productUC.SetOptions(ctx, productID, input)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, options)
+	}
+}
+
+// GenerateVariants godoc
+// @Summary Generate product variants
+// @Description create a variant for every option value combination not yet present, admin only
+// @Tags Products
+// @Produce json
+// @Param product_id path string true "product_id"
+// @Success 200 {array} models.ProductVariant
+// @Failure 500 {object} httpErrors.RestError
+// @Router /products/{product_id}/variants/generate [post]
+func (h *productHandlers) GenerateVariants() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		productID, err := uuid.Parse(c.Param("product_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		variants, err := h.productUC.GenerateVariants(ctx, productID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, variants)
+	}
+}
+
+// GetVariant godoc
+// @Summary Get variant
+// @Description get sellable product variant by id
+// @Tags Products
+// @Produce json
+// @Param variant_id path string true "variant_id"
+// @Success 200 {object} models.ProductVariant
+// @Failure 404 {object} httpErrors.RestError
+// @Router /products/variants/{variant_id} [get]
+func (h *productHandlers) GetVariant() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		variantID, err := uuid.Parse(c.Param("variant_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		variant, err := h.productUC.GetVariant(ctx, variantID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, variant)
+	}
+}
+
+// UpdateVariant godoc
+// @Summary Update variant
+// @Description update variant SKU, price override, barcode, weight and images, admin only
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param product_id path string true "product_id"
+// @Param variant_id path string true "variant_id"
+// @Success 200 {object} models.ProductVariant
+// @Failure 500 {object} httpErrors.RestError
+// @Router /products/{product_id}/variants/{variant_id} [put]
+func (h *productHandlers) UpdateVariant() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		productID, err := uuid.Parse(c.Param("product_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		variantID, err := uuid.Parse(c.Param("variant_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		variant := &models.ProductVariant{}
+		if err = utils.ReadRequest(c, variant); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		variant.ProductID = productID
+		variant.VariantID = variantID
+
+		updatedVariant, err := h.productUC.UpdateVariant(ctx, variant)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, updatedVariant)
+	}
+}
+
+// DeleteVariant godoc
+// @Summary Delete variant
+// @Description archive product variant, it stays on past orders, stock history and saved items, admin only
+// @Tags Products
+// @Param product_id path string true "product_id"
+// @Param variant_id path string true "variant_id"
+// @Success 204
+// @Failure 500 {object} httpErrors.RestError
+// @Router /products/{product_id}/variants/{variant_id} [delete]
+func (h *productHandlers) DeleteVariant() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		productID, err := uuid.Parse(c.Param("product_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		variantID, err := uuid.Parse(c.Param("variant_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.productUC.DeleteVariant(ctx, productID, variantID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,24 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/product"
+	"github.com/labstack/echo/v4"
+)
+
+func MapProductRoutes(productGroup *echo.Group, h product.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	productGroup.GET("", h.List())
+	productGroup.GET("/:product_id", h.GetByID())
+	productGroup.GET("/slug/:slug", h.GetBySlug())
+	productGroup.GET("/variants/:variant_id", h.GetVariant())
+	productGroup.POST("", h.Create(), adminOnly...)
+	productGroup.PUT("/:product_id", h.Update(), adminOnly...)
+	productGroup.DELETE("/:product_id", h.Delete(), adminOnly...)
+	productGroup.PUT("/:product_id/options", h.SetOptions(), adminOnly...)
+	productGroup.POST("/:product_id/variants/generate", h.GenerateVariants(), adminOnly...)
+	productGroup.PUT("/:product_id/variants/:variant_id", h.UpdateVariant(), adminOnly...)
+	productGroup.DELETE("/:product_id/variants/:variant_id", h.DeleteVariant(), adminOnly...)
+}
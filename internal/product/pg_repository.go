@@ -0,0 +1,28 @@
+package product
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Product repository
+type Repository interface {
+	Create(ctx context.Context, product *models.Product) (*models.Product, error)
+	Update(ctx context.Context, productID uuid.UUID, input *models.ProductUpdateInput) (*models.Product, error)
+	Archive(ctx context.Context, productID uuid.UUID) error
+	GetByID(ctx context.Context, productID uuid.UUID) (*models.Product, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Product, error)
+	GetBySKU(ctx context.Context, sku string) (*models.Product, error)
+	List(ctx context.Context, status string, pq *utils.PaginationQuery) (*models.ProductsList, error)
+	GetOptions(ctx context.Context, productID uuid.UUID) ([]*models.ProductOption, error)
+	ReplaceOptions(ctx context.Context, productID uuid.UUID, options []*models.ProductOption) ([]*models.ProductOption, error)
+	GetVariants(ctx context.Context, productID uuid.UUID) ([]*models.ProductVariant, error)
+	GetVariantByID(ctx context.Context, variantID uuid.UUID) (*models.ProductVariant, error)
+	GetVariantBySKU(ctx context.Context, sku string) (*models.ProductVariant, error)
+	CreateVariants(ctx context.Context, productID uuid.UUID, variants []*models.ProductVariant) ([]*models.ProductVariant, error)
+	UpdateVariant(ctx context.Context, variant *models.ProductVariant) (*models.ProductVariant, error)
+	ArchiveVariant(ctx context.Context, productID uuid.UUID, variantID uuid.UUID) error
+}
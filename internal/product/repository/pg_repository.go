@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/product"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Product repository
+type productRepo struct {
+	db *sqlx.DB
+}
+
+// Product repository constructor
+func NewProductRepository(db *sqlx.DB) product.Repository {
+	return &productRepo{db: db}
+}
+
+// Create product together with its default variant
+func (r *productRepo) Create(ctx context.Context, p *models.Product) (*models.Product, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "productRepo.Create.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	created := &models.Product{}
+	if err = tx.QueryRowxContext(
+		ctx, createProductQuery, &p.SKU, &p.Slug, &p.Title, &p.Description, &p.Price, &p.Currency, &p.Status, &p.Brand, &p.TaxClass,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "productRepo.Create.StructScan")
+	}
+
+	if err = r.createVariant(ctx, tx, models.NewDefaultVariant(created)); err != nil {
+		return nil, errors.Wrap(err, "productRepo.Create.createVariant")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "productRepo.Create.Commit")
+	}
+
+	return created, nil
+}
+
+// Update product, default variant follows product SKU and title
+func (r *productRepo) Update(ctx context.Context, productID uuid.UUID, in *models.ProductUpdateInput) (*models.Product, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "productRepo.Update.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	updated := &models.Product{}
+	if err = tx.GetContext(ctx, updated, updateProductQuery, in.SKU, in.Slug, in.Title, in.Description,
+		in.Price, in.Currency, in.Status, in.Brand, in.TaxClass, productID,
+	); err != nil {
+		return nil, errors.Wrap(err, "productRepo.Update.GetContext")
+	}
+
+	if _, err = tx.ExecContext(ctx, syncDefaultVariantQuery, &updated.SKU, &updated.Title, &updated.ProductID); err != nil {
+		return nil, errors.Wrap(err, "productRepo.Update.ExecContext.syncDefaultVariant")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "productRepo.Update.Commit")
+	}
+
+	return updated, nil
+}
+
+func (r *productRepo) Archive(ctx context.Context, productID uuid.UUID) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, archiveProductQuery, productID)
+	if err != nil {
+		return errors.Wrap(err, "productRepo.Archive.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "productRepo.Archive.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "productRepo.Archive.rowsAffected")
+	}
+
+	return nil
+}
+
+func (r *productRepo) GetByID(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	p := &models.Product{}
+	if err := r.db.GetContext(ctx, p, getProductByIdQuery, productID); err != nil {
+		return nil, errors.Wrap(err, "productRepo.GetByID.GetContext")
+	}
+
+	return p, nil
+}
+
+func (r *productRepo) GetBySlug(ctx context.Context, slug string) (*models.Product, error) {
+	p := &models.Product{}
+	if err := r.db.GetContext(ctx, p, getProductBySlugQuery, slug); err != nil {
+		return nil, errors.Wrap(err, "productRepo.GetBySlug.GetContext")
+	}
+
+	return p, nil
+}
+
+func (r *productRepo) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	p := &models.Product{}
+	if err := r.db.GetContext(ctx, p, getProductBySkuQuery, sku); err != nil {
+		return nil, errors.Wrap(err, "productRepo.GetBySKU.GetContext")
+	}
+
+	return p, nil
+}
+
+func (r *productRepo) List(ctx context.Context, status string, pq *utils.PaginationQuery) (*models.ProductsList, error) {
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalProductsQuery, status); err != nil {
+		return nil, errors.Wrap(err, "productRepo.List.GetContext.totalCount")
+	}
+
+	products := make([]*models.Product, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &products, listProductsQuery, status, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "productRepo.List.SelectContext")
+		}
+	}
+
+	return &models.ProductsList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Products:   products,
+	}, nil
+}
+
+func (r *productRepo) GetOptions(ctx context.Context, productID uuid.UUID) ([]*models.ProductOption, error) {
+	options := make([]*models.ProductOption, 0)
+	if err := r.db.SelectContext(ctx, &options, getProductOptionsQuery, productID); err != nil {
+		return nil, errors.Wrap(err, "productRepo.GetOptions.SelectContext")
+	}
+
+	return options, nil
+}
+
+// Replace all product options, existing variants keep their option values
+func (r *productRepo) ReplaceOptions(ctx context.Context, productID uuid.UUID, options []*models.ProductOption) ([]*models.ProductOption, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "productRepo.ReplaceOptions.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, deleteProductOptionsQuery, productID); err != nil {
+		return nil, errors.Wrap(err, "productRepo.ReplaceOptions.ExecContext.delete")
+	}
+
+	created := make([]*models.ProductOption, 0, len(options))
+	for i, opt := range options {
+		o := &models.ProductOption{}
+		if err = tx.QueryRowxContext(ctx, createProductOptionQuery, productID, &opt.Name, i, &opt.Values).StructScan(o); err != nil {
+			return nil, errors.Wrap(err, "productRepo.ReplaceOptions.StructScan")
+		}
+		created = append(created, o)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "productRepo.ReplaceOptions.Commit")
+	}
+
+	return created, nil
+}
+
+func (r *productRepo) GetVariants(ctx context.Context, productID uuid.UUID) ([]*models.ProductVariant, error) {
+	variants := make([]*models.ProductVariant, 0)
+	if err := r.db.SelectContext(ctx, &variants, getProductVariantsQuery, productID); err != nil {
+		return nil, errors.Wrap(err, "productRepo.GetVariants.SelectContext")
+	}
+
+	return variants, nil
+}
+
+func (r *productRepo) GetVariantByID(ctx context.Context, variantID uuid.UUID) (*models.ProductVariant, error) {
+	v := &models.ProductVariant{}
+	if err := r.db.GetContext(ctx, v, getVariantByIdQuery, variantID); err != nil {
+		return nil, errors.Wrap(err, "productRepo.GetVariantByID.GetContext")
+	}
+
+	return v, nil
+}
+
+func (r *productRepo) GetVariantBySKU(ctx context.Context, sku string) (*models.ProductVariant, error) {
+	v := &models.ProductVariant{}
+	if err := r.db.GetContext(ctx, v, getVariantBySkuQuery, sku); err != nil {
+		return nil, errors.Wrap(err, "productRepo.GetVariantBySKU.GetContext")
+	}
+
+	return v, nil
+}
+
+// Insert missing variants of the option matrix, archived ones are restored, and archive the default variant they replace
+func (r *productRepo) CreateVariants(ctx context.Context, productID uuid.UUID, variants []*models.ProductVariant) ([]*models.ProductVariant, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "productRepo.CreateVariants.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, archiveDefaultVariantQuery, productID); err != nil {
+		return nil, errors.Wrap(err, "productRepo.CreateVariants.ExecContext.archiveDefault")
+	}
+
+	for _, v := range variants {
+		if err = r.createVariant(ctx, tx, v); err != nil {
+			return nil, errors.Wrap(err, "productRepo.CreateVariants.createVariant")
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "productRepo.CreateVariants.Commit")
+	}
+
+	return r.GetVariants(ctx, productID)
+}
+
+func (r *productRepo) UpdateVariant(ctx context.Context, v *models.ProductVariant) (*models.ProductVariant, error) {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, updateVariantQuery, &v.SKU, &v.Title, v.PriceOverride, v.Barcode,
+		&v.WeightGrams, &v.Images, &v.VariantID, &v.ProductID,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "productRepo.UpdateVariant.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, errors.Wrap(err, "productRepo.UpdateVariant.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return nil, errors.Wrap(sql.ErrNoRows, "productRepo.UpdateVariant.rowsAffected")
+	}
+
+	return r.GetVariantByID(ctx, v.VariantID)
+}
+
+func (r *productRepo) ArchiveVariant(ctx context.Context, productID uuid.UUID, variantID uuid.UUID) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, archiveVariantQuery, variantID, productID)
+	if err != nil {
+		return errors.Wrap(err, "productRepo.ArchiveVariant.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "productRepo.ArchiveVariant.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "productRepo.ArchiveVariant.rowsAffected")
+	}
+
+	return nil
+}
+
+func (r *productRepo) createVariant(ctx context.Context, tx *sqlx.Tx, v *models.ProductVariant) error {
+	_, err := tx.ExecContext(ctx, createVariantQuery, &v.ProductID, &v.SKU, &v.Title, &v.Options, v.PriceOverride,
+		v.Barcode, &v.WeightGrams, &v.Images, &v.IsDefault,
+	)
+
+	return err
+}
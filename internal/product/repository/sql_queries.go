@@ -0,0 +1,118 @@
+package repository
+
+const (
+	createProductQuery = `
+		INSERT INTO products(sku, slug, title, description, price, currency, status, brand, tax_class, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, now(), now())
+		RETURNING *
+	`
+
+	// NULL parameters keep the current value
+	updateProductQuery = `
+		UPDATE products
+		SET sku = COALESCE($1, sku),
+			slug = COALESCE($2, slug),
+			title = COALESCE($3, title),
+			description = COALESCE($4, description),
+			price = COALESCE($5, price),
+			currency = COALESCE($6, currency),
+			status = COALESCE($7, status),
+			brand = COALESCE($8, brand),
+			tax_class = COALESCE($9, tax_class),
+			updated_at = now()
+		WHERE product_id = $10
+		RETURNING *
+	`
+
+	// Products stay referenced by order lines and stock movements, so they are archived instead of deleted
+	archiveProductQuery = `UPDATE products SET status = 'archived', updated_at = now() WHERE product_id = $1`
+
+	getProductByIdQuery = `SELECT * FROM products WHERE product_id = $1`
+
+	getProductBySlugQuery = `SELECT * FROM products WHERE slug = $1`
+
+	getProductBySkuQuery = `SELECT * FROM products WHERE sku = $1`
+
+	getTotalProductsQuery = `SELECT COUNT(product_id) FROM products WHERE status = $1`
+
+	listProductsQuery = `
+		SELECT * FROM products
+		WHERE status = $1
+		ORDER BY created_at DESC, product_id
+		OFFSET $2 LIMIT $3
+	`
+
+	getProductOptionsQuery = `SELECT * FROM product_options WHERE product_id = $1 ORDER BY position`
+
+	deleteProductOptionsQuery = `DELETE FROM product_options WHERE product_id = $1`
+
+	createProductOptionQuery = `
+		INSERT INTO product_options(product_id, name, position, option_values)
+		VALUES ($1, $2, $3, $4)
+		RETURNING *
+	`
+
+	variantColumns = `
+		v.variant_id, v.product_id, v.sku, v.title, v.options, v.price_override,
+		COALESCE(v.price_override, p.price) AS price, p.currency, v.barcode, v.weight_grams,
+		v.images, v.attributes, v.is_default, v.status, v.created_at, v.updated_at
+	`
+
+	getProductVariantsQuery = `
+		SELECT ` + variantColumns + `
+		FROM product_variants v
+		JOIN products p ON p.product_id = v.product_id
+		WHERE v.product_id = $1 AND v.status = 'active'
+		ORDER BY v.is_default DESC, v.created_at, v.sku
+	`
+
+	getVariantByIdQuery = `
+		SELECT ` + variantColumns + `
+		FROM product_variants v
+		JOIN products p ON p.product_id = v.product_id
+		WHERE v.variant_id = $1
+	`
+
+	getVariantBySkuQuery = `
+		SELECT ` + variantColumns + `
+		FROM product_variants v
+		JOIN products p ON p.product_id = v.product_id
+		WHERE v.sku = $1
+	`
+
+	createVariantQuery = `
+		INSERT INTO product_variants(product_id, sku, title, options, price_override, barcode, weight_grams, images, is_default, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, now(), now())
+		ON CONFLICT (product_id, options) DO UPDATE SET status = 'active', updated_at = now()
+		WHERE product_variants.status = 'archived'
+	`
+
+	// Variants stay referenced by stock movements, order lines and saved items, so they are archived instead of deleted
+	archiveDefaultVariantQuery = `
+		UPDATE product_variants SET status = 'archived', updated_at = now()
+		WHERE product_id = $1 AND is_default AND status = 'active'
+	`
+
+	updateVariantQuery = `
+		UPDATE product_variants
+		SET sku = COALESCE(NULLIF($1, ''), sku),
+			title = COALESCE(NULLIF($2, ''), title),
+			price_override = $3,
+			barcode = $4,
+			weight_grams = $5,
+			images = $6,
+			updated_at = now()
+		WHERE variant_id = $7 AND product_id = $8
+	`
+
+	archiveVariantQuery = `
+		UPDATE product_variants SET status = 'archived', updated_at = now()
+		WHERE variant_id = $1 AND product_id = $2 AND status = 'active'
+	`
+
+	syncDefaultVariantQuery = `
+		UPDATE product_variants
+		SET sku = $1, title = $2, updated_at = now()
+		WHERE product_id = $3 AND is_default
+	`
+)
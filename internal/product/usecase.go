@@ -0,0 +1,24 @@
+package product
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Product UseCase
+type UseCase interface {
+	Create(ctx context.Context, product *models.Product) (*models.Product, error)
+	Update(ctx context.Context, productID uuid.UUID, input *models.ProductUpdateInput) (*models.Product, error)
+	Delete(ctx context.Context, productID uuid.UUID) error
+	GetByID(ctx context.Context, productID uuid.UUID) (*models.Product, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Product, error)
+	List(ctx context.Context, pq *utils.PaginationQuery) (*models.ProductsList, error)
+	SetOptions(ctx context.Context, productID uuid.UUID, input *models.ProductOptionsInput) ([]*models.ProductOption, error)
+	GenerateVariants(ctx context.Context, productID uuid.UUID) ([]*models.ProductVariant, error)
+	GetVariant(ctx context.Context, variantID uuid.UUID) (*models.ProductVariant, error)
+	UpdateVariant(ctx context.Context, variant *models.ProductVariant) (*models.ProductVariant, error)
+	DeleteVariant(ctx context.Context, productID uuid.UUID, variantID uuid.UUID) error
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/product"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxVariantsPerProduct = 100
+	maxSKULength          = 64
+)
+
+// Product UseCase
+type productUC struct {
+	cfg         *config.Config
+	logger      logger.Logger
+	productRepo product.Repository
+}
+
+// Product UseCase constructor
+func NewProductUseCase(cfg *config.Config, logger logger.Logger, productRepo product.Repository) product.UseCase {
+	return &productUC{
+		cfg:         cfg,
+		logger:      logger,
+		productRepo: productRepo,
+	}
+}
+
+func (u *productUC) Create(ctx context.Context, p *models.Product) (*models.Product, error) {
+	// TODO: Tracing
+
+	p.PrepareCreate()
+	if p.Slug == "" {
+		return nil, httpErrors.NewBadRequestError(errors.New("productUC.Create: slug can't be derived from title"))
+	}
+
+	if err := utils.ValidateStruct(ctx, p); err != nil {
+		return nil, httpErrors.NewBadRequestError(errors.WithMessage(err, "productUC.Create.ValidateStruct"))
+	}
+
+	return u.productRepo.Create(ctx, p)
+}
+
+func (u *productUC) Update(ctx context.Context, productID uuid.UUID, in *models.ProductUpdateInput) (*models.Product, error) {
+	// TODO: Tracing
+
+	if _, err := u.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	in.PrepareUpdate()
+	if err := utils.ValidateStruct(ctx, in); err != nil {
+		return nil, httpErrors.NewBadRequestError(errors.WithMessage(err, "productUC.Update.ValidateStruct"))
+	}
+
+	return u.productRepo.Update(ctx, productID, in)
+}
+
+// Archive product, it is hidden from the storefront but stays on past orders and stock history
+func (u *productUC) Delete(ctx context.Context, productID uuid.UUID) error {
+	// TODO: Tracing
+
+	return u.productRepo.Archive(ctx, productID)
+}
+
+// Get active product by id, drafts and archived products are hidden from the storefront
+func (u *productUC) GetByID(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	// TODO: Tracing
+
+	p, err := u.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Status != models.ProductStatusActive {
+		return nil, httpErrors.NewNotFoundError(errors.New("productUC.GetByID: product is not active"))
+	}
+
+	return u.withVariants(ctx, p)
+}
+
+// Get active product by slug
+func (u *productUC) GetBySlug(ctx context.Context, slug string) (*models.Product, error) {
+	// TODO: Tracing
+
+	p, err := u.productRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Status != models.ProductStatusActive {
+		return nil, httpErrors.NewNotFoundError(errors.New("productUC.GetBySlug: product is not active"))
+	}
+
+	return u.withVariants(ctx, p)
+}
+
+// List active products
+func (u *productUC) List(ctx context.Context, pq *utils.PaginationQuery) (*models.ProductsList, error) {
+	// TODO: Tracing
+
+	return u.productRepo.List(ctx, models.ProductStatusActive, pq)
+}
+
+// Replace product options, variants are not touched until they are generated again
+func (u *productUC) SetOptions(ctx context.Context, productID uuid.UUID, input *models.ProductOptionsInput) ([]*models.ProductOption, error) {
+	// TODO: Tracing
+
+	if _, err := u.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{}, len(input.Options))
+	for _, opt := range input.Options {
+		opt.Name = strings.TrimSpace(opt.Name)
+		if _, ok := names[strings.ToLower(opt.Name)]; ok {
+			return nil, httpErrors.NewBadRequestError(errors.Errorf("productUC.SetOptions: duplicated option %q", opt.Name))
+		}
+		names[strings.ToLower(opt.Name)] = struct{}{}
+
+		for i, value := range opt.Values {
+			opt.Values[i] = strings.TrimSpace(value)
+		}
+	}
+
+	return u.productRepo.ReplaceOptions(ctx, productID, input.Options)
+}
+
+// Generate variants for every option value combination, existing combinations are kept
+func (u *productUC) GenerateVariants(ctx context.Context, productID uuid.UUID) ([]*models.ProductVariant, error) {
+	// TODO: Tracing
+
+	p, err := u.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := u.productRepo.GetOptions(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := models.BuildVariantMatrix(p, options)
+	if len(variants) == 0 {
+		return nil, httpErrors.NewBadRequestError(errors.New("productUC.GenerateVariants: product has no options"))
+	}
+
+	if len(variants) > maxVariantsPerProduct {
+		return nil, httpErrors.NewBadRequestError(errors.Errorf("productUC.GenerateVariants: %d variants exceed limit of %d", len(variants), maxVariantsPerProduct))
+	}
+
+	if err = checkVariantSKUs(variants); err != nil {
+		return nil, err
+	}
+
+	return u.productRepo.CreateVariants(ctx, productID, variants)
+}
+
+// Generated SKUs keep only letters and digits of the option values, so values that differ in other characters
+// get the same SKU. Those and SKUs over the column length are rejected before anything is saved.
+func checkVariantSKUs(variants []*models.ProductVariant) error {
+	seen := make(map[string]*models.ProductVariant, len(variants))
+	for _, v := range variants {
+		if len(v.SKU) > maxSKULength {
+			return httpErrors.NewRestErrorWithMessage(
+				http.StatusBadRequest,
+				fmt.Sprintf("SKU %s of variant %s is longer than %d characters, shorten the product SKU or option values", v.SKU, v.Title, maxSKULength),
+				errors.Errorf("productUC.checkVariantSKUs: sku %s is too long", v.SKU),
+			)
+		}
+		if other, ok := seen[v.SKU]; ok {
+			return httpErrors.NewRestErrorWithMessage(
+				http.StatusBadRequest,
+				fmt.Sprintf("Variants %s and %s both get SKU %s, make their option values differ in letters or digits", other.Title, v.Title, v.SKU),
+				errors.Errorf("productUC.checkVariantSKUs: duplicate sku %s", v.SKU),
+			)
+		}
+		seen[v.SKU] = v
+	}
+
+	return nil
+}
+
+func (u *productUC) GetVariant(ctx context.Context, variantID uuid.UUID) (*models.ProductVariant, error) {
+	// TODO: Tracing
+
+	return u.productRepo.GetVariantByID(ctx, variantID)
+}
+
+func (u *productUC) UpdateVariant(ctx context.Context, v *models.ProductVariant) (*models.ProductVariant, error) {
+	// TODO: Tracing
+
+	v.PrepareUpdate()
+
+	return u.productRepo.UpdateVariant(ctx, v)
+}
+
+// Archive variant, it is no longer sold but stays on past orders, stock history and saved items
+func (u *productUC) DeleteVariant(ctx context.Context, productID uuid.UUID, variantID uuid.UUID) error {
+	// TODO: Tracing
+
+	return u.productRepo.ArchiveVariant(ctx, productID, variantID)
+}
+
+func (u *productUC) withVariants(ctx context.Context, p *models.Product) (*models.Product, error) {
+	options, err := u.productRepo.GetOptions(ctx, p.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := u.productRepo.GetVariants(ctx, p.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Options = options
+	p.Variants = variants
+
+	return p, nil
+}
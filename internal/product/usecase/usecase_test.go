@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/product"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/google/uuid"
+)
+
+// Product repository of one product and its options, other methods are not used by the variant tests
+type variantProductRepo struct {
+	product.Repository
+	p       *models.Product
+	options []*models.ProductOption
+	created []*models.ProductVariant
+}
+
+func (r *variantProductRepo) GetByID(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	return r.p, nil
+}
+
+func (r *variantProductRepo) GetOptions(ctx context.Context, productID uuid.UUID) ([]*models.ProductOption, error) {
+	return r.options, nil
+}
+
+func (r *variantProductRepo) CreateVariants(ctx context.Context, productID uuid.UUID, variants []*models.ProductVariant) ([]*models.ProductVariant, error) {
+	r.created = variants
+	return variants, nil
+}
+
+func TestGenerateVariantsSKUs(t *testing.T) {
+	tests := []struct {
+		name    string
+		sku     string
+		values  models.StringList
+		wantErr []string
+	}{
+		{name: "distinct skus", sku: "TEE", values: models.StringList{"S", "M"}},
+		{
+			name:    "values that only differ in symbols",
+			sku:     "TEE",
+			values:  models.StringList{"10 ½", "10"},
+			wantErr: []string{"10 ½", "10", "TEE-10"},
+		},
+		{
+			name:    "sku over the column length",
+			sku:     strings.Repeat("A", 60),
+			values:  models.StringList{"Small"},
+			wantErr: []string{strings.Repeat("A", 60) + "-SMALL", "Small"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &variantProductRepo{
+				p:       &models.Product{ProductID: uuid.New(), SKU: tt.sku},
+				options: []*models.ProductOption{{Name: "Size", Values: tt.values}},
+			}
+			u := &productUC{productRepo: repo}
+
+			_, err := u.GenerateVariants(context.Background(), repo.p.ProductID)
+			if tt.wantErr == nil {
+				if err != nil || len(repo.created) != len(tt.values) {
+					t.Fatalf("GenerateVariants() error = %v, created %d variants", err, len(repo.created))
+				}
+				return
+			}
+
+			restErr := httpErrors.ParseError(err)
+			if restErr.Status() != http.StatusBadRequest {
+				t.Fatalf("GenerateVariants() error = %v, want bad request", err)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(restErr.Error(), want) {
+					t.Fatalf("GenerateVariants() error %q does not name %q", restErr.Error(), want)
+				}
+			}
+			if repo.created != nil {
+				t.Fatalf("rejected variants were saved")
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package promotion
+
+import "github.com/labstack/echo/v4"
+
+// Promotion HTTP Handlers interface
+type Handlers interface {
+	Create() echo.HandlerFunc
+	Update() echo.HandlerFunc
+	Delete() echo.HandlerFunc
+	GetByID() echo.HandlerFunc
+	List() echo.HandlerFunc
+	Preview() echo.HandlerFunc
+}
@@ -0,0 +1,204 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/promotion"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Promotion handlers
+type promotionHandlers struct {
+	cfg         *config.Config
+	logger      logger.Logger
+	promotionUC promotion.UseCase
+}
+
+// Promotion handlers constructor
+func NewPromotionHandlers(cfg *config.Config, logger logger.Logger, promotionUC promotion.UseCase) promotion.Handlers {
+	return &promotionHandlers{
+		cfg:         cfg,
+		logger:      logger,
+		promotionUC: promotionUC,
+	}
+}
+
+// Create godoc
+// @Summary Create promotion
+// @Description create discount code or automatic promotion when code is empty, admin only
+// @Tags Promotions
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Promotion
+// @Failure 400 {object} httpErrors.RestError
+// @Router /promotions [post]
+func (h *promotionHandlers) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		p := &models.Promotion{}
+		if err := utils.ReadRequest(c, p); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		createdPromotion, err := h.promotionUC.Create(ctx, p)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, createdPromotion)
+	}
+}
+
+// Update godoc
+// @Summary Update promotion
+// @Description replace promotion settings, usage count is kept, admin only
+// @Tags Promotions
+// @Accept json
+// @Produce json
+// @Param promotion_id path string true "promotion_id"
+// @Success 200 {object} models.Promotion
+// @Failure 400 {object} httpErrors.RestError
+// @Router /promotions/{promotion_id} [put]
+func (h *promotionHandlers) Update() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		promotionID, err := uuid.Parse(c.Param("promotion_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		p := &models.Promotion{}
+		if err = utils.ReadRequest(c, p); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		p.PromotionID = promotionID
+
+		updatedPromotion, err := h.promotionUC.Update(ctx, p)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, updatedPromotion)
+	}
+}
+
+// Delete godoc
+// @Summary Delete promotion
+// @Description delete promotion, discounts of placed orders are kept, admin only
+// @Tags Promotions
+// @Param promotion_id path string true "promotion_id"
+// @Success 204
+// @Failure 404 {object} httpErrors.RestError
+// @Router /promotions/{promotion_id} [delete]
+func (h *promotionHandlers) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		promotionID, err := uuid.Parse(c.Param("promotion_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.promotionUC.Delete(ctx, promotionID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetByID godoc
+// @Summary Get promotion
+// @Description get promotion by id, admin only
+// @Tags Promotions
+// @Produce json
+// @Param promotion_id path string true "promotion_id"
+// @Success 200 {object} models.Promotion
+// @Failure 404 {object} httpErrors.RestError
+// @Router /promotions/{promotion_id} [get]
+func (h *promotionHandlers) GetByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		promotionID, err := uuid.Parse(c.Param("promotion_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		p, err := h.promotionUC.GetByID(ctx, promotionID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, p)
+	}
+}
+
+// List godoc
+// @Summary Get promotions
+// @Description get promotions, newest first, admin only
+// @Tags Promotions
+// @Produce json
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.PromotionsList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /promotions [get]
+func (h *promotionHandlers) List() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		promotionsList, err := h.promotionUC.List(ctx, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, promotionsList)
+	}
+}
+
+// Preview godoc
+// @Summary Preview promotions
+// @Description discounts own cart would get at checkout with the given promo codes, with reasons for codes that don't apply
+// @Tags Promotions
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.PromotionResult
+// @Failure 400 {object} httpErrors.RestError
+// @Router /promotions/preview [post]
+func (h *promotionHandlers) Preview() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		input := &models.CheckoutInput{}
+		if err := utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		result, err := h.promotionUC.Preview(ctx, input)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, result)
+	}
+}
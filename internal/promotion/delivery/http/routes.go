@@ -0,0 +1,19 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/promotion"
+	"github.com/labstack/echo/v4"
+)
+
+func MapPromotionRoutes(promotionGroup *echo.Group, h promotion.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	promotionGroup.POST("/preview", h.Preview(), mw.AuthJWTMiddleware)
+	promotionGroup.GET("", h.List(), adminOnly...)
+	promotionGroup.POST("", h.Create(), adminOnly...)
+	promotionGroup.GET("/:promotion_id", h.GetByID(), adminOnly...)
+	promotionGroup.PUT("/:promotion_id", h.Update(), adminOnly...)
+	promotionGroup.DELETE("/:promotion_id", h.Delete(), adminOnly...)
+}
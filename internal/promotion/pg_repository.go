@@ -0,0 +1,21 @@
+package promotion
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Promotion repository
+type Repository interface {
+	Create(ctx context.Context, promotion *models.Promotion) (*models.Promotion, error)
+	Update(ctx context.Context, promotion *models.Promotion) (*models.Promotion, error)
+	Delete(ctx context.Context, promotionID uuid.UUID) error
+	GetByID(ctx context.Context, promotionID uuid.UUID) (*models.Promotion, error)
+	GetByCode(ctx context.Context, code string) (*models.Promotion, error)
+	List(ctx context.Context, pq *utils.PaginationQuery) (*models.PromotionsList, error)
+	ListAutomatic(ctx context.Context) ([]*models.Promotion, error)
+	CountCustomerUsage(ctx context.Context, promotionID uuid.UUID, userID uuid.UUID) (int, error)
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/promotion"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Promotion repository
+type promotionRepo struct {
+	db *sqlx.DB
+}
+
+// Promotion repository constructor
+func NewPromotionRepository(db *sqlx.DB) promotion.Repository {
+	return &promotionRepo{db: db}
+}
+
+func (r *promotionRepo) Create(ctx context.Context, p *models.Promotion) (*models.Promotion, error) {
+	// TODO: Tracing
+
+	created := &models.Promotion{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		createPromotionQuery,
+		&p.Name,
+		&p.Description,
+		p.Code,
+		&p.Kind,
+		&p.Value,
+		&p.Currency,
+		p.ProductID,
+		&p.MinSubtotal,
+		&p.BuyQuantity,
+		&p.GetQuantity,
+		&p.Stackable,
+		&p.Priority,
+		p.UsageLimit,
+		p.PerCustomerLimit,
+		&p.Active,
+		p.StartsAt,
+		p.EndsAt,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "promotionRepo.Create.StructScan")
+	}
+
+	return created, nil
+}
+
+func (r *promotionRepo) Update(ctx context.Context, p *models.Promotion) (*models.Promotion, error) {
+	// TODO: Tracing
+
+	updated := &models.Promotion{}
+	if err := r.db.GetContext(
+		ctx,
+		updated,
+		updatePromotionQuery,
+		&p.Name,
+		&p.Description,
+		p.Code,
+		&p.Kind,
+		&p.Value,
+		&p.Currency,
+		p.ProductID,
+		&p.MinSubtotal,
+		&p.BuyQuantity,
+		&p.GetQuantity,
+		&p.Stackable,
+		&p.Priority,
+		p.UsageLimit,
+		p.PerCustomerLimit,
+		&p.Active,
+		p.StartsAt,
+		p.EndsAt,
+		&p.PromotionID,
+	); err != nil {
+		return nil, errors.Wrap(err, "promotionRepo.Update.GetContext")
+	}
+
+	return updated, nil
+}
+
+// Delete promotion, discounts of placed orders keep their description and amount
+func (r *promotionRepo) Delete(ctx context.Context, promotionID uuid.UUID) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, deletePromotionQuery, promotionID)
+	if err != nil {
+		return errors.Wrap(err, "promotionRepo.Delete.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "promotionRepo.Delete.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "promotionRepo.Delete.rowsAffected")
+	}
+
+	return nil
+}
+
+func (r *promotionRepo) GetByID(ctx context.Context, promotionID uuid.UUID) (*models.Promotion, error) {
+	// TODO: Tracing
+
+	p := &models.Promotion{}
+	if err := r.db.GetContext(ctx, p, getPromotionByIdQuery, promotionID); err != nil {
+		return nil, errors.Wrap(err, "promotionRepo.GetByID.GetContext")
+	}
+
+	return p, nil
+}
+
+func (r *promotionRepo) GetByCode(ctx context.Context, code string) (*models.Promotion, error) {
+	// TODO: Tracing
+
+	p := &models.Promotion{}
+	if err := r.db.GetContext(ctx, p, getPromotionByCodeQuery, code); err != nil {
+		return nil, errors.Wrap(err, "promotionRepo.GetByCode.GetContext")
+	}
+
+	return p, nil
+}
+
+func (r *promotionRepo) List(ctx context.Context, pq *utils.PaginationQuery) (*models.PromotionsList, error) {
+	// TODO: Tracing
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalPromotionsQuery); err != nil {
+		return nil, errors.Wrap(err, "promotionRepo.List.GetContext.totalCount")
+	}
+
+	promotions := make([]*models.Promotion, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &promotions, listPromotionsQuery, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "promotionRepo.List.SelectContext")
+		}
+	}
+
+	return &models.PromotionsList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Promotions: promotions,
+	}, nil
+}
+
+// Active promotions without code, highest priority first
+func (r *promotionRepo) ListAutomatic(ctx context.Context) ([]*models.Promotion, error) {
+	// TODO: Tracing
+
+	promotions := make([]*models.Promotion, 0)
+	if err := r.db.SelectContext(ctx, &promotions, listAutomaticPromotionsQuery); err != nil {
+		return nil, errors.Wrap(err, "promotionRepo.ListAutomatic.SelectContext")
+	}
+
+	return promotions, nil
+}
+
+// Times the customer used the promotion on orders that weren't cancelled
+func (r *promotionRepo) CountCustomerUsage(ctx context.Context, promotionID uuid.UUID, userID uuid.UUID) (int, error) {
+	// TODO: Tracing
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, countCustomerUsageQuery, promotionID, userID); err != nil {
+		return 0, errors.Wrap(err, "promotionRepo.CountCustomerUsage.GetContext")
+	}
+
+	return count, nil
+}
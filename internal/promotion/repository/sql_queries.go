@@ -0,0 +1,47 @@
+package repository
+
+const (
+	createPromotionQuery = `
+		INSERT INTO promotions(name, description, code, kind, value, currency, product_id, min_subtotal, buy_quantity,
+			get_quantity, stackable, priority, usage_limit, per_customer_limit, active, starts_at, ends_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, now(), now())
+		RETURNING *
+	`
+
+	updatePromotionQuery = `
+		UPDATE promotions
+		SET name = $1, description = $2, code = $3, kind = $4, value = $5, currency = $6, product_id = $7,
+			min_subtotal = $8, buy_quantity = $9, get_quantity = $10, stackable = $11, priority = $12,
+			usage_limit = $13, per_customer_limit = $14, active = $15, starts_at = $16, ends_at = $17, updated_at = now()
+		WHERE promotion_id = $18
+		RETURNING *
+	`
+
+	deletePromotionQuery = `DELETE FROM promotions WHERE promotion_id = $1`
+
+	getPromotionByIdQuery = `SELECT * FROM promotions WHERE promotion_id = $1`
+
+	getPromotionByCodeQuery = `SELECT * FROM promotions WHERE code = $1`
+
+	getTotalPromotionsQuery = `SELECT COUNT(promotion_id) FROM promotions`
+
+	listPromotionsQuery = `
+		SELECT * FROM promotions
+		ORDER BY created_at DESC, promotion_id
+		OFFSET $1 LIMIT $2
+	`
+
+	listAutomaticPromotionsQuery = `
+		SELECT * FROM promotions
+		WHERE code IS NULL AND active
+		ORDER BY priority DESC, promotion_id
+	`
+
+	// Discounts of cancelled orders don't count, the usage was given back
+	countCustomerUsageQuery = `
+		SELECT COUNT(d.order_discount_id)
+		FROM order_discounts d
+		JOIN orders o ON o.order_id = d.order_id
+		WHERE d.promotion_id = $1 AND d.user_id = $2 AND o.status <> 'cancelled'
+	`
+)
@@ -0,0 +1,20 @@
+package promotion
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Promotion UseCase
+type UseCase interface {
+	Create(ctx context.Context, promotion *models.Promotion) (*models.Promotion, error)
+	Update(ctx context.Context, promotion *models.Promotion) (*models.Promotion, error)
+	Delete(ctx context.Context, promotionID uuid.UUID) error
+	GetByID(ctx context.Context, promotionID uuid.UUID) (*models.Promotion, error)
+	List(ctx context.Context, pq *utils.PaginationQuery) (*models.PromotionsList, error)
+	Evaluate(ctx context.Context, cart *models.Cart, codes []string) (*models.PromotionResult, error)
+	Preview(ctx context.Context, input *models.CheckoutInput) (*models.PromotionResult, error)
+}
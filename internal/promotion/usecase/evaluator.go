@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Promotion offered to a cart, code is empty for automatic promotions
+type offer struct {
+	promotion *models.Promotion
+	code      string
+}
+
+// Offer that passed every check with the discount it gives
+type candidate struct {
+	offer
+	amount int64
+	detail string
+}
+
+// Evaluate offers against the cart. Stackable promotions combine with each other, a promotion that isn't
+// stackable only applies alone and wins when its discount is larger than all stackable ones together.
+// Priority decides the order discounts are applied in, the total discount never exceeds the subtotal.
+// Usage holds the times the customer used promotions with a per customer limit.
+func evaluate(c *models.Cart, offers []offer, usage map[uuid.UUID]int, now time.Time) *models.PromotionResult {
+	result := &models.PromotionResult{
+		Discounts: make([]*models.OrderDiscount, 0),
+		Rejected:  make([]*models.RejectedPromotion, 0),
+	}
+
+	// Automatic promotions that don't apply are left out silently
+	reject := func(o offer, reason string) {
+		if o.code != "" {
+			result.Rejected = append(result.Rejected, &models.RejectedPromotion{Code: o.code, Reason: reason})
+		}
+	}
+
+	candidates := make([]*candidate, 0, len(offers))
+	for _, o := range offers {
+		amount, detail, reason := discountOf(o.promotion, c, usage, now)
+		if reason != "" {
+			reject(o, reason)
+			continue
+		}
+		candidates = append(candidates, &candidate{offer: o, amount: amount, detail: detail})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].promotion.Priority != candidates[j].promotion.Priority {
+			return candidates[i].promotion.Priority > candidates[j].promotion.Priority
+		}
+		return candidates[i].amount > candidates[j].amount
+	})
+
+	var exclusive *candidate
+	var stackedTotal int64
+	stacked := make([]*candidate, 0, len(candidates))
+	for _, cand := range candidates {
+		if cand.promotion.Stackable {
+			stacked = append(stacked, cand)
+			stackedTotal += cand.amount
+			continue
+		}
+		if exclusive == nil || cand.amount > exclusive.amount {
+			exclusive = cand
+		}
+	}
+
+	chosen := stacked
+	if exclusive != nil && (len(stacked) == 0 || exclusive.amount > stackedTotal) {
+		chosen = []*candidate{exclusive}
+		for _, cand := range candidates {
+			if cand != exclusive {
+				reject(cand.offer, fmt.Sprintf("can't be combined with %s", exclusive.promotion.Name))
+			}
+		}
+	} else {
+		for _, cand := range candidates {
+			if !cand.promotion.Stackable {
+				reject(cand.offer, "can't be combined with other promotions that give a larger discount")
+			}
+		}
+	}
+
+	remaining := c.Totals.Subtotal
+	for _, cand := range chosen {
+		amount := cand.amount
+		if amount > remaining {
+			amount = remaining
+		}
+		freeShipping := cand.promotion.Kind == models.PromotionKindFreeShipping
+		if amount == 0 && !freeShipping {
+			reject(cand.offer, "order is already fully discounted")
+			continue
+		}
+		remaining -= amount
+
+		promotionID := cand.promotion.PromotionID
+		discount := &models.OrderDiscount{
+			PromotionID:  &promotionID,
+			UserID:       c.UserID,
+			Description:  fmt.Sprintf("%s: %s", cand.promotion.Name, cand.detail),
+			Amount:       amount,
+			FreeShipping: freeShipping,
+		}
+		if cand.code != "" {
+			code := cand.code
+			discount.Code = &code
+		}
+
+		result.Discounts = append(result.Discounts, discount)
+		result.DiscountTotal += amount
+		result.FreeShipping = result.FreeShipping || freeShipping
+	}
+
+	return result
+}
+
+// Discount the promotion gives to the cart, or the reason it doesn't apply
+func discountOf(p *models.Promotion, c *models.Cart, usage map[uuid.UUID]int, now time.Time) (int64, string, string) {
+	switch {
+	case !p.Active:
+		return 0, "", "promotion is not active"
+	case p.StartsAt != nil && now.Before(*p.StartsAt):
+		return 0, "", "promotion has not started yet"
+	case p.EndsAt != nil && !now.Before(*p.EndsAt):
+		return 0, "", "promotion has ended"
+	case p.UsageLimit != nil && p.UsedCount >= *p.UsageLimit:
+		return 0, "", "promotion has been fully redeemed"
+	case p.Currency != c.Totals.Currency:
+		return 0, "", fmt.Sprintf("promotion only applies to %s carts", p.Currency)
+	case c.Totals.Subtotal < p.MinSubtotal:
+		return 0, "", fmt.Sprintf("spend at least %s to use this promotion", formatAmount(p.MinSubtotal, p.Currency))
+	}
+
+	if p.PerCustomerLimit != nil {
+		if c.UserID == nil {
+			return 0, "", "sign in to use this promotion"
+		}
+		if usage[p.PromotionID] >= *p.PerCustomerLimit {
+			return 0, "", "promotion was already used the maximum number of times"
+		}
+	}
+
+	// Unit prices of the lines the promotion covers
+	units := make([]int64, 0)
+	var eligibleSubtotal int64
+	for _, item := range c.Items {
+		if !item.Available || (p.ProductID != nil && item.ProductID != *p.ProductID) {
+			continue
+		}
+		for i := 0; i < item.Quantity; i++ {
+			units = append(units, item.UnitPrice)
+		}
+		eligibleSubtotal += item.LineTotal
+	}
+	if len(units) == 0 {
+		return 0, "", "no eligible items in cart"
+	}
+
+	var amount int64
+	var detail string
+	switch p.Kind {
+	case models.PromotionKindPercentage:
+		amount = eligibleSubtotal * p.Value / 100
+		detail = fmt.Sprintf("%d%% off", p.Value)
+	case models.PromotionKindFixed:
+		amount = p.Value
+		if amount > eligibleSubtotal {
+			amount = eligibleSubtotal
+		}
+		detail = fmt.Sprintf("%s off", formatAmount(p.Value, p.Currency))
+	case models.PromotionKindFreeShipping:
+		return 0, "free shipping", ""
+	case models.PromotionKindBuyXGetY:
+		group := p.BuyQuantity + p.GetQuantity
+		free := len(units) / group * p.GetQuantity
+		if free == 0 {
+			return 0, "", fmt.Sprintf("add %d more eligible items", group-len(units))
+		}
+
+		// Cheapest units go free
+		sort.Slice(units, func(i, j int) bool { return units[i] > units[j] })
+		for _, price := range units[len(units)-free:] {
+			amount += price
+		}
+		detail = fmt.Sprintf("buy %d get %d free", p.BuyQuantity, p.GetQuantity)
+	}
+
+	if amount == 0 {
+		return 0, "", "nothing to discount"
+	}
+
+	return amount, detail, ""
+}
+
+// Minor units as a decimal amount with currency
+func formatAmount(amount int64, currency string) string {
+	return fmt.Sprintf("%d.%02d %s", amount/100, amount%100, currency)
+}
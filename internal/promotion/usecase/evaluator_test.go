@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+var (
+	testNow      = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	testMugID    = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	testPosterID = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+)
+
+// Cart of 2 mugs at 10.00 and 1 poster at 5.00
+func testCart(userID *uuid.UUID) *models.Cart {
+	c := &models.Cart{
+		UserID: userID,
+		Items: []*models.CartItem{
+			{SKU: "MUG", ProductID: testMugID, UnitPrice: 1000, Quantity: 2, Currency: "USD", Available: true},
+			{SKU: "POSTER", ProductID: testPosterID, UnitPrice: 500, Quantity: 1, Currency: "USD", Available: true},
+		},
+	}
+	c.CalculateTotals()
+	return c
+}
+
+func testPromotion(name string, kind string, value int64, stackable bool, priority int) *models.Promotion {
+	return &models.Promotion{
+		PromotionID: uuid.NewSHA1(uuid.NameSpaceOID, []byte(name)),
+		Name:        name,
+		Kind:        kind,
+		Value:       value,
+		Currency:    "USD",
+		Stackable:   stackable,
+		Priority:    priority,
+		Active:      true,
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestDiscountOf(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		promotion  func(p *models.Promotion)
+		kind       string
+		value      int64
+		guest      bool
+		usage      int
+		wantAmount int64
+		wantReason string
+	}{
+		{name: "percentage", kind: models.PromotionKindPercentage, value: 10, wantAmount: 250},
+		{name: "percentage of one product", kind: models.PromotionKindPercentage, value: 50,
+			promotion: func(p *models.Promotion) { p.ProductID = &testMugID }, wantAmount: 1000},
+		{name: "fixed", kind: models.PromotionKindFixed, value: 700, wantAmount: 700},
+		{name: "fixed capped at eligible lines", kind: models.PromotionKindFixed, value: 900,
+			promotion: func(p *models.Promotion) { p.ProductID = &testPosterID }, wantAmount: 500},
+		{name: "free shipping", kind: models.PromotionKindFreeShipping},
+		{name: "buy 1 get 1 frees the cheapest unit", kind: models.PromotionKindBuyXGetY,
+			promotion: func(p *models.Promotion) { p.BuyQuantity, p.GetQuantity = 1, 1 }, wantAmount: 500},
+		{name: "buy 1 get 1 on one product", kind: models.PromotionKindBuyXGetY,
+			promotion: func(p *models.Promotion) { p.BuyQuantity, p.GetQuantity, p.ProductID = 1, 1, &testMugID }, wantAmount: 1000},
+		{name: "buy x get y needs more items", kind: models.PromotionKindBuyXGetY,
+			promotion: func(p *models.Promotion) { p.BuyQuantity, p.GetQuantity = 3, 1 }, wantReason: "add 1 more eligible items"},
+		{name: "inactive", kind: models.PromotionKindFixed, value: 100,
+			promotion: func(p *models.Promotion) { p.Active = false }, wantReason: "promotion is not active"},
+		{name: "not started", kind: models.PromotionKindFixed, value: 100,
+			promotion: func(p *models.Promotion) { p.StartsAt = timePtr(testNow.Add(time.Hour)) }, wantReason: "promotion has not started yet"},
+		{name: "ended", kind: models.PromotionKindFixed, value: 100,
+			promotion: func(p *models.Promotion) { p.EndsAt = timePtr(testNow) }, wantReason: "promotion has ended"},
+		{name: "fully redeemed", kind: models.PromotionKindFixed, value: 100,
+			promotion: func(p *models.Promotion) { p.UsageLimit, p.UsedCount = intPtr(5), 5 }, wantReason: "promotion has been fully redeemed"},
+		{name: "other currency", kind: models.PromotionKindFixed, value: 100,
+			promotion: func(p *models.Promotion) { p.Currency = "EUR" }, wantReason: "promotion only applies to EUR carts"},
+		{name: "below min subtotal", kind: models.PromotionKindFixed, value: 100,
+			promotion: func(p *models.Promotion) { p.MinSubtotal = 3000 }, wantReason: "spend at least 30.00 USD to use this promotion"},
+		{name: "no eligible items", kind: models.PromotionKindFixed, value: 100,
+			promotion: func(p *models.Promotion) { id := uuid.New(); p.ProductID = &id }, wantReason: "no eligible items in cart"},
+		{name: "per customer limit needs sign in", kind: models.PromotionKindFixed, value: 100, guest: true,
+			promotion: func(p *models.Promotion) { p.PerCustomerLimit = intPtr(1) }, wantReason: "sign in to use this promotion"},
+		{name: "per customer limit reached", kind: models.PromotionKindFixed, value: 100, usage: 2,
+			promotion: func(p *models.Promotion) { p.PerCustomerLimit = intPtr(2) }, wantReason: "promotion was already used the maximum number of times"},
+		{name: "per customer limit left", kind: models.PromotionKindFixed, value: 100, usage: 1,
+			promotion: func(p *models.Promotion) { p.PerCustomerLimit = intPtr(2) }, wantAmount: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPromotion(tt.name, tt.kind, tt.value, false, 0)
+			if tt.promotion != nil {
+				tt.promotion(p)
+			}
+			c := testCart(&userID)
+			if tt.guest {
+				c = testCart(nil)
+			}
+
+			amount, _, reason := discountOf(p, c, map[uuid.UUID]int{p.PromotionID: tt.usage}, testNow)
+			if amount != tt.wantAmount || reason != tt.wantReason {
+				t.Fatalf("discountOf() = %d, %q, want %d, %q", amount, reason, tt.wantAmount, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	userID := uuid.New()
+	tenPercent := testPromotion("Ten percent", models.PromotionKindPercentage, 10, true, 0)
+	fiveOff := testPromotion("Five off", models.PromotionKindFixed, 500, true, 0)
+	sevenOff := testPromotion("Seven off", models.PromotionKindFixed, 700, false, 0)
+	twelveOff := testPromotion("Twelve off", models.PromotionKindFixed, 1200, false, 0)
+	freeShipping := testPromotion("Free shipping", models.PromotionKindFreeShipping, 0, true, 0)
+	wholeCart := testPromotion("Whole cart", models.PromotionKindFixed, 2500, true, 10)
+	expired := testPromotion("Expired", models.PromotionKindFixed, 100, true, 0)
+	expired.EndsAt = timePtr(testNow.Add(-time.Hour))
+
+	tests := []struct {
+		name         string
+		offers       []offer
+		wantAmounts  []int64
+		wantTotal    int64
+		wantFree     bool
+		wantRejected []string
+	}{
+		{name: "no offers", wantAmounts: []int64{}, wantRejected: []string{}},
+		{
+			name:         "stackable promotions combine",
+			offers:       []offer{{promotion: tenPercent}, {promotion: fiveOff, code: "FIVE"}},
+			wantAmounts:  []int64{500, 250},
+			wantTotal:    750,
+			wantRejected: []string{},
+		},
+		{
+			name:         "stacked total beats smaller exclusive",
+			offers:       []offer{{promotion: tenPercent}, {promotion: fiveOff}, {promotion: sevenOff, code: "SEVEN"}},
+			wantAmounts:  []int64{500, 250},
+			wantTotal:    750,
+			wantRejected: []string{"SEVEN"},
+		},
+		{
+			name:         "larger exclusive wins alone",
+			offers:       []offer{{promotion: tenPercent, code: "TEN"}, {promotion: fiveOff}, {promotion: twelveOff}},
+			wantAmounts:  []int64{1200},
+			wantTotal:    1200,
+			wantRejected: []string{"TEN"},
+		},
+		{
+			name:         "only the largest exclusive applies",
+			offers:       []offer{{promotion: sevenOff, code: "SEVEN"}, {promotion: twelveOff, code: "TWELVE"}},
+			wantAmounts:  []int64{1200},
+			wantTotal:    1200,
+			wantRejected: []string{"SEVEN"},
+		},
+		{
+			name:         "free shipping stacks",
+			offers:       []offer{{promotion: freeShipping}, {promotion: fiveOff}},
+			wantAmounts:  []int64{500, 0},
+			wantTotal:    500,
+			wantFree:     true,
+			wantRejected: []string{},
+		},
+		{
+			name:         "total never exceeds subtotal",
+			offers:       []offer{{promotion: tenPercent, code: "TEN"}, {promotion: wholeCart}},
+			wantAmounts:  []int64{2500},
+			wantTotal:    2500,
+			wantRejected: []string{"TEN"},
+		},
+		{
+			name:         "automatic promotions are not reported as rejected",
+			offers:       []offer{{promotion: expired}, {promotion: expired, code: "OLD"}},
+			wantAmounts:  []int64{},
+			wantRejected: []string{"OLD"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := evaluate(testCart(&userID), tt.offers, map[uuid.UUID]int{}, testNow)
+
+			amounts := make([]int64, 0, len(result.Discounts))
+			for _, d := range result.Discounts {
+				amounts = append(amounts, d.Amount)
+			}
+			rejected := make([]string, 0, len(result.Rejected))
+			for _, r := range result.Rejected {
+				rejected = append(rejected, r.Code)
+			}
+
+			if !reflect.DeepEqual(amounts, tt.wantAmounts) {
+				t.Fatalf("discount amounts = %v, want %v", amounts, tt.wantAmounts)
+			}
+			if result.DiscountTotal != tt.wantTotal || result.FreeShipping != tt.wantFree {
+				t.Fatalf("total = %d free shipping = %v, want %d %v", result.DiscountTotal, result.FreeShipping, tt.wantTotal, tt.wantFree)
+			}
+			if !reflect.DeepEqual(rejected, tt.wantRejected) {
+				t.Fatalf("rejected codes = %v, want %v", rejected, tt.wantRejected)
+			}
+		})
+	}
+}
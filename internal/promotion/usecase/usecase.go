@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/cart"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/promotion"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Promotion UseCase
+type promotionUC struct {
+	cfg           *config.Config
+	logger        logger.Logger
+	promotionRepo promotion.Repository
+	cartUC        cart.UseCase
+}
+
+// Promotion UseCase constructor
+func NewPromotionUseCase(cfg *config.Config, logger logger.Logger, promotionRepo promotion.Repository, cartUC cart.UseCase) promotion.UseCase {
+	return &promotionUC{
+		cfg:           cfg,
+		logger:        logger,
+		promotionRepo: promotionRepo,
+		cartUC:        cartUC,
+	}
+}
+
+func (u *promotionUC) Create(ctx context.Context, p *models.Promotion) (*models.Promotion, error) {
+	// TODO: Tracing
+
+	p.Prepare()
+	if err := validatePromotion(p); err != nil {
+		return nil, err
+	}
+
+	return u.promotionRepo.Create(ctx, p)
+}
+
+// Replace promotion settings, the usage count is kept
+func (u *promotionUC) Update(ctx context.Context, p *models.Promotion) (*models.Promotion, error) {
+	// TODO: Tracing
+
+	p.Prepare()
+	if err := validatePromotion(p); err != nil {
+		return nil, err
+	}
+
+	return u.promotionRepo.Update(ctx, p)
+}
+
+func (u *promotionUC) Delete(ctx context.Context, promotionID uuid.UUID) error {
+	// TODO: Tracing
+
+	return u.promotionRepo.Delete(ctx, promotionID)
+}
+
+func (u *promotionUC) GetByID(ctx context.Context, promotionID uuid.UUID) (*models.Promotion, error) {
+	// TODO: Tracing
+
+	return u.promotionRepo.GetByID(ctx, promotionID)
+}
+
+func (u *promotionUC) List(ctx context.Context, pq *utils.PaginationQuery) (*models.PromotionsList, error) {
+	// TODO: Tracing
+
+	return u.promotionRepo.List(ctx, pq)
+}
+
+// Evaluate automatic promotions and entered codes against the cart snapshot
+func (u *promotionUC) Evaluate(ctx context.Context, c *models.Cart, codes []string) (*models.PromotionResult, error) {
+	// TODO: Tracing
+
+	if c.Totals == nil {
+		c.CalculateTotals()
+	}
+
+	automatic, err := u.promotionRepo.ListAutomatic(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	offers := make([]offer, 0, len(automatic)+len(codes))
+	for _, p := range automatic {
+		offers = append(offers, offer{promotion: p})
+	}
+
+	rejected := make([]*models.RejectedPromotion, 0)
+	seen := make(map[string]bool, len(codes))
+	for _, raw := range codes {
+		code := models.NormalizePromoCode(raw)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		p, err := u.promotionRepo.GetByCode(ctx, code)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				rejected = append(rejected, &models.RejectedPromotion{Code: code, Reason: "unknown promo code"})
+				continue
+			}
+			return nil, err
+		}
+		offers = append(offers, offer{promotion: p, code: code})
+	}
+
+	usage := make(map[uuid.UUID]int)
+	if c.UserID != nil {
+		for _, o := range offers {
+			if o.promotion.PerCustomerLimit == nil {
+				continue
+			}
+			usage[o.promotion.PromotionID], err = u.promotionRepo.CountCustomerUsage(ctx, o.promotion.PromotionID, *c.UserID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result := evaluate(c, offers, usage, time.Now())
+	result.Rejected = append(rejected, result.Rejected...)
+
+	return result, nil
+}
+
+// Discounts the cart of the user in context would get at checkout
+func (u *promotionUC) Preview(ctx context.Context, input *models.CheckoutInput) (*models.PromotionResult, error) {
+	// TODO: Tracing
+
+	c, err := u.cartUC.Get(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return u.Evaluate(ctx, c, input.PromoCodes)
+}
+
+// Check settings the kind of promotion depends on
+func validatePromotion(p *models.Promotion) error {
+	switch p.Kind {
+	case models.PromotionKindPercentage:
+		if p.Value < 1 || p.Value > 100 {
+			return httpErrors.NewBadRequestError(errors.New("promotionUC.validatePromotion: percentage value must be between 1 and 100"))
+		}
+	case models.PromotionKindFixed:
+		if p.Value == 0 {
+			return httpErrors.NewBadRequestError(errors.New("promotionUC.validatePromotion: fixed value must be positive"))
+		}
+	case models.PromotionKindFreeShipping:
+		p.Value = 0
+	case models.PromotionKindBuyXGetY:
+		if p.BuyQuantity == 0 || p.GetQuantity == 0 {
+			return httpErrors.NewBadRequestError(errors.New("promotionUC.validatePromotion: buy_quantity and get_quantity must be positive"))
+		}
+	}
+
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return httpErrors.NewBadRequestError(errors.New("promotionUC.validatePromotion: ends_at must be after starts_at"))
+	}
+
+	return nil
+}
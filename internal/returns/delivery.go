@@ -0,0 +1,17 @@
+package returns
+
+import "github.com/labstack/echo/v4"
+
+// Return HTTP Handlers interface
+type Handlers interface {
+	Create() echo.HandlerFunc
+	GetByID() echo.HandlerFunc
+	ListMine() echo.HandlerFunc
+	List() echo.HandlerFunc
+	Cancel() echo.HandlerFunc
+	Approve() echo.HandlerFunc
+	Reject() echo.HandlerFunc
+	Receive() echo.HandlerFunc
+	Refund() echo.HandlerFunc
+	Close() echo.HandlerFunc
+}
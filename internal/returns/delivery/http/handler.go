@@ -0,0 +1,348 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/returns"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Return handlers
+type returnHandlers struct {
+	cfg      *config.Config
+	logger   logger.Logger
+	returnUC returns.UseCase
+}
+
+// Return handlers constructor
+func NewReturnHandlers(cfg *config.Config, logger logger.Logger, returnUC returns.UseCase) returns.Handlers {
+	return &returnHandlers{
+		cfg:      cfg,
+		logger:   logger,
+		returnUC: returnUC,
+	}
+}
+
+// Create godoc
+// @Summary Request return
+// @Description request return of items of own delivered order, with a reason and quantity per item
+// @Tags Returns
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Return
+// @Failure 400 {object} httpErrors.RestError
+// @Router /returns [post]
+func (h *returnHandlers) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		input := &models.ReturnInput{}
+		if err := utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		ret, err := h.returnUC.Create(ctx, input)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, ret)
+	}
+}
+
+// GetByID godoc
+// @Summary Get return by id
+// @Description get own return with items and history, admins can get any return
+// @Tags Returns
+// @Produce json
+// @Param return_id path string true "return_id"
+// @Success 200 {object} models.Return
+// @Failure 404 {object} httpErrors.RestError
+// @Router /returns/{return_id} [get]
+func (h *returnHandlers) GetByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		returnID, err := uuid.Parse(c.Param("return_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		ret, err := h.returnUC.GetByID(ctx, returnID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, ret)
+	}
+}
+
+// ListMine godoc
+// @Summary Get my returns
+// @Description get own returns, newest first
+// @Tags Returns
+// @Produce json
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.ReturnsList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /returns/me [get]
+func (h *returnHandlers) ListMine() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		returnsList, err := h.returnUC.ListMine(ctx, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, returnsList)
+	}
+}
+
+// List godoc
+// @Summary Get returns
+// @Description get returns of every user, optionally by status, admin only
+// @Tags Returns
+// @Produce json
+// @Param status query string false "return status"
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.ReturnsList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /returns [get]
+func (h *returnHandlers) List() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		query := &models.ReturnsQuery{}
+		if err := utils.ReadRequest(c, query); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		returnsList, err := h.returnUC.List(ctx, query, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, returnsList)
+	}
+}
+
+// Cancel godoc
+// @Summary Cancel return
+// @Description cancel own return until its parcel is received
+// @Tags Returns
+// @Produce json
+// @Param return_id path string true "return_id"
+// @Success 200 {object} models.Return
+// @Failure 409 {object} httpErrors.RestError
+// @Router /returns/{return_id}/cancel [post]
+func (h *returnHandlers) Cancel() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		returnID, err := uuid.Parse(c.Param("return_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		ret, err := h.returnUC.Cancel(ctx, returnID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, ret)
+	}
+}
+
+// Approve godoc
+// @Summary Approve return
+// @Description approve requested return with the return shipping label, admin only
+// @Tags Returns
+// @Accept json
+// @Produce json
+// @Param return_id path string true "return_id"
+// @Success 200 {object} models.Return
+// @Failure 409 {object} httpErrors.RestError
+// @Router /returns/{return_id}/approve [post]
+func (h *returnHandlers) Approve() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		returnID, err := uuid.Parse(c.Param("return_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		approval := &models.ReturnApproval{}
+		if err = utils.ReadRequest(c, approval); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		ret, err := h.returnUC.Approve(ctx, returnID, approval)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, ret)
+	}
+}
+
+// Reject godoc
+// @Summary Reject return
+// @Description reject requested return, admin only
+// @Tags Returns
+// @Accept json
+// @Produce json
+// @Param return_id path string true "return_id"
+// @Success 200 {object} models.Return
+// @Failure 409 {object} httpErrors.RestError
+// @Router /returns/{return_id}/reject [post]
+func (h *returnHandlers) Reject() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		returnID, err := uuid.Parse(c.Param("return_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		decision := &models.ReturnDecision{}
+		if err = utils.ReadRequest(c, decision); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		ret, err := h.returnUC.Reject(ctx, returnID, decision)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, ret)
+	}
+}
+
+// Receive godoc
+// @Summary Receive return
+// @Description record received and restocked quantity of every item of an approved return, restocked units go back to stock, admin only
+// @Tags Returns
+// @Accept json
+// @Produce json
+// @Param return_id path string true "return_id"
+// @Success 200 {object} models.Return
+// @Failure 409 {object} httpErrors.RestError
+// @Router /returns/{return_id}/receive [post]
+func (h *returnHandlers) Receive() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		returnID, err := uuid.Parse(c.Param("return_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		receipt := &models.ReturnReceipt{}
+		if err = utils.ReadRequest(c, receipt); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		ret, err := h.returnUC.Receive(ctx, returnID, receipt)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, ret)
+	}
+}
+
+// Refund godoc
+// @Summary Refund return
+// @Description refund received return through the payment provider or to store credit, without amount the paid price of the received units is refunded, a failed refund is retried for what is still owed, admin only
+// @Tags Returns
+// @Accept json
+// @Produce json
+// @Param return_id path string true "return_id"
+// @Success 200 {object} models.Return
+// @Failure 409 {object} httpErrors.RestError
+// @Router /returns/{return_id}/refund [post]
+func (h *returnHandlers) Refund() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		returnID, err := uuid.Parse(c.Param("return_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		refund := &models.ReturnRefund{}
+		if err = utils.ReadRequest(c, refund); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		ret, err := h.returnUC.Refund(ctx, returnID, refund)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, ret)
+	}
+}
+
+// Close godoc
+// @Summary Close return
+// @Description close received return without refund, admin only
+// @Tags Returns
+// @Accept json
+// @Produce json
+// @Param return_id path string true "return_id"
+// @Success 200 {object} models.Return
+// @Failure 409 {object} httpErrors.RestError
+// @Router /returns/{return_id}/close [post]
+func (h *returnHandlers) Close() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		returnID, err := uuid.Parse(c.Param("return_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		decision := &models.ReturnDecision{}
+		if err = utils.ReadRequest(c, decision); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		ret, err := h.returnUC.Close(ctx, returnID, decision)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, ret)
+	}
+}
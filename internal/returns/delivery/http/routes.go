@@ -0,0 +1,23 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/returns"
+	"github.com/labstack/echo/v4"
+)
+
+func MapReturnRoutes(returnGroup *echo.Group, h returns.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	returnGroup.POST("", h.Create(), mw.AuthJWTMiddleware)
+	returnGroup.GET("", h.List(), adminOnly...)
+	returnGroup.GET("/me", h.ListMine(), mw.AuthJWTMiddleware)
+	returnGroup.GET("/:return_id", h.GetByID(), mw.AuthJWTMiddleware)
+	returnGroup.POST("/:return_id/cancel", h.Cancel(), mw.AuthJWTMiddleware)
+	returnGroup.POST("/:return_id/approve", h.Approve(), adminOnly...)
+	returnGroup.POST("/:return_id/reject", h.Reject(), adminOnly...)
+	returnGroup.POST("/:return_id/receive", h.Receive(), adminOnly...)
+	returnGroup.POST("/:return_id/refund", h.Refund(), adminOnly...)
+	returnGroup.POST("/:return_id/close", h.Close(), adminOnly...)
+}
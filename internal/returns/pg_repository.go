@@ -0,0 +1,20 @@
+package returns
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Return repository
+type Repository interface {
+	Create(ctx context.Context, orderID uuid.UUID, build func(returned map[uuid.UUID]int) (*models.Return, error)) (*models.Return, error)
+	GetByID(ctx context.Context, returnID uuid.UUID) (*models.Return, error)
+	GetItems(ctx context.Context, returnID uuid.UUID) ([]*models.ReturnItem, error)
+	GetEvents(ctx context.Context, returnID uuid.UUID) ([]*models.ReturnEvent, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, pq *utils.PaginationQuery) (*models.ReturnsList, error)
+	List(ctx context.Context, status string, pq *utils.PaginationQuery) (*models.ReturnsList, error)
+	UpdateStatus(ctx context.Context, r *models.Return, from string, event *models.ReturnEvent) (*models.Return, error)
+}
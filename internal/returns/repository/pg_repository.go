@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/returns"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Return repository
+type returnRepo struct {
+	db *sqlx.DB
+}
+
+// Return repository constructor
+func NewReturnRepository(db *sqlx.DB) returns.Repository {
+	return &returnRepo{db: db}
+}
+
+// Returned quantity of an order line
+type returnedQuantity struct {
+	OrderItemID uuid.UUID `db:"order_item_id"`
+	Quantity    int       `db:"quantity"`
+}
+
+// Create return while its order is locked, build gets the quantities per order line already taken by returns
+// that were not rejected or cancelled so the same unit can't be returned twice
+func (r *returnRepo) Create(ctx context.Context, orderID uuid.UUID, build func(returned map[uuid.UUID]int) (*models.Return, error)) (*models.Return, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "returnRepo.Create.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	var lockedID uuid.UUID
+	if err = tx.GetContext(ctx, &lockedID, lockOrderQuery, orderID); err != nil {
+		return nil, errors.Wrap(err, "returnRepo.Create.GetContext.lockOrder")
+	}
+
+	quantities := make([]*returnedQuantity, 0)
+	if err = tx.SelectContext(ctx, &quantities, getReturnedQuantitiesQuery, orderID); err != nil {
+		return nil, errors.Wrap(err, "returnRepo.Create.SelectContext.returnedQuantities")
+	}
+
+	returned := make(map[uuid.UUID]int, len(quantities))
+	for _, q := range quantities {
+		returned[q.OrderItemID] = q.Quantity
+	}
+
+	ret, err := build(returned)
+	if err != nil {
+		return nil, err
+	}
+
+	created := &models.Return{}
+	if err = tx.QueryRowxContext(
+		ctx,
+		createReturnQuery,
+		&ret.OrderID,
+		ret.UserID,
+		&ret.Status,
+		&ret.Note,
+		&ret.Currency,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "returnRepo.Create.StructScan.return")
+	}
+
+	created.Items = make([]*models.ReturnItem, 0, len(ret.Items))
+	for _, item := range ret.Items {
+		createdItem := &models.ReturnItem{}
+		if err = tx.QueryRowxContext(
+			ctx,
+			createReturnItemQuery,
+			created.ReturnID,
+			&item.OrderItemID,
+			&item.SKU,
+			&item.Quantity,
+			&item.Reason,
+			&item.Note,
+		).StructScan(createdItem); err != nil {
+			return nil, errors.Wrap(err, "returnRepo.Create.StructScan.item")
+		}
+		created.Items = append(created.Items, createdItem)
+	}
+
+	if _, err = tx.ExecContext(ctx, createReturnEventQuery, created.ReturnID, nil, &created.Status, "return requested", ret.UserID); err != nil {
+		return nil, errors.Wrap(err, "returnRepo.Create.ExecContext.event")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "returnRepo.Create.Commit")
+	}
+
+	return created, nil
+}
+
+func (r *returnRepo) GetByID(ctx context.Context, returnID uuid.UUID) (*models.Return, error) {
+	// TODO: Tracing
+
+	ret := &models.Return{}
+	if err := r.db.GetContext(ctx, ret, getReturnByIdQuery, returnID); err != nil {
+		return nil, errors.Wrap(err, "returnRepo.GetByID.GetContext")
+	}
+
+	return ret, nil
+}
+
+func (r *returnRepo) GetItems(ctx context.Context, returnID uuid.UUID) ([]*models.ReturnItem, error) {
+	// TODO: Tracing
+
+	items := make([]*models.ReturnItem, 0)
+	if err := r.db.SelectContext(ctx, &items, getReturnItemsQuery, returnID); err != nil {
+		return nil, errors.Wrap(err, "returnRepo.GetItems.SelectContext")
+	}
+
+	return items, nil
+}
+
+func (r *returnRepo) GetEvents(ctx context.Context, returnID uuid.UUID) ([]*models.ReturnEvent, error) {
+	// TODO: Tracing
+
+	events := make([]*models.ReturnEvent, 0)
+	if err := r.db.SelectContext(ctx, &events, getReturnEventsQuery, returnID); err != nil {
+		return nil, errors.Wrap(err, "returnRepo.GetEvents.SelectContext")
+	}
+
+	return events, nil
+}
+
+func (r *returnRepo) ListByUser(ctx context.Context, userID uuid.UUID, pq *utils.PaginationQuery) (*models.ReturnsList, error) {
+	// TODO: Tracing
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalReturnsByUserQuery, userID); err != nil {
+		return nil, errors.Wrap(err, "returnRepo.ListByUser.GetContext.totalCount")
+	}
+
+	list := make([]*models.Return, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &list, listReturnsByUserQuery, userID, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "returnRepo.ListByUser.SelectContext")
+		}
+	}
+
+	return newReturnsList(list, totalCount, pq), nil
+}
+
+func (r *returnRepo) List(ctx context.Context, status string, pq *utils.PaginationQuery) (*models.ReturnsList, error) {
+	// TODO: Tracing
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalReturnsQuery, status); err != nil {
+		return nil, errors.Wrap(err, "returnRepo.List.GetContext.totalCount")
+	}
+
+	list := make([]*models.Return, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &list, listReturnsQuery, status, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "returnRepo.List.SelectContext")
+		}
+	}
+
+	return newReturnsList(list, totalCount, pq), nil
+}
+
+// Move return to event.ToStatus only if it still has status from, saving its label and refund amount
+// and, when given, the inspection and refund of its items. Received returns restock their restocked units.
+// The event and stock movements are recorded in the same transaction.
+func (r *returnRepo) UpdateStatus(ctx context.Context, ret *models.Return, from string, event *models.ReturnEvent) (*models.Return, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "returnRepo.UpdateStatus.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	updated := &models.Return{}
+	if err = tx.GetContext(
+		ctx,
+		updated,
+		updateReturnStatusQuery,
+		&event.ToStatus,
+		ret.LabelCarrier,
+		ret.LabelTracking,
+		ret.LabelURL,
+		&ret.RefundAmount,
+		&ret.RefundMethod,
+		&ret.RefundedAmount,
+		&ret.ReturnID,
+		from,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "returnRepo.UpdateStatus: return is no longer %s", from)
+		}
+		return nil, errors.Wrap(err, "returnRepo.UpdateStatus.GetContext")
+	}
+
+	if ret.Items != nil {
+		updated.Items = make([]*models.ReturnItem, 0, len(ret.Items))
+		for _, item := range ret.Items {
+			updatedItem := &models.ReturnItem{}
+			if err = tx.GetContext(
+				ctx,
+				updatedItem,
+				updateReturnItemQuery,
+				&item.ReceivedQuantity,
+				&item.RestockedQuantity,
+				&item.InspectionNote,
+				&item.RefundAmount,
+				&item.ReturnItemID,
+				&ret.ReturnID,
+			); err != nil {
+				return nil, errors.Wrap(err, "returnRepo.UpdateStatus.GetContext.item")
+			}
+			updated.Items = append(updated.Items, updatedItem)
+		}
+	}
+
+	if event.ToStatus == models.ReturnStatusReceived {
+		if _, err = tx.ExecContext(ctx, ensureRestockLevelsQuery, &ret.ReturnID); err != nil {
+			return nil, errors.Wrap(err, "returnRepo.UpdateStatus.ExecContext.ensureRestockLevels")
+		}
+		if _, err = tx.ExecContext(ctx, restockReturnQuery, &ret.ReturnID, event.ActorID); err != nil {
+			return nil, errors.Wrap(err, "returnRepo.UpdateStatus.ExecContext.restockReturn")
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, createReturnEventQuery, &ret.ReturnID, from, &event.ToStatus, &event.Note, event.ActorID); err != nil {
+		return nil, errors.Wrap(err, "returnRepo.UpdateStatus.ExecContext.event")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "returnRepo.UpdateStatus.Commit")
+	}
+
+	return updated, nil
+}
+
+func newReturnsList(list []*models.Return, totalCount int, pq *utils.PaginationQuery) *models.ReturnsList {
+	return &models.ReturnsList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Returns:    list,
+	}
+}
@@ -0,0 +1,94 @@
+package repository
+
+const (
+	// Serializes return requests of the same order
+	lockOrderQuery = `SELECT order_id FROM orders WHERE order_id = $1 FOR UPDATE`
+
+	getReturnedQuantitiesQuery = `
+		SELECT i.order_item_id, SUM(i.quantity) AS quantity
+		FROM return_items i
+		JOIN returns r ON r.return_id = i.return_id
+		WHERE r.order_id = $1 AND r.status NOT IN ('rejected', 'cancelled')
+		GROUP BY i.order_item_id
+	`
+
+	createReturnQuery = `
+		INSERT INTO returns(order_id, user_id, status, note, currency, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, now(), now())
+		RETURNING *
+	`
+
+	createReturnItemQuery = `
+		INSERT INTO return_items(return_id, order_item_id, sku, quantity, reason, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING *
+	`
+
+	createReturnEventQuery = `
+		INSERT INTO return_events(return_id, from_status, to_status, note, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, now())
+	`
+
+	getReturnByIdQuery = `SELECT * FROM returns WHERE return_id = $1`
+
+	getReturnItemsQuery = `SELECT * FROM return_items WHERE return_id = $1 ORDER BY sku, return_item_id`
+
+	getReturnEventsQuery = `SELECT * FROM return_events WHERE return_id = $1 ORDER BY created_at, event_id`
+
+	getTotalReturnsByUserQuery = `SELECT COUNT(return_id) FROM returns WHERE user_id = $1`
+
+	listReturnsByUserQuery = `
+		SELECT * FROM returns
+		WHERE user_id = $1
+		ORDER BY created_at DESC, return_id
+		OFFSET $2 LIMIT $3
+	`
+
+	getTotalReturnsQuery = `SELECT COUNT(return_id) FROM returns WHERE ($1 = '' OR status = $1)`
+
+	listReturnsQuery = `
+		SELECT * FROM returns
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC, return_id
+		OFFSET $2 LIMIT $3
+	`
+
+	updateReturnStatusQuery = `
+		UPDATE returns
+		SET status = $1, label_carrier = $2, label_tracking = $3, label_url = $4, refund_amount = $5, refund_method = $6,
+			refunded_amount = $7, updated_at = now()
+		WHERE return_id = $8 AND status = $9
+		RETURNING *
+	`
+
+	updateReturnItemQuery = `
+		UPDATE return_items
+		SET received_quantity = $1, restocked_quantity = $2, inspection_note = $3, refund_amount = $4
+		WHERE return_item_id = $5 AND return_id = $6
+		RETURNING *
+	`
+
+	ensureRestockLevelsQuery = `
+		INSERT INTO stock_levels(sku)
+		SELECT DISTINCT sku FROM return_items WHERE return_id = $1 AND restocked_quantity > 0
+		ON CONFLICT (sku) DO NOTHING
+	`
+
+	// Received returns put their restocked units back into stock, recorded in the stock ledger
+	restockReturnQuery = `
+		WITH restocked AS (
+			SELECT sku, SUM(restocked_quantity) AS quantity
+			FROM return_items
+			WHERE return_id = $1 AND restocked_quantity > 0
+			GROUP BY sku
+		), applied AS (
+			UPDATE stock_levels s
+			SET on_hand = s.on_hand + r.quantity, updated_at = now()
+			FROM restocked r
+			WHERE s.sku = r.sku
+			RETURNING s.sku, r.quantity, s.on_hand
+		)
+		INSERT INTO stock_movements(sku, movement_type, quantity, on_hand_after, reason, actor_user_id, reference_id, created_at)
+		SELECT sku, 'return', quantity, on_hand, 'return ' || $1::text, $2::uuid, $1, now() FROM applied
+	`
+)
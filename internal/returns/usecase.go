@@ -0,0 +1,23 @@
+package returns
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Return UseCase, customers request and cancel returns of their own orders, admins handle the rest
+type UseCase interface {
+	Create(ctx context.Context, input *models.ReturnInput) (*models.Return, error)
+	GetByID(ctx context.Context, returnID uuid.UUID) (*models.Return, error)
+	ListMine(ctx context.Context, pq *utils.PaginationQuery) (*models.ReturnsList, error)
+	List(ctx context.Context, query *models.ReturnsQuery, pq *utils.PaginationQuery) (*models.ReturnsList, error)
+	Cancel(ctx context.Context, returnID uuid.UUID) (*models.Return, error)
+	Approve(ctx context.Context, returnID uuid.UUID, approval *models.ReturnApproval) (*models.Return, error)
+	Reject(ctx context.Context, returnID uuid.UUID, decision *models.ReturnDecision) (*models.Return, error)
+	Receive(ctx context.Context, returnID uuid.UUID, receipt *models.ReturnReceipt) (*models.Return, error)
+	Refund(ctx context.Context, returnID uuid.UUID, refund *models.ReturnRefund) (*models.Return, error)
+	Close(ctx context.Context, returnID uuid.UUID, decision *models.ReturnDecision) (*models.Return, error)
+}
@@ -0,0 +1,565 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/inventory"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/order"
+	"github.com/fekuna/go-store/internal/payment"
+	"github.com/fekuna/go-store/internal/returns"
+	"github.com/fekuna/go-store/internal/storecredit"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Return UseCase
+type returnUC struct {
+	cfg         *config.Config
+	logger      logger.Logger
+	returnRepo  returns.Repository
+	orderRepo   order.Repository
+	orderUC     order.UseCase
+	inventoryUC inventory.UseCase
+	refunder    payment.Refunder
+	creditUC    storecredit.UseCase
+}
+
+// Return UseCase constructor
+func NewReturnUseCase(cfg *config.Config, logger logger.Logger, returnRepo returns.Repository, orderRepo order.Repository, orderUC order.UseCase, inventoryUC inventory.UseCase, refunder payment.Refunder, creditUC storecredit.UseCase) returns.UseCase {
+	return &returnUC{
+		cfg:         cfg,
+		logger:      logger,
+		returnRepo:  returnRepo,
+		orderRepo:   orderRepo,
+		orderUC:     orderUC,
+		inventoryUC: inventoryUC,
+		refunder:    refunder,
+		creditUC:    creditUC,
+	}
+}
+
+// Request return of delivered lines of own order within the return window
+func (u *returnUC) Create(ctx context.Context, input *models.ReturnInput) (*models.Return, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := u.orderUC.GetByID(ctx, input.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	// Admins see every order but returns are requested by its customer
+	if o.UserID == nil || *o.UserID != user.UserID {
+		return nil, httpErrors.NewForbiddenError(errors.New("returnUC.Create: order belongs to another user"))
+	}
+	if o.Status != models.OrderStatusDelivered {
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "returnUC.Create: order is %s", o.Status)
+	}
+	if err = u.checkWindow(ctx, o); err != nil {
+		return nil, err
+	}
+
+	orderItems, err := u.orderRepo.GetItems(ctx, o.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*models.OrderItem, len(orderItems))
+	for _, item := range orderItems {
+		byID[item.OrderItemID] = item
+	}
+
+	input.Prepare()
+
+	return u.returnRepo.Create(ctx, o.OrderID, func(returned map[uuid.UUID]int) (*models.Return, error) {
+		ret := &models.Return{
+			OrderID:  o.OrderID,
+			UserID:   &user.UserID,
+			Status:   models.ReturnStatusRequested,
+			Note:     input.Note,
+			Currency: o.Currency,
+			Items:    make([]*models.ReturnItem, 0, len(input.Items)),
+		}
+
+		seen := make(map[uuid.UUID]bool, len(input.Items))
+		for _, in := range input.Items {
+			item, ok := byID[in.OrderItemID]
+			if !ok {
+				return nil, httpErrors.NewBadRequestError(errors.Errorf("returnUC.Create: order item %s is not in the order", in.OrderItemID))
+			}
+			if seen[in.OrderItemID] {
+				return nil, httpErrors.NewBadRequestError(errors.Errorf("returnUC.Create: order item %s listed twice", in.OrderItemID))
+			}
+			seen[in.OrderItemID] = true
+
+			if left := item.Quantity - returned[item.OrderItemID]; in.Quantity > left {
+				return nil, httpErrors.NewRestErrorWithMessage(
+					http.StatusBadRequest,
+					fmt.Sprintf("Only %d of %s can be returned", left, item.SKU),
+					errors.New("returnUC.Create: quantity exceeds returnable quantity"),
+				)
+			}
+
+			ret.Items = append(ret.Items, &models.ReturnItem{
+				OrderItemID: item.OrderItemID,
+				SKU:         item.SKU,
+				Quantity:    in.Quantity,
+				Reason:      in.Reason,
+				Note:        in.Note,
+			})
+		}
+
+		return ret, nil
+	})
+}
+
+// Get return with items and history, customers only see their own returns
+func (u *returnUC) GetByID(ctx context.Context, returnID uuid.UUID) (*models.Return, error) {
+	// TODO: Tracing
+
+	ret, err := u.getVisible(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := u.returnRepo.GetItems(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := u.returnRepo.GetEvents(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Items = items
+	ret.Events = events
+
+	return ret, nil
+}
+
+// Returns of the user in context, newest first
+func (u *returnUC) ListMine(ctx context.Context, pq *utils.PaginationQuery) (*models.ReturnsList, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.returnRepo.ListByUser(ctx, user.UserID, pq)
+}
+
+// Returns of every user, optionally by status
+func (u *returnUC) List(ctx context.Context, query *models.ReturnsQuery, pq *utils.PaginationQuery) (*models.ReturnsList, error) {
+	// TODO: Tracing
+
+	return u.returnRepo.List(ctx, query.Status, pq)
+}
+
+// Cancel own return until its parcel is received
+func (u *returnUC) Cancel(ctx context.Context, returnID uuid.UUID) (*models.Return, error) {
+	// TODO: Tracing
+
+	ret, err := u.getVisible(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.changeStatus(ctx, ret, models.ReturnStatusCancelled, "cancelled by customer")
+}
+
+// Approve requested return with its return shipping label, admin only
+func (u *returnUC) Approve(ctx context.Context, returnID uuid.UUID, approval *models.ReturnApproval) (*models.Return, error) {
+	// TODO: Tracing
+
+	ret, err := u.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	approval.Prepare()
+	ret.LabelCarrier = approval.LabelCarrier
+	ret.LabelTracking = approval.LabelTracking
+	ret.LabelURL = approval.LabelURL
+
+	return u.changeStatus(ctx, ret, models.ReturnStatusApproved, approval.Note)
+}
+
+// Reject requested return, admin only
+func (u *returnUC) Reject(ctx context.Context, returnID uuid.UUID, decision *models.ReturnDecision) (*models.Return, error) {
+	// TODO: Tracing
+
+	ret, err := u.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	decision.Prepare()
+
+	return u.changeStatus(ctx, ret, models.ReturnStatusRejected, decision.Note)
+}
+
+// Record inspection of the received parcel and put restocked units back into stock, admin only
+func (u *returnUC) Receive(ctx context.Context, returnID uuid.UUID, receipt *models.ReturnReceipt) (*models.Return, error) {
+	// TODO: Tracing
+
+	ret, err := u.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+	if !models.CanTransitionReturn(ret.Status, models.ReturnStatusReceived) {
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "returnUC.Receive: return is %s", ret.Status)
+	}
+
+	items, err := u.returnRepo.GetItems(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*models.ReturnItem, len(items))
+	for _, item := range items {
+		item.ReceivedQuantity = 0
+		item.RestockedQuantity = 0
+		item.InspectionNote = ""
+		byID[item.ReturnItemID] = item
+	}
+
+	receipt.Prepare()
+	seen := make(map[uuid.UUID]bool, len(receipt.Items))
+	for _, in := range receipt.Items {
+		item, ok := byID[in.ReturnItemID]
+		if !ok || seen[in.ReturnItemID] {
+			return nil, httpErrors.NewBadRequestError(errors.Errorf("returnUC.Receive: unknown or repeated return item %s", in.ReturnItemID))
+		}
+		seen[in.ReturnItemID] = true
+
+		if in.ReceivedQuantity > item.Quantity || in.RestockQuantity > in.ReceivedQuantity {
+			return nil, httpErrors.NewBadRequestError(errors.Errorf("returnUC.Receive: item %s receives %d of %d and restocks %d",
+				item.SKU, in.ReceivedQuantity, item.Quantity, in.RestockQuantity))
+		}
+
+		item.ReceivedQuantity = in.ReceivedQuantity
+		item.RestockedQuantity = in.RestockQuantity
+		item.InspectionNote = in.InspectionNote
+	}
+	ret.Items = items
+
+	// The stock of restocked units must exist, restocking happens in the status change
+	for _, item := range items {
+		if item.RestockedQuantity == 0 {
+			continue
+		}
+		if _, err = u.inventoryUC.GetStock(ctx, item.SKU); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.changeStatus(ctx, ret, models.ReturnStatusReceived, receiptNote(receipt.Note, items))
+}
+
+// Refund received return through the payment provider or to store credit of the customer, admin only.
+// Without an amount the paid price of the received units is refunded, shipping is not. A refund that
+// failed part way is retried for what is still owed, with the method of the first attempt.
+func (u *returnUC) Refund(ctx context.Context, returnID uuid.UUID, refund *models.ReturnRefund) (*models.Return, error) {
+	// TODO: Tracing
+
+	ret, err := u.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+	if !models.CanTransitionReturn(ret.Status, models.ReturnStatusRefunded) {
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "returnUC.Refund: return is %s", ret.Status)
+	}
+
+	refund.Prepare()
+	if ret.Status == models.ReturnStatusRefundFailed {
+		return u.retryRefund(ctx, ret, refund)
+	}
+
+	o, err := u.orderRepo.GetByID(ctx, ret.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	orderItems, err := u.orderRepo.GetItems(ctx, ret.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*models.OrderItem, len(orderItems))
+	for _, item := range orderItems {
+		byID[item.OrderItemID] = item
+	}
+
+	items, err := u.returnRepo.GetItems(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	var computed int64
+	for _, item := range items {
+		item.RefundAmount = 0
+		if orderItem, ok := byID[item.OrderItemID]; ok {
+			item.RefundAmount = paidAmount(o, orderItem, item.ReceivedQuantity)
+		}
+		computed += item.RefundAmount
+	}
+
+	if refund.Method == models.ReturnRefundStoreCredit && ret.UserID == nil {
+		return nil, httpErrors.NewRestErrorWithMessage(
+			http.StatusBadRequest,
+			"Return of a guest order can't be refunded to store credit",
+			errors.New("returnUC.Refund: store credit without user"),
+		)
+	}
+
+	amount := refund.Amount
+	if amount == 0 {
+		amount = computed
+	}
+	if amount == 0 {
+		return nil, httpErrors.NewRestErrorWithMessage(
+			http.StatusBadRequest,
+			"Nothing to refund, close the return instead",
+			errors.New("returnUC.Refund: zero refund"),
+		)
+	}
+	if amount > computed {
+		return nil, httpErrors.NewRestErrorWithMessage(
+			http.StatusBadRequest,
+			fmt.Sprintf("At most %d can be refunded, the paid price of the received units", computed),
+			errors.Errorf("returnUC.Refund: refund %d is more than %d", amount, computed),
+		)
+	}
+
+	ret.Items = items
+	ret.RefundAmount = amount
+	ret.RefundMethod = refund.Method
+	note := fmt.Sprintf("refund of %d %s", amount, ret.Currency)
+	if refund.Method == models.ReturnRefundStoreCredit {
+		note += " to store credit"
+	}
+	if refund.Note != "" {
+		note += ": " + refund.Note
+	}
+
+	return u.payRefund(ctx, ret, note, refund.Note)
+}
+
+// Refund what a failed refund still owes, the amount and method of the first attempt can't change
+func (u *returnUC) retryRefund(ctx context.Context, ret *models.Return, refund *models.ReturnRefund) (*models.Return, error) {
+	owed := ret.RefundAmount - ret.RefundedAmount
+	if refund.Method != ret.RefundMethod || (refund.Amount != 0 && refund.Amount != owed) {
+		return nil, httpErrors.NewRestErrorWithMessage(
+			http.StatusBadRequest,
+			fmt.Sprintf("The failed refund is retried for the %d still owed to %s", owed, ret.RefundMethod),
+			errors.Errorf("returnUC.retryRefund: %d to %s requested, %d to %s owed", refund.Amount, refund.Method, owed, ret.RefundMethod),
+		)
+	}
+
+	note := fmt.Sprintf("refund retried for %d %s", owed, ret.Currency)
+	if refund.Note != "" {
+		note += ": " + refund.Note
+	}
+
+	return u.payRefund(ctx, ret, note, refund.Note)
+}
+
+// Pay out what ret still owes. The return is claimed as refunded first so a second request can't refund again,
+// when the refund fails it moves to refund_failed with the part that went through. Refunds to the original
+// method go back through the provider as far as the captured payments allow, the part the order paid with
+// gift cards or store credit is credited to store credit.
+func (u *returnUC) payRefund(ctx context.Context, ret *models.Return, note string, customerNote string) (*models.Return, error) {
+	owed := ret.RefundAmount - ret.RefundedAmount
+	refundedBefore := ret.RefundedAmount
+
+	ret.RefundedAmount = ret.RefundAmount
+	updated, err := u.changeStatus(ctx, ret, models.ReturnStatusRefunded, note)
+	if err != nil {
+		return nil, err
+	}
+
+	toProvider := int64(0)
+	if ret.RefundMethod != models.ReturnRefundStoreCredit {
+		refundable, err := u.refunder.RefundableAmount(ctx, ret.OrderID)
+		if err != nil {
+			u.failRefund(ctx, updated, refundedBefore, err)
+			return nil, err
+		}
+		toProvider = owed
+		if toProvider > refundable {
+			toProvider = refundable
+		}
+	}
+
+	if toProvider > 0 {
+		refunded, err := u.refunder.RefundOrder(ctx, ret.OrderID, toProvider, "return_"+ret.ReturnID.String())
+		if err != nil {
+			if refunded < toProvider {
+				u.failRefund(ctx, updated, refundedBefore+refunded, err)
+				return nil, err
+			}
+			u.logger.Errorf("returnUC.payRefund: return %s refunded, recording it failed: %v", ret.ReturnID, err)
+		}
+	}
+
+	if toCredit := owed - toProvider; toCredit > 0 {
+		_, err = u.creditUC.Credit(ctx, &models.StoreCreditEntry{
+			UserID:   *ret.UserID,
+			Currency: ret.Currency,
+			Amount:   toCredit,
+			Reason:   models.StoreCreditRefund,
+			OrderID:  &ret.OrderID,
+			ReturnID: &ret.ReturnID,
+			Note:     customerNote,
+		})
+		if err != nil {
+			u.failRefund(ctx, updated, refundedBefore+toProvider, err)
+			return nil, err
+		}
+	}
+
+	return updated, nil
+}
+
+// Close received return without refund, admin only
+func (u *returnUC) Close(ctx context.Context, returnID uuid.UUID, decision *models.ReturnDecision) (*models.Return, error) {
+	// TODO: Tracing
+
+	ret, err := u.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	decision.Prepare()
+
+	return u.changeStatus(ctx, ret, models.ReturnStatusClosed, decision.Note)
+}
+
+func (u *returnUC) changeStatus(ctx context.Context, ret *models.Return, to string, note string) (*models.Return, error) {
+	if !models.CanTransitionReturn(ret.Status, to) {
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "returnUC.changeStatus: %s to %s", ret.Status, to)
+	}
+
+	event := &models.ReturnEvent{ToStatus: to, Note: note}
+	if user, err := middleware.GetUserFromCtx(ctx); err == nil {
+		event.ActorID = &user.UserID
+	}
+
+	return u.returnRepo.UpdateStatus(ctx, ret, ret.Status, event)
+}
+
+// Move a return whose refund failed to refund_failed, keeping the amount refunded so far
+func (u *returnUC) failRefund(ctx context.Context, ret *models.Return, refunded int64, cause error) {
+	ret.RefundedAmount = refunded
+
+	event := &models.ReturnEvent{
+		ToStatus: models.ReturnStatusRefundFailed,
+		Note:     fmt.Sprintf("refund failed after refunding %d of %d %s", refunded, ret.RefundAmount, ret.Currency),
+	}
+	if user, err := middleware.GetUserFromCtx(ctx); err == nil {
+		event.ActorID = &user.UserID
+	}
+
+	if _, err := u.returnRepo.UpdateStatus(ctx, ret, models.ReturnStatusRefunded, event); err != nil {
+		u.logger.Errorf("returnUC.failRefund: return %s stays refunded after failed refund (%v), %d refunded: %v", ret.ReturnID, cause, refunded, err)
+	}
+}
+
+// Returns are accepted until the window after the latest delivery closes, a zero window never closes
+func (u *returnUC) checkWindow(ctx context.Context, o *models.Order) error {
+	if u.cfg.Returns.Window <= 0 {
+		return nil
+	}
+
+	events, err := u.orderRepo.GetEvents(ctx, o.OrderID)
+	if err != nil {
+		return err
+	}
+
+	deliveredAt := o.UpdatedAt
+	for _, event := range events {
+		if event.ToStatus == models.OrderStatusDelivered {
+			deliveredAt = event.CreatedAt
+		}
+	}
+
+	if time.Since(deliveredAt) > time.Second*u.cfg.Returns.Window {
+		return httpErrors.NewRestErrorWithMessage(
+			http.StatusBadRequest,
+			"The return window of this order has closed",
+			errors.New("returnUC.checkWindow: return window closed"),
+		)
+	}
+
+	return nil
+}
+
+// Return of the user in context, admins see every return
+func (u *returnUC) getVisible(ctx context.Context, returnID uuid.UUID) (*models.Return, error) {
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := u.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsAdmin() && (ret.UserID == nil || *ret.UserID != user.UserID) {
+		return nil, httpErrors.NewNotFoundError(errors.New("returnUC.getVisible: return belongs to another user"))
+	}
+
+	return ret, nil
+}
+
+// Paid price of quantity units of an order line: its share of the order discount taken off and exclusive tax added
+func paidAmount(o *models.Order, item *models.OrderItem, quantity int) int64 {
+	if quantity <= 0 || item.Quantity == 0 {
+		return 0
+	}
+
+	paid := item.LineTotal
+	if o.Subtotal > 0 {
+		paid -= o.DiscountTotal * item.LineTotal / o.Subtotal
+	}
+	if !o.PricesIncludeTax {
+		paid += item.TaxAmount
+	}
+
+	return paid * int64(quantity) / int64(item.Quantity)
+}
+
+// History note of a receipt, like "received 2/2 SKU-1 (restocked 1), received 0/1 SKU-2"
+func receiptNote(note string, items []*models.ReturnItem) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		part := fmt.Sprintf("received %d/%d %s", item.ReceivedQuantity, item.Quantity, item.SKU)
+		if item.RestockedQuantity > 0 {
+			part += fmt.Sprintf(" (restocked %d)", item.RestockedQuantity)
+		}
+		parts = append(parts, part)
+	}
+
+	summary := strings.Join(parts, ", ")
+	if note != "" {
+		summary = note + ": " + summary
+	}
+	if runes := []rune(summary); len(runes) > 1000 {
+		summary = string(runes[:1000])
+	}
+
+	return summary
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/returns"
+	"github.com/fekuna/go-store/internal/storecredit"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Return repository that keeps the last saved state of one return
+type memReturnRepo struct {
+	returns.Repository
+	saved models.Return
+}
+
+func (r *memReturnRepo) UpdateStatus(ctx context.Context, ret *models.Return, from string, event *models.ReturnEvent) (*models.Return, error) {
+	r.saved = *ret
+	r.saved.Status = event.ToStatus
+	updated := r.saved
+	return &updated, nil
+}
+
+// Provider payments of one order with what they can still refund
+type memRefunder struct {
+	refundable int64
+	refunded   int64
+	failAfter  int64
+}
+
+func (r *memRefunder) RefundOrder(ctx context.Context, orderID uuid.UUID, amount int64, reference string) (int64, error) {
+	if r.failAfter > 0 && amount > r.failAfter {
+		r.refunded += r.failAfter
+		r.refundable -= r.failAfter
+		return r.failAfter, errors.New("provider unavailable")
+	}
+	r.refunded += amount
+	r.refundable -= amount
+	return amount, nil
+}
+
+func (r *memRefunder) RefundableAmount(ctx context.Context, orderID uuid.UUID) (int64, error) {
+	return r.refundable, nil
+}
+
+// Store credit ledger of the refunds
+type memCredit struct {
+	storecredit.UseCase
+	credited int64
+}
+
+func (c *memCredit) Credit(ctx context.Context, entry *models.StoreCreditEntry) (*models.StoreCreditEntry, error) {
+	c.credited += entry.Amount
+	return entry, nil
+}
+
+func newReturnTestUC(repo *memReturnRepo, refunder *memRefunder, credit *memCredit) *returnUC {
+	cfg := &config.Config{Logger: config.LoggerConfig{Level: "fatal"}}
+	apiLogger := logger.NewApiLogger(cfg)
+	apiLogger.InitLogger()
+
+	return &returnUC{cfg: cfg, logger: apiLogger, returnRepo: repo, refunder: refunder, creditUC: credit}
+}
+
+func TestPayRefundSplitsOriginalMethod(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name         string
+		method       string
+		refundable   int64
+		failAfter    int64
+		wantProvider int64
+		wantCredit   int64
+		wantStatus   string
+		wantRefunded int64
+	}{
+		{
+			name:         "paid by card",
+			method:       models.ReturnRefundOriginal,
+			refundable:   5000,
+			wantProvider: 3000,
+			wantStatus:   models.ReturnStatusRefunded,
+			wantRefunded: 3000,
+		},
+		{
+			name:         "paid by card and gift card",
+			method:       models.ReturnRefundOriginal,
+			refundable:   1000,
+			wantProvider: 1000,
+			wantCredit:   2000,
+			wantStatus:   models.ReturnStatusRefunded,
+			wantRefunded: 3000,
+		},
+		{
+			name:         "paid by store credit",
+			method:       models.ReturnRefundOriginal,
+			wantCredit:   3000,
+			wantStatus:   models.ReturnStatusRefunded,
+			wantRefunded: 3000,
+		},
+		{
+			name:         "store credit requested",
+			method:       models.ReturnRefundStoreCredit,
+			refundable:   5000,
+			wantCredit:   3000,
+			wantStatus:   models.ReturnStatusRefunded,
+			wantRefunded: 3000,
+		},
+		{
+			name:         "provider fails halfway",
+			method:       models.ReturnRefundOriginal,
+			refundable:   2000,
+			failAfter:    500,
+			wantProvider: 500,
+			wantStatus:   models.ReturnStatusRefundFailed,
+			wantRefunded: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refunder := &memRefunder{refundable: tt.refundable, failAfter: tt.failAfter}
+			credit := &memCredit{}
+			repo := &memReturnRepo{}
+			u := newReturnTestUC(repo, refunder, credit)
+
+			ret := &models.Return{
+				ReturnID:     uuid.New(),
+				OrderID:      uuid.New(),
+				UserID:       &userID,
+				Status:       models.ReturnStatusReceived,
+				Currency:     "USD",
+				RefundAmount: 3000,
+				RefundMethod: tt.method,
+			}
+			_, err := u.payRefund(context.Background(), ret, "refund", "")
+			if (err != nil) != (tt.wantStatus == models.ReturnStatusRefundFailed) {
+				t.Fatalf("payRefund() error = %v", err)
+			}
+
+			if refunder.refunded != tt.wantProvider || credit.credited != tt.wantCredit {
+				t.Fatalf("refunded %d through the provider and %d to store credit, want %d and %d",
+					refunder.refunded, credit.credited, tt.wantProvider, tt.wantCredit)
+			}
+			if saved := repo.saved; saved.Status != tt.wantStatus || saved.RefundedAmount != tt.wantRefunded {
+				t.Fatalf("return is %s with %d refunded, want %s with %d", saved.Status, saved.RefundedAmount,
+					tt.wantStatus, tt.wantRefunded)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package review
+
+import "github.com/labstack/echo/v4"
+
+// Review HTTP Handlers interface
+type Handlers interface {
+	Create() echo.HandlerFunc
+	Update() echo.HandlerFunc
+	Delete() echo.HandlerFunc
+	ListByProduct() echo.HandlerFunc
+	ListByStatus() echo.HandlerFunc
+	Moderate() echo.HandlerFunc
+	AddVote() echo.HandlerFunc
+	RemoveVote() echo.HandlerFunc
+}
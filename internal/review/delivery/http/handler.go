@@ -0,0 +1,288 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/review"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Review handlers
+type reviewHandlers struct {
+	cfg      *config.Config
+	logger   logger.Logger
+	reviewUC review.UseCase
+}
+
+// Review handlers constructor
+func NewReviewHandlers(cfg *config.Config, logger logger.Logger, reviewUC review.UseCase) review.Handlers {
+	return &reviewHandlers{
+		cfg:      cfg,
+		logger:   logger,
+		reviewUC: reviewUC,
+	}
+}
+
+// Create godoc
+// @Summary Create review
+// @Description rate and review product, one review per user per product, hidden until approved
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param product_id path string true "product_id"
+// @Success 201 {object} models.Review
+// @Failure 500 {object} httpErrors.RestError
+// @Router /products/{product_id}/reviews [post]
+func (h *reviewHandlers) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		productID, err := uuid.Parse(c.Param("product_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		rv := &models.Review{}
+		if err = utils.ReadRequest(c, rv); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		rv.ProductID = productID
+
+		createdReview, err := h.reviewUC.Create(ctx, rv)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, createdReview)
+	}
+}
+
+// Update godoc
+// @Summary Update review
+// @Description update own review, it goes back to moderation
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param review_id path string true "review_id"
+// @Success 200 {object} models.Review
+// @Failure 500 {object} httpErrors.RestError
+// @Router /reviews/{review_id} [put]
+func (h *reviewHandlers) Update() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		reviewID, err := uuid.Parse(c.Param("review_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		rv := &models.Review{}
+		if err = utils.ReadRequest(c, rv); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		rv.ReviewID = reviewID
+
+		updatedReview, err := h.reviewUC.Update(ctx, rv)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, updatedReview)
+	}
+}
+
+// Delete godoc
+// @Summary Delete review
+// @Description delete own review, admins can delete any review
+// @Tags Reviews
+// @Param review_id path string true "review_id"
+// @Success 204
+// @Failure 500 {object} httpErrors.RestError
+// @Router /reviews/{review_id} [delete]
+func (h *reviewHandlers) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		reviewID, err := uuid.Parse(c.Param("review_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.reviewUC.Delete(ctx, reviewID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// ListByProduct godoc
+// @Summary Get product reviews
+// @Description get approved reviews of product with pagination
+// @Tags Reviews
+// @Produce json
+// @Param product_id path string true "product_id"
+// @Param sort query string false "newest, helpful, rating_desc or rating_asc"
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.ReviewsList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /products/{product_id}/reviews [get]
+func (h *reviewHandlers) ListByProduct() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		productID, err := uuid.Parse(c.Param("product_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		query := &models.ReviewsQuery{}
+		if err = utils.ReadRequest(c, query); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		reviewsList, err := h.reviewUC.ListByProduct(ctx, productID, query, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, reviewsList)
+	}
+}
+
+// ListByStatus godoc
+// @Summary Get reviews by moderation status
+// @Description get reviews with given status, pending by default, oldest first, admin only
+// @Tags Reviews
+// @Produce json
+// @Param status query string false "pending, approved or rejected"
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.ReviewsList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /reviews [get]
+func (h *reviewHandlers) ListByStatus() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		query := &models.ReviewsQuery{}
+		if err := utils.ReadRequest(c, query); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		reviewsList, err := h.reviewUC.ListByStatus(ctx, query, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, reviewsList)
+	}
+}
+
+// Moderate godoc
+// @Summary Moderate review
+// @Description approve or reject review, product rating is refreshed, admin only
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param review_id path string true "review_id"
+// @Success 200 {object} models.Review
+// @Failure 500 {object} httpErrors.RestError
+// @Router /reviews/{review_id}/moderation [put]
+func (h *reviewHandlers) Moderate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		reviewID, err := uuid.Parse(c.Param("review_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		moderation := &models.ReviewModeration{}
+		if err = utils.ReadRequest(c, moderation); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		moderatedReview, err := h.reviewUC.Moderate(ctx, reviewID, moderation)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, moderatedReview)
+	}
+}
+
+// AddVote godoc
+// @Summary Vote review as helpful
+// @Description vote approved review of another user as helpful, voting twice has no effect
+// @Tags Reviews
+// @Param review_id path string true "review_id"
+// @Success 204
+// @Failure 500 {object} httpErrors.RestError
+// @Router /reviews/{review_id}/helpful [post]
+func (h *reviewHandlers) AddVote() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		reviewID, err := uuid.Parse(c.Param("review_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.reviewUC.AddVote(ctx, reviewID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// RemoveVote godoc
+// @Summary Remove helpful vote
+// @Description remove own helpful vote from review
+// @Tags Reviews
+// @Param review_id path string true "review_id"
+// @Success 204
+// @Failure 500 {object} httpErrors.RestError
+// @Router /reviews/{review_id}/helpful [delete]
+func (h *reviewHandlers) RemoveVote() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		reviewID, err := uuid.Parse(c.Param("review_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.reviewUC.RemoveVote(ctx, reviewID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/review"
+	"github.com/labstack/echo/v4"
+)
+
+func MapReviewRoutes(reviewGroup *echo.Group, productGroup *echo.Group, h review.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	productGroup.GET("/:product_id/reviews", h.ListByProduct())
+	productGroup.POST("/:product_id/reviews", h.Create(), mw.AuthJWTMiddleware)
+
+	reviewGroup.GET("", h.ListByStatus(), adminOnly...)
+	reviewGroup.PUT("/:review_id", h.Update(), mw.AuthJWTMiddleware)
+	reviewGroup.DELETE("/:review_id", h.Delete(), mw.AuthJWTMiddleware)
+	reviewGroup.PUT("/:review_id/moderation", h.Moderate(), adminOnly...)
+	reviewGroup.POST("/:review_id/helpful", h.AddVote(), mw.AuthJWTMiddleware)
+	reviewGroup.DELETE("/:review_id/helpful", h.RemoveVote(), mw.AuthJWTMiddleware)
+}
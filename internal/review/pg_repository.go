@@ -0,0 +1,22 @@
+package review
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Review repository
+type Repository interface {
+	Create(ctx context.Context, review *models.Review) (*models.Review, error)
+	Update(ctx context.Context, review *models.Review) (*models.Review, error)
+	Delete(ctx context.Context, reviewID uuid.UUID) error
+	GetByID(ctx context.Context, reviewID uuid.UUID) (*models.Review, error)
+	ListByProduct(ctx context.Context, productID uuid.UUID, sort string, pq *utils.PaginationQuery) (*models.ReviewsList, error)
+	ListByStatus(ctx context.Context, status string, pq *utils.PaginationQuery) (*models.ReviewsList, error)
+	Moderate(ctx context.Context, reviewID uuid.UUID, status string, moderatorID uuid.UUID) (*models.Review, error)
+	AddVote(ctx context.Context, reviewID uuid.UUID, userID uuid.UUID) error
+	RemoveVote(ctx context.Context, reviewID uuid.UUID, userID uuid.UUID) error
+}
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/review"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Approved product reviews sort orders
+var reviewSortOrders = map[string]string{
+	"newest":      "r.created_at DESC, r.review_id",
+	"helpful":     "r.helpful_count DESC, r.created_at DESC, r.review_id",
+	"rating_desc": "r.rating DESC, r.created_at DESC, r.review_id",
+	"rating_asc":  "r.rating ASC, r.created_at DESC, r.review_id",
+}
+
+// Review repository
+type reviewRepo struct {
+	db *sqlx.DB
+}
+
+// Review repository constructor
+func NewReviewRepository(db *sqlx.DB) review.Repository {
+	return &reviewRepo{db: db}
+}
+
+func (r *reviewRepo) Create(ctx context.Context, rv *models.Review) (*models.Review, error) {
+	// TODO: Tracing
+
+	created := &models.Review{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		createReviewQuery,
+		&rv.ProductID,
+		&rv.UserID,
+		&rv.Rating,
+		&rv.Title,
+		&rv.Body,
+		&rv.Status,
+		&rv.VerifiedPurchase,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "reviewRepo.Create.StructScan")
+	}
+
+	return created, nil
+}
+
+// Update review content, product rating is refreshed because an approved review goes back to moderation
+func (r *reviewRepo) Update(ctx context.Context, rv *models.Review) (*models.Review, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "reviewRepo.Update.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	if err = lockProduct(ctx, tx, rv.ProductID); err != nil {
+		return nil, err
+	}
+
+	updated := &models.Review{}
+	if err = tx.GetContext(ctx, updated, updateReviewQuery, &rv.Rating, &rv.Title, &rv.Body, &rv.Status, &rv.ReviewID); err != nil {
+		return nil, errors.Wrap(err, "reviewRepo.Update.GetContext")
+	}
+
+	if err = refreshProductRating(ctx, tx, rv.ProductID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "reviewRepo.Update.Commit")
+	}
+
+	return updated, nil
+}
+
+func (r *reviewRepo) Delete(ctx context.Context, reviewID uuid.UUID) error {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "reviewRepo.Delete.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	productID, err := reviewProductID(ctx, tx, reviewID)
+	if err != nil {
+		return err
+	}
+
+	if err = lockProduct(ctx, tx, productID); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, deleteReviewQuery, reviewID)
+	if err != nil {
+		return errors.Wrap(err, "reviewRepo.Delete.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "reviewRepo.Delete.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "reviewRepo.Delete.rowsAffected")
+	}
+
+	if err = refreshProductRating(ctx, tx, productID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "reviewRepo.Delete.Commit")
+	}
+
+	return nil
+}
+
+func (r *reviewRepo) GetByID(ctx context.Context, reviewID uuid.UUID) (*models.Review, error) {
+	// TODO: Tracing
+
+	rv := &models.Review{}
+	if err := r.db.GetContext(ctx, rv, getReviewByIdQuery, reviewID); err != nil {
+		return nil, errors.Wrap(err, "reviewRepo.GetByID.GetContext")
+	}
+
+	return rv, nil
+}
+
+func (r *reviewRepo) ListByProduct(ctx context.Context, productID uuid.UUID, sort string, pq *utils.PaginationQuery) (*models.ReviewsList, error) {
+	// TODO: Tracing
+
+	order, ok := reviewSortOrders[sort]
+	if !ok {
+		order = reviewSortOrders["newest"]
+	}
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalProductReviewsQuery, productID); err != nil {
+		return nil, errors.Wrap(err, "reviewRepo.ListByProduct.GetContext.totalCount")
+	}
+
+	reviews := make([]*models.Review, 0, pq.GetSize())
+	if totalCount > 0 {
+		query := fmt.Sprintf(listProductReviewsQuery, order)
+		if err := r.db.SelectContext(ctx, &reviews, query, productID, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "reviewRepo.ListByProduct.SelectContext")
+		}
+	}
+
+	return newReviewsList(totalCount, pq, reviews), nil
+}
+
+func (r *reviewRepo) ListByStatus(ctx context.Context, status string, pq *utils.PaginationQuery) (*models.ReviewsList, error) {
+	// TODO: Tracing
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalReviewsByStatusQuery, status); err != nil {
+		return nil, errors.Wrap(err, "reviewRepo.ListByStatus.GetContext.totalCount")
+	}
+
+	reviews := make([]*models.Review, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &reviews, listReviewsByStatusQuery, status, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "reviewRepo.ListByStatus.SelectContext")
+		}
+	}
+
+	return newReviewsList(totalCount, pq, reviews), nil
+}
+
+// Set moderation status and refresh product rating in one transaction
+func (r *reviewRepo) Moderate(ctx context.Context, reviewID uuid.UUID, status string, moderatorID uuid.UUID) (*models.Review, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "reviewRepo.Moderate.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	productID, err := reviewProductID(ctx, tx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = lockProduct(ctx, tx, productID); err != nil {
+		return nil, err
+	}
+
+	moderated := &models.Review{}
+	if err = tx.GetContext(ctx, moderated, moderateReviewQuery, status, moderatorID, reviewID); err != nil {
+		return nil, errors.Wrap(err, "reviewRepo.Moderate.GetContext")
+	}
+
+	if err = refreshProductRating(ctx, tx, productID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "reviewRepo.Moderate.Commit")
+	}
+
+	return moderated, nil
+}
+
+// Add helpful vote, voting twice is a no-op
+func (r *reviewRepo) AddVote(ctx context.Context, reviewID uuid.UUID, userID uuid.UUID) error {
+	// TODO: Tracing
+
+	return r.changeVote(ctx, createVoteQuery, 1, reviewID, userID)
+}
+
+// Remove helpful vote, removing a missing vote is a no-op
+func (r *reviewRepo) RemoveVote(ctx context.Context, reviewID uuid.UUID, userID uuid.UUID) error {
+	// TODO: Tracing
+
+	return r.changeVote(ctx, deleteVoteQuery, -1, reviewID, userID)
+}
+
+// Helpful count only moves when the vote row really changed
+func (r *reviewRepo) changeVote(ctx context.Context, voteQuery string, delta int, reviewID uuid.UUID, userID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "reviewRepo.changeVote.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, voteQuery, reviewID, userID)
+	if err != nil {
+		return errors.Wrap(err, "reviewRepo.changeVote.ExecContext.vote")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "reviewRepo.changeVote.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return nil
+	}
+
+	if _, err = tx.ExecContext(ctx, updateHelpfulCountQuery, delta, reviewID); err != nil {
+		return errors.Wrap(err, "reviewRepo.changeVote.ExecContext.helpfulCount")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "reviewRepo.changeVote.Commit")
+	}
+
+	return nil
+}
+
+func reviewProductID(ctx context.Context, tx *sqlx.Tx, reviewID uuid.UUID) (uuid.UUID, error) {
+	var productID uuid.UUID
+	if err := tx.GetContext(ctx, &productID, getReviewProductIdQuery, reviewID); err != nil {
+		return uuid.Nil, errors.Wrap(err, "reviewRepo.reviewProductID.GetContext")
+	}
+
+	return productID, nil
+}
+
+func lockProduct(ctx context.Context, tx *sqlx.Tx, productID uuid.UUID) error {
+	var lockedID uuid.UUID
+	if err := tx.GetContext(ctx, &lockedID, lockProductQuery, productID); err != nil {
+		return errors.Wrap(err, "reviewRepo.lockProduct.GetContext")
+	}
+
+	return nil
+}
+
+func refreshProductRating(ctx context.Context, tx *sqlx.Tx, productID uuid.UUID) error {
+	if _, err := tx.ExecContext(ctx, refreshProductRatingQuery, productID); err != nil {
+		return errors.Wrap(err, "reviewRepo.refreshProductRating.ExecContext")
+	}
+
+	return nil
+}
+
+func newReviewsList(totalCount int, pq *utils.PaginationQuery, reviews []*models.Review) *models.ReviewsList {
+	return &models.ReviewsList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Reviews:    reviews,
+	}
+}
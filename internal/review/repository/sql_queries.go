@@ -0,0 +1,82 @@
+package repository
+
+const (
+	reviewColumns = `r.*, u.first_name || ' ' || LEFT(u.last_name, 1) || '.' AS author_name`
+
+	createReviewQuery = `
+		INSERT INTO reviews(product_id, user_id, rating, title, body, status, verified_purchase, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, now(), now())
+		RETURNING *
+	`
+
+	updateReviewQuery = `
+		UPDATE reviews
+		SET rating = $1, title = $2, body = $3, status = $4, moderated_by = NULL, moderated_at = NULL, updated_at = now()
+		WHERE review_id = $5
+		RETURNING *
+	`
+
+	deleteReviewQuery = `DELETE FROM reviews WHERE review_id = $1 RETURNING product_id`
+
+	getReviewByIdQuery = `
+		SELECT ` + reviewColumns + `
+		FROM reviews r
+		JOIN users u ON u.user_id = r.user_id
+		WHERE r.review_id = $1
+	`
+
+	getReviewProductIdQuery = `SELECT product_id FROM reviews WHERE review_id = $1`
+
+	getTotalProductReviewsQuery = `SELECT COUNT(*) FROM reviews WHERE product_id = $1 AND status = 'approved'`
+
+	listProductReviewsQuery = `
+		SELECT ` + reviewColumns + `
+		FROM reviews r
+		JOIN users u ON u.user_id = r.user_id
+		WHERE r.product_id = $1 AND r.status = 'approved'
+		ORDER BY %s
+		OFFSET $2 LIMIT $3
+	`
+
+	getTotalReviewsByStatusQuery = `SELECT COUNT(*) FROM reviews WHERE status = $1`
+
+	listReviewsByStatusQuery = `
+		SELECT ` + reviewColumns + `
+		FROM reviews r
+		JOIN users u ON u.user_id = r.user_id
+		WHERE r.status = $1
+		ORDER BY r.created_at, r.review_id
+		OFFSET $2 LIMIT $3
+	`
+
+	moderateReviewQuery = `
+		UPDATE reviews
+		SET status = $1, moderated_by = $2, moderated_at = now(), updated_at = now()
+		WHERE review_id = $3
+		RETURNING *
+	`
+
+	// Serializes aggregate refreshes of one product
+	lockProductQuery = `SELECT product_id FROM products WHERE product_id = $1 FOR UPDATE`
+
+	refreshProductRatingQuery = `
+		UPDATE products p
+		SET rating_avg = s.rating_avg, rating_count = s.rating_count
+		FROM (
+			SELECT COALESCE(ROUND(AVG(rating), 2), 0) AS rating_avg, COUNT(*) AS rating_count
+			FROM reviews
+			WHERE product_id = $1 AND status = 'approved'
+		) s
+		WHERE p.product_id = $1
+	`
+
+	createVoteQuery = `
+		INSERT INTO review_votes(review_id, user_id, created_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT DO NOTHING
+	`
+
+	deleteVoteQuery = `DELETE FROM review_votes WHERE review_id = $1 AND user_id = $2`
+
+	updateHelpfulCountQuery = `UPDATE reviews SET helpful_count = helpful_count + $1 WHERE review_id = $2`
+)
@@ -0,0 +1,21 @@
+package review
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Review UseCase
+type UseCase interface {
+	Create(ctx context.Context, review *models.Review) (*models.Review, error)
+	Update(ctx context.Context, review *models.Review) (*models.Review, error)
+	Delete(ctx context.Context, reviewID uuid.UUID) error
+	ListByProduct(ctx context.Context, productID uuid.UUID, query *models.ReviewsQuery, pq *utils.PaginationQuery) (*models.ReviewsList, error)
+	ListByStatus(ctx context.Context, query *models.ReviewsQuery, pq *utils.PaginationQuery) (*models.ReviewsList, error)
+	Moderate(ctx context.Context, reviewID uuid.UUID, moderation *models.ReviewModeration) (*models.Review, error)
+	AddVote(ctx context.Context, reviewID uuid.UUID) error
+	RemoveVote(ctx context.Context, reviewID uuid.UUID) error
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/order"
+	"github.com/fekuna/go-store/internal/product"
+	"github.com/fekuna/go-store/internal/review"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Review UseCase
+type reviewUC struct {
+	cfg         *config.Config
+	logger      logger.Logger
+	reviewRepo  review.Repository
+	productRepo product.Repository
+	orderRepo   order.Repository
+}
+
+// Review UseCase constructor
+func NewReviewUseCase(cfg *config.Config, logger logger.Logger, reviewRepo review.Repository, productRepo product.Repository, orderRepo order.Repository) review.UseCase {
+	return &reviewUC{
+		cfg:         cfg,
+		logger:      logger,
+		reviewRepo:  reviewRepo,
+		productRepo: productRepo,
+		orderRepo:   orderRepo,
+	}
+}
+
+// Create review for active product, it stays hidden until approved
+func (u *reviewUC) Create(ctx context.Context, rv *models.Review) (*models.Review, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := u.productRepo.GetByID(ctx, rv.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != models.ProductStatusActive {
+		return nil, httpErrors.NewNotFoundError(errors.New("reviewUC.Create: product is not active"))
+	}
+
+	rv.UserID = user.UserID
+	rv.Prepare()
+	rv.VerifiedPurchase, err = u.orderRepo.HasPurchased(ctx, user.UserID, rv.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.reviewRepo.Create(ctx, rv)
+}
+
+// Update own review, it goes back to moderation
+func (u *reviewUC) Update(ctx context.Context, rv *models.Review) (*models.Review, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := u.reviewRepo.GetByID(ctx, rv.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.UserID != user.UserID {
+		return nil, httpErrors.NewForbiddenError(errors.New("reviewUC.Update: review belongs to another user"))
+	}
+
+	rv.ProductID = existing.ProductID
+	rv.Prepare()
+
+	return u.reviewRepo.Update(ctx, rv)
+}
+
+// Delete own review, admins can delete any review
+func (u *reviewUC) Delete(ctx context.Context, reviewID uuid.UUID) error {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	existing, err := u.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+
+	if existing.UserID != user.UserID && !user.IsAdmin() {
+		return httpErrors.NewForbiddenError(errors.New("reviewUC.Delete: review belongs to another user"))
+	}
+
+	return u.reviewRepo.Delete(ctx, reviewID)
+}
+
+// Approved reviews of product
+func (u *reviewUC) ListByProduct(ctx context.Context, productID uuid.UUID, query *models.ReviewsQuery, pq *utils.PaginationQuery) (*models.ReviewsList, error) {
+	// TODO: Tracing
+
+	return u.reviewRepo.ListByProduct(ctx, productID, query.Sort, pq)
+}
+
+// Moderation queue, pending reviews by default
+func (u *reviewUC) ListByStatus(ctx context.Context, query *models.ReviewsQuery, pq *utils.PaginationQuery) (*models.ReviewsList, error) {
+	// TODO: Tracing
+
+	status := query.Status
+	if status == "" {
+		status = models.ReviewStatusPending
+	}
+
+	return u.reviewRepo.ListByStatus(ctx, status, pq)
+}
+
+func (u *reviewUC) Moderate(ctx context.Context, reviewID uuid.UUID, moderation *models.ReviewModeration) (*models.Review, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.reviewRepo.Moderate(ctx, reviewID, moderation.Status, user.UserID)
+}
+
+// Vote approved review of another user as helpful
+func (u *reviewUC) AddVote(ctx context.Context, reviewID uuid.UUID) error {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	existing, err := u.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+
+	if existing.Status != models.ReviewStatusApproved {
+		return httpErrors.NewNotFoundError(errors.New("reviewUC.AddVote: review is not approved"))
+	}
+
+	if existing.UserID == user.UserID {
+		return httpErrors.NewBadRequestError(errors.New("reviewUC.AddVote: can't vote for own review"))
+	}
+
+	return u.reviewRepo.AddVote(ctx, reviewID, user.UserID)
+}
+
+func (u *reviewUC) RemoveVote(ctx context.Context, reviewID uuid.UUID) error {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	return u.reviewRepo.RemoveVote(ctx, reviewID, user.UserID)
+}
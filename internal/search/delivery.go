@@ -0,0 +1,8 @@
+package search
+
+import "github.com/labstack/echo/v4"
+
+// Search HTTP Handlers interface
+type Handlers interface {
+	Search() echo.HandlerFunc
+}
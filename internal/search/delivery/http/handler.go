@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/search"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// Search handlers
+type searchHandlers struct {
+	cfg      *config.Config
+	logger   logger.Logger
+	searchUC search.UseCase
+}
+
+// Search handlers constructor
+func NewSearchHandlers(cfg *config.Config, logger logger.Logger, searchUC search.UseCase) search.Handlers {
+	return &searchHandlers{
+		cfg:      cfg,
+		logger:   logger,
+		searchUC: searchUC,
+	}
+}
+
+// Search godoc
+// @Summary Search products
+// @Description full text search over sellable items with prefix and typo tolerant matching, ranked by relevance
+// @Tags Search
+// @Produce json
+// @Param q query string true "search query"
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.SearchResultsList
+// @Failure 400 {object} httpErrors.RestError
+// @Router /search [get]
+func (h *searchHandlers) Search() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		query := &models.SearchQuery{}
+		if err := utils.ReadRequest(c, query); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		results, err := h.searchUC.Search(ctx, query.Query, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, results)
+	}
+}
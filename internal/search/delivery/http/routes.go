@@ -0,0 +1,10 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/search"
+	"github.com/labstack/echo/v4"
+)
+
+func MapSearchRoutes(searchGroup *echo.Group, h search.Handlers) {
+	searchGroup.GET("", h.Search())
+}
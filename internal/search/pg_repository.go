@@ -0,0 +1,13 @@
+package search
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+)
+
+// Search repository
+type Repository interface {
+	Search(ctx context.Context, tsQuery string, rawQuery string, pq *utils.PaginationQuery) (*models.SearchResultsList, error)
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/search"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Search repository
+type searchRepo struct {
+	db *sqlx.DB
+}
+
+// Search repository constructor
+func NewSearchRepository(db *sqlx.DB) search.Repository {
+	return &searchRepo{db: db}
+}
+
+func (r *searchRepo) Search(ctx context.Context, tsQuery string, rawQuery string, pq *utils.PaginationQuery) (*models.SearchResultsList, error) {
+	// TODO: Tracing
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalSearchResultsQuery, tsQuery, rawQuery); err != nil {
+		return nil, errors.Wrap(err, "searchRepo.Search.GetContext.totalCount")
+	}
+
+	results := make([]*models.SearchResult, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &results, searchQuery, tsQuery, rawQuery, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "searchRepo.Search.SelectContext")
+		}
+	}
+
+	return &models.SearchResultsList{
+		Query:      rawQuery,
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Results:    results,
+	}, nil
+}
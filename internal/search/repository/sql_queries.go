@@ -0,0 +1,38 @@
+package repository
+
+const (
+	// $1 is a prefix tsquery, $2 the raw query used for typo tolerant trigram matching
+	searchMatchCondition = `
+		p.status = 'active' AND v.status = 'active'
+		AND (v.search_vector @@ to_tsquery('english', $1) OR $2 <% p.title)
+	`
+
+	// Wrapped around a column it HTML escapes the text, so the only markup in a highlight is the <mark> of ts_headline
+	htmlEscapeStart = `replace(replace(replace(replace(replace(`
+	htmlEscapeEnd   = `, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+
+	getTotalSearchResultsQuery = `
+		SELECT COUNT(v.variant_id)
+		FROM product_variants v
+		JOIN products p ON p.product_id = v.product_id
+		WHERE ` + searchMatchCondition
+
+	// Snippets are built for the requested page only, ts_headline is expensive
+	searchQuery = `
+		WITH ranked AS (
+			SELECT v.variant_id, v.product_id, v.sku, p.slug, p.title, v.title AS variant_title,
+				COALESCE(v.price_override, p.price) AS price, p.currency, p.description,
+				ts_rank_cd(v.search_vector, to_tsquery('english', $1)) + word_similarity($2, p.title) * 0.5 AS rank
+			FROM product_variants v
+			JOIN products p ON p.product_id = v.product_id
+			WHERE ` + searchMatchCondition + `
+			ORDER BY rank DESC, v.sku
+			OFFSET $3 LIMIT $4
+		)
+		SELECT variant_id, product_id, sku, slug, title, variant_title, price, currency, rank,
+			ts_headline('english', ` + htmlEscapeStart + `title` + htmlEscapeEnd + `, to_tsquery('english', $1), 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS title_highlight,
+			ts_headline('english', ` + htmlEscapeStart + `description` + htmlEscapeEnd + `, to_tsquery('english', $1), 'MaxFragments=2, MaxWords=20, MinWords=5, StartSel=<mark>, StopSel=</mark>') AS snippet
+		FROM ranked
+		ORDER BY rank DESC, sku
+	`
+)
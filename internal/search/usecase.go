@@ -0,0 +1,13 @@
+package search
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+)
+
+// Search UseCase
+type UseCase interface {
+	Search(ctx context.Context, query string, pq *utils.PaginationQuery) (*models.SearchResultsList, error)
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/search"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+const maxSearchTerms = 8
+
+// Search UseCase
+type searchUC struct {
+	cfg        *config.Config
+	logger     logger.Logger
+	searchRepo search.Repository
+}
+
+// Search UseCase constructor
+func NewSearchUseCase(cfg *config.Config, logger logger.Logger, searchRepo search.Repository) search.UseCase {
+	return &searchUC{
+		cfg:        cfg,
+		logger:     logger,
+		searchRepo: searchRepo,
+	}
+}
+
+func (u *searchUC) Search(ctx context.Context, query string, pq *utils.PaginationQuery) (*models.SearchResultsList, error) {
+	// TODO: Tracing
+
+	query = strings.TrimSpace(query)
+
+	tsQuery := buildPrefixTsQuery(query)
+	if tsQuery == "" {
+		return nil, httpErrors.NewBadRequestError(errors.New("searchUC.Search: query has no searchable terms"))
+	}
+
+	return u.searchRepo.Search(ctx, tsQuery, query, pq)
+}
+
+// Build tsquery text where every term matches as a prefix: "red shi" -> "red:* & shi:*".
+// Terms are reduced to letters and digits so user input can't inject tsquery operators.
+func buildPrefixTsQuery(query string) string {
+	terms := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+
+	return strings.Join(terms, " & ")
+}
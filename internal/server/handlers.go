@@ -1,37 +1,197 @@
 package server
 
 import (
+	"context"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
+	addressHttp "github.com/fekuna/go-store/internal/address/delivery/http"
+	addressRepository "github.com/fekuna/go-store/internal/address/repository"
+	addressUseCase "github.com/fekuna/go-store/internal/address/usecase"
+	attributeHttp "github.com/fekuna/go-store/internal/attribute/delivery/http"
+	attributeRepository "github.com/fekuna/go-store/internal/attribute/repository"
+	attributeUseCase "github.com/fekuna/go-store/internal/attribute/usecase"
 	authHttp "github.com/fekuna/go-store/internal/auth/delivery/http"
 	authRepository "github.com/fekuna/go-store/internal/auth/repository"
 	authUC "github.com/fekuna/go-store/internal/auth/usecase"
+	cartHttp "github.com/fekuna/go-store/internal/cart/delivery/http"
+	cartRepository "github.com/fekuna/go-store/internal/cart/repository"
+	cartUseCase "github.com/fekuna/go-store/internal/cart/usecase"
+	cartRecoveryHttp "github.com/fekuna/go-store/internal/cartrecovery/delivery/http"
+	cartRecoveryRepository "github.com/fekuna/go-store/internal/cartrecovery/repository"
+	cartRecoveryUseCase "github.com/fekuna/go-store/internal/cartrecovery/usecase"
+	catalogHttp "github.com/fekuna/go-store/internal/catalog/delivery/http"
+	catalogRepository "github.com/fekuna/go-store/internal/catalog/repository"
+	catalogUseCase "github.com/fekuna/go-store/internal/catalog/usecase"
+	categoryHttp "github.com/fekuna/go-store/internal/category/delivery/http"
+	categoryRepository "github.com/fekuna/go-store/internal/category/repository"
+	categoryUseCase "github.com/fekuna/go-store/internal/category/usecase"
+	checkoutHttp "github.com/fekuna/go-store/internal/checkout/delivery/http"
+	checkoutRepository "github.com/fekuna/go-store/internal/checkout/repository"
+	checkoutUseCase "github.com/fekuna/go-store/internal/checkout/usecase"
+	giftCardHttp "github.com/fekuna/go-store/internal/giftcard/delivery/http"
+	giftCardRepository "github.com/fekuna/go-store/internal/giftcard/repository"
+	giftCardUseCase "github.com/fekuna/go-store/internal/giftcard/usecase"
+	idempotencyRepository "github.com/fekuna/go-store/internal/idempotency/repository"
+	idempotencyUseCase "github.com/fekuna/go-store/internal/idempotency/usecase"
+	inventoryHttp "github.com/fekuna/go-store/internal/inventory/delivery/http"
+	inventoryRepository "github.com/fekuna/go-store/internal/inventory/repository"
+	inventoryUseCase "github.com/fekuna/go-store/internal/inventory/usecase"
+	invoiceHttp "github.com/fekuna/go-store/internal/invoice/delivery/http"
+	invoiceRepository "github.com/fekuna/go-store/internal/invoice/repository"
+	invoiceUseCase "github.com/fekuna/go-store/internal/invoice/usecase"
 	apiMiddlewares "github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	orderHttp "github.com/fekuna/go-store/internal/order/delivery/http"
+	orderRepository "github.com/fekuna/go-store/internal/order/repository"
+	orderUseCase "github.com/fekuna/go-store/internal/order/usecase"
+	paymentHttp "github.com/fekuna/go-store/internal/payment/delivery/http"
+	paymentRepository "github.com/fekuna/go-store/internal/payment/repository"
+	paymentUseCase "github.com/fekuna/go-store/internal/payment/usecase"
+	productHttp "github.com/fekuna/go-store/internal/product/delivery/http"
+	productRepository "github.com/fekuna/go-store/internal/product/repository"
+	productUseCase "github.com/fekuna/go-store/internal/product/usecase"
+	promotionHttp "github.com/fekuna/go-store/internal/promotion/delivery/http"
+	promotionRepository "github.com/fekuna/go-store/internal/promotion/repository"
+	promotionUseCase "github.com/fekuna/go-store/internal/promotion/usecase"
+	returnHttp "github.com/fekuna/go-store/internal/returns/delivery/http"
+	returnRepository "github.com/fekuna/go-store/internal/returns/repository"
+	returnUseCase "github.com/fekuna/go-store/internal/returns/usecase"
+	reviewHttp "github.com/fekuna/go-store/internal/review/delivery/http"
+	reviewRepository "github.com/fekuna/go-store/internal/review/repository"
+	reviewUseCase "github.com/fekuna/go-store/internal/review/usecase"
+	searchHttp "github.com/fekuna/go-store/internal/search/delivery/http"
+	searchRepository "github.com/fekuna/go-store/internal/search/repository"
+	searchUseCase "github.com/fekuna/go-store/internal/search/usecase"
 	sessRepository "github.com/fekuna/go-store/internal/session/repository"
 	sessUC "github.com/fekuna/go-store/internal/session/usecase"
+	shippingHttp "github.com/fekuna/go-store/internal/shipping/delivery/http"
+	shippingRepository "github.com/fekuna/go-store/internal/shipping/repository"
+	shippingUseCase "github.com/fekuna/go-store/internal/shipping/usecase"
+	storeCreditHttp "github.com/fekuna/go-store/internal/storecredit/delivery/http"
+	storeCreditRepository "github.com/fekuna/go-store/internal/storecredit/repository"
+	storeCreditUseCase "github.com/fekuna/go-store/internal/storecredit/usecase"
+	subscriptionHttp "github.com/fekuna/go-store/internal/subscription/delivery/http"
+	subscriptionRepository "github.com/fekuna/go-store/internal/subscription/repository"
+	subscriptionUseCase "github.com/fekuna/go-store/internal/subscription/usecase"
+	taxHttp "github.com/fekuna/go-store/internal/tax/delivery/http"
+	taxRepository "github.com/fekuna/go-store/internal/tax/repository"
+	taxUseCase "github.com/fekuna/go-store/internal/tax/usecase"
+	wishlistHttp "github.com/fekuna/go-store/internal/wishlist/delivery/http"
+	wishlistRepository "github.com/fekuna/go-store/internal/wishlist/repository"
+	wishlistUseCase "github.com/fekuna/go-store/internal/wishlist/usecase"
+	"github.com/fekuna/go-store/pkg/carrier"
+	"github.com/fekuna/go-store/pkg/mailer"
+	"github.com/fekuna/go-store/pkg/payment"
 )
 
-func (s *Server) MapHandlers(e *echo.Echo) error {
+func (s *Server) MapHandlers(ctx context.Context, e *echo.Echo) error {
 	// Init Repository
 	authRepo := authRepository.NewAuthRepository(s.db)
 	sessRepo := sessRepository.NewSessionRepository(s.db)
 	authMinioRepo := authRepository.NewAuthMinioRepository(s.minioClient)
+	productRepo := productRepository.NewProductRepository(s.db)
+	categoryRepo := categoryRepository.NewCategoryRepository(s.db)
+	inventoryRepo := inventoryRepository.NewInventoryRepository(s.db)
+	searchRepo := searchRepository.NewSearchRepository(s.db)
+	catalogRepo := catalogRepository.NewCatalogRepository(s.db)
+	catalogMinioRepo := catalogRepository.NewCatalogMinioRepository(s.minioClient)
+	attributeRepo := attributeRepository.NewAttributeRepository(s.db)
+	reviewRepo := reviewRepository.NewReviewRepository(s.db)
+	wishlistRepo := wishlistRepository.NewWishlistRepository(s.db)
+	cartRepo := cartRepository.NewCartRepository(s.db)
+	orderRepo := orderRepository.NewOrderRepository(s.db)
+	checkoutRepo := checkoutRepository.NewCheckoutRepository(s.db)
+	paymentRepo := paymentRepository.NewPaymentRepository(s.db)
+	promotionRepo := promotionRepository.NewPromotionRepository(s.db)
+	taxRepo := taxRepository.NewTaxRepository(s.db)
+	shippingRepo := shippingRepository.NewShippingRepository(s.db)
+	addressRepo := addressRepository.NewAddressRepository(s.db)
+	returnRepo := returnRepository.NewReturnRepository(s.db)
+	invoiceRepo := invoiceRepository.NewInvoiceRepository(s.db)
+	invoiceMinioRepo := invoiceRepository.NewInvoiceMinioRepository(s.minioClient)
+	giftCardRepo := giftCardRepository.NewGiftCardRepository(s.db)
+	storeCreditRepo := storeCreditRepository.NewStoreCreditRepository(s.db)
+	subscriptionRepo := subscriptionRepository.NewSubscriptionRepository(s.db)
+	cartRecoveryRepo := cartRecoveryRepository.NewCartRecoveryRepository(s.db)
+	idempotencyRepo := idempotencyRepository.NewIdempotencyRepository(s.db)
+
+	paymentGateway, err := payment.NewGateway(s.cfg)
+	if err != nil {
+		return err
+	}
+
+	shippingCarrier, err := carrier.NewCarrier(s.cfg)
+	if err != nil {
+		return err
+	}
+
+	mail, err := mailer.NewMailer(s.cfg, s.logger)
+	if err != nil {
+		return err
+	}
 
 	// Init useCase
-	authUC := authUC.NewAuthUseCase(s.cfg, s.logger, authRepo, authMinioRepo)
 	sessUC := sessUC.NewSessionUseCase(s.cfg, s.logger, sessRepo)
+	productUC := productUseCase.NewProductUseCase(s.cfg, s.logger, productRepo)
+	categoryUC := categoryUseCase.NewCategoryUseCase(s.cfg, s.logger, categoryRepo)
+	inventoryUC := inventoryUseCase.NewInventoryUseCase(s.cfg, s.logger, inventoryRepo)
+	searchUC := searchUseCase.NewSearchUseCase(s.cfg, s.logger, searchRepo)
+	catalogUC := catalogUseCase.NewCatalogUseCase(s.cfg, s.logger, catalogRepo, productRepo, catalogMinioRepo)
+	attributeUC := attributeUseCase.NewAttributeUseCase(s.cfg, s.logger, attributeRepo)
+	reviewUC := reviewUseCase.NewReviewUseCase(s.cfg, s.logger, reviewRepo, productRepo, orderRepo)
+	cartUC := cartUseCase.NewCartUseCase(s.cfg, s.logger, cartRepo, productRepo)
+	authUC := authUC.NewAuthUseCase(s.cfg, s.logger, authRepo, authMinioRepo, cartUC)
+	orderUC := orderUseCase.NewOrderUseCase(s.cfg, s.logger, orderRepo)
+	taxUC := taxUseCase.NewTaxUseCase(s.cfg, s.logger, taxRepo)
+	promotionUC := promotionUseCase.NewPromotionUseCase(s.cfg, s.logger, promotionRepo, cartUC)
+	shippingUC := shippingUseCase.NewShippingUseCase(s.cfg, s.logger, shippingRepo, cartUC, shippingCarrier)
+	checkoutUC := checkoutUseCase.NewCheckoutUseCase(s.cfg, s.logger, checkoutRepo, cartRepo, orderRepo, promotionUC, taxUC, shippingUC, addressRepo, giftCardRepo, storeCreditRepo)
+	paymentUC := paymentUseCase.NewPaymentUseCase(s.cfg, s.logger, paymentRepo, orderRepo, orderUC, paymentGateway)
+	giftCardUC := giftCardUseCase.NewGiftCardUseCase(s.cfg, s.logger, giftCardRepo)
+	storeCreditUC := storeCreditUseCase.NewStoreCreditUseCase(s.cfg, s.logger, storeCreditRepo)
+	returnUC := returnUseCase.NewReturnUseCase(s.cfg, s.logger, returnRepo, orderRepo, orderUC, inventoryUC, paymentUC, storeCreditUC)
+	subscriptionUC := subscriptionUseCase.NewSubscriptionUseCase(s.cfg, s.logger, subscriptionRepo, addressRepo, orderRepo, checkoutUC, paymentUC, mail)
+	cartRecoveryUC := cartRecoveryUseCase.NewCartRecoveryUseCase(s.cfg, s.logger, cartRecoveryRepo, cartRepo, cartUC, mail)
+	invoiceUC := invoiceUseCase.NewInvoiceUseCase(s.cfg, s.logger, invoiceRepo, invoiceMinioRepo, orderRepo, orderUC, returnRepo, addressRepo)
+	idempotencyUC := idempotencyUseCase.NewIdempotencyUseCase(s.cfg, s.logger, idempotencyRepo)
+	addressUC := addressUseCase.NewAddressUseCase(s.cfg, s.logger, addressRepo)
+	wishlistUC := wishlistUseCase.NewWishlistUseCase(s.cfg, s.logger, wishlistRepo, productRepo, cartUC)
 
 	// Init handlers
 	authHandlers := authHttp.NewAuthHandlers(s.cfg, s.logger, authUC, sessUC)
+	productHandlers := productHttp.NewProductHandlers(s.cfg, s.logger, productUC)
+	categoryHandlers := categoryHttp.NewCategoryHandlers(s.cfg, s.logger, categoryUC)
+	inventoryHandlers := inventoryHttp.NewInventoryHandlers(s.cfg, s.logger, inventoryUC)
+	searchHandlers := searchHttp.NewSearchHandlers(s.cfg, s.logger, searchUC)
+	catalogHandlers := catalogHttp.NewCatalogHandlers(s.cfg, s.logger, catalogUC)
+	attributeHandlers := attributeHttp.NewAttributeHandlers(s.cfg, s.logger, attributeUC)
+	reviewHandlers := reviewHttp.NewReviewHandlers(s.cfg, s.logger, reviewUC)
+	wishlistHandlers := wishlistHttp.NewWishlistHandlers(s.cfg, s.logger, wishlistUC)
+	cartHandlers := cartHttp.NewCartHandlers(s.cfg, s.logger, cartUC)
+	orderHandlers := orderHttp.NewOrderHandlers(s.cfg, s.logger, orderUC)
+	checkoutHandlers := checkoutHttp.NewCheckoutHandlers(s.cfg, s.logger, checkoutUC)
+	paymentHandlers := paymentHttp.NewPaymentHandlers(s.cfg, s.logger, paymentUC)
+	promotionHandlers := promotionHttp.NewPromotionHandlers(s.cfg, s.logger, promotionUC)
+	taxHandlers := taxHttp.NewTaxHandlers(s.cfg, s.logger, taxUC)
+	shippingHandlers := shippingHttp.NewShippingHandlers(s.cfg, s.logger, shippingUC)
+	addressHandlers := addressHttp.NewAddressHandlers(s.cfg, s.logger, addressUC)
+	returnHandlers := returnHttp.NewReturnHandlers(s.cfg, s.logger, returnUC)
+	invoiceHandlers := invoiceHttp.NewInvoiceHandlers(s.cfg, s.logger, invoiceUC)
+	giftCardHandlers := giftCardHttp.NewGiftCardHandlers(s.cfg, s.logger, giftCardUC)
+	storeCreditHandlers := storeCreditHttp.NewStoreCreditHandlers(s.cfg, s.logger, storeCreditUC)
+	subscriptionHandlers := subscriptionHttp.NewSubscriptionHandlers(s.cfg, s.logger, subscriptionUC)
+	cartRecoveryHandlers := cartRecoveryHttp.NewCartRecoveryHandlers(s.cfg, s.logger, cartRecoveryUC)
 
-	mw := apiMiddlewares.NewMiddlewareManager(s.cfg, s.logger, sessUC, authUC)
+	mw := apiMiddlewares.NewMiddlewareManager(s.cfg, s.logger, sessUC, authUC, idempotencyUC)
 
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderXRequestID},
+		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderXRequestID, echo.HeaderAuthorization, models.CartTokenHeader, models.IdempotencyKeyHeader},
 	}))
 	e.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
 		StackSize:         1 << 10,
@@ -47,16 +207,119 @@ func (s *Server) MapHandlers(e *echo.Echo) error {
 		},
 	}))
 	e.Use(middleware.Secure())
-	e.Use(middleware.BodyLimit("2M"))
+	e.Use(middleware.BodyLimitWithConfig(middleware.BodyLimitConfig{
+		Limit: "2M",
+		// Catalog imports check their own larger limit
+		Skipper: func(c echo.Context) bool {
+			return c.Request().Method == http.MethodPost && strings.HasSuffix(c.Path(), "/catalog/imports")
+		},
+	}))
 	if s.cfg.Server.Debug {
 		e.Use(mw.DebugMiddleware)
 	}
 
 	v1 := e.Group("/api/v1")
+	v1.Use(mw.IdempotencyMiddleware)
 
 	authGroup := v1.Group("/auth")
+	addressGroup := v1.Group("/auth/me/addresses")
+	productGroup := v1.Group("/products")
+	categoryGroup := v1.Group("/categories")
+	inventoryGroup := v1.Group("/inventory")
+	searchGroup := v1.Group("/search")
+	catalogGroup := v1.Group("/catalog")
+	attributeGroup := v1.Group("/attribute-sets")
+	reviewGroup := v1.Group("/reviews")
+	wishlistGroup := v1.Group("/wishlists")
+	cartGroup := v1.Group("/cart")
+	orderGroup := v1.Group("/orders")
+	checkoutGroup := v1.Group("/checkout")
+	paymentGroup := v1.Group("/payments")
+	promotionGroup := v1.Group("/promotions")
+	taxGroup := v1.Group("/tax")
+	shippingGroup := v1.Group("/shipping")
+	returnGroup := v1.Group("/returns")
+	invoiceGroup := v1.Group("/invoices")
+	giftCardGroup := v1.Group("/gift-cards")
+	storeCreditGroup := v1.Group("/store-credit")
+	subscriptionGroup := v1.Group("/subscriptions")
+	cartRecoveryGroup := v1.Group("/cart-recovery")
 
 	authHttp.MapAuthRoutes(authGroup, authHandlers, mw)
+	addressHttp.MapAddressRoutes(addressGroup, addressHandlers, mw)
+	productHttp.MapProductRoutes(productGroup, productHandlers, mw)
+	categoryHttp.MapCategoryRoutes(categoryGroup, categoryHandlers, mw)
+	inventoryHttp.MapInventoryRoutes(inventoryGroup, inventoryHandlers, mw)
+	searchHttp.MapSearchRoutes(searchGroup, searchHandlers)
+	catalogHttp.MapCatalogRoutes(catalogGroup, catalogHandlers, mw)
+	attributeHttp.MapAttributeRoutes(attributeGroup, productGroup, attributeHandlers, mw)
+	reviewHttp.MapReviewRoutes(reviewGroup, productGroup, reviewHandlers, mw)
+	wishlistHttp.MapWishlistRoutes(wishlistGroup, wishlistHandlers, mw)
+	cartHttp.MapCartRoutes(cartGroup, cartHandlers, mw)
+	orderHttp.MapOrderRoutes(orderGroup, orderHandlers, mw)
+	checkoutHttp.MapCheckoutRoutes(checkoutGroup, checkoutHandlers, mw)
+	paymentHttp.MapPaymentRoutes(paymentGroup, orderGroup, paymentHandlers, mw)
+	promotionHttp.MapPromotionRoutes(promotionGroup, promotionHandlers, mw)
+	taxHttp.MapTaxRoutes(taxGroup, taxHandlers, mw)
+	shippingHttp.MapShippingRoutes(shippingGroup, shippingHandlers, mw)
+	returnHttp.MapReturnRoutes(returnGroup, returnHandlers, mw)
+	invoiceHttp.MapInvoiceRoutes(invoiceGroup, invoiceHandlers, mw)
+	giftCardHttp.MapGiftCardRoutes(giftCardGroup, giftCardHandlers, mw)
+	storeCreditHttp.MapStoreCreditRoutes(storeCreditGroup, storeCreditHandlers, mw)
+	subscriptionHttp.MapSubscriptionRoutes(subscriptionGroup, subscriptionHandlers, mw)
+	cartRecoveryHttp.MapCartRecoveryRoutes(cartRecoveryGroup, cartRecoveryHandlers, mw)
+
+	// Background jobs
+	s.runPeriodic(ctx, "cart expiration", time.Second*s.cfg.Cart.CleanupInterval, func(ctx context.Context) error {
+		deleted, err := cartUC.DeleteExpired(ctx)
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			s.logger.Infof("deleted %d expired carts", deleted)
+		}
+		return nil
+	})
+	s.runPeriodic(ctx, "reservation expiration", time.Second*s.cfg.Checkout.ExpiryInterval, func(ctx context.Context) error {
+		expired, err := checkoutUC.ExpireReservations(ctx)
+		if err != nil {
+			return err
+		}
+		if expired > 0 {
+			s.logger.Infof("cancelled %d orders with expired reservations", expired)
+		}
+		return nil
+	})
+	s.runPeriodic(ctx, "idempotency key expiration", time.Second*s.cfg.Idempotency.CleanupInterval, func(ctx context.Context) error {
+		deleted, err := idempotencyUC.DeleteExpired(ctx)
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			s.logger.Infof("deleted %d expired idempotency keys", deleted)
+		}
+		return nil
+	})
+	s.runPeriodic(ctx, "subscription orders", time.Second*s.cfg.Subscription.RunInterval, func(ctx context.Context) error {
+		ran, err := subscriptionUC.RunDue(ctx)
+		if err != nil {
+			return err
+		}
+		if ran > 0 {
+			s.logger.Infof("ran %d due subscriptions", ran)
+		}
+		return nil
+	})
+	s.runPeriodic(ctx, "cart recovery reminders", time.Second*s.cfg.CartRecovery.RunInterval, func(ctx context.Context) error {
+		sent, err := cartRecoveryUC.RunDue(ctx)
+		if err != nil {
+			return err
+		}
+		if sent > 0 {
+			s.logger.Infof("sent %d abandoned cart reminders", sent)
+		}
+		return nil
+	})
 
 	return nil
 }
@@ -56,7 +56,10 @@ func (s *Server) Run() error {
 		}
 	}()
 
-	if err := s.MapHandlers(s.echo); err != nil {
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	if err := s.MapHandlers(workersCtx, s.echo); err != nil {
 		return err
 	}
 
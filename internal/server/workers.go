@@ -0,0 +1,29 @@
+package server
+
+import (
+	"context"
+	"time"
+)
+
+// Run fn every interval until ctx is done, a job without a positive interval is disabled
+func (s *Server) runPeriodic(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	if interval <= 0 {
+		s.logger.Warnf("worker %s: disabled, interval is %v", name, interval)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := fn(ctx); err != nil {
+					s.logger.Errorf("worker %s: %v", name, err)
+				}
+			}
+		}
+	}()
+}
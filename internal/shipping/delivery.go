@@ -0,0 +1,16 @@
+package shipping
+
+import "github.com/labstack/echo/v4"
+
+// Shipping HTTP Handlers interface
+type Handlers interface {
+	CreateZone() echo.HandlerFunc
+	UpdateZone() echo.HandlerFunc
+	DeleteZone() echo.HandlerFunc
+	GetZone() echo.HandlerFunc
+	ListZones() echo.HandlerFunc
+	CreateMethod() echo.HandlerFunc
+	UpdateMethod() echo.HandlerFunc
+	DeleteMethod() echo.HandlerFunc
+	Quote() echo.HandlerFunc
+}
@@ -0,0 +1,293 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/shipping"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Shipping handlers
+type shippingHandlers struct {
+	cfg        *config.Config
+	logger     logger.Logger
+	shippingUC shipping.UseCase
+}
+
+// Shipping handlers constructor
+func NewShippingHandlers(cfg *config.Config, logger logger.Logger, shippingUC shipping.UseCase) shipping.Handlers {
+	return &shippingHandlers{
+		cfg:        cfg,
+		logger:     logger,
+		shippingUC: shippingUC,
+	}
+}
+
+// CreateZone godoc
+// @Summary Create shipping zone
+// @Description create shipping zone of a country, optionally narrowed to region and postcode pattern, admin only
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.ShippingZone
+// @Failure 400 {object} httpErrors.RestError
+// @Router /shipping/zones [post]
+func (h *shippingHandlers) CreateZone() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		zone := &models.ShippingZone{}
+		if err := utils.ReadRequest(c, zone); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		createdZone, err := h.shippingUC.CreateZone(ctx, zone)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, createdZone)
+	}
+}
+
+// UpdateZone godoc
+// @Summary Update shipping zone
+// @Description replace shipping zone settings, admin only
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param zone_id path string true "zone_id"
+// @Success 200 {object} models.ShippingZone
+// @Failure 400 {object} httpErrors.RestError
+// @Router /shipping/zones/{zone_id} [put]
+func (h *shippingHandlers) UpdateZone() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		zoneID, err := uuid.Parse(c.Param("zone_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		zone := &models.ShippingZone{}
+		if err = utils.ReadRequest(c, zone); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		zone.ZoneID = zoneID
+
+		updatedZone, err := h.shippingUC.UpdateZone(ctx, zone)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, updatedZone)
+	}
+}
+
+// DeleteZone godoc
+// @Summary Delete shipping zone
+// @Description delete shipping zone with its methods, admin only
+// @Tags Shipping
+// @Param zone_id path string true "zone_id"
+// @Success 204
+// @Failure 404 {object} httpErrors.RestError
+// @Router /shipping/zones/{zone_id} [delete]
+func (h *shippingHandlers) DeleteZone() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		zoneID, err := uuid.Parse(c.Param("zone_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.shippingUC.DeleteZone(ctx, zoneID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetZone godoc
+// @Summary Get shipping zone
+// @Description get shipping zone with its methods, admin only
+// @Tags Shipping
+// @Produce json
+// @Param zone_id path string true "zone_id"
+// @Success 200 {object} models.ShippingZone
+// @Failure 404 {object} httpErrors.RestError
+// @Router /shipping/zones/{zone_id} [get]
+func (h *shippingHandlers) GetZone() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		zoneID, err := uuid.Parse(c.Param("zone_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		zone, err := h.shippingUC.GetZone(ctx, zoneID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, zone)
+	}
+}
+
+// ListZones godoc
+// @Summary Get shipping zones
+// @Description get all shipping zones by country and priority, admin only
+// @Tags Shipping
+// @Produce json
+// @Success 200 {array} models.ShippingZone
+// @Failure 500 {object} httpErrors.RestError
+// @Router /shipping/zones [get]
+func (h *shippingHandlers) ListZones() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		zones, err := h.shippingUC.ListZones(ctx)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, zones)
+	}
+}
+
+// CreateMethod godoc
+// @Summary Create shipping method
+// @Description create flat, weight, free_over, pickup or carrier method in zone, admin only
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param zone_id path string true "zone_id"
+// @Success 201 {object} models.ShippingMethod
+// @Failure 400 {object} httpErrors.RestError
+// @Router /shipping/zones/{zone_id}/methods [post]
+func (h *shippingHandlers) CreateMethod() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		zoneID, err := uuid.Parse(c.Param("zone_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		method := &models.ShippingMethod{}
+		if err = utils.ReadRequest(c, method); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		method.ZoneID = zoneID
+
+		createdMethod, err := h.shippingUC.CreateMethod(ctx, method)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, createdMethod)
+	}
+}
+
+// UpdateMethod godoc
+// @Summary Update shipping method
+// @Description replace shipping method settings, admin only
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param method_id path string true "method_id"
+// @Success 200 {object} models.ShippingMethod
+// @Failure 400 {object} httpErrors.RestError
+// @Router /shipping/methods/{method_id} [put]
+func (h *shippingHandlers) UpdateMethod() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		methodID, err := uuid.Parse(c.Param("method_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		method := &models.ShippingMethod{}
+		if err = utils.ReadRequest(c, method); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		method.MethodID = methodID
+
+		updatedMethod, err := h.shippingUC.UpdateMethod(ctx, method)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, updatedMethod)
+	}
+}
+
+// DeleteMethod godoc
+// @Summary Delete shipping method
+// @Description delete shipping method, orders keep its name and price, admin only
+// @Tags Shipping
+// @Param method_id path string true "method_id"
+// @Success 204
+// @Failure 404 {object} httpErrors.RestError
+// @Router /shipping/methods/{method_id} [delete]
+func (h *shippingHandlers) DeleteMethod() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		methodID, err := uuid.Parse(c.Param("method_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.shippingUC.DeleteMethod(ctx, methodID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// Quote godoc
+// @Summary Quote shipping
+// @Description shipping rates of the current cart to the address, cheapest first, guests pass X-Cart-Token
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param X-Cart-Token header string false "guest cart token"
+// @Success 200 {object} models.ShippingQuote
+// @Failure 400 {object} httpErrors.RestError
+// @Router /shipping/quote [post]
+func (h *shippingHandlers) Quote() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		input := &models.ShippingQuoteInput{}
+		if err := utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		quote, err := h.shippingUC.QuoteCart(ctx, c.Request().Header.Get(models.CartTokenHeader), &input.Address)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, quote)
+	}
+}
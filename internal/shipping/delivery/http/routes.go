@@ -0,0 +1,22 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/shipping"
+	"github.com/labstack/echo/v4"
+)
+
+func MapShippingRoutes(shippingGroup *echo.Group, h shipping.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	shippingGroup.POST("/quote", h.Quote(), mw.OptionalAuthMiddleware)
+	shippingGroup.GET("/zones", h.ListZones(), adminOnly...)
+	shippingGroup.POST("/zones", h.CreateZone(), adminOnly...)
+	shippingGroup.GET("/zones/:zone_id", h.GetZone(), adminOnly...)
+	shippingGroup.PUT("/zones/:zone_id", h.UpdateZone(), adminOnly...)
+	shippingGroup.DELETE("/zones/:zone_id", h.DeleteZone(), adminOnly...)
+	shippingGroup.POST("/zones/:zone_id/methods", h.CreateMethod(), adminOnly...)
+	shippingGroup.PUT("/methods/:method_id", h.UpdateMethod(), adminOnly...)
+	shippingGroup.DELETE("/methods/:method_id", h.DeleteMethod(), adminOnly...)
+}
@@ -0,0 +1,22 @@
+package shipping
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Shipping repository
+type Repository interface {
+	CreateZone(ctx context.Context, zone *models.ShippingZone) (*models.ShippingZone, error)
+	UpdateZone(ctx context.Context, zone *models.ShippingZone) (*models.ShippingZone, error)
+	DeleteZone(ctx context.Context, zoneID uuid.UUID) error
+	GetZone(ctx context.Context, zoneID uuid.UUID) (*models.ShippingZone, error)
+	ListZones(ctx context.Context) ([]*models.ShippingZone, error)
+	ListZonesByCountry(ctx context.Context, country string) ([]*models.ShippingZone, error)
+	CreateMethod(ctx context.Context, method *models.ShippingMethod) (*models.ShippingMethod, error)
+	UpdateMethod(ctx context.Context, method *models.ShippingMethod) (*models.ShippingMethod, error)
+	DeleteMethod(ctx context.Context, methodID uuid.UUID) error
+	GetMethods(ctx context.Context, zoneID uuid.UUID) ([]*models.ShippingMethod, error)
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/shipping"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Shipping repository
+type shippingRepo struct {
+	db *sqlx.DB
+}
+
+// Shipping repository constructor
+func NewShippingRepository(db *sqlx.DB) shipping.Repository {
+	return &shippingRepo{db: db}
+}
+
+func (r *shippingRepo) CreateZone(ctx context.Context, z *models.ShippingZone) (*models.ShippingZone, error) {
+	// TODO: Tracing
+
+	created := &models.ShippingZone{}
+	if err := r.db.QueryRowxContext(
+		ctx, createZoneQuery, &z.Name, &z.Country, z.Region, z.PostcodePattern, &z.Priority,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "shippingRepo.CreateZone.StructScan")
+	}
+
+	return created, nil
+}
+
+func (r *shippingRepo) UpdateZone(ctx context.Context, z *models.ShippingZone) (*models.ShippingZone, error) {
+	// TODO: Tracing
+
+	updated := &models.ShippingZone{}
+	if err := r.db.GetContext(
+		ctx, updated, updateZoneQuery, &z.Name, &z.Country, z.Region, z.PostcodePattern, &z.Priority, &z.ZoneID,
+	); err != nil {
+		return nil, errors.Wrap(err, "shippingRepo.UpdateZone.GetContext")
+	}
+
+	return updated, nil
+}
+
+// Delete zone with its methods
+func (r *shippingRepo) DeleteZone(ctx context.Context, zoneID uuid.UUID) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, deleteZoneQuery, zoneID)
+	if err != nil {
+		return errors.Wrap(err, "shippingRepo.DeleteZone.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "shippingRepo.DeleteZone.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "shippingRepo.DeleteZone.rowsAffected")
+	}
+
+	return nil
+}
+
+func (r *shippingRepo) GetZone(ctx context.Context, zoneID uuid.UUID) (*models.ShippingZone, error) {
+	// TODO: Tracing
+
+	z := &models.ShippingZone{}
+	if err := r.db.GetContext(ctx, z, getZoneByIdQuery, zoneID); err != nil {
+		return nil, errors.Wrap(err, "shippingRepo.GetZone.GetContext")
+	}
+
+	return z, nil
+}
+
+func (r *shippingRepo) ListZones(ctx context.Context) ([]*models.ShippingZone, error) {
+	// TODO: Tracing
+
+	zones := make([]*models.ShippingZone, 0)
+	if err := r.db.SelectContext(ctx, &zones, listZonesQuery); err != nil {
+		return nil, errors.Wrap(err, "shippingRepo.ListZones.SelectContext")
+	}
+
+	return zones, nil
+}
+
+// Zones of country compared case insensitively, highest priority first
+func (r *shippingRepo) ListZonesByCountry(ctx context.Context, country string) ([]*models.ShippingZone, error) {
+	// TODO: Tracing
+
+	zones := make([]*models.ShippingZone, 0)
+	if err := r.db.SelectContext(ctx, &zones, listZonesByCountryQuery, country); err != nil {
+		return nil, errors.Wrap(err, "shippingRepo.ListZonesByCountry.SelectContext")
+	}
+
+	return zones, nil
+}
+
+func (r *shippingRepo) CreateMethod(ctx context.Context, m *models.ShippingMethod) (*models.ShippingMethod, error) {
+	// TODO: Tracing
+
+	created := &models.ShippingMethod{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		createMethodQuery,
+		&m.ZoneID,
+		&m.Name,
+		&m.Kind,
+		&m.Price,
+		&m.Currency,
+		m.FreeOver,
+		m.WeightRates,
+		m.CarrierService,
+		m.EstimatedDays,
+		&m.Active,
+		&m.Position,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "shippingRepo.CreateMethod.StructScan")
+	}
+
+	return created, nil
+}
+
+func (r *shippingRepo) UpdateMethod(ctx context.Context, m *models.ShippingMethod) (*models.ShippingMethod, error) {
+	// TODO: Tracing
+
+	updated := &models.ShippingMethod{}
+	if err := r.db.GetContext(
+		ctx,
+		updated,
+		updateMethodQuery,
+		&m.Name,
+		&m.Kind,
+		&m.Price,
+		&m.Currency,
+		m.FreeOver,
+		m.WeightRates,
+		m.CarrierService,
+		m.EstimatedDays,
+		&m.Active,
+		&m.Position,
+		&m.MethodID,
+	); err != nil {
+		return nil, errors.Wrap(err, "shippingRepo.UpdateMethod.GetContext")
+	}
+
+	return updated, nil
+}
+
+func (r *shippingRepo) DeleteMethod(ctx context.Context, methodID uuid.UUID) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, deleteMethodQuery, methodID)
+	if err != nil {
+		return errors.Wrap(err, "shippingRepo.DeleteMethod.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "shippingRepo.DeleteMethod.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "shippingRepo.DeleteMethod.rowsAffected")
+	}
+
+	return nil
+}
+
+// Methods of zone by position, inactive ones included
+func (r *shippingRepo) GetMethods(ctx context.Context, zoneID uuid.UUID) ([]*models.ShippingMethod, error) {
+	// TODO: Tracing
+
+	methods := make([]*models.ShippingMethod, 0)
+	if err := r.db.SelectContext(ctx, &methods, getMethodsQuery, zoneID); err != nil {
+		return nil, errors.Wrap(err, "shippingRepo.GetMethods.SelectContext")
+	}
+
+	return methods, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+const (
+	createZoneQuery = `
+		INSERT INTO shipping_zones(name, country, region, postcode_pattern, priority, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, now(), now())
+		RETURNING *
+	`
+
+	updateZoneQuery = `
+		UPDATE shipping_zones
+		SET name = $1, country = $2, region = $3, postcode_pattern = $4, priority = $5, updated_at = now()
+		WHERE zone_id = $6
+		RETURNING *
+	`
+
+	deleteZoneQuery = `DELETE FROM shipping_zones WHERE zone_id = $1`
+
+	getZoneByIdQuery = `SELECT * FROM shipping_zones WHERE zone_id = $1`
+
+	listZonesQuery = `SELECT * FROM shipping_zones ORDER BY country, priority DESC, name`
+
+	listZonesByCountryQuery = `SELECT * FROM shipping_zones WHERE lower(country) = lower($1) ORDER BY priority DESC, zone_id`
+
+	createMethodQuery = `
+		INSERT INTO shipping_methods(zone_id, name, kind, price, currency, free_over, weight_rates, carrier_service,
+			estimated_days, active, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, now(), now())
+		RETURNING *
+	`
+
+	updateMethodQuery = `
+		UPDATE shipping_methods
+		SET name = $1, kind = $2, price = $3, currency = $4, free_over = $5, weight_rates = $6, carrier_service = $7,
+			estimated_days = $8, active = $9, position = $10, updated_at = now()
+		WHERE method_id = $11
+		RETURNING *
+	`
+
+	deleteMethodQuery = `DELETE FROM shipping_methods WHERE method_id = $1`
+
+	getMethodsQuery = `SELECT * FROM shipping_methods WHERE zone_id = $1 ORDER BY position, name`
+)
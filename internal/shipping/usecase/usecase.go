@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/cart"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/shipping"
+	"github.com/fekuna/go-store/pkg/carrier"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Shipping UseCase
+type shippingUC struct {
+	cfg          *config.Config
+	logger       logger.Logger
+	shippingRepo shipping.Repository
+	cartUC       cart.UseCase
+	carrier      carrier.Carrier
+}
+
+// Shipping UseCase constructor, carrier is nil when live rates are disabled
+func NewShippingUseCase(cfg *config.Config, logger logger.Logger, shippingRepo shipping.Repository, cartUC cart.UseCase, carrier carrier.Carrier) shipping.UseCase {
+	return &shippingUC{
+		cfg:          cfg,
+		logger:       logger,
+		shippingRepo: shippingRepo,
+		cartUC:       cartUC,
+		carrier:      carrier,
+	}
+}
+
+func (u *shippingUC) CreateZone(ctx context.Context, z *models.ShippingZone) (*models.ShippingZone, error) {
+	// TODO: Tracing
+
+	z.Prepare()
+	if z.PostcodePattern != nil && !models.ValidPostcodePattern(*z.PostcodePattern) {
+		return nil, httpErrors.NewBadRequestError(errors.New("shippingUC.CreateZone: invalid postcode pattern"))
+	}
+
+	return u.shippingRepo.CreateZone(ctx, z)
+}
+
+func (u *shippingUC) UpdateZone(ctx context.Context, z *models.ShippingZone) (*models.ShippingZone, error) {
+	// TODO: Tracing
+
+	z.Prepare()
+	if z.PostcodePattern != nil && !models.ValidPostcodePattern(*z.PostcodePattern) {
+		return nil, httpErrors.NewBadRequestError(errors.New("shippingUC.UpdateZone: invalid postcode pattern"))
+	}
+
+	return u.shippingRepo.UpdateZone(ctx, z)
+}
+
+func (u *shippingUC) DeleteZone(ctx context.Context, zoneID uuid.UUID) error {
+	// TODO: Tracing
+
+	return u.shippingRepo.DeleteZone(ctx, zoneID)
+}
+
+// Get zone with all its methods
+func (u *shippingUC) GetZone(ctx context.Context, zoneID uuid.UUID) (*models.ShippingZone, error) {
+	// TODO: Tracing
+
+	z, err := u.shippingRepo.GetZone(ctx, zoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	z.Methods, err = u.shippingRepo.GetMethods(ctx, zoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
+
+func (u *shippingUC) ListZones(ctx context.Context) ([]*models.ShippingZone, error) {
+	// TODO: Tracing
+
+	return u.shippingRepo.ListZones(ctx)
+}
+
+func (u *shippingUC) CreateMethod(ctx context.Context, m *models.ShippingMethod) (*models.ShippingMethod, error) {
+	// TODO: Tracing
+
+	if _, err := u.shippingRepo.GetZone(ctx, m.ZoneID); err != nil {
+		return nil, err
+	}
+
+	m.Prepare()
+	if err := validateMethod(m); err != nil {
+		return nil, err
+	}
+
+	return u.shippingRepo.CreateMethod(ctx, m)
+}
+
+// Replace method settings, the zone of a method doesn't change
+func (u *shippingUC) UpdateMethod(ctx context.Context, m *models.ShippingMethod) (*models.ShippingMethod, error) {
+	// TODO: Tracing
+
+	m.Prepare()
+	if err := validateMethod(m); err != nil {
+		return nil, err
+	}
+
+	return u.shippingRepo.UpdateMethod(ctx, m)
+}
+
+func (u *shippingUC) DeleteMethod(ctx context.Context, methodID uuid.UUID) error {
+	// TODO: Tracing
+
+	return u.shippingRepo.DeleteMethod(ctx, methodID)
+}
+
+// Rates of the active methods of the zone matching the address, cheapest first. Methods in another
+// currency than the cart and parcels heavier than every weight rate are left out.
+func (u *shippingUC) Quote(ctx context.Context, c *models.Cart, address *models.ShippingAddress) (*models.ShippingQuote, error) {
+	// TODO: Tracing
+
+	if c.Totals == nil {
+		c.CalculateTotals()
+	}
+
+	quote := &models.ShippingQuote{Currency: c.Totals.Currency, Rates: make([]*models.ShippingRate, 0)}
+	for _, item := range c.Items {
+		if item.Available {
+			quote.WeightGrams += item.WeightGrams * item.Quantity
+		}
+	}
+
+	if c.Totals.ItemCount == 0 || strings.TrimSpace(address.Country) == "" {
+		return quote, nil
+	}
+
+	zones, err := u.shippingRepo.ListZonesByCountry(ctx, strings.TrimSpace(address.Country))
+	if err != nil {
+		return nil, err
+	}
+
+	zone := matchZone(zones, address)
+	if zone == nil {
+		return quote, nil
+	}
+	quote.ZoneID = &zone.ZoneID
+
+	methods, err := u.shippingRepo.GetMethods(ctx, zone.ZoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	var liveRates []*carrier.Rate
+	liveQuoted := false
+	for _, m := range methods {
+		if !m.Active || m.Currency != quote.Currency {
+			continue
+		}
+
+		rate := &models.ShippingRate{
+			MethodID:      m.MethodID,
+			Name:          m.Name,
+			Kind:          m.Kind,
+			Price:         m.Price,
+			Currency:      m.Currency,
+			EstimatedDays: m.EstimatedDays,
+		}
+
+		switch m.Kind {
+		case models.ShippingKindWeight:
+			price, ok := weightPrice(m.WeightRates, quote.WeightGrams)
+			if !ok {
+				continue
+			}
+			rate.Price = price
+		case models.ShippingKindFreeOver:
+			if m.FreeOver != nil && c.Totals.Subtotal >= *m.FreeOver {
+				rate.Price = 0
+			}
+		case models.ShippingKindCarrier:
+			if !liveQuoted {
+				liveRates = u.liveRates(ctx, address, quote, c.Totals.Subtotal)
+				liveQuoted = true
+			}
+			live := findService(liveRates, m.CarrierService)
+			if live == nil {
+				continue
+			}
+			rate.Price = live.Price
+			if rate.EstimatedDays == nil {
+				days := live.EstimatedDays
+				rate.EstimatedDays = &days
+			}
+		}
+
+		quote.Rates = append(quote.Rates, rate)
+	}
+
+	sort.SliceStable(quote.Rates, func(i, j int) bool { return quote.Rates[i].Price < quote.Rates[j].Price })
+
+	return quote, nil
+}
+
+// Quote the current cart, the one of the user in context or the guest cart of token
+func (u *shippingUC) QuoteCart(ctx context.Context, token string, address *models.ShippingAddress) (*models.ShippingQuote, error) {
+	// TODO: Tracing
+
+	c, err := u.cartUC.Get(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.Quote(ctx, c, address)
+}
+
+// Carrier rates of the parcel, a failing carrier only hides its methods
+func (u *shippingUC) liveRates(ctx context.Context, address *models.ShippingAddress, quote *models.ShippingQuote, value int64) []*carrier.Rate {
+	if u.carrier == nil {
+		return nil
+	}
+
+	rates, err := u.carrier.Rates(ctx, &carrier.RateRequest{
+		Country:     address.Country,
+		Region:      address.Region,
+		Postcode:    address.Postcode,
+		WeightGrams: quote.WeightGrams,
+		Value:       value,
+		Currency:    quote.Currency,
+	})
+	if err != nil {
+		u.logger.Warnf("shippingUC.liveRates: %s: %v", u.carrier.Name(), err)
+		return nil
+	}
+
+	return rates
+}
+
+// Most specific zone matching the address, zones come ordered by priority so the first one of equal specificity wins
+func matchZone(zones []*models.ShippingZone, address *models.ShippingAddress) *models.ShippingZone {
+	var best *models.ShippingZone
+	bestScore := -1
+	for _, z := range zones {
+		score, ok := models.MatchArea(z.Region, z.PostcodePattern, address.Region, address.Postcode)
+		if ok && score > bestScore {
+			best, bestScore = z, score
+		}
+	}
+
+	return best
+}
+
+// Price of the first weight rate the parcel fits in, rates are sorted by max grams
+func weightPrice(rates models.WeightRates, grams int) (int64, bool) {
+	for _, rate := range rates {
+		if grams <= rate.MaxGrams {
+			return rate.Price, true
+		}
+	}
+	return 0, false
+}
+
+func findService(rates []*carrier.Rate, service *string) *carrier.Rate {
+	if service == nil {
+		return nil
+	}
+	for _, rate := range rates {
+		if rate.Service == *service {
+			return rate
+		}
+	}
+	return nil
+}
+
+// Check settings the kind of method depends on
+func validateMethod(m *models.ShippingMethod) error {
+	switch m.Kind {
+	case models.ShippingKindWeight:
+		if len(m.WeightRates) == 0 {
+			return httpErrors.NewBadRequestError(errors.New("shippingUC.validateMethod: weight methods need weight_rates"))
+		}
+		sort.SliceStable(m.WeightRates, func(i, j int) bool { return m.WeightRates[i].MaxGrams < m.WeightRates[j].MaxGrams })
+	case models.ShippingKindFreeOver:
+		if m.FreeOver == nil {
+			return httpErrors.NewBadRequestError(errors.New("shippingUC.validateMethod: free_over methods need free_over"))
+		}
+	case models.ShippingKindCarrier:
+		if m.CarrierService == nil {
+			return httpErrors.NewBadRequestError(errors.New("shippingUC.validateMethod: carrier methods need carrier_service"))
+		}
+	}
+
+	return nil
+}
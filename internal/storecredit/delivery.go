@@ -0,0 +1,10 @@
+package storecredit
+
+import "github.com/labstack/echo/v4"
+
+// Store credit HTTP Handlers interface
+type Handlers interface {
+	Grant() echo.HandlerFunc
+	GetMine() echo.HandlerFunc
+	GetByUser() echo.HandlerFunc
+}
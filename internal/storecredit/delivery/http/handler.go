@@ -0,0 +1,128 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/storecredit"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Store credit handlers
+type storeCreditHandlers struct {
+	cfg           *config.Config
+	logger        logger.Logger
+	storeCreditUC storecredit.UseCase
+}
+
+// Store credit handlers constructor
+func NewStoreCreditHandlers(cfg *config.Config, logger logger.Logger, storeCreditUC storecredit.UseCase) storecredit.Handlers {
+	return &storeCreditHandlers{
+		cfg:           cfg,
+		logger:        logger,
+		storeCreditUC: storeCreditUC,
+	}
+}
+
+// Grant godoc
+// @Summary Grant store credit
+// @Description grant goodwill credit to the user or adjust the balance, adjustments can't take the balance below zero, admin only
+// @Tags StoreCredit
+// @Accept json
+// @Produce json
+// @Param user_id path string true "user_id"
+// @Success 201 {object} models.StoreCreditEntry
+// @Failure 409 {object} httpErrors.RestError
+// @Router /store-credit/users/{user_id} [post]
+func (h *storeCreditHandlers) Grant() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		userID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		input := &models.StoreCreditInput{}
+		if err = utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		entry, err := h.storeCreditUC.Grant(ctx, userID, input)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, entry)
+	}
+}
+
+// GetMine godoc
+// @Summary Get my store credit
+// @Description get own store credit balances and ledger entries, newest first
+// @Tags StoreCredit
+// @Produce json
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.StoreCreditList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /store-credit/me [get]
+func (h *storeCreditHandlers) GetMine() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		list, err := h.storeCreditUC.GetMine(ctx, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, list)
+	}
+}
+
+// GetByUser godoc
+// @Summary Get user store credit
+// @Description get store credit balances and ledger entries of the user, newest first, admin only
+// @Tags StoreCredit
+// @Produce json
+// @Param user_id path string true "user_id"
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.StoreCreditList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /store-credit/users/{user_id} [get]
+func (h *storeCreditHandlers) GetByUser() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		userID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		list, err := h.storeCreditUC.GetByUser(ctx, userID, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, list)
+	}
+}
@@ -0,0 +1,16 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/storecredit"
+	"github.com/labstack/echo/v4"
+)
+
+func MapStoreCreditRoutes(storeCreditGroup *echo.Group, h storecredit.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	storeCreditGroup.GET("/me", h.GetMine(), mw.AuthJWTMiddleware)
+	storeCreditGroup.GET("/users/:user_id", h.GetByUser(), adminOnly...)
+	storeCreditGroup.POST("/users/:user_id", h.Grant(), adminOnly...)
+}
@@ -0,0 +1,17 @@
+package storecredit
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Store credit repository, the ledger is append only
+type Repository interface {
+	Append(ctx context.Context, entry *models.StoreCreditEntry) (*models.StoreCreditEntry, error)
+	Balance(ctx context.Context, userID uuid.UUID, currency string) (int64, error)
+	Balances(ctx context.Context, userID uuid.UUID) ([]*models.StoreCreditBalance, error)
+	ListEntries(ctx context.Context, userID uuid.UUID, pq *utils.PaginationQuery) (*models.StoreCreditList, error)
+}
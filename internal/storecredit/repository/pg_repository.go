@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/storecredit"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Store credit repository
+type storeCreditRepo struct {
+	db *sqlx.DB
+}
+
+// Store credit repository constructor
+func NewStoreCreditRepository(db *sqlx.DB) storecredit.Repository {
+	return &storeCreditRepo{db: db}
+}
+
+// Append entry while the user is locked, a debit larger than the balance fails with InsufficientCredit
+func (r *storeCreditRepo) Append(ctx context.Context, entry *models.StoreCreditEntry) (*models.StoreCreditEntry, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "storeCreditRepo.Append.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	var lockedID uuid.UUID
+	if err = tx.GetContext(ctx, &lockedID, lockUserQuery, entry.UserID); err != nil {
+		return nil, errors.Wrap(err, "storeCreditRepo.Append.GetContext.lockUser")
+	}
+
+	if entry.Amount < 0 {
+		var balance int64
+		if err = tx.GetContext(ctx, &balance, getBalanceQuery, entry.UserID, entry.Currency); err != nil {
+			return nil, errors.Wrap(err, "storeCreditRepo.Append.GetContext.balance")
+		}
+		if balance+entry.Amount < 0 {
+			return nil, errors.Wrapf(httpErrors.InsufficientCredit, "storeCreditRepo.Append: balance is %d", balance)
+		}
+	}
+
+	created := &models.StoreCreditEntry{}
+	if err = tx.QueryRowxContext(
+		ctx,
+		createEntryQuery,
+		&entry.UserID,
+		&entry.Currency,
+		&entry.Amount,
+		&entry.Reason,
+		entry.OrderID,
+		entry.ReturnID,
+		&entry.Note,
+		entry.ActorID,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "storeCreditRepo.Append.StructScan")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "storeCreditRepo.Append.Commit")
+	}
+
+	return created, nil
+}
+
+// Balance of the user in the currency, without lock
+func (r *storeCreditRepo) Balance(ctx context.Context, userID uuid.UUID, currency string) (int64, error) {
+	// TODO: Tracing
+
+	var balance int64
+	if err := r.db.GetContext(ctx, &balance, getBalanceQuery, userID, currency); err != nil {
+		return 0, errors.Wrap(err, "storeCreditRepo.Balance.GetContext")
+	}
+
+	return balance, nil
+}
+
+// Balances of the user per currency
+func (r *storeCreditRepo) Balances(ctx context.Context, userID uuid.UUID) ([]*models.StoreCreditBalance, error) {
+	// TODO: Tracing
+
+	balances := make([]*models.StoreCreditBalance, 0)
+	if err := r.db.SelectContext(ctx, &balances, getBalancesQuery, userID); err != nil {
+		return nil, errors.Wrap(err, "storeCreditRepo.Balances.SelectContext")
+	}
+
+	return balances, nil
+}
+
+// Ledger entries of the user, newest first
+func (r *storeCreditRepo) ListEntries(ctx context.Context, userID uuid.UUID, pq *utils.PaginationQuery) (*models.StoreCreditList, error) {
+	// TODO: Tracing
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalEntriesQuery, userID); err != nil {
+		return nil, errors.Wrap(err, "storeCreditRepo.ListEntries.GetContext.totalCount")
+	}
+
+	entries := make([]*models.StoreCreditEntry, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &entries, listEntriesQuery, userID, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "storeCreditRepo.ListEntries.SelectContext")
+		}
+	}
+
+	return &models.StoreCreditList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Entries:    entries,
+	}, nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+const (
+	// Serializes ledger writes of a user so a debit sees every earlier entry. NO KEY UPDATE doesn't block
+	// inserts elsewhere that reference the user.
+	lockUserQuery = `SELECT user_id FROM users WHERE user_id = $1 FOR NO KEY UPDATE`
+
+	getBalanceQuery = `SELECT COALESCE(SUM(amount), 0) FROM store_credit_ledger WHERE user_id = $1 AND currency = $2`
+
+	getBalancesQuery = `
+		SELECT currency, SUM(amount) AS balance
+		FROM store_credit_ledger
+		WHERE user_id = $1
+		GROUP BY currency
+		ORDER BY currency
+	`
+
+	createEntryQuery = `
+		INSERT INTO store_credit_ledger(user_id, currency, amount, reason, order_id, return_id, note, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, now())
+		RETURNING *
+	`
+
+	getTotalEntriesQuery = `SELECT COUNT(entry_id) FROM store_credit_ledger WHERE user_id = $1`
+
+	listEntriesQuery = `
+		SELECT * FROM store_credit_ledger
+		WHERE user_id = $1
+		ORDER BY created_at DESC, entry_id
+		OFFSET $2 LIMIT $3
+	`
+)
@@ -0,0 +1,17 @@
+package storecredit
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Store credit UseCase, customers see their own ledger and admins grant credit
+type UseCase interface {
+	Grant(ctx context.Context, userID uuid.UUID, input *models.StoreCreditInput) (*models.StoreCreditEntry, error)
+	Credit(ctx context.Context, entry *models.StoreCreditEntry) (*models.StoreCreditEntry, error)
+	GetMine(ctx context.Context, pq *utils.PaginationQuery) (*models.StoreCreditList, error)
+	GetByUser(ctx context.Context, userID uuid.UUID, pq *utils.PaginationQuery) (*models.StoreCreditList, error)
+}
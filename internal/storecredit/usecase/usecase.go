@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/storecredit"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Store credit UseCase
+type storeCreditUC struct {
+	cfg             *config.Config
+	logger          logger.Logger
+	storeCreditRepo storecredit.Repository
+}
+
+// Store credit UseCase constructor
+func NewStoreCreditUseCase(cfg *config.Config, logger logger.Logger, storeCreditRepo storecredit.Repository) storecredit.UseCase {
+	return &storeCreditUC{cfg: cfg, logger: logger, storeCreditRepo: storeCreditRepo}
+}
+
+// Grant goodwill credit or adjust the balance of the user, admin only
+func (u *storeCreditUC) Grant(ctx context.Context, userID uuid.UUID, input *models.StoreCreditInput) (*models.StoreCreditEntry, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Prepare()
+	if input.Reason == models.StoreCreditGoodwill && input.Amount < 0 {
+		return nil, httpErrors.NewRestErrorWithMessage(
+			http.StatusBadRequest,
+			"Goodwill credit must be positive, use an adjustment to take credit away",
+			errors.New("storeCreditUC.Grant: negative goodwill"),
+		)
+	}
+
+	return u.storeCreditRepo.Append(ctx, &models.StoreCreditEntry{
+		UserID:   userID,
+		Currency: input.Currency,
+		Amount:   input.Amount,
+		Reason:   input.Reason,
+		Note:     input.Note,
+		ActorID:  &user.UserID,
+	})
+}
+
+// Add credit for another part of the store, like a refund to store credit
+func (u *storeCreditUC) Credit(ctx context.Context, entry *models.StoreCreditEntry) (*models.StoreCreditEntry, error) {
+	// TODO: Tracing
+
+	if entry.Amount <= 0 {
+		return nil, errors.New("storeCreditUC.Credit: amount must be positive")
+	}
+	if user, err := middleware.GetUserFromCtx(ctx); err == nil && entry.ActorID == nil {
+		entry.ActorID = &user.UserID
+	}
+
+	return u.storeCreditRepo.Append(ctx, entry)
+}
+
+// Balances and ledger of the user in context
+func (u *storeCreditUC) GetMine(ctx context.Context, pq *utils.PaginationQuery) (*models.StoreCreditList, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.getList(ctx, user.UserID, pq)
+}
+
+// Balances and ledger of the user, admin only
+func (u *storeCreditUC) GetByUser(ctx context.Context, userID uuid.UUID, pq *utils.PaginationQuery) (*models.StoreCreditList, error) {
+	// TODO: Tracing
+
+	return u.getList(ctx, userID, pq)
+}
+
+func (u *storeCreditUC) getList(ctx context.Context, userID uuid.UUID, pq *utils.PaginationQuery) (*models.StoreCreditList, error) {
+	list, err := u.storeCreditRepo.ListEntries(ctx, userID, pq)
+	if err != nil {
+		return nil, err
+	}
+
+	list.Balances, err = u.storeCreditRepo.Balances(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
@@ -0,0 +1,16 @@
+package subscription
+
+import "github.com/labstack/echo/v4"
+
+// Subscription HTTP Handlers interface
+type Handlers interface {
+	Create() echo.HandlerFunc
+	GetByID() echo.HandlerFunc
+	ListMine() echo.HandlerFunc
+	List() echo.HandlerFunc
+	Update() echo.HandlerFunc
+	Pause() echo.HandlerFunc
+	Resume() echo.HandlerFunc
+	Skip() echo.HandlerFunc
+	Cancel() echo.HandlerFunc
+}
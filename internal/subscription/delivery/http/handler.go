@@ -0,0 +1,296 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/subscription"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Subscription handlers
+type subscriptionHandlers struct {
+	cfg            *config.Config
+	logger         logger.Logger
+	subscriptionUC subscription.UseCase
+}
+
+// Subscription handlers constructor
+func NewSubscriptionHandlers(cfg *config.Config, logger logger.Logger, subscriptionUC subscription.UseCase) subscription.Handlers {
+	return &subscriptionHandlers{
+		cfg:            cfg,
+		logger:         logger,
+		subscriptionUC: subscriptionUC,
+	}
+}
+
+// Create godoc
+// @Summary Subscribe
+// @Description subscribe to recurring orders of the given items, charged to a payment method saved at the payment provider
+// @Tags Subscriptions
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Subscription
+// @Failure 400 {object} httpErrors.RestError
+// @Router /subscriptions [post]
+func (h *subscriptionHandlers) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		input := &models.SubscriptionInput{}
+		if err := utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		s, err := h.subscriptionUC.Create(ctx, input)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, s)
+	}
+}
+
+// GetByID godoc
+// @Summary Get subscription by id
+// @Description get own subscription with items and latest runs, admins can get any subscription
+// @Tags Subscriptions
+// @Produce json
+// @Param subscription_id path string true "subscription_id"
+// @Success 200 {object} models.Subscription
+// @Failure 404 {object} httpErrors.RestError
+// @Router /subscriptions/{subscription_id} [get]
+func (h *subscriptionHandlers) GetByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		s, err := h.subscriptionUC.GetByID(ctx, subscriptionID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, s)
+	}
+}
+
+// ListMine godoc
+// @Summary Get my subscriptions
+// @Description get own subscriptions, newest first
+// @Tags Subscriptions
+// @Produce json
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.SubscriptionsList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /subscriptions/me [get]
+func (h *subscriptionHandlers) ListMine() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		subscriptionsList, err := h.subscriptionUC.ListMine(ctx, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, subscriptionsList)
+	}
+}
+
+// List godoc
+// @Summary Get subscriptions
+// @Description get subscriptions of every user, optionally by status, admin only
+// @Tags Subscriptions
+// @Produce json
+// @Param status query string false "subscription status"
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "number of elements per page" Format(size)
+// @Success 200 {object} models.SubscriptionsList
+// @Failure 500 {object} httpErrors.RestError
+// @Router /subscriptions [get]
+func (h *subscriptionHandlers) List() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		query := &models.SubscriptionsQuery{}
+		if err := utils.ReadRequest(c, query); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+
+		subscriptionsList, err := h.subscriptionUC.List(ctx, query, pq)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, subscriptionsList)
+	}
+}
+
+// Update godoc
+// @Summary Update subscription
+// @Description replace items, interval, delivery and payment method of own subscription, a start moves the next delivery
+// @Tags Subscriptions
+// @Accept json
+// @Produce json
+// @Param subscription_id path string true "subscription_id"
+// @Success 200 {object} models.Subscription
+// @Failure 409 {object} httpErrors.RestError
+// @Router /subscriptions/{subscription_id} [put]
+func (h *subscriptionHandlers) Update() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		input := &models.SubscriptionInput{}
+		if err = utils.ReadRequest(c, input); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		s, err := h.subscriptionUC.Update(ctx, subscriptionID, input)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, s)
+	}
+}
+
+// Pause godoc
+// @Summary Pause subscription
+// @Description stop placing orders of own subscription until it is resumed
+// @Tags Subscriptions
+// @Produce json
+// @Param subscription_id path string true "subscription_id"
+// @Success 200 {object} models.Subscription
+// @Failure 409 {object} httpErrors.RestError
+// @Router /subscriptions/{subscription_id}/pause [post]
+func (h *subscriptionHandlers) Pause() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		s, err := h.subscriptionUC.Pause(ctx, subscriptionID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, s)
+	}
+}
+
+// Resume godoc
+// @Summary Resume subscription
+// @Description resume own paused or suspended subscription, a missed delivery is placed right away
+// @Tags Subscriptions
+// @Produce json
+// @Param subscription_id path string true "subscription_id"
+// @Success 200 {object} models.Subscription
+// @Failure 409 {object} httpErrors.RestError
+// @Router /subscriptions/{subscription_id}/resume [post]
+func (h *subscriptionHandlers) Resume() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		s, err := h.subscriptionUC.Resume(ctx, subscriptionID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, s)
+	}
+}
+
+// Skip godoc
+// @Summary Skip delivery
+// @Description skip the next delivery of own subscription
+// @Tags Subscriptions
+// @Produce json
+// @Param subscription_id path string true "subscription_id"
+// @Success 200 {object} models.Subscription
+// @Failure 409 {object} httpErrors.RestError
+// @Router /subscriptions/{subscription_id}/skip [post]
+func (h *subscriptionHandlers) Skip() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		s, err := h.subscriptionUC.Skip(ctx, subscriptionID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, s)
+	}
+}
+
+// Cancel godoc
+// @Summary Cancel subscription
+// @Description cancel own subscription, placed orders are not affected
+// @Tags Subscriptions
+// @Produce json
+// @Param subscription_id path string true "subscription_id"
+// @Success 200 {object} models.Subscription
+// @Failure 409 {object} httpErrors.RestError
+// @Router /subscriptions/{subscription_id}/cancel [post]
+func (h *subscriptionHandlers) Cancel() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		s, err := h.subscriptionUC.Cancel(ctx, subscriptionID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, s)
+	}
+}
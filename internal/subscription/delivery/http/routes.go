@@ -0,0 +1,22 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/subscription"
+	"github.com/labstack/echo/v4"
+)
+
+func MapSubscriptionRoutes(subscriptionGroup *echo.Group, h subscription.Handlers, mw *middleware.MiddlewareManager) {
+	adminOnly := []echo.MiddlewareFunc{mw.AuthJWTMiddleware, mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin})}
+
+	subscriptionGroup.POST("", h.Create(), mw.AuthJWTMiddleware)
+	subscriptionGroup.GET("", h.List(), adminOnly...)
+	subscriptionGroup.GET("/me", h.ListMine(), mw.AuthJWTMiddleware)
+	subscriptionGroup.GET("/:subscription_id", h.GetByID(), mw.AuthJWTMiddleware)
+	subscriptionGroup.PUT("/:subscription_id", h.Update(), mw.AuthJWTMiddleware)
+	subscriptionGroup.POST("/:subscription_id/pause", h.Pause(), mw.AuthJWTMiddleware)
+	subscriptionGroup.POST("/:subscription_id/resume", h.Resume(), mw.AuthJWTMiddleware)
+	subscriptionGroup.POST("/:subscription_id/skip", h.Skip(), mw.AuthJWTMiddleware)
+	subscriptionGroup.POST("/:subscription_id/cancel", h.Cancel(), mw.AuthJWTMiddleware)
+}
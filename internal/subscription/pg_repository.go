@@ -0,0 +1,24 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Subscription repository
+type Repository interface {
+	Create(ctx context.Context, s *models.Subscription) (*models.Subscription, error)
+	GetByID(ctx context.Context, subscriptionID uuid.UUID) (*models.Subscription, error)
+	GetItems(ctx context.Context, subscriptionID uuid.UUID) ([]*models.SubscriptionItem, error)
+	GetRuns(ctx context.Context, subscriptionID uuid.UUID) ([]*models.SubscriptionRun, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, pq *utils.PaginationQuery) (*models.SubscriptionsList, error)
+	List(ctx context.Context, status string, pq *utils.PaginationQuery) (*models.SubscriptionsList, error)
+	Update(ctx context.Context, s *models.Subscription) (*models.Subscription, error)
+	UpdateSchedule(ctx context.Context, s *models.Subscription, from string) (*models.Subscription, error)
+	ClaimDue(ctx context.Context, now time.Time, claimUntil time.Time, limit int) ([]*models.Subscription, error)
+	CreateRun(ctx context.Context, run *models.SubscriptionRun) error
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/subscription"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Subscription repository
+type subscriptionRepo struct {
+	db *sqlx.DB
+}
+
+// Subscription repository constructor
+func NewSubscriptionRepository(db *sqlx.DB) subscription.Repository {
+	return &subscriptionRepo{db: db}
+}
+
+// Create subscription with its items
+func (r *subscriptionRepo) Create(ctx context.Context, s *models.Subscription) (*models.Subscription, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "subscriptionRepo.Create.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	created := &models.Subscription{}
+	if err = tx.QueryRowxContext(
+		ctx,
+		createSubscriptionQuery,
+		&s.UserID,
+		&s.Status,
+		&s.IntervalUnit,
+		&s.IntervalCount,
+		&s.NextRunAt,
+		s.ShippingAddressID,
+		s.ShippingMethodID,
+		s.PaymentMethodID,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "subscriptionRepo.Create.StructScan")
+	}
+
+	if created.Items, err = createItems(ctx, tx, created.SubscriptionID, s.Items); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "subscriptionRepo.Create.Commit")
+	}
+
+	return created, nil
+}
+
+func (r *subscriptionRepo) GetByID(ctx context.Context, subscriptionID uuid.UUID) (*models.Subscription, error) {
+	// TODO: Tracing
+
+	s := &models.Subscription{}
+	if err := r.db.GetContext(ctx, s, getSubscriptionByIdQuery, subscriptionID); err != nil {
+		return nil, errors.Wrap(err, "subscriptionRepo.GetByID.GetContext")
+	}
+
+	return s, nil
+}
+
+func (r *subscriptionRepo) GetItems(ctx context.Context, subscriptionID uuid.UUID) ([]*models.SubscriptionItem, error) {
+	// TODO: Tracing
+
+	items := make([]*models.SubscriptionItem, 0)
+	if err := r.db.SelectContext(ctx, &items, getSubscriptionItemsQuery, subscriptionID); err != nil {
+		return nil, errors.Wrap(err, "subscriptionRepo.GetItems.SelectContext")
+	}
+
+	return items, nil
+}
+
+// Latest runs of the subscription, newest first
+func (r *subscriptionRepo) GetRuns(ctx context.Context, subscriptionID uuid.UUID) ([]*models.SubscriptionRun, error) {
+	// TODO: Tracing
+
+	runs := make([]*models.SubscriptionRun, 0)
+	if err := r.db.SelectContext(ctx, &runs, getSubscriptionRunsQuery, subscriptionID); err != nil {
+		return nil, errors.Wrap(err, "subscriptionRepo.GetRuns.SelectContext")
+	}
+
+	return runs, nil
+}
+
+func (r *subscriptionRepo) ListByUser(ctx context.Context, userID uuid.UUID, pq *utils.PaginationQuery) (*models.SubscriptionsList, error) {
+	// TODO: Tracing
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalSubscriptionsByUserQuery, userID); err != nil {
+		return nil, errors.Wrap(err, "subscriptionRepo.ListByUser.GetContext.totalCount")
+	}
+
+	list := make([]*models.Subscription, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &list, listSubscriptionsByUserQuery, userID, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "subscriptionRepo.ListByUser.SelectContext")
+		}
+	}
+
+	return newSubscriptionsList(list, totalCount, pq), nil
+}
+
+func (r *subscriptionRepo) List(ctx context.Context, status string, pq *utils.PaginationQuery) (*models.SubscriptionsList, error) {
+	// TODO: Tracing
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalSubscriptionsQuery, status); err != nil {
+		return nil, errors.Wrap(err, "subscriptionRepo.List.GetContext.totalCount")
+	}
+
+	list := make([]*models.Subscription, 0, pq.GetSize())
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &list, listSubscriptionsQuery, status, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "subscriptionRepo.List.SelectContext")
+		}
+	}
+
+	return newSubscriptionsList(list, totalCount, pq), nil
+}
+
+// Replace the plan of a subscription that is not cancelled, its items included
+func (r *subscriptionRepo) Update(ctx context.Context, s *models.Subscription) (*models.Subscription, error) {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "subscriptionRepo.Update.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	updated := &models.Subscription{}
+	if err = tx.GetContext(
+		ctx,
+		updated,
+		updateSubscriptionQuery,
+		&s.IntervalUnit,
+		&s.IntervalCount,
+		&s.NextRunAt,
+		s.ShippingAddressID,
+		s.ShippingMethodID,
+		s.PaymentMethodID,
+		&s.SubscriptionID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrap(httpErrors.InvalidStateTransition, "subscriptionRepo.Update: subscription is cancelled")
+		}
+		return nil, errors.Wrap(err, "subscriptionRepo.Update.GetContext")
+	}
+
+	if _, err = tx.ExecContext(ctx, deleteSubscriptionItemsQuery, &s.SubscriptionID); err != nil {
+		return nil, errors.Wrap(err, "subscriptionRepo.Update.ExecContext.deleteItems")
+	}
+
+	if updated.Items, err = createItems(ctx, tx, updated.SubscriptionID, s.Items); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "subscriptionRepo.Update.Commit")
+	}
+
+	return updated, nil
+}
+
+// Save status and schedule only if the subscription still has status from, releasing its claim
+func (r *subscriptionRepo) UpdateSchedule(ctx context.Context, s *models.Subscription, from string) (*models.Subscription, error) {
+	// TODO: Tracing
+
+	updated := &models.Subscription{}
+	if err := r.db.GetContext(
+		ctx,
+		updated,
+		updateSubscriptionScheduleQuery,
+		&s.Status,
+		&s.NextRunAt,
+		s.RetryAt,
+		&s.FailedAttempts,
+		&s.LastError,
+		s.LastOrderID,
+		s.CancelledAt,
+		&s.SubscriptionID,
+		from,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "subscriptionRepo.UpdateSchedule: subscription is no longer %s", from)
+		}
+		return nil, errors.Wrap(err, "subscriptionRepo.UpdateSchedule.GetContext")
+	}
+
+	return updated, nil
+}
+
+// Claim up to limit subscriptions due at now until claimUntil, so other instances leave them alone
+func (r *subscriptionRepo) ClaimDue(ctx context.Context, now time.Time, claimUntil time.Time, limit int) ([]*models.Subscription, error) {
+	// TODO: Tracing
+
+	list := make([]*models.Subscription, 0)
+	if err := r.db.SelectContext(ctx, &list, claimDueSubscriptionsQuery, now, claimUntil, limit); err != nil {
+		return nil, errors.Wrap(err, "subscriptionRepo.ClaimDue.SelectContext")
+	}
+
+	return list, nil
+}
+
+func (r *subscriptionRepo) CreateRun(ctx context.Context, run *models.SubscriptionRun) error {
+	// TODO: Tracing
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		createSubscriptionRunQuery,
+		&run.SubscriptionID,
+		&run.ScheduledAt,
+		&run.Attempt,
+		&run.Status,
+		run.OrderID,
+		run.PaymentID,
+		&run.Error,
+	); err != nil {
+		return errors.Wrap(err, "subscriptionRepo.CreateRun.ExecContext")
+	}
+
+	return nil
+}
+
+func createItems(ctx context.Context, tx *sqlx.Tx, subscriptionID uuid.UUID, items []*models.SubscriptionItem) ([]*models.SubscriptionItem, error) {
+	created := make([]*models.SubscriptionItem, 0, len(items))
+	for _, item := range items {
+		createdItem := &models.SubscriptionItem{}
+		if err := tx.QueryRowxContext(ctx, createSubscriptionItemQuery, subscriptionID, &item.SKU, &item.Quantity).StructScan(createdItem); err != nil {
+			return nil, errors.Wrap(err, "subscriptionRepo.createItems.StructScan")
+		}
+		created = append(created, createdItem)
+	}
+
+	return created, nil
+}
+
+func newSubscriptionsList(list []*models.Subscription, totalCount int, pq *utils.PaginationQuery) *models.SubscriptionsList {
+	return &models.SubscriptionsList{
+		TotalCount:    totalCount,
+		TotalPages:    utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:          pq.GetPage(),
+		Size:          pq.GetSize(),
+		HasMore:       utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Subscriptions: list,
+	}
+}
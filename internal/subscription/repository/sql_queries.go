@@ -0,0 +1,91 @@
+package repository
+
+const (
+	createSubscriptionQuery = `
+		INSERT INTO subscriptions(user_id, status, interval_unit, interval_count, next_run_at, shipping_address_id,
+			shipping_method_id, payment_method_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, now(), now())
+		RETURNING *
+	`
+
+	createSubscriptionItemQuery = `
+		INSERT INTO subscription_items(subscription_id, sku, quantity)
+		VALUES ($1, $2, $3)
+		RETURNING *
+	`
+
+	deleteSubscriptionItemsQuery = `DELETE FROM subscription_items WHERE subscription_id = $1`
+
+	getSubscriptionByIdQuery = `
+		SELECT s.*, u.email
+		FROM subscriptions s
+		JOIN users u ON u.user_id = s.user_id
+		WHERE s.subscription_id = $1
+	`
+
+	getSubscriptionItemsQuery = `SELECT * FROM subscription_items WHERE subscription_id = $1 ORDER BY sku`
+
+	getSubscriptionRunsQuery = `
+		SELECT * FROM subscription_runs
+		WHERE subscription_id = $1
+		ORDER BY created_at DESC, run_id
+		LIMIT 50
+	`
+
+	getTotalSubscriptionsByUserQuery = `SELECT COUNT(subscription_id) FROM subscriptions WHERE user_id = $1`
+
+	listSubscriptionsByUserQuery = `
+		SELECT * FROM subscriptions
+		WHERE user_id = $1
+		ORDER BY created_at DESC, subscription_id
+		OFFSET $2 LIMIT $3
+	`
+
+	getTotalSubscriptionsQuery = `SELECT COUNT(subscription_id) FROM subscriptions WHERE ($1 = '' OR status = $1)`
+
+	listSubscriptionsQuery = `
+		SELECT * FROM subscriptions
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC, subscription_id
+		OFFSET $2 LIMIT $3
+	`
+
+	updateSubscriptionQuery = `
+		UPDATE subscriptions
+		SET interval_unit = $1, interval_count = $2, next_run_at = $3, shipping_address_id = $4, shipping_method_id = $5,
+			payment_method_id = $6, updated_at = now()
+		WHERE subscription_id = $7 AND status <> 'cancelled'
+		RETURNING *
+	`
+
+	updateSubscriptionScheduleQuery = `
+		UPDATE subscriptions
+		SET status = $1, next_run_at = $2, retry_at = $3, failed_attempts = $4, last_error = $5, last_order_id = $6,
+			cancelled_at = $7, claimed_until = NULL, updated_at = now()
+		WHERE subscription_id = $8 AND status = $9
+		RETURNING *
+	`
+
+	// Skipped rows are being claimed by another instance right now
+	claimDueSubscriptionsQuery = `
+		UPDATE subscriptions s
+		SET claimed_until = $2
+		FROM users u
+		WHERE u.user_id = s.user_id AND s.subscription_id IN (
+			SELECT subscription_id FROM subscriptions
+			WHERE status IN ('active', 'past_due') AND COALESCE(retry_at, next_run_at) <= $1
+				AND (claimed_until IS NULL OR claimed_until < $1)
+			ORDER BY COALESCE(retry_at, next_run_at)
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING s.*, u.email
+	`
+
+	// A run recorded before a crash is not recorded again when it is retried
+	createSubscriptionRunQuery = `
+		INSERT INTO subscription_runs(subscription_id, scheduled_at, attempt, status, order_id, payment_id, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, now())
+		ON CONFLICT DO NOTHING
+	`
+)
@@ -0,0 +1,23 @@
+package subscription
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Subscription UseCase, customers manage their own subscriptions and the scheduler places their orders
+type UseCase interface {
+	Create(ctx context.Context, input *models.SubscriptionInput) (*models.Subscription, error)
+	GetByID(ctx context.Context, subscriptionID uuid.UUID) (*models.Subscription, error)
+	ListMine(ctx context.Context, pq *utils.PaginationQuery) (*models.SubscriptionsList, error)
+	List(ctx context.Context, query *models.SubscriptionsQuery, pq *utils.PaginationQuery) (*models.SubscriptionsList, error)
+	Update(ctx context.Context, subscriptionID uuid.UUID, input *models.SubscriptionInput) (*models.Subscription, error)
+	Pause(ctx context.Context, subscriptionID uuid.UUID) (*models.Subscription, error)
+	Resume(ctx context.Context, subscriptionID uuid.UUID) (*models.Subscription, error)
+	Skip(ctx context.Context, subscriptionID uuid.UUID) (*models.Subscription, error)
+	Cancel(ctx context.Context, subscriptionID uuid.UUID) (*models.Subscription, error)
+	RunDue(ctx context.Context) (int, error)
+}
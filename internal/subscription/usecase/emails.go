@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/pkg/mailer"
+)
+
+const emailDateLayout = "2 Jan 2006"
+
+// Email about a failed run, either with the next retry or the suspension after the last attempt
+func dunningMessage(s *models.Subscription, run *models.SubscriptionRun, maxAttempts int) *mailer.Message {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "We couldn't complete your subscription delivery of %s: %s.\n\n",
+		run.ScheduledAt.Format(emailDateLayout), run.Error)
+
+	subject := "Your subscription payment failed"
+	if s.Status == models.SubscriptionStatusSuspended {
+		subject = "Your subscription is suspended"
+		fmt.Fprintf(b, "After %d failed attempts your subscription is suspended and no more orders will be placed. "+
+			"Update your payment method and resume the subscription to continue your deliveries.\n", s.FailedAttempts)
+	} else {
+		fmt.Fprintf(b, "We will try again on %s, %d attempts are left before the subscription is suspended. "+
+			"Update your payment method to avoid missing your delivery.\n",
+			s.RetryAt.Format(emailDateLayout), maxAttempts-s.FailedAttempts)
+	}
+	fmt.Fprintf(b, "\nSubscription: %s\n", s.SubscriptionID)
+
+	return &mailer.Message{
+		To:      []string{s.Email},
+		Subject: subject,
+		Body:    b.String(),
+	}
+}
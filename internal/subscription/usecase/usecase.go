@@ -0,0 +1,436 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/address"
+	"github.com/fekuna/go-store/internal/checkout"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/order"
+	"github.com/fekuna/go-store/internal/payment"
+	"github.com/fekuna/go-store/internal/subscription"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/mailer"
+	gateway "github.com/fekuna/go-store/pkg/payment"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Subscription UseCase
+type subscriptionUC struct {
+	cfg              *config.Config
+	logger           logger.Logger
+	subscriptionRepo subscription.Repository
+	addressRepo      address.Repository
+	orderRepo        order.Repository
+	checkoutUC       checkout.UseCase
+	charger          payment.Charger
+	mailer           mailer.Mailer
+}
+
+// Subscription UseCase constructor
+func NewSubscriptionUseCase(cfg *config.Config, logger logger.Logger, subscriptionRepo subscription.Repository, addressRepo address.Repository, orderRepo order.Repository, checkoutUC checkout.UseCase, charger payment.Charger, mailer mailer.Mailer) subscription.UseCase {
+	return &subscriptionUC{
+		cfg:              cfg,
+		logger:           logger,
+		subscriptionRepo: subscriptionRepo,
+		addressRepo:      addressRepo,
+		orderRepo:        orderRepo,
+		checkoutUC:       checkoutUC,
+		charger:          charger,
+		mailer:           mailer,
+	}
+}
+
+// Subscribe the user in context, the first order is placed at the start or by the next scheduler run
+func (u *subscriptionUC) Create(ctx context.Context, input *models.SubscriptionInput) (*models.Subscription, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &models.Subscription{
+		UserID:    user.UserID,
+		Status:    models.SubscriptionStatusActive,
+		NextRunAt: time.Now(),
+	}
+	if err = u.applyInput(ctx, s, input); err != nil {
+		return nil, err
+	}
+
+	return u.subscriptionRepo.Create(ctx, s)
+}
+
+// Get subscription with items and latest runs, customers only see their own subscriptions
+func (u *subscriptionUC) GetByID(ctx context.Context, subscriptionID uuid.UUID) (*models.Subscription, error) {
+	// TODO: Tracing
+
+	s, err := u.getVisible(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Items, err = u.subscriptionRepo.GetItems(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	if s.Runs, err = u.subscriptionRepo.GetRuns(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Subscriptions of the user in context, newest first
+func (u *subscriptionUC) ListMine(ctx context.Context, pq *utils.PaginationQuery) (*models.SubscriptionsList, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.subscriptionRepo.ListByUser(ctx, user.UserID, pq)
+}
+
+// Subscriptions of every user, optionally by status
+func (u *subscriptionUC) List(ctx context.Context, query *models.SubscriptionsQuery, pq *utils.PaginationQuery) (*models.SubscriptionsList, error) {
+	// TODO: Tracing
+
+	return u.subscriptionRepo.List(ctx, query.Status, pq)
+}
+
+// Replace items, interval, delivery and payment method of own subscription
+func (u *subscriptionUC) Update(ctx context.Context, subscriptionID uuid.UUID, input *models.SubscriptionInput) (*models.Subscription, error) {
+	// TODO: Tracing
+
+	s, err := u.getVisible(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if s.Status == models.SubscriptionStatusCancelled {
+		return nil, errors.Wrap(httpErrors.InvalidStateTransition, "subscriptionUC.Update: subscription is cancelled")
+	}
+
+	if err = u.applyInput(ctx, s, input); err != nil {
+		return nil, err
+	}
+
+	return u.subscriptionRepo.Update(ctx, s)
+}
+
+// Stop placing orders until resumed, a charge being retried is given up
+func (u *subscriptionUC) Pause(ctx context.Context, subscriptionID uuid.UUID) (*models.Subscription, error) {
+	// TODO: Tracing
+
+	s, err := u.getVisible(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.RetryAt = nil
+	s.FailedAttempts = 0
+	s.LastError = ""
+
+	return u.changeStatus(ctx, s, models.SubscriptionStatusPaused)
+}
+
+// Resume paused or suspended subscription, a delivery date that passed meanwhile is due right away
+func (u *subscriptionUC) Resume(ctx context.Context, subscriptionID uuid.UUID) (*models.Subscription, error) {
+	// TODO: Tracing
+
+	s, err := u.getVisible(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.PaymentMethodID == nil {
+		return nil, httpErrors.NewRestErrorWithMessage(
+			http.StatusBadRequest,
+			"Set up a payment method first",
+			errors.New("subscriptionUC.Resume: subscription without payment method"),
+		)
+	}
+
+	if now := time.Now(); s.NextRunAt.Before(now) {
+		s.NextRunAt = now
+	}
+	s.RetryAt = nil
+	s.FailedAttempts = 0
+	s.LastError = ""
+
+	return u.changeStatus(ctx, s, models.SubscriptionStatusActive)
+}
+
+// Skip the next delivery, a past due delivery is given up and the subscription is active again
+func (u *subscriptionUC) Skip(ctx context.Context, subscriptionID uuid.UUID) (*models.Subscription, error) {
+	// TODO: Tracing
+
+	s, err := u.getVisible(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	to := s.Status
+	switch s.Status {
+	case models.SubscriptionStatusActive, models.SubscriptionStatusPaused:
+	case models.SubscriptionStatusPastDue:
+		to = models.SubscriptionStatusActive
+	default:
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "subscriptionUC.Skip: subscription is %s", s.Status)
+	}
+
+	s.NextRunAt = s.NextAfter(time.Now())
+	s.RetryAt = nil
+	s.FailedAttempts = 0
+	s.LastError = ""
+
+	return u.changeStatus(ctx, s, to)
+}
+
+// Cancel own subscription, orders already placed are not affected
+func (u *subscriptionUC) Cancel(ctx context.Context, subscriptionID uuid.UUID) (*models.Subscription, error) {
+	// TODO: Tracing
+
+	s, err := u.getVisible(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Status == models.SubscriptionStatusCancelled {
+		return nil, errors.Wrap(httpErrors.InvalidStateTransition, "subscriptionUC.Cancel: subscription is cancelled")
+	}
+
+	now := time.Now()
+	s.RetryAt = nil
+	s.CancelledAt = &now
+
+	return u.changeStatus(ctx, s, models.SubscriptionStatusCancelled)
+}
+
+// Place and charge the orders of due subscriptions, claimed in batches so several instances can run this.
+// Returns the number of runs, failed charges included.
+func (u *subscriptionUC) RunDue(ctx context.Context) (int, error) {
+	// TODO: Tracing
+
+	ran := 0
+	for {
+		now := time.Now()
+		claimed, err := u.subscriptionRepo.ClaimDue(ctx, now, now.Add(time.Second*u.cfg.Subscription.ClaimTTL), u.cfg.Subscription.BatchSize)
+		if err != nil {
+			return ran, err
+		}
+
+		for _, s := range claimed {
+			if err = u.run(ctx, s); err != nil {
+				// The claim runs out and the subscription is picked up again
+				u.logger.Errorf("subscriptionUC.RunDue: subscription %s: %v", s.SubscriptionID, err)
+				continue
+			}
+			ran++
+		}
+
+		if len(claimed) < u.cfg.Subscription.BatchSize {
+			return ran, nil
+		}
+	}
+}
+
+// Place the order of the due delivery and charge it. Failures the customer can fix, like a declined card or
+// an item out of stock, are retried later and the customer is told. Other errors are returned.
+func (u *subscriptionUC) run(ctx context.Context, s *models.Subscription) error {
+	run := &models.SubscriptionRun{
+		SubscriptionID: s.SubscriptionID,
+		ScheduledAt:    s.NextRunAt,
+		Attempt:        s.FailedAttempts + 1,
+	}
+
+	if s.PaymentMethodID == nil {
+		return u.fail(ctx, s, run, "please set up your payment method again")
+	}
+
+	o, err := u.checkoutUC.PlaceSubscriptionOrder(ctx, s)
+	if err != nil {
+		// Paused or cancelled meanwhile
+		if errors.Is(err, httpErrors.InvalidStateTransition) {
+			return nil
+		}
+		// Items changed while the order was priced, the next run prices it again
+		if errors.Is(err, httpErrors.CartChanged) || httpErrors.ParseError(err).Status() >= http.StatusInternalServerError {
+			return err
+		}
+		return u.fail(ctx, s, run, "we couldn't prepare your order: "+httpErrors.ParseError(err).Error())
+	}
+	run.OrderID = &o.OrderID
+
+	// Keyed by delivery date and attempt, a run retried after a crash is not charged again
+	key := fmt.Sprintf("subscription_%s_%d_%d", s.SubscriptionID, s.NextRunAt.Unix(), run.Attempt)
+	p, err := u.charger.ChargeOrder(ctx, o.OrderID, *s.PaymentMethodID, key)
+	if err != nil {
+		var decline *gateway.DeclineError
+		declined := errors.As(err, &decline)
+		// Anything but a decline may have been charged, the next run retries it with the same key
+		if !declined && !errors.Is(err, httpErrors.InvalidStateTransition) {
+			return err
+		}
+
+		event := &models.OrderEvent{ToStatus: models.OrderStatusCancelled, Note: "subscription charge failed"}
+		if _, cancelErr := u.orderRepo.UpdateStatus(ctx, o.OrderID, models.OrderStatusPending, event); cancelErr != nil {
+			u.logger.Errorf("subscriptionUC.run: cancel order %s: %v", o.OrderID, cancelErr)
+		}
+
+		reason := "the payment could not be processed"
+		if declined {
+			reason = decline.Message
+		} else {
+			u.logger.Errorf("subscriptionUC.run: charge order %s: %v", o.OrderID, err)
+		}
+		return u.fail(ctx, s, run, reason)
+	}
+	run.PaymentID = &p.PaymentID
+	run.Status = models.SubscriptionRunSucceeded
+
+	if err = u.subscriptionRepo.CreateRun(ctx, run); err != nil {
+		return err
+	}
+
+	from := s.Status
+	s.Status = models.SubscriptionStatusActive
+	s.NextRunAt = s.NextAfter(time.Now())
+	s.RetryAt = nil
+	s.FailedAttempts = 0
+	s.LastError = ""
+	s.LastOrderID = &o.OrderID
+	_, err = u.subscriptionRepo.UpdateSchedule(ctx, s, from)
+
+	return err
+}
+
+// Record the failed run and retry later, after MaxAttempts failures the subscription is suspended
+func (u *subscriptionUC) fail(ctx context.Context, s *models.Subscription, run *models.SubscriptionRun, reason string) error {
+	run.Status = models.SubscriptionRunFailed
+	run.Error = reason
+	if err := u.subscriptionRepo.CreateRun(ctx, run); err != nil {
+		return err
+	}
+
+	from := s.Status
+	s.FailedAttempts = run.Attempt
+	s.LastError = reason
+	if s.FailedAttempts >= u.cfg.Subscription.MaxAttempts {
+		s.Status = models.SubscriptionStatusSuspended
+		s.RetryAt = nil
+	} else {
+		retryAt := time.Now().Add(time.Second * u.cfg.Subscription.RetryDelay * time.Duration(s.FailedAttempts))
+		s.Status = models.SubscriptionStatusPastDue
+		s.RetryAt = &retryAt
+	}
+
+	updated, err := u.subscriptionRepo.UpdateSchedule(ctx, s, from)
+	if err != nil {
+		return err
+	}
+	updated.Email = s.Email
+
+	u.notify(ctx, dunningMessage(updated, run, u.cfg.Subscription.MaxAttempts))
+
+	return nil
+}
+
+// Send email to the customer, failures are only logged
+func (u *subscriptionUC) notify(ctx context.Context, msg *mailer.Message) {
+	if err := u.mailer.Send(ctx, msg); err != nil {
+		u.logger.Errorf("subscriptionUC.notify: %v", err)
+	}
+}
+
+// Move subscription to another status, or keep it, saving its schedule
+func (u *subscriptionUC) changeStatus(ctx context.Context, s *models.Subscription, to string) (*models.Subscription, error) {
+	from := s.Status
+	if from != to && !models.CanTransitionSubscription(from, to) {
+		return nil, errors.Wrapf(httpErrors.InvalidStateTransition, "subscriptionUC.changeStatus: %s to %s", from, to)
+	}
+
+	s.Status = to
+
+	return u.subscriptionRepo.UpdateSchedule(ctx, s, from)
+}
+
+// Copy the request onto the subscription, the shipping address and payment method have to be ones of the subscriber
+func (u *subscriptionUC) applyInput(ctx context.Context, s *models.Subscription, input *models.SubscriptionInput) error {
+	input.Prepare()
+
+	if input.StartAt != nil {
+		if !input.StartAt.After(time.Now()) {
+			return httpErrors.NewRestErrorWithMessage(
+				http.StatusBadRequest,
+				"Start must be in the future",
+				errors.New("subscriptionUC.applyInput: start in the past"),
+			)
+		}
+		s.NextRunAt = *input.StartAt
+	}
+
+	if input.ShippingAddressID != nil {
+		if _, err := u.addressRepo.GetByID(ctx, s.UserID, *input.ShippingAddressID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return httpErrors.NewRestErrorWithMessage(
+					http.StatusBadRequest,
+					"Shipping address not found",
+					errors.Wrap(err, "subscriptionUC.applyInput"),
+				)
+			}
+			return err
+		}
+	}
+
+	if err := u.charger.CheckMethod(ctx, s.UserID, input.PaymentMethodID); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(input.Items))
+	s.Items = make([]*models.SubscriptionItem, 0, len(input.Items))
+	for _, item := range input.Items {
+		if seen[item.SKU] {
+			return httpErrors.NewBadRequestError(errors.Errorf("subscriptionUC.applyInput: sku %s appears twice", item.SKU))
+		}
+		seen[item.SKU] = true
+		s.Items = append(s.Items, &models.SubscriptionItem{SKU: item.SKU, Quantity: item.Quantity})
+	}
+
+	s.IntervalUnit = input.IntervalUnit
+	s.IntervalCount = input.IntervalCount
+	s.ShippingAddressID = input.ShippingAddressID
+	s.ShippingMethodID = input.ShippingMethodID
+	s.PaymentMethodID = &input.PaymentMethodID
+
+	return nil
+}
+
+// Subscription of the user in context, admins see every subscription
+func (u *subscriptionUC) getVisible(ctx context.Context, subscriptionID uuid.UUID) (*models.Subscription, error) {
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := u.subscriptionRepo.GetByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsAdmin() && s.UserID != user.UserID {
+		return nil, httpErrors.NewNotFoundError(errors.New("subscriptionUC.getVisible: subscription belongs to another user"))
+	}
+
+	return s, nil
+}
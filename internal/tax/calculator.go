@@ -0,0 +1,13 @@
+package tax
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+)
+
+// Tax calculator used at checkout. The zone based engine of the tax UseCase is the default,
+// an external tax service can replace it by implementing this interface.
+type Calculator interface {
+	Calculate(ctx context.Context, req *models.TaxRequest) (*models.TaxResult, error)
+}
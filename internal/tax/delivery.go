@@ -0,0 +1,15 @@
+package tax
+
+import "github.com/labstack/echo/v4"
+
+// Tax HTTP Handlers interface
+type Handlers interface {
+	CreateZone() echo.HandlerFunc
+	UpdateZone() echo.HandlerFunc
+	DeleteZone() echo.HandlerFunc
+	GetZone() echo.HandlerFunc
+	ListZones() echo.HandlerFunc
+	SetRate() echo.HandlerFunc
+	DeleteRate() echo.HandlerFunc
+	Calculate() echo.HandlerFunc
+}
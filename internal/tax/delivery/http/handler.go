@@ -0,0 +1,260 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/tax"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Tax handlers
+type taxHandlers struct {
+	cfg    *config.Config
+	logger logger.Logger
+	taxUC  tax.UseCase
+}
+
+// Tax handlers constructor
+func NewTaxHandlers(cfg *config.Config, logger logger.Logger, taxUC tax.UseCase) tax.Handlers {
+	return &taxHandlers{
+		cfg:    cfg,
+		logger: logger,
+		taxUC:  taxUC,
+	}
+}
+
+// CreateZone godoc
+// @Summary Create tax zone
+// @Description create tax zone of a country, optionally narrowed to region and postcode pattern, admin only
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.TaxZone
+// @Failure 400 {object} httpErrors.RestError
+// @Router /tax/zones [post]
+func (h *taxHandlers) CreateZone() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		zone := &models.TaxZone{}
+		if err := utils.ReadRequest(c, zone); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		createdZone, err := h.taxUC.CreateZone(ctx, zone)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, createdZone)
+	}
+}
+
+// UpdateZone godoc
+// @Summary Update tax zone
+// @Description replace tax zone settings, admin only
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param zone_id path string true "zone_id"
+// @Success 200 {object} models.TaxZone
+// @Failure 400 {object} httpErrors.RestError
+// @Router /tax/zones/{zone_id} [put]
+func (h *taxHandlers) UpdateZone() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		zoneID, err := uuid.Parse(c.Param("zone_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		zone := &models.TaxZone{}
+		if err = utils.ReadRequest(c, zone); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		zone.ZoneID = zoneID
+
+		updatedZone, err := h.taxUC.UpdateZone(ctx, zone)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, updatedZone)
+	}
+}
+
+// DeleteZone godoc
+// @Summary Delete tax zone
+// @Description delete tax zone with its rates, admin only
+// @Tags Tax
+// @Param zone_id path string true "zone_id"
+// @Success 204
+// @Failure 404 {object} httpErrors.RestError
+// @Router /tax/zones/{zone_id} [delete]
+func (h *taxHandlers) DeleteZone() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		zoneID, err := uuid.Parse(c.Param("zone_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.taxUC.DeleteZone(ctx, zoneID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetZone godoc
+// @Summary Get tax zone
+// @Description get tax zone with its rates, admin only
+// @Tags Tax
+// @Produce json
+// @Param zone_id path string true "zone_id"
+// @Success 200 {object} models.TaxZone
+// @Failure 404 {object} httpErrors.RestError
+// @Router /tax/zones/{zone_id} [get]
+func (h *taxHandlers) GetZone() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		zoneID, err := uuid.Parse(c.Param("zone_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		zone, err := h.taxUC.GetZone(ctx, zoneID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, zone)
+	}
+}
+
+// ListZones godoc
+// @Summary Get tax zones
+// @Description get all tax zones by country and priority, admin only
+// @Tags Tax
+// @Produce json
+// @Success 200 {array} models.TaxZone
+// @Failure 500 {object} httpErrors.RestError
+// @Router /tax/zones [get]
+func (h *taxHandlers) ListZones() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		zones, err := h.taxUC.ListZones(ctx)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, zones)
+	}
+}
+
+// SetRate godoc
+// @Summary Set tax rate
+// @Description create or replace the rate of a tax class in a zone, rate_ppm is in millionths of the price, admin only
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param zone_id path string true "zone_id"
+// @Param tax_class path string true "tax_class"
+// @Success 200 {object} models.TaxRate
+// @Failure 404 {object} httpErrors.RestError
+// @Router /tax/zones/{zone_id}/rates/{tax_class} [put]
+func (h *taxHandlers) SetRate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		zoneID, err := uuid.Parse(c.Param("zone_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		rate := &models.TaxRate{}
+		if err = utils.ReadRequest(c, rate); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		rate.ZoneID = zoneID
+		rate.TaxClass = c.Param("tax_class")
+
+		savedRate, err := h.taxUC.SetRate(ctx, rate)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, savedRate)
+	}
+}
+
+// DeleteRate godoc
+// @Summary Delete tax rate
+// @Description delete the rate of a tax class in a zone, the class is no longer taxed there, admin only
+// @Tags Tax
+// @Param zone_id path string true "zone_id"
+// @Param tax_class path string true "tax_class"
+// @Success 204
+// @Failure 404 {object} httpErrors.RestError
+// @Router /tax/zones/{zone_id}/rates/{tax_class} [delete]
+func (h *taxHandlers) DeleteRate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		zoneID, err := uuid.Parse(c.Param("zone_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.taxUC.DeleteRate(ctx, zoneID, c.Param("tax_class")); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// Calculate godoc
+// @Summary Calculate taxes
+// @Description calculate taxes of lines for an address with the configured zones, admin only
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TaxResult
+// @Failure 400 {object} httpErrors.RestError
+// @Router /tax/calculate [post]
+func (h *taxHandlers) Calculate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		req := &models.TaxRequest{}
+		if err := utils.ReadRequest(c, req); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		result, err := h.taxUC.Calculate(ctx, req)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, result)
+	}
+}
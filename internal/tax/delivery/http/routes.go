@@ -0,0 +1,22 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/tax"
+	"github.com/labstack/echo/v4"
+)
+
+func MapTaxRoutes(taxGroup *echo.Group, h tax.Handlers, mw *middleware.MiddlewareManager) {
+	taxGroup.Use(mw.AuthJWTMiddleware)
+	taxGroup.Use(mw.RoleBasedAuthMiddleware([]string{models.RoleAdmin}))
+
+	taxGroup.GET("/zones", h.ListZones())
+	taxGroup.POST("/zones", h.CreateZone())
+	taxGroup.GET("/zones/:zone_id", h.GetZone())
+	taxGroup.PUT("/zones/:zone_id", h.UpdateZone())
+	taxGroup.DELETE("/zones/:zone_id", h.DeleteZone())
+	taxGroup.PUT("/zones/:zone_id/rates/:tax_class", h.SetRate())
+	taxGroup.DELETE("/zones/:zone_id/rates/:tax_class", h.DeleteRate())
+	taxGroup.POST("/calculate", h.Calculate())
+}
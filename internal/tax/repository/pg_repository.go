@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/tax"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Tax repository
+type taxRepo struct {
+	db *sqlx.DB
+}
+
+// Tax repository constructor
+func NewTaxRepository(db *sqlx.DB) tax.Repository {
+	return &taxRepo{db: db}
+}
+
+func (r *taxRepo) CreateZone(ctx context.Context, z *models.TaxZone) (*models.TaxZone, error) {
+	// TODO: Tracing
+
+	created := &models.TaxZone{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		createZoneQuery,
+		&z.Name,
+		&z.Country,
+		z.Region,
+		z.PostcodePattern,
+		&z.Priority,
+		&z.PricesIncludeTax,
+		&z.Rounding,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "taxRepo.CreateZone.StructScan")
+	}
+
+	return created, nil
+}
+
+func (r *taxRepo) UpdateZone(ctx context.Context, z *models.TaxZone) (*models.TaxZone, error) {
+	// TODO: Tracing
+
+	updated := &models.TaxZone{}
+	if err := r.db.GetContext(
+		ctx,
+		updated,
+		updateZoneQuery,
+		&z.Name,
+		&z.Country,
+		z.Region,
+		z.PostcodePattern,
+		&z.Priority,
+		&z.PricesIncludeTax,
+		&z.Rounding,
+		&z.ZoneID,
+	); err != nil {
+		return nil, errors.Wrap(err, "taxRepo.UpdateZone.GetContext")
+	}
+
+	return updated, nil
+}
+
+// Delete zone with its rates
+func (r *taxRepo) DeleteZone(ctx context.Context, zoneID uuid.UUID) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, deleteZoneQuery, zoneID)
+	if err != nil {
+		return errors.Wrap(err, "taxRepo.DeleteZone.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "taxRepo.DeleteZone.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "taxRepo.DeleteZone.rowsAffected")
+	}
+
+	return nil
+}
+
+func (r *taxRepo) GetZone(ctx context.Context, zoneID uuid.UUID) (*models.TaxZone, error) {
+	// TODO: Tracing
+
+	z := &models.TaxZone{}
+	if err := r.db.GetContext(ctx, z, getZoneByIdQuery, zoneID); err != nil {
+		return nil, errors.Wrap(err, "taxRepo.GetZone.GetContext")
+	}
+
+	return z, nil
+}
+
+func (r *taxRepo) ListZones(ctx context.Context) ([]*models.TaxZone, error) {
+	// TODO: Tracing
+
+	zones := make([]*models.TaxZone, 0)
+	if err := r.db.SelectContext(ctx, &zones, listZonesQuery); err != nil {
+		return nil, errors.Wrap(err, "taxRepo.ListZones.SelectContext")
+	}
+
+	return zones, nil
+}
+
+// Zones of country compared case insensitively, highest priority first
+func (r *taxRepo) ListZonesByCountry(ctx context.Context, country string) ([]*models.TaxZone, error) {
+	// TODO: Tracing
+
+	zones := make([]*models.TaxZone, 0)
+	if err := r.db.SelectContext(ctx, &zones, listZonesByCountryQuery, country); err != nil {
+		return nil, errors.Wrap(err, "taxRepo.ListZonesByCountry.SelectContext")
+	}
+
+	return zones, nil
+}
+
+func (r *taxRepo) GetRates(ctx context.Context, zoneID uuid.UUID) ([]*models.TaxRate, error) {
+	// TODO: Tracing
+
+	rates := make([]*models.TaxRate, 0)
+	if err := r.db.SelectContext(ctx, &rates, getRatesQuery, zoneID); err != nil {
+		return nil, errors.Wrap(err, "taxRepo.GetRates.SelectContext")
+	}
+
+	return rates, nil
+}
+
+// Create or replace the rate of a tax class in a zone
+func (r *taxRepo) SetRate(ctx context.Context, rate *models.TaxRate) (*models.TaxRate, error) {
+	// TODO: Tracing
+
+	saved := &models.TaxRate{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		setRateQuery,
+		&rate.ZoneID,
+		&rate.TaxClass,
+		&rate.Name,
+		&rate.RatePPM,
+	).StructScan(saved); err != nil {
+		return nil, errors.Wrap(err, "taxRepo.SetRate.StructScan")
+	}
+
+	return saved, nil
+}
+
+func (r *taxRepo) DeleteRate(ctx context.Context, zoneID uuid.UUID, taxClass string) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, deleteRateQuery, zoneID, taxClass)
+	if err != nil {
+		return errors.Wrap(err, "taxRepo.DeleteRate.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "taxRepo.DeleteRate.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "taxRepo.DeleteRate.rowsAffected")
+	}
+
+	return nil
+}
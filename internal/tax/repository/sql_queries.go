@@ -0,0 +1,36 @@
+package repository
+
+const (
+	createZoneQuery = `
+		INSERT INTO tax_zones(name, country, region, postcode_pattern, priority, prices_include_tax, rounding, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, now(), now())
+		RETURNING *
+	`
+
+	updateZoneQuery = `
+		UPDATE tax_zones
+		SET name = $1, country = $2, region = $3, postcode_pattern = $4, priority = $5,
+			prices_include_tax = $6, rounding = $7, updated_at = now()
+		WHERE zone_id = $8
+		RETURNING *
+	`
+
+	deleteZoneQuery = `DELETE FROM tax_zones WHERE zone_id = $1`
+
+	getZoneByIdQuery = `SELECT * FROM tax_zones WHERE zone_id = $1`
+
+	listZonesQuery = `SELECT * FROM tax_zones ORDER BY country, priority DESC, name`
+
+	listZonesByCountryQuery = `SELECT * FROM tax_zones WHERE lower(country) = lower($1) ORDER BY priority DESC, zone_id`
+
+	getRatesQuery = `SELECT * FROM tax_rates WHERE zone_id = $1 ORDER BY tax_class`
+
+	setRateQuery = `
+		INSERT INTO tax_rates(zone_id, tax_class, name, rate_ppm, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, now(), now())
+		ON CONFLICT (zone_id, tax_class) DO UPDATE SET name = EXCLUDED.name, rate_ppm = EXCLUDED.rate_ppm, updated_at = now()
+		RETURNING *
+	`
+
+	deleteRateQuery = `DELETE FROM tax_rates WHERE zone_id = $1 AND tax_class = $2`
+)
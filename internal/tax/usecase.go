@@ -0,0 +1,20 @@
+package tax
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Tax UseCase
+type UseCase interface {
+	Calculator
+	CreateZone(ctx context.Context, zone *models.TaxZone) (*models.TaxZone, error)
+	UpdateZone(ctx context.Context, zone *models.TaxZone) (*models.TaxZone, error)
+	DeleteZone(ctx context.Context, zoneID uuid.UUID) error
+	GetZone(ctx context.Context, zoneID uuid.UUID) (*models.TaxZone, error)
+	ListZones(ctx context.Context) ([]*models.TaxZone, error)
+	SetRate(ctx context.Context, rate *models.TaxRate) (*models.TaxRate, error)
+	DeleteRate(ctx context.Context, zoneID uuid.UUID, taxClass string) error
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/fekuna/go-store/internal/models"
+)
+
+// Most specific zone matching the address, zones come ordered by priority so the first one of equal specificity wins
+func matchZone(zones []*models.TaxZone, address *models.TaxAddress) *models.TaxZone {
+	var best *models.TaxZone
+	bestScore := -1
+	for _, z := range zones {
+		score, ok := models.MatchArea(z.Region, z.PostcodePattern, address.Region, address.Postcode)
+		if ok && score > bestScore {
+			best, bestScore = z, score
+		}
+	}
+
+	return best
+}
+
+// Taxes of lines in zone. Inclusive prices already contain the tax, exclusive prices get it added.
+// Line rounding rounds the tax of every line, order rounding rounds the order total once and spreads
+// the cents over lines by their largest remainders.
+func calculate(zone *models.TaxZone, rates []*models.TaxRate, lines []*models.TaxLine) *models.TaxResult {
+	ratesByClass := make(map[string]int64, len(rates))
+	for _, rate := range rates {
+		ratesByClass[rate.TaxClass] = rate.RatePPM
+	}
+
+	zoneID := zone.ZoneID
+	result := &models.TaxResult{
+		ZoneID:           &zoneID,
+		PricesIncludeTax: zone.PricesIncludeTax,
+		Lines:            make([]*models.TaxLineResult, 0, len(lines)),
+	}
+
+	exact := make([]*big.Rat, 0, len(lines))
+	for _, line := range lines {
+		rate := ratesByClass[models.NormalizeTaxClass(line.TaxClass)]
+		exact = append(exact, lineTax(line.Amount, rate, zone.PricesIncludeTax))
+		result.Lines = append(result.Lines, &models.TaxLineResult{Ref: line.Ref, RatePPM: rate})
+	}
+
+	if zone.Rounding == models.TaxRoundingOrder {
+		roundOrder(exact, result.Lines)
+	} else {
+		for i, tax := range exact {
+			result.Lines[i].Tax = roundHalfUp(tax)
+		}
+	}
+
+	for _, line := range result.Lines {
+		result.TaxTotal += line.Tax
+	}
+
+	return result
+}
+
+// Result without tax for addresses no zone covers
+func untaxed(lines []*models.TaxLine) *models.TaxResult {
+	result := &models.TaxResult{Lines: make([]*models.TaxLineResult, 0, len(lines))}
+	for _, line := range lines {
+		result.Lines = append(result.Lines, &models.TaxLineResult{Ref: line.Ref})
+	}
+	return result
+}
+
+// Exact tax of amount in minor units
+func lineTax(amount int64, ratePPM int64, inclusive bool) *big.Rat {
+	denominator := int64(models.TaxRateScale)
+	if inclusive {
+		denominator += ratePPM
+	}
+
+	numerator := new(big.Int).Mul(big.NewInt(amount), big.NewInt(ratePPM))
+	return new(big.Rat).SetFrac(numerator, big.NewInt(denominator))
+}
+
+// Round the order total once and give the cents left after flooring to lines with the largest remainders
+func roundOrder(exact []*big.Rat, lines []*models.TaxLineResult) {
+	total := new(big.Rat)
+	remainders := make([]*big.Rat, len(exact))
+	var floored int64
+	for i, tax := range exact {
+		total.Add(total, tax)
+
+		floor := floorOf(tax)
+		lines[i].Tax = floor
+		floored += floor
+		remainders[i] = new(big.Rat).Sub(tax, new(big.Rat).SetInt64(floor))
+	}
+
+	order := make([]int, len(exact))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]].Cmp(remainders[order[j]]) > 0
+	})
+
+	left := roundHalfUp(total) - floored
+	for _, i := range order {
+		if left <= 0 {
+			break
+		}
+		lines[i].Tax++
+		left--
+	}
+}
+
+func floorOf(r *big.Rat) int64 {
+	return new(big.Int).Quo(r.Num(), r.Denom()).Int64()
+}
+
+// Round non negative amount half up to whole minor units
+func roundHalfUp(r *big.Rat) int64 {
+	quotient, remainder := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if new(big.Int).Mul(remainder, big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	return quotient.Int64()
+}
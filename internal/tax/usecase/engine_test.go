@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestCalculate(t *testing.T) {
+	rates := []*models.TaxRate{
+		{TaxClass: models.TaxClassStandard, RatePPM: 200000},
+		{TaxClass: "reduced", RatePPM: 55000},
+		{TaxClass: "ten", RatePPM: 100000},
+	}
+
+	tests := []struct {
+		name      string
+		inclusive bool
+		rounding  string
+		lines     []*models.TaxLine
+		wantTaxes []int64
+		wantRates []int64
+	}{
+		{
+			name:      "exclusive line rounding",
+			rounding:  models.TaxRoundingLine,
+			lines:     []*models.TaxLine{{Ref: "a", Amount: 1999}, {Ref: "b", TaxClass: "reduced", Amount: 999}},
+			wantTaxes: []int64{400, 55},
+			wantRates: []int64{200000, 55000},
+		},
+		{
+			name:      "half a cent rounds up",
+			rounding:  models.TaxRoundingLine,
+			lines:     []*models.TaxLine{{Ref: "a", TaxClass: "ten", Amount: 5}, {Ref: "b", TaxClass: "ten", Amount: 4}},
+			wantTaxes: []int64{1, 0},
+			wantRates: []int64{100000, 100000},
+		},
+		{
+			name:      "inclusive prices contain the tax",
+			inclusive: true,
+			rounding:  models.TaxRoundingLine,
+			lines:     []*models.TaxLine{{Ref: "a", Amount: 1200}, {Ref: "b", Amount: 1000}},
+			wantTaxes: []int64{200, 167},
+			wantRates: []int64{200000, 200000},
+		},
+		{
+			name:      "tax class is normalized, unknown classes are untaxed",
+			rounding:  models.TaxRoundingLine,
+			lines:     []*models.TaxLine{{Ref: "a", TaxClass: " Reduced ", Amount: 1000}, {Ref: "b", TaxClass: "books", Amount: 1000}},
+			wantTaxes: []int64{55, 0},
+			wantRates: []int64{55000, 0},
+		},
+		{
+			name:      "line rounding loses the cents of equal lines",
+			rounding:  models.TaxRoundingLine,
+			lines:     []*models.TaxLine{{Ref: "a", TaxClass: "ten", Amount: 333}, {Ref: "b", TaxClass: "ten", Amount: 333}, {Ref: "c", TaxClass: "ten", Amount: 333}},
+			wantTaxes: []int64{33, 33, 33},
+			wantRates: []int64{100000, 100000, 100000},
+		},
+		{
+			name:      "order rounding gives the cent to the first equal remainder",
+			rounding:  models.TaxRoundingOrder,
+			lines:     []*models.TaxLine{{Ref: "a", TaxClass: "ten", Amount: 333}, {Ref: "b", TaxClass: "ten", Amount: 333}, {Ref: "c", TaxClass: "ten", Amount: 333}},
+			wantTaxes: []int64{34, 33, 33},
+			wantRates: []int64{100000, 100000, 100000},
+		},
+		{
+			name:      "order rounding gives cents to the largest remainders",
+			rounding:  models.TaxRoundingOrder,
+			lines:     []*models.TaxLine{{Ref: "a", TaxClass: "ten", Amount: 105}, {Ref: "b", TaxClass: "ten", Amount: 104}, {Ref: "c", TaxClass: "ten", Amount: 107}},
+			wantTaxes: []int64{11, 10, 11},
+			wantRates: []int64{100000, 100000, 100000},
+		},
+		{
+			name:      "order rounding of inclusive prices",
+			inclusive: true,
+			rounding:  models.TaxRoundingOrder,
+			lines:     []*models.TaxLine{{Ref: "a", Amount: 1000}, {Ref: "b", Amount: 1000}, {Ref: "c", Amount: 1000}},
+			wantTaxes: []int64{167, 167, 166},
+			wantRates: []int64{200000, 200000, 200000},
+		},
+		{
+			name:      "no lines",
+			rounding:  models.TaxRoundingOrder,
+			lines:     []*models.TaxLine{},
+			wantTaxes: []int64{},
+			wantRates: []int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone := &models.TaxZone{ZoneID: uuid.New(), PricesIncludeTax: tt.inclusive, Rounding: tt.rounding}
+			result := calculate(zone, rates, tt.lines)
+
+			taxes := make([]int64, 0, len(result.Lines))
+			ratesPPM := make([]int64, 0, len(result.Lines))
+			var total int64
+			for i, line := range result.Lines {
+				if line.Ref != tt.lines[i].Ref {
+					t.Fatalf("line %d ref = %q, want %q", i, line.Ref, tt.lines[i].Ref)
+				}
+				taxes = append(taxes, line.Tax)
+				ratesPPM = append(ratesPPM, line.RatePPM)
+				total += line.Tax
+			}
+
+			if !reflect.DeepEqual(taxes, tt.wantTaxes) {
+				t.Fatalf("line taxes = %v, want %v", taxes, tt.wantTaxes)
+			}
+			if !reflect.DeepEqual(ratesPPM, tt.wantRates) {
+				t.Fatalf("line rates = %v, want %v", ratesPPM, tt.wantRates)
+			}
+			if result.TaxTotal != total {
+				t.Fatalf("tax total = %d, want sum of lines %d", result.TaxTotal, total)
+			}
+			if result.PricesIncludeTax != tt.inclusive || result.ZoneID == nil || *result.ZoneID != zone.ZoneID {
+				t.Fatalf("result zone = %v inclusive = %v, want %s %v", result.ZoneID, result.PricesIncludeTax, zone.ZoneID, tt.inclusive)
+			}
+		})
+	}
+}
+
+func TestRoundHalfUp(t *testing.T) {
+	tests := []struct {
+		num   int64
+		denom int64
+		want  int64
+	}{
+		{num: 0, denom: 1, want: 0},
+		{num: 49, denom: 100, want: 0},
+		{num: 1, denom: 2, want: 1},
+		{num: 3, denom: 2, want: 2},
+		{num: 2, denom: 3, want: 1},
+		{num: 499, denom: 1, want: 499},
+	}
+
+	for _, tt := range tests {
+		if got := roundHalfUp(big.NewRat(tt.num, tt.denom)); got != tt.want {
+			t.Fatalf("roundHalfUp(%d/%d) = %d, want %d", tt.num, tt.denom, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/tax"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Tax UseCase
+type taxUC struct {
+	cfg     *config.Config
+	logger  logger.Logger
+	taxRepo tax.Repository
+}
+
+// Tax UseCase constructor
+func NewTaxUseCase(cfg *config.Config, logger logger.Logger, taxRepo tax.Repository) tax.UseCase {
+	return &taxUC{
+		cfg:     cfg,
+		logger:  logger,
+		taxRepo: taxRepo,
+	}
+}
+
+func (u *taxUC) CreateZone(ctx context.Context, z *models.TaxZone) (*models.TaxZone, error) {
+	// TODO: Tracing
+
+	z.Prepare()
+	if err := validateZone(z); err != nil {
+		return nil, err
+	}
+
+	return u.taxRepo.CreateZone(ctx, z)
+}
+
+func (u *taxUC) UpdateZone(ctx context.Context, z *models.TaxZone) (*models.TaxZone, error) {
+	// TODO: Tracing
+
+	z.Prepare()
+	if err := validateZone(z); err != nil {
+		return nil, err
+	}
+
+	return u.taxRepo.UpdateZone(ctx, z)
+}
+
+func (u *taxUC) DeleteZone(ctx context.Context, zoneID uuid.UUID) error {
+	// TODO: Tracing
+
+	return u.taxRepo.DeleteZone(ctx, zoneID)
+}
+
+// Get zone with its rates
+func (u *taxUC) GetZone(ctx context.Context, zoneID uuid.UUID) (*models.TaxZone, error) {
+	// TODO: Tracing
+
+	z, err := u.taxRepo.GetZone(ctx, zoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	z.Rates, err = u.taxRepo.GetRates(ctx, zoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
+
+func (u *taxUC) ListZones(ctx context.Context) ([]*models.TaxZone, error) {
+	// TODO: Tracing
+
+	return u.taxRepo.ListZones(ctx)
+}
+
+func (u *taxUC) SetRate(ctx context.Context, rate *models.TaxRate) (*models.TaxRate, error) {
+	// TODO: Tracing
+
+	if _, err := u.taxRepo.GetZone(ctx, rate.ZoneID); err != nil {
+		return nil, err
+	}
+
+	rate.TaxClass = models.NormalizeTaxClass(rate.TaxClass)
+	rate.Name = strings.TrimSpace(rate.Name)
+
+	return u.taxRepo.SetRate(ctx, rate)
+}
+
+func (u *taxUC) DeleteRate(ctx context.Context, zoneID uuid.UUID, taxClass string) error {
+	// TODO: Tracing
+
+	return u.taxRepo.DeleteRate(ctx, zoneID, models.NormalizeTaxClass(taxClass))
+}
+
+// Calculate taxes with the zone matching the address, addresses no zone covers are not taxed
+func (u *taxUC) Calculate(ctx context.Context, req *models.TaxRequest) (*models.TaxResult, error) {
+	// TODO: Tracing
+
+	country := strings.TrimSpace(req.Address.Country)
+	if country == "" {
+		return untaxed(req.Lines), nil
+	}
+
+	zones, err := u.taxRepo.ListZonesByCountry(ctx, country)
+	if err != nil {
+		return nil, err
+	}
+
+	zone := matchZone(zones, &req.Address)
+	if zone == nil {
+		return untaxed(req.Lines), nil
+	}
+
+	rates, err := u.taxRepo.GetRates(ctx, zone.ZoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	return calculate(zone, rates, req.Lines), nil
+}
+
+func validateZone(z *models.TaxZone) error {
+	if z.PostcodePattern != nil && !models.ValidPostcodePattern(*z.PostcodePattern) {
+		return httpErrors.NewBadRequestError(errors.New("taxUC.validateZone: invalid postcode pattern"))
+	}
+
+	return nil
+}
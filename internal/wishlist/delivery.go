@@ -0,0 +1,19 @@
+package wishlist
+
+import "github.com/labstack/echo/v4"
+
+// Wishlist HTTP Handlers interface
+type Handlers interface {
+	Create() echo.HandlerFunc
+	Rename() echo.HandlerFunc
+	Delete() echo.HandlerFunc
+	GetByID() echo.HandlerFunc
+	List() echo.HandlerFunc
+	AddItem() echo.HandlerFunc
+	RemoveItem() echo.HandlerFunc
+	ReorderItems() echo.HandlerFunc
+	MoveToCart() echo.HandlerFunc
+	Share() echo.HandlerFunc
+	Unshare() echo.HandlerFunc
+	GetShared() echo.HandlerFunc
+}
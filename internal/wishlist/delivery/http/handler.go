@@ -0,0 +1,383 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/wishlist"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/fekuna/go-store/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Wishlist handlers
+type wishlistHandlers struct {
+	cfg        *config.Config
+	logger     logger.Logger
+	wishlistUC wishlist.UseCase
+}
+
+// Wishlist handlers constructor
+func NewWishlistHandlers(cfg *config.Config, logger logger.Logger, wishlistUC wishlist.UseCase) wishlist.Handlers {
+	return &wishlistHandlers{
+		cfg:        cfg,
+		logger:     logger,
+		wishlistUC: wishlistUC,
+	}
+}
+
+// Create godoc
+// @Summary Create wishlist
+// @Description create named wishlist
+// @Tags Wishlists
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Wishlist
+// @Failure 500 {object} httpErrors.RestError
+// @Router /wishlists [post]
+func (h *wishlistHandlers) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		w := &models.Wishlist{}
+		if err := utils.ReadRequest(c, w); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		createdWishlist, err := h.wishlistUC.Create(ctx, w)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, createdWishlist)
+	}
+}
+
+// Rename godoc
+// @Summary Rename wishlist
+// @Description rename own wishlist
+// @Tags Wishlists
+// @Accept json
+// @Produce json
+// @Param wishlist_id path string true "wishlist_id"
+// @Success 200 {object} models.Wishlist
+// @Failure 500 {object} httpErrors.RestError
+// @Router /wishlists/{wishlist_id} [put]
+func (h *wishlistHandlers) Rename() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		wishlistID, err := uuid.Parse(c.Param("wishlist_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		w := &models.Wishlist{}
+		if err = utils.ReadRequest(c, w); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		w.WishlistID = wishlistID
+
+		renamedWishlist, err := h.wishlistUC.Rename(ctx, w)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, renamedWishlist)
+	}
+}
+
+// Delete godoc
+// @Summary Delete wishlist
+// @Description delete own wishlist with its items
+// @Tags Wishlists
+// @Param wishlist_id path string true "wishlist_id"
+// @Success 204
+// @Failure 500 {object} httpErrors.RestError
+// @Router /wishlists/{wishlist_id} [delete]
+func (h *wishlistHandlers) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		wishlistID, err := uuid.Parse(c.Param("wishlist_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.wishlistUC.Delete(ctx, wishlistID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetByID godoc
+// @Summary Get wishlist
+// @Description get own wishlist with items in saved order
+// @Tags Wishlists
+// @Produce json
+// @Param wishlist_id path string true "wishlist_id"
+// @Success 200 {object} models.Wishlist
+// @Failure 500 {object} httpErrors.RestError
+// @Router /wishlists/{wishlist_id} [get]
+func (h *wishlistHandlers) GetByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		wishlistID, err := uuid.Parse(c.Param("wishlist_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		w, err := h.wishlistUC.GetByID(ctx, wishlistID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, w)
+	}
+}
+
+// List godoc
+// @Summary Get wishlists
+// @Description get own wishlists without items
+// @Tags Wishlists
+// @Produce json
+// @Success 200 {array} models.Wishlist
+// @Failure 500 {object} httpErrors.RestError
+// @Router /wishlists [get]
+func (h *wishlistHandlers) List() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		wishlists, err := h.wishlistUC.List(ctx)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, wishlists)
+	}
+}
+
+// AddItem godoc
+// @Summary Add wishlist item
+// @Description add variant to the end of own wishlist, adding it twice has no effect
+// @Tags Wishlists
+// @Accept json
+// @Produce json
+// @Param wishlist_id path string true "wishlist_id"
+// @Success 200 {object} models.Wishlist
+// @Failure 500 {object} httpErrors.RestError
+// @Router /wishlists/{wishlist_id}/items [post]
+func (h *wishlistHandlers) AddItem() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		wishlistID, err := uuid.Parse(c.Param("wishlist_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		item := &models.WishlistItem{}
+		if err = utils.ReadRequest(c, item); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		w, err := h.wishlistUC.AddItem(ctx, wishlistID, item.VariantID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, w)
+	}
+}
+
+// RemoveItem godoc
+// @Summary Remove wishlist item
+// @Description remove variant from own wishlist
+// @Tags Wishlists
+// @Produce json
+// @Param wishlist_id path string true "wishlist_id"
+// @Param variant_id path string true "variant_id"
+// @Success 200 {object} models.Wishlist
+// @Failure 500 {object} httpErrors.RestError
+// @Router /wishlists/{wishlist_id}/items/{variant_id} [delete]
+func (h *wishlistHandlers) RemoveItem() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		wishlistID, err := uuid.Parse(c.Param("wishlist_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		variantID, err := uuid.Parse(c.Param("variant_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		w, err := h.wishlistUC.RemoveItem(ctx, wishlistID, variantID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, w)
+	}
+}
+
+// MoveToCart godoc
+// @Summary Move wishlist item to cart
+// @Description add one of the variant to own cart and remove it from the wishlist
+// @Tags Wishlists
+// @Produce json
+// @Param wishlist_id path string true "wishlist_id"
+// @Param variant_id path string true "variant_id"
+// @Success 200 {object} models.Cart
+// @Failure 500 {object} httpErrors.RestError
+// @Router /wishlists/{wishlist_id}/items/{variant_id}/move-to-cart [post]
+func (h *wishlistHandlers) MoveToCart() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		wishlistID, err := uuid.Parse(c.Param("wishlist_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		variantID, err := uuid.Parse(c.Param("variant_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		updatedCart, err := h.wishlistUC.MoveToCart(ctx, wishlistID, variantID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, updatedCart)
+	}
+}
+
+// ReorderItems godoc
+// @Summary Reorder wishlist items
+// @Description set order of own wishlist items, every item must be listed exactly once
+// @Tags Wishlists
+// @Accept json
+// @Produce json
+// @Param wishlist_id path string true "wishlist_id"
+// @Success 200 {object} models.Wishlist
+// @Failure 500 {object} httpErrors.RestError
+// @Router /wishlists/{wishlist_id}/items/order [put]
+func (h *wishlistHandlers) ReorderItems() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		wishlistID, err := uuid.Parse(c.Param("wishlist_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		order := &models.WishlistOrder{}
+		if err = utils.ReadRequest(c, order); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		w, err := h.wishlistUC.ReorderItems(ctx, wishlistID, order)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, w)
+	}
+}
+
+// Share godoc
+// @Summary Share wishlist
+// @Description create public share token for own wishlist, an existing token is kept
+// @Tags Wishlists
+// @Produce json
+// @Param wishlist_id path string true "wishlist_id"
+// @Success 200 {object} models.Wishlist
+// @Failure 500 {object} httpErrors.RestError
+// @Router /wishlists/{wishlist_id}/share [post]
+func (h *wishlistHandlers) Share() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		wishlistID, err := uuid.Parse(c.Param("wishlist_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		w, err := h.wishlistUC.Share(ctx, wishlistID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, w)
+	}
+}
+
+// Unshare godoc
+// @Summary Unshare wishlist
+// @Description revoke public share token of own wishlist
+// @Tags Wishlists
+// @Produce json
+// @Param wishlist_id path string true "wishlist_id"
+// @Success 200 {object} models.Wishlist
+// @Failure 500 {object} httpErrors.RestError
+// @Router /wishlists/{wishlist_id}/share [delete]
+func (h *wishlistHandlers) Unshare() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		wishlistID, err := uuid.Parse(c.Param("wishlist_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		w, err := h.wishlistUC.Unshare(ctx, wishlistID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, w)
+	}
+}
+
+// GetShared godoc
+// @Summary Get shared wishlist
+// @Description get wishlist by public share token
+// @Tags Wishlists
+// @Produce json
+// @Param token path string true "token"
+// @Success 200 {object} models.SharedWishlist
+// @Failure 500 {object} httpErrors.RestError
+// @Router /wishlists/shared/{token} [get]
+func (h *wishlistHandlers) GetShared() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// TODO: tracing
+		ctx := c.Request().Context()
+
+		shared, err := h.wishlistUC.GetShared(ctx, c.Param("token"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, shared)
+	}
+}
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/wishlist"
+	"github.com/labstack/echo/v4"
+)
+
+func MapWishlistRoutes(wishlistGroup *echo.Group, h wishlist.Handlers, mw *middleware.MiddlewareManager) {
+	wishlistGroup.GET("/shared/:token", h.GetShared())
+	wishlistGroup.GET("", h.List(), mw.AuthJWTMiddleware)
+	wishlistGroup.POST("", h.Create(), mw.AuthJWTMiddleware)
+	wishlistGroup.GET("/:wishlist_id", h.GetByID(), mw.AuthJWTMiddleware)
+	wishlistGroup.PUT("/:wishlist_id", h.Rename(), mw.AuthJWTMiddleware)
+	wishlistGroup.DELETE("/:wishlist_id", h.Delete(), mw.AuthJWTMiddleware)
+	wishlistGroup.POST("/:wishlist_id/items", h.AddItem(), mw.AuthJWTMiddleware)
+	wishlistGroup.PUT("/:wishlist_id/items/order", h.ReorderItems(), mw.AuthJWTMiddleware)
+	wishlistGroup.DELETE("/:wishlist_id/items/:variant_id", h.RemoveItem(), mw.AuthJWTMiddleware)
+	wishlistGroup.POST("/:wishlist_id/items/:variant_id/move-to-cart", h.MoveToCart(), mw.AuthJWTMiddleware)
+	wishlistGroup.POST("/:wishlist_id/share", h.Share(), mw.AuthJWTMiddleware)
+	wishlistGroup.DELETE("/:wishlist_id/share", h.Unshare(), mw.AuthJWTMiddleware)
+}
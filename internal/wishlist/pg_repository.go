@@ -0,0 +1,24 @@
+package wishlist
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Wishlist repository, every user scoped method only sees wishlists of userID
+type Repository interface {
+	Create(ctx context.Context, w *models.Wishlist) (*models.Wishlist, error)
+	Rename(ctx context.Context, w *models.Wishlist) (*models.Wishlist, error)
+	Delete(ctx context.Context, userID uuid.UUID, wishlistID uuid.UUID) error
+	GetByID(ctx context.Context, userID uuid.UUID, wishlistID uuid.UUID) (*models.Wishlist, error)
+	GetByShareToken(ctx context.Context, token string) (*models.Wishlist, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Wishlist, error)
+	CountByUser(ctx context.Context, userID uuid.UUID) (int, error)
+	SetShareToken(ctx context.Context, userID uuid.UUID, wishlistID uuid.UUID, token *string) (*models.Wishlist, error)
+	GetItems(ctx context.Context, wishlistID uuid.UUID) ([]*models.WishlistItem, error)
+	AddItem(ctx context.Context, wishlistID uuid.UUID, variantID uuid.UUID) error
+	RemoveItem(ctx context.Context, wishlistID uuid.UUID, variantID uuid.UUID) error
+	ReorderItems(ctx context.Context, wishlistID uuid.UUID, variantIDs []uuid.UUID) error
+}
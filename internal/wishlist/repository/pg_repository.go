@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/wishlist"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Wishlist repository
+type wishlistRepo struct {
+	db *sqlx.DB
+}
+
+// Wishlist repository constructor
+func NewWishlistRepository(db *sqlx.DB) wishlist.Repository {
+	return &wishlistRepo{db: db}
+}
+
+func (r *wishlistRepo) Create(ctx context.Context, w *models.Wishlist) (*models.Wishlist, error) {
+	// TODO: Tracing
+
+	created := &models.Wishlist{}
+	if err := r.db.QueryRowxContext(ctx, createWishlistQuery, &w.UserID, &w.Name).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "wishlistRepo.Create.StructScan")
+	}
+
+	return created, nil
+}
+
+func (r *wishlistRepo) Rename(ctx context.Context, w *models.Wishlist) (*models.Wishlist, error) {
+	// TODO: Tracing
+
+	renamed := &models.Wishlist{}
+	if err := r.db.GetContext(ctx, renamed, renameWishlistQuery, &w.Name, &w.WishlistID, &w.UserID); err != nil {
+		return nil, errors.Wrap(err, "wishlistRepo.Rename.GetContext")
+	}
+
+	return renamed, nil
+}
+
+func (r *wishlistRepo) Delete(ctx context.Context, userID uuid.UUID, wishlistID uuid.UUID) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, deleteWishlistQuery, wishlistID, userID)
+	if err != nil {
+		return errors.Wrap(err, "wishlistRepo.Delete.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "wishlistRepo.Delete.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "wishlistRepo.Delete.rowsAffected")
+	}
+
+	return nil
+}
+
+func (r *wishlistRepo) GetByID(ctx context.Context, userID uuid.UUID, wishlistID uuid.UUID) (*models.Wishlist, error) {
+	// TODO: Tracing
+
+	w := &models.Wishlist{}
+	if err := r.db.GetContext(ctx, w, getWishlistByIdQuery, wishlistID, userID); err != nil {
+		return nil, errors.Wrap(err, "wishlistRepo.GetByID.GetContext")
+	}
+
+	return w, nil
+}
+
+func (r *wishlistRepo) GetByShareToken(ctx context.Context, token string) (*models.Wishlist, error) {
+	// TODO: Tracing
+
+	w := &models.Wishlist{}
+	if err := r.db.GetContext(ctx, w, getWishlistByShareTokenQuery, token); err != nil {
+		return nil, errors.Wrap(err, "wishlistRepo.GetByShareToken.GetContext")
+	}
+
+	return w, nil
+}
+
+func (r *wishlistRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Wishlist, error) {
+	// TODO: Tracing
+
+	wishlists := make([]*models.Wishlist, 0)
+	if err := r.db.SelectContext(ctx, &wishlists, listWishlistsByUserQuery, userID); err != nil {
+		return nil, errors.Wrap(err, "wishlistRepo.ListByUser.SelectContext")
+	}
+
+	return wishlists, nil
+}
+
+func (r *wishlistRepo) CountByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	// TODO: Tracing
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, countWishlistsByUserQuery, userID); err != nil {
+		return 0, errors.Wrap(err, "wishlistRepo.CountByUser.GetContext")
+	}
+
+	return count, nil
+}
+
+func (r *wishlistRepo) SetShareToken(ctx context.Context, userID uuid.UUID, wishlistID uuid.UUID, token *string) (*models.Wishlist, error) {
+	// TODO: Tracing
+
+	w := &models.Wishlist{}
+	if err := r.db.GetContext(ctx, w, setShareTokenQuery, token, wishlistID, userID); err != nil {
+		return nil, errors.Wrap(err, "wishlistRepo.SetShareToken.GetContext")
+	}
+
+	return w, nil
+}
+
+func (r *wishlistRepo) GetItems(ctx context.Context, wishlistID uuid.UUID) ([]*models.WishlistItem, error) {
+	// TODO: Tracing
+
+	items := make([]*models.WishlistItem, 0)
+	if err := r.db.SelectContext(ctx, &items, getWishlistItemsQuery, wishlistID); err != nil {
+		return nil, errors.Wrap(err, "wishlistRepo.GetItems.SelectContext")
+	}
+
+	return items, nil
+}
+
+// Append item to the end of wishlist, adding it twice is a no-op
+func (r *wishlistRepo) AddItem(ctx context.Context, wishlistID uuid.UUID, variantID uuid.UUID) error {
+	// TODO: Tracing
+
+	if _, err := r.db.ExecContext(ctx, addWishlistItemQuery, wishlistID, variantID); err != nil {
+		return errors.Wrap(err, "wishlistRepo.AddItem.ExecContext")
+	}
+
+	if _, err := r.db.ExecContext(ctx, touchWishlistQuery, wishlistID); err != nil {
+		return errors.Wrap(err, "wishlistRepo.AddItem.ExecContext.touch")
+	}
+
+	return nil
+}
+
+func (r *wishlistRepo) RemoveItem(ctx context.Context, wishlistID uuid.UUID, variantID uuid.UUID) error {
+	// TODO: Tracing
+
+	result, err := r.db.ExecContext(ctx, removeWishlistItemQuery, wishlistID, variantID)
+	if err != nil {
+		return errors.Wrap(err, "wishlistRepo.RemoveItem.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "wishlistRepo.RemoveItem.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "wishlistRepo.RemoveItem.rowsAffected")
+	}
+
+	if _, err = r.db.ExecContext(ctx, touchWishlistQuery, wishlistID); err != nil {
+		return errors.Wrap(err, "wishlistRepo.RemoveItem.ExecContext.touch")
+	}
+
+	return nil
+}
+
+// Set item positions to the given order, it must list every item of wishlist exactly once
+func (r *wishlistRepo) ReorderItems(ctx context.Context, wishlistID uuid.UUID, variantIDs []uuid.UUID) error {
+	// TODO: Tracing
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "wishlistRepo.ReorderItems.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	current := make([]uuid.UUID, 0)
+	if err = tx.SelectContext(ctx, &current, getWishlistItemIdsQuery, wishlistID); err != nil {
+		return errors.Wrap(err, "wishlistRepo.ReorderItems.SelectContext")
+	}
+
+	if !sameItems(current, variantIDs) {
+		return httpErrors.NewBadRequestError(errors.New("wishlistRepo.ReorderItems: order must list every wishlist item exactly once"))
+	}
+
+	for i, variantID := range variantIDs {
+		if _, err = tx.ExecContext(ctx, setWishlistItemPositionQuery, i, wishlistID, variantID); err != nil {
+			return errors.Wrap(err, "wishlistRepo.ReorderItems.ExecContext.position")
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, touchWishlistQuery, wishlistID); err != nil {
+		return errors.Wrap(err, "wishlistRepo.ReorderItems.ExecContext.touch")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "wishlistRepo.ReorderItems.Commit")
+	}
+
+	return nil
+}
+
+func sameItems(current []uuid.UUID, order []uuid.UUID) bool {
+	if len(current) != len(order) {
+		return false
+	}
+
+	set := make(map[uuid.UUID]bool, len(current))
+	for _, id := range current {
+		set[id] = true
+	}
+	for _, id := range order {
+		if !set[id] {
+			return false
+		}
+		delete(set, id)
+	}
+
+	return true
+}
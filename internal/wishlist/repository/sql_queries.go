@@ -0,0 +1,59 @@
+package repository
+
+const (
+	createWishlistQuery = `
+		INSERT INTO wishlists(user_id, name, created_at, updated_at)
+		VALUES ($1, $2, now(), now())
+		RETURNING *
+	`
+
+	renameWishlistQuery = `
+		UPDATE wishlists SET name = $1, updated_at = now()
+		WHERE wishlist_id = $2 AND user_id = $3
+		RETURNING *
+	`
+
+	deleteWishlistQuery = `DELETE FROM wishlists WHERE wishlist_id = $1 AND user_id = $2`
+
+	getWishlistByIdQuery = `SELECT * FROM wishlists WHERE wishlist_id = $1 AND user_id = $2`
+
+	getWishlistByShareTokenQuery = `SELECT * FROM wishlists WHERE share_token = $1`
+
+	listWishlistsByUserQuery = `SELECT * FROM wishlists WHERE user_id = $1 ORDER BY created_at, name`
+
+	countWishlistsByUserQuery = `SELECT COUNT(*) FROM wishlists WHERE user_id = $1`
+
+	setShareTokenQuery = `
+		UPDATE wishlists SET share_token = $1, updated_at = now()
+		WHERE wishlist_id = $2 AND user_id = $3
+		RETURNING *
+	`
+
+	getWishlistItemsQuery = `
+		SELECT i.wishlist_id, i.variant_id, i.position, i.added_at,
+			p.product_id, v.sku, p.slug, p.title, v.title AS variant_title,
+			COALESCE(v.price_override, p.price) AS price, p.currency,
+			p.status = 'active' AND v.status = 'active' AS available
+		FROM wishlist_items i
+		JOIN product_variants v ON v.variant_id = i.variant_id
+		JOIN products p ON p.product_id = v.product_id
+		WHERE i.wishlist_id = $1
+		ORDER BY i.position, i.added_at
+	`
+
+	addWishlistItemQuery = `
+		INSERT INTO wishlist_items(wishlist_id, variant_id, position, added_at)
+		SELECT $1, $2, COALESCE(MAX(position) + 1, 0), now()
+		FROM wishlist_items
+		WHERE wishlist_id = $1
+		ON CONFLICT (wishlist_id, variant_id) DO NOTHING
+	`
+
+	removeWishlistItemQuery = `DELETE FROM wishlist_items WHERE wishlist_id = $1 AND variant_id = $2`
+
+	getWishlistItemIdsQuery = `SELECT variant_id FROM wishlist_items WHERE wishlist_id = $1 FOR UPDATE`
+
+	setWishlistItemPositionQuery = `UPDATE wishlist_items SET position = $1 WHERE wishlist_id = $2 AND variant_id = $3`
+
+	touchWishlistQuery = `UPDATE wishlists SET updated_at = now() WHERE wishlist_id = $1`
+)
@@ -0,0 +1,24 @@
+package wishlist
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/google/uuid"
+)
+
+// Wishlist UseCase, wishlists belong to the user in context
+type UseCase interface {
+	Create(ctx context.Context, w *models.Wishlist) (*models.Wishlist, error)
+	Rename(ctx context.Context, w *models.Wishlist) (*models.Wishlist, error)
+	Delete(ctx context.Context, wishlistID uuid.UUID) error
+	GetByID(ctx context.Context, wishlistID uuid.UUID) (*models.Wishlist, error)
+	List(ctx context.Context) ([]*models.Wishlist, error)
+	AddItem(ctx context.Context, wishlistID uuid.UUID, variantID uuid.UUID) (*models.Wishlist, error)
+	RemoveItem(ctx context.Context, wishlistID uuid.UUID, variantID uuid.UUID) (*models.Wishlist, error)
+	ReorderItems(ctx context.Context, wishlistID uuid.UUID, order *models.WishlistOrder) (*models.Wishlist, error)
+	MoveToCart(ctx context.Context, wishlistID uuid.UUID, variantID uuid.UUID) (*models.Cart, error)
+	Share(ctx context.Context, wishlistID uuid.UUID) (*models.Wishlist, error)
+	Unshare(ctx context.Context, wishlistID uuid.UUID) (*models.Wishlist, error)
+	GetShared(ctx context.Context, token string) (*models.SharedWishlist, error)
+}
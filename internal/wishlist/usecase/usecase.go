@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/internal/cart"
+	"github.com/fekuna/go-store/internal/middleware"
+	"github.com/fekuna/go-store/internal/models"
+	"github.com/fekuna/go-store/internal/product"
+	"github.com/fekuna/go-store/internal/wishlist"
+	"github.com/fekuna/go-store/pkg/httpErrors"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxWishlistsPerUser = 20
+	maxWishlistItems    = 200
+)
+
+// Wishlist UseCase
+type wishlistUC struct {
+	cfg          *config.Config
+	logger       logger.Logger
+	wishlistRepo wishlist.Repository
+	productRepo  product.Repository
+	cartUC       cart.UseCase
+}
+
+// Wishlist UseCase constructor
+func NewWishlistUseCase(cfg *config.Config, logger logger.Logger, wishlistRepo wishlist.Repository, productRepo product.Repository, cartUC cart.UseCase) wishlist.UseCase {
+	return &wishlistUC{
+		cfg:          cfg,
+		logger:       logger,
+		wishlistRepo: wishlistRepo,
+		productRepo:  productRepo,
+		cartUC:       cartUC,
+	}
+}
+
+func (u *wishlistUC) Create(ctx context.Context, w *models.Wishlist) (*models.Wishlist, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := u.wishlistRepo.CountByUser(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxWishlistsPerUser {
+		return nil, httpErrors.NewBadRequestError(errors.Errorf("wishlistUC.Create: max %d wishlists per user", maxWishlistsPerUser))
+	}
+
+	w.UserID = user.UserID
+	w.Prepare()
+
+	return u.wishlistRepo.Create(ctx, w)
+}
+
+func (u *wishlistUC) Rename(ctx context.Context, w *models.Wishlist) (*models.Wishlist, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	w.UserID = user.UserID
+	w.Prepare()
+
+	return u.wishlistRepo.Rename(ctx, w)
+}
+
+func (u *wishlistUC) Delete(ctx context.Context, wishlistID uuid.UUID) error {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	return u.wishlistRepo.Delete(ctx, user.UserID, wishlistID)
+}
+
+// Get own wishlist with items
+func (u *wishlistUC) GetByID(ctx context.Context, wishlistID uuid.UUID) (*models.Wishlist, error) {
+	// TODO: Tracing
+
+	w, err := u.getOwn(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.withItems(ctx, w)
+}
+
+// Own wishlists without items
+func (u *wishlistUC) List(ctx context.Context) ([]*models.Wishlist, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.wishlistRepo.ListByUser(ctx, user.UserID)
+}
+
+func (u *wishlistUC) AddItem(ctx context.Context, wishlistID uuid.UUID, variantID uuid.UUID) (*models.Wishlist, error) {
+	// TODO: Tracing
+
+	w, err := u.getOwn(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = u.productRepo.GetVariantByID(ctx, variantID); err != nil {
+		return nil, err
+	}
+
+	items, err := u.wishlistRepo.GetItems(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) >= maxWishlistItems {
+		return nil, httpErrors.NewBadRequestError(errors.Errorf("wishlistUC.AddItem: max %d items per wishlist", maxWishlistItems))
+	}
+
+	if err = u.wishlistRepo.AddItem(ctx, wishlistID, variantID); err != nil {
+		return nil, err
+	}
+
+	return u.withItems(ctx, w)
+}
+
+func (u *wishlistUC) RemoveItem(ctx context.Context, wishlistID uuid.UUID, variantID uuid.UUID) (*models.Wishlist, error) {
+	// TODO: Tracing
+
+	w, err := u.getOwn(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = u.wishlistRepo.RemoveItem(ctx, wishlistID, variantID); err != nil {
+		return nil, err
+	}
+
+	return u.withItems(ctx, w)
+}
+
+func (u *wishlistUC) ReorderItems(ctx context.Context, wishlistID uuid.UUID, order *models.WishlistOrder) (*models.Wishlist, error) {
+	// TODO: Tracing
+
+	w, err := u.getOwn(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = u.wishlistRepo.ReorderItems(ctx, wishlistID, order.VariantIDs); err != nil {
+		return nil, err
+	}
+
+	return u.withItems(ctx, w)
+}
+
+// Add one of the variant to the user's cart and drop it from the wishlist
+func (u *wishlistUC) MoveToCart(ctx context.Context, wishlistID uuid.UUID, variantID uuid.UUID) (*models.Cart, error) {
+	// TODO: Tracing
+
+	if _, err := u.getOwn(ctx, wishlistID); err != nil {
+		return nil, err
+	}
+
+	items, err := u.wishlistRepo.GetItems(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	if !containsVariant(items, variantID) {
+		return nil, httpErrors.NewNotFoundError(errors.New("wishlistUC.MoveToCart: variant is not in the wishlist"))
+	}
+
+	variant, err := u.productRepo.GetVariantByID(ctx, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := u.cartUC.AddItem(ctx, "", &models.CartItemInput{SKU: variant.SKU, Quantity: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = u.wishlistRepo.RemoveItem(ctx, wishlistID, variantID); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Share wishlist, an existing link is kept so links already sent keep working
+func (u *wishlistUC) Share(ctx context.Context, wishlistID uuid.UUID) (*models.Wishlist, error) {
+	// TODO: Tracing
+
+	w, err := u.getOwn(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.ShareToken != nil {
+		return w, nil
+	}
+
+	token, err := models.NewShareToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return u.wishlistRepo.SetShareToken(ctx, w.UserID, wishlistID, &token)
+}
+
+// Revoke share link
+func (u *wishlistUC) Unshare(ctx context.Context, wishlistID uuid.UUID) (*models.Wishlist, error) {
+	// TODO: Tracing
+
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.wishlistRepo.SetShareToken(ctx, user.UserID, wishlistID, nil)
+}
+
+// Public view of shared wishlist
+func (u *wishlistUC) GetShared(ctx context.Context, token string) (*models.SharedWishlist, error) {
+	// TODO: Tracing
+
+	w, err := u.wishlistRepo.GetByShareToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := u.wishlistRepo.GetItems(ctx, w.WishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SharedWishlist{Name: w.Name, Items: items}, nil
+}
+
+func (u *wishlistUC) getOwn(ctx context.Context, wishlistID uuid.UUID) (*models.Wishlist, error) {
+	user, err := middleware.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.wishlistRepo.GetByID(ctx, user.UserID, wishlistID)
+}
+
+func (u *wishlistUC) withItems(ctx context.Context, w *models.Wishlist) (*models.Wishlist, error) {
+	items, err := u.wishlistRepo.GetItems(ctx, w.WishlistID)
+	if err != nil {
+		return nil, err
+	}
+	w.Items = items
+
+	return w, nil
+}
+
+func containsVariant(items []*models.WishlistItem, variantID uuid.UUID) bool {
+	for _, item := range items {
+		if item.VariantID == variantID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,14 @@
+CREATE TABLE products (
+    product_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sku VARCHAR(64) UNIQUE NOT NULL CHECK (sku <> ''),
+    slug VARCHAR(128) UNIQUE NOT NULL CHECK (slug <> ''),
+    title VARCHAR(250) NOT NULL CHECK (title <> ''),
+    description TEXT NOT NULL DEFAULT '',
+    price BIGINT NOT NULL DEFAULT 0 CHECK (price >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    status VARCHAR(10) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'active', 'archived')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX products_status_created_at_idx ON products (status, created_at DESC);
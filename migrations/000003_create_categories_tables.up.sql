@@ -0,0 +1,22 @@
+CREATE TABLE categories (
+    category_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    parent_id UUID REFERENCES categories (category_id) ON DELETE RESTRICT,
+    name VARCHAR(100) NOT NULL CHECK (name <> ''),
+    slug VARCHAR(128) UNIQUE NOT NULL CHECK (slug <> ''),
+    path TEXT NOT NULL,
+    depth INTEGER NOT NULL DEFAULT 0 CHECK (depth >= 0),
+    position INTEGER NOT NULL DEFAULT 0 CHECK (position >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX categories_path_idx ON categories (path text_pattern_ops);
+CREATE INDEX categories_parent_position_idx ON categories (parent_id, position);
+
+CREATE TABLE product_categories (
+    product_id UUID NOT NULL REFERENCES products (product_id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories (category_id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, category_id)
+);
+
+CREATE INDEX product_categories_category_id_idx ON product_categories (category_id);
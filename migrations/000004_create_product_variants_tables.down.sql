@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS product_variants CASCADE;
+DROP TABLE IF EXISTS product_options CASCADE;
@@ -0,0 +1,30 @@
+CREATE TABLE product_options (
+    option_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products (product_id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL CHECK (name <> ''),
+    position INTEGER NOT NULL DEFAULT 0,
+    option_values JSONB NOT NULL DEFAULT '[]'::jsonb CHECK (jsonb_typeof(option_values) = 'array'),
+    UNIQUE (product_id, name)
+);
+
+CREATE TABLE product_variants (
+    variant_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products (product_id) ON DELETE CASCADE,
+    sku VARCHAR(64) UNIQUE NOT NULL CHECK (sku <> ''),
+    title VARCHAR(250) NOT NULL DEFAULT '',
+    options JSONB NOT NULL DEFAULT '{}'::jsonb CHECK (jsonb_typeof(options) = 'object'),
+    price_override BIGINT CHECK (price_override >= 0),
+    barcode VARCHAR(32),
+    weight_grams INTEGER NOT NULL DEFAULT 0 CHECK (weight_grams >= 0),
+    images JSONB NOT NULL DEFAULT '[]'::jsonb CHECK (jsonb_typeof(images) = 'array'),
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (product_id, options)
+);
+
+CREATE UNIQUE INDEX product_variants_barcode_idx ON product_variants (barcode) WHERE barcode IS NOT NULL;
+
+-- Every existing product becomes sellable through a default variant
+INSERT INTO product_variants (product_id, sku, title, is_default)
+SELECT product_id, sku, title, TRUE FROM products;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stock_movements CASCADE;
+DROP TABLE IF EXISTS stock_levels CASCADE;
+DROP FUNCTION IF EXISTS stock_movements_append_only();
@@ -0,0 +1,38 @@
+CREATE TABLE stock_levels (
+    sku VARCHAR(64) PRIMARY KEY REFERENCES product_variants (sku) ON UPDATE CASCADE ON DELETE CASCADE,
+    on_hand INTEGER NOT NULL DEFAULT 0 CHECK (on_hand >= 0),
+    low_stock_threshold INTEGER NOT NULL DEFAULT 0 CHECK (low_stock_threshold >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE stock_movements (
+    movement_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sku VARCHAR(64) NOT NULL REFERENCES stock_levels (sku) ON UPDATE CASCADE,
+    movement_type VARCHAR(20) NOT NULL CHECK (movement_type IN ('receipt', 'sale', 'return', 'adjustment')),
+    quantity INTEGER NOT NULL CHECK (quantity <> 0),
+    on_hand_after INTEGER NOT NULL CHECK (on_hand_after >= 0),
+    reason VARCHAR(250) NOT NULL DEFAULT '',
+    actor_user_id UUID REFERENCES users (user_id) ON DELETE SET NULL,
+    reference_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX stock_movements_sku_created_at_idx ON stock_movements (sku, created_at DESC);
+CREATE INDEX stock_movements_reference_id_idx ON stock_movements (reference_id) WHERE reference_id IS NOT NULL;
+
+-- Ledger is append only, only SKU renames and actor cleanup cascade into it
+CREATE OR REPLACE FUNCTION stock_movements_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE'
+        OR (NEW.movement_id, NEW.movement_type, NEW.quantity, NEW.on_hand_after, NEW.reason, NEW.reference_id, NEW.created_at)
+            IS DISTINCT FROM (OLD.movement_id, OLD.movement_type, OLD.quantity, OLD.on_hand_after, OLD.reason, OLD.reference_id, OLD.created_at)
+    THEN
+        RAISE EXCEPTION 'stock_movements is append only';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stock_movements_append_only
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW EXECUTE PROCEDURE stock_movements_append_only();
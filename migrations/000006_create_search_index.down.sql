@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS products_search_vector_refresh ON products;
+DROP FUNCTION IF EXISTS products_search_vector_refresh();
+DROP TRIGGER IF EXISTS product_variants_search_vector_update ON product_variants;
+DROP FUNCTION IF EXISTS product_variants_search_vector_update();
+DROP INDEX IF EXISTS products_title_trgm_idx;
+ALTER TABLE product_variants DROP COLUMN IF EXISTS search_vector;
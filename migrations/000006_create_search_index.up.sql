@@ -0,0 +1,41 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE product_variants ADD COLUMN search_vector TSVECTOR;
+
+-- Title and SKU weigh most, then the variant title, then the description
+CREATE OR REPLACE FUNCTION product_variants_search_vector_update() RETURNS trigger AS $$
+DECLARE
+    p_title TEXT;
+    p_description TEXT;
+BEGIN
+    SELECT title, description INTO p_title, p_description FROM products WHERE product_id = NEW.product_id;
+
+    NEW.search_vector :=
+        setweight(to_tsvector('english', COALESCE(p_title, '')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(NEW.sku, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(NEW.title, '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(p_description, '')), 'C');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER product_variants_search_vector_update
+    BEFORE INSERT OR UPDATE ON product_variants
+    FOR EACH ROW EXECUTE PROCEDURE product_variants_search_vector_update();
+
+-- Touching the variants recomputes their vectors from the updated product
+CREATE OR REPLACE FUNCTION products_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    UPDATE product_variants SET search_vector = NULL WHERE product_id = NEW.product_id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER products_search_vector_refresh
+    AFTER UPDATE OF title, description ON products
+    FOR EACH ROW EXECUTE PROCEDURE products_search_vector_refresh();
+
+UPDATE product_variants SET search_vector = NULL;
+
+CREATE INDEX product_variants_search_vector_idx ON product_variants USING GIN (search_vector);
+CREATE INDEX products_title_trgm_idx ON products USING GIN (title gin_trgm_ops);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS product_variants_product_id_idx;
+DROP INDEX IF EXISTS products_active_title_idx;
+DROP INDEX IF EXISTS products_active_created_at_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS brand;
@@ -0,0 +1,6 @@
+ALTER TABLE products ADD COLUMN brand VARCHAR(100) NOT NULL DEFAULT '';
+
+CREATE INDEX products_brand_idx ON products (brand) WHERE status = 'active';
+CREATE INDEX products_active_created_at_idx ON products (created_at DESC, product_id DESC) WHERE status = 'active';
+CREATE INDEX products_active_title_idx ON products (title, product_id) WHERE status = 'active';
+CREATE INDEX product_variants_product_id_idx ON product_variants (product_id);
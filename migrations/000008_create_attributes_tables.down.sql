@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS product_variants_attributes_idx;
+ALTER TABLE product_variants DROP COLUMN IF EXISTS attributes;
+ALTER TABLE products DROP COLUMN IF EXISTS attribute_set_id;
+DROP TABLE IF EXISTS attributes CASCADE;
+DROP TABLE IF EXISTS attribute_sets CASCADE;
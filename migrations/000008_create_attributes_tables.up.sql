@@ -0,0 +1,28 @@
+CREATE TABLE attribute_sets
+(
+    attribute_set_id UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    name             VARCHAR(100)             NOT NULL UNIQUE CHECK ( name <> '' ),
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMP WITH TIME ZONE          DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE attributes
+(
+    attribute_id     UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    attribute_set_id UUID                     NOT NULL REFERENCES attribute_sets (attribute_set_id) ON DELETE CASCADE,
+    code             VARCHAR(50)              NOT NULL CHECK ( code <> '' ),
+    label            VARCHAR(100)             NOT NULL,
+    type             VARCHAR(10)              NOT NULL CHECK ( type IN ('text', 'number', 'enum', 'boolean') ),
+    required         BOOLEAN                  NOT NULL DEFAULT FALSE,
+    enum_values      JSONB                    NOT NULL DEFAULT '[]',
+    rules            VARCHAR(250)             NOT NULL DEFAULT '',
+    position         INTEGER                  NOT NULL DEFAULT 0,
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMP WITH TIME ZONE          DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (attribute_set_id, code)
+);
+
+ALTER TABLE products ADD COLUMN attribute_set_id UUID REFERENCES attribute_sets (attribute_set_id) ON DELETE SET NULL;
+ALTER TABLE product_variants ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX product_variants_attributes_idx ON product_variants USING GIN (attributes jsonb_path_ops);
@@ -0,0 +1,4 @@
+ALTER TABLE products DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE products DROP COLUMN IF EXISTS rating_avg;
+DROP TABLE IF EXISTS review_votes CASCADE;
+DROP TABLE IF EXISTS reviews CASCADE;
@@ -0,0 +1,32 @@
+CREATE TABLE reviews
+(
+    review_id         UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    product_id        UUID                     NOT NULL REFERENCES products (product_id) ON DELETE CASCADE,
+    user_id           UUID                     NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    rating            SMALLINT                 NOT NULL CHECK ( rating BETWEEN 1 AND 5 ),
+    title             VARCHAR(150)             NOT NULL DEFAULT '',
+    body              TEXT                     NOT NULL DEFAULT '',
+    status            VARCHAR(10)              NOT NULL DEFAULT 'pending' CHECK ( status IN ('pending', 'approved', 'rejected') ),
+    verified_purchase BOOLEAN                  NOT NULL DEFAULT FALSE,
+    helpful_count     INTEGER                  NOT NULL DEFAULT 0 CHECK ( helpful_count >= 0 ),
+    moderated_by      UUID                     REFERENCES users (user_id) ON DELETE SET NULL,
+    moderated_at      TIMESTAMP WITH TIME ZONE,
+    created_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMP WITH TIME ZONE          DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (product_id, user_id)
+);
+
+CREATE INDEX reviews_product_approved_idx ON reviews (product_id, created_at DESC) WHERE status = 'approved';
+CREATE INDEX reviews_status_idx ON reviews (status, created_at);
+
+CREATE TABLE review_votes
+(
+    review_id  UUID                     NOT NULL REFERENCES reviews (review_id) ON DELETE CASCADE,
+    user_id    UUID                     NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (review_id, user_id)
+);
+
+-- Aggregates of approved reviews, refreshed by the review repository on every change
+ALTER TABLE products ADD COLUMN rating_avg NUMERIC(3, 2) NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN rating_count INTEGER NOT NULL DEFAULT 0;
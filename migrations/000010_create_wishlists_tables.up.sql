@@ -0,0 +1,21 @@
+CREATE TABLE wishlists
+(
+    wishlist_id UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    user_id     UUID                     NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    name        VARCHAR(100)             NOT NULL CHECK ( name <> '' ),
+    share_token VARCHAR(64) UNIQUE,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP WITH TIME ZONE          DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE wishlist_items
+(
+    wishlist_id UUID                     NOT NULL REFERENCES wishlists (wishlist_id) ON DELETE CASCADE,
+    variant_id  UUID                     NOT NULL REFERENCES product_variants (variant_id) ON DELETE CASCADE,
+    position    INTEGER                  NOT NULL DEFAULT 0 CHECK ( position >= 0 ),
+    added_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (wishlist_id, variant_id)
+);
+
+CREATE INDEX wishlist_items_position_idx ON wishlist_items (wishlist_id, position);
@@ -0,0 +1,30 @@
+CREATE TABLE import_jobs
+(
+    job_id       UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    format       VARCHAR(10)              NOT NULL CHECK ( format IN ('csv', 'jsonl') ),
+    dry_run      BOOLEAN                  NOT NULL DEFAULT FALSE,
+    status       VARCHAR(10)              NOT NULL DEFAULT 'pending' CHECK ( status IN ('pending', 'running', 'completed', 'failed') ),
+    file_name    VARCHAR(250)             NOT NULL DEFAULT '',
+    object_key   VARCHAR(250)             NOT NULL DEFAULT '',
+    total_rows   INTEGER                  NOT NULL DEFAULT 0,
+    created_rows INTEGER                  NOT NULL DEFAULT 0,
+    updated_rows INTEGER                  NOT NULL DEFAULT 0,
+    failed_rows  INTEGER                  NOT NULL DEFAULT 0,
+    error        TEXT                     NOT NULL DEFAULT '',
+    created_by   UUID                     REFERENCES users (user_id) ON DELETE SET NULL,
+    started_at   TIMESTAMP WITH TIME ZONE,
+    finished_at  TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP WITH TIME ZONE          DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX import_jobs_created_at_idx ON import_jobs (created_at DESC);
+
+CREATE TABLE import_job_errors
+(
+    job_id     UUID         NOT NULL REFERENCES import_jobs (job_id) ON DELETE CASCADE,
+    row_number INTEGER      NOT NULL,
+    sku        VARCHAR(64)  NOT NULL DEFAULT '',
+    message    TEXT         NOT NULL,
+    PRIMARY KEY (job_id, row_number)
+);
@@ -0,0 +1,21 @@
+CREATE TABLE carts
+(
+    cart_id    UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    user_id    UUID                     REFERENCES users (user_id) ON DELETE CASCADE,
+    status     VARCHAR(10)              NOT NULL DEFAULT 'active' CHECK ( status IN ('active', 'converted') ),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One active cart per user, guest carts have no user
+CREATE UNIQUE INDEX carts_user_active_idx ON carts (user_id) WHERE status = 'active';
+CREATE INDEX carts_updated_at_idx ON carts (updated_at) WHERE status = 'active';
+
+CREATE TABLE cart_items
+(
+    cart_id  UUID                     NOT NULL REFERENCES carts (cart_id) ON DELETE CASCADE,
+    sku      VARCHAR(64)              NOT NULL REFERENCES product_variants (sku) ON UPDATE CASCADE ON DELETE CASCADE,
+    quantity INTEGER                  NOT NULL CHECK ( quantity > 0 AND quantity <= 99 ),
+    added_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (cart_id, sku)
+);
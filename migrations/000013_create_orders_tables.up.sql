@@ -0,0 +1,48 @@
+CREATE TABLE orders
+(
+    order_id   UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    user_id    UUID                     REFERENCES users (user_id) ON DELETE SET NULL,
+    email      VARCHAR(64)              NOT NULL,
+    status     VARCHAR(10)              NOT NULL DEFAULT 'pending' CHECK ( status IN ('pending', 'paid', 'fulfilled', 'shipped', 'delivered', 'cancelled', 'refunded') ),
+    currency   CHAR(3)                  NOT NULL DEFAULT 'USD',
+    item_count INTEGER                  NOT NULL CHECK ( item_count > 0 ),
+    subtotal   BIGINT                   NOT NULL CHECK ( subtotal >= 0 ),
+    total      BIGINT                   NOT NULL CHECK ( total >= 0 ),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX orders_user_idx ON orders (user_id, created_at DESC);
+CREATE INDEX orders_status_idx ON orders (status, created_at DESC);
+
+-- Lines keep title, SKU and price as they were at purchase time
+CREATE TABLE order_items
+(
+    order_item_id UUID PRIMARY KEY      DEFAULT uuid_generate_v4(),
+    order_id      UUID         NOT NULL REFERENCES orders (order_id) ON DELETE CASCADE,
+    variant_id    UUID         REFERENCES product_variants (variant_id) ON DELETE SET NULL,
+    product_id    UUID         REFERENCES products (product_id) ON DELETE SET NULL,
+    sku           VARCHAR(64)  NOT NULL,
+    title         VARCHAR(250) NOT NULL,
+    variant_title VARCHAR(250) NOT NULL DEFAULT '',
+    unit_price    BIGINT       NOT NULL CHECK ( unit_price >= 0 ),
+    quantity      INTEGER      NOT NULL CHECK ( quantity > 0 ),
+    line_total    BIGINT       NOT NULL CHECK ( line_total >= 0 )
+);
+
+CREATE INDEX order_items_order_idx ON order_items (order_id);
+CREATE INDEX order_items_product_idx ON order_items (product_id);
+
+-- Status history, append only
+CREATE TABLE order_events
+(
+    event_id    UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    order_id    UUID                     NOT NULL REFERENCES orders (order_id) ON DELETE CASCADE,
+    from_status VARCHAR(10),
+    to_status   VARCHAR(10)              NOT NULL,
+    note        VARCHAR(500)             NOT NULL DEFAULT '',
+    actor_id    UUID                     REFERENCES users (user_id) ON DELETE SET NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX order_events_order_idx ON order_events (order_id, created_at);
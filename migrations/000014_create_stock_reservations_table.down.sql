@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS stock_reservations CASCADE;
+ALTER TABLE stock_levels DROP COLUMN IF EXISTS reserved;
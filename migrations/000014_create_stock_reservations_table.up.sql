@@ -0,0 +1,17 @@
+-- Quantity held by pending orders, available stock is on_hand - reserved
+ALTER TABLE stock_levels ADD COLUMN reserved INTEGER NOT NULL DEFAULT 0 CHECK (reserved >= 0);
+
+CREATE TABLE stock_reservations
+(
+    reservation_id UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    order_id       UUID                     NOT NULL REFERENCES orders (order_id) ON DELETE CASCADE,
+    sku            VARCHAR(64)              NOT NULL REFERENCES stock_levels (sku) ON UPDATE CASCADE,
+    quantity       INTEGER                  NOT NULL CHECK ( quantity > 0 ),
+    status         VARCHAR(10)              NOT NULL DEFAULT 'active' CHECK ( status IN ('active', 'committed', 'released') ),
+    expires_at     TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (order_id, sku)
+);
+
+CREATE INDEX stock_reservations_expires_at_idx ON stock_reservations (expires_at) WHERE status = 'active';
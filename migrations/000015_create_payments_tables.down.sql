@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS webhook_events CASCADE;
+DROP TABLE IF EXISTS payments CASCADE;
@@ -0,0 +1,28 @@
+CREATE TABLE payments
+(
+    payment_id      UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    order_id        UUID                     NOT NULL REFERENCES orders (order_id) ON DELETE CASCADE,
+    provider        VARCHAR(20)              NOT NULL,
+    intent_id       VARCHAR(255)             NOT NULL,
+    status          VARCHAR(10)              NOT NULL DEFAULT 'pending' CHECK ( status IN ('pending', 'succeeded', 'failed', 'refunded') ),
+    amount          BIGINT                   NOT NULL CHECK ( amount > 0 ),
+    refunded_amount BIGINT                   NOT NULL DEFAULT 0 CHECK ( refunded_amount >= 0 AND refunded_amount <= amount ),
+    currency        CHAR(3)                  NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, intent_id)
+);
+
+CREATE INDEX payments_order_idx ON payments (order_id, created_at);
+
+-- Every verified webhook event once, providers retry deliveries
+CREATE TABLE webhook_events
+(
+    provider     VARCHAR(20)              NOT NULL,
+    event_id     VARCHAR(255)             NOT NULL,
+    event_type   VARCHAR(50)              NOT NULL,
+    payload      JSONB                    NOT NULL,
+    received_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (provider, event_id)
+);
@@ -0,0 +1,17 @@
+-- Responses of unsafe requests sent with an Idempotency-Key header, scope is a hash of the caller credentials
+CREATE TABLE idempotency_keys
+(
+    scope                 CHAR(64)                 NOT NULL,
+    idempotency_key       VARCHAR(255)             NOT NULL,
+    fingerprint           CHAR(64)                 NOT NULL,
+    status                VARCHAR(12)              NOT NULL DEFAULT 'in_progress' CHECK ( status IN ('in_progress', 'completed') ),
+    response_status       INTEGER,
+    response_content_type VARCHAR(255),
+    response_body         BYTEA,
+    created_at            TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_at             TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at            TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (scope, idempotency_key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS order_discounts CASCADE;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_total;
+DROP TABLE IF EXISTS promotions CASCADE;
@@ -0,0 +1,47 @@
+-- Promotions without code apply automatically, value is a percent for percentage and minor units for fixed
+CREATE TABLE promotions
+(
+    promotion_id       UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    name               VARCHAR(250)             NOT NULL,
+    description        VARCHAR(1000)            NOT NULL DEFAULT '',
+    code               VARCHAR(64) UNIQUE,
+    kind               VARCHAR(20)              NOT NULL CHECK ( kind IN ('percentage', 'fixed', 'free_shipping', 'buy_x_get_y') ),
+    value              BIGINT                   NOT NULL DEFAULT 0 CHECK ( value >= 0 ),
+    currency           CHAR(3)                  NOT NULL DEFAULT 'USD',
+    product_id         UUID                     REFERENCES products (product_id) ON DELETE CASCADE,
+    min_subtotal       BIGINT                   NOT NULL DEFAULT 0 CHECK ( min_subtotal >= 0 ),
+    buy_quantity       INTEGER                  NOT NULL DEFAULT 0 CHECK ( buy_quantity >= 0 ),
+    get_quantity       INTEGER                  NOT NULL DEFAULT 0 CHECK ( get_quantity >= 0 ),
+    stackable          BOOLEAN                  NOT NULL DEFAULT FALSE,
+    priority           INTEGER                  NOT NULL DEFAULT 0,
+    usage_limit        INTEGER CHECK ( usage_limit > 0 ),
+    per_customer_limit INTEGER CHECK ( per_customer_limit > 0 ),
+    used_count         INTEGER                  NOT NULL DEFAULT 0 CHECK ( used_count >= 0 ),
+    active             BOOLEAN                  NOT NULL DEFAULT TRUE,
+    starts_at          TIMESTAMP WITH TIME ZONE,
+    ends_at            TIMESTAMP WITH TIME ZONE,
+    created_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ( ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at )
+);
+
+CREATE INDEX promotions_automatic_idx ON promotions (priority DESC) WHERE code IS NULL AND active;
+
+ALTER TABLE orders ADD COLUMN discount_total BIGINT NOT NULL DEFAULT 0 CHECK ( discount_total >= 0 );
+
+-- Discounts applied to an order, also the usage record of promotions
+CREATE TABLE order_discounts
+(
+    order_discount_id UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    order_id          UUID                     NOT NULL REFERENCES orders (order_id) ON DELETE CASCADE,
+    promotion_id      UUID                     REFERENCES promotions (promotion_id) ON DELETE SET NULL,
+    user_id           UUID                     REFERENCES users (user_id) ON DELETE SET NULL,
+    code              VARCHAR(64),
+    description       VARCHAR(500)             NOT NULL,
+    amount            BIGINT                   NOT NULL CHECK ( amount >= 0 ),
+    free_shipping     BOOLEAN                  NOT NULL DEFAULT FALSE,
+    created_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (order_id, promotion_id)
+);
+
+CREATE INDEX order_discounts_promotion_user_idx ON order_discounts (promotion_id, user_id);
@@ -0,0 +1,10 @@
+ALTER TABLE order_items
+    DROP COLUMN IF EXISTS tax_amount,
+    DROP COLUMN IF EXISTS tax_rate_ppm,
+    DROP COLUMN IF EXISTS tax_class;
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS prices_include_tax,
+    DROP COLUMN IF EXISTS tax_total;
+DROP TABLE IF EXISTS tax_rates CASCADE;
+DROP TABLE IF EXISTS tax_zones CASCADE;
+ALTER TABLE products DROP COLUMN IF EXISTS tax_class;
@@ -0,0 +1,40 @@
+ALTER TABLE products ADD COLUMN tax_class VARCHAR(32) NOT NULL DEFAULT 'standard';
+
+-- Region is matched against the city of the address, postcode pattern is a glob like 90*
+CREATE TABLE tax_zones
+(
+    zone_id            UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    name               VARCHAR(100)             NOT NULL,
+    country            VARCHAR(30)              NOT NULL,
+    region             VARCHAR(30),
+    postcode_pattern   VARCHAR(30),
+    priority           INTEGER                  NOT NULL DEFAULT 0,
+    prices_include_tax BOOLEAN                  NOT NULL DEFAULT FALSE,
+    rounding           VARCHAR(5)               NOT NULL DEFAULT 'line' CHECK ( rounding IN ('line', 'order') ),
+    created_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX tax_zones_country_idx ON tax_zones (lower(country));
+
+-- Rate in millionths of the price, 200000 is 20%
+CREATE TABLE tax_rates
+(
+    rate_id    UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    zone_id    UUID                     NOT NULL REFERENCES tax_zones (zone_id) ON DELETE CASCADE,
+    tax_class  VARCHAR(32)              NOT NULL,
+    name       VARCHAR(100)             NOT NULL,
+    rate_ppm   INTEGER                  NOT NULL CHECK ( rate_ppm >= 0 AND rate_ppm <= 1000000 ),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (zone_id, tax_class)
+);
+
+ALTER TABLE orders
+    ADD COLUMN tax_total          BIGINT  NOT NULL DEFAULT 0 CHECK ( tax_total >= 0 ),
+    ADD COLUMN prices_include_tax BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE order_items
+    ADD COLUMN tax_class    VARCHAR(32) NOT NULL DEFAULT 'standard',
+    ADD COLUMN tax_rate_ppm INTEGER     NOT NULL DEFAULT 0,
+    ADD COLUMN tax_amount   BIGINT      NOT NULL DEFAULT 0 CHECK ( tax_amount >= 0 );
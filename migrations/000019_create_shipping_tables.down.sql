@@ -0,0 +1,7 @@
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS shipping_address,
+    DROP COLUMN IF EXISTS shipping_total,
+    DROP COLUMN IF EXISTS shipping_method,
+    DROP COLUMN IF EXISTS shipping_method_id;
+DROP TABLE IF EXISTS shipping_methods CASCADE;
+DROP TABLE IF EXISTS shipping_zones CASCADE;
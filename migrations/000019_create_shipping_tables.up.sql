@@ -0,0 +1,41 @@
+-- Region is matched against the city of the address, postcode pattern is a glob like 90*
+CREATE TABLE shipping_zones
+(
+    zone_id          UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    name             VARCHAR(100)             NOT NULL,
+    country          VARCHAR(30)              NOT NULL,
+    region           VARCHAR(30),
+    postcode_pattern VARCHAR(30),
+    priority         INTEGER                  NOT NULL DEFAULT 0,
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX shipping_zones_country_idx ON shipping_zones (lower(country));
+
+-- Weight rates are [{"max_grams": 1000, "price": 500}, ...] sorted by max_grams
+CREATE TABLE shipping_methods
+(
+    method_id       UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    zone_id         UUID                     NOT NULL REFERENCES shipping_zones (zone_id) ON DELETE CASCADE,
+    name            VARCHAR(100)             NOT NULL,
+    kind            VARCHAR(10)              NOT NULL CHECK ( kind IN ('flat', 'weight', 'free_over', 'pickup', 'carrier') ),
+    price           BIGINT                   NOT NULL DEFAULT 0 CHECK ( price >= 0 ),
+    currency        CHAR(3)                  NOT NULL DEFAULT 'USD',
+    free_over       BIGINT CHECK ( free_over >= 0 ),
+    weight_rates    JSONB                    NOT NULL DEFAULT '[]',
+    carrier_service VARCHAR(64),
+    estimated_days  INTEGER CHECK ( estimated_days >= 0 ),
+    active          BOOLEAN                  NOT NULL DEFAULT TRUE,
+    position        INTEGER                  NOT NULL DEFAULT 0,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX shipping_methods_zone_idx ON shipping_methods (zone_id, position);
+
+ALTER TABLE orders
+    ADD COLUMN shipping_method_id UUID REFERENCES shipping_methods (method_id) ON DELETE SET NULL,
+    ADD COLUMN shipping_method    VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN shipping_total     BIGINT       NOT NULL DEFAULT 0 CHECK ( shipping_total >= 0 ),
+    ADD COLUMN shipping_address   JSONB;
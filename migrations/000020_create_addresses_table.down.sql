@@ -0,0 +1,16 @@
+ALTER TABLE users
+    ADD COLUMN address  VARCHAR(250),
+    ADD COLUMN city     VARCHAR(30),
+    ADD COLUMN country  VARCHAR(30),
+    ADD COLUMN postcode INTEGER;
+
+UPDATE users u
+SET address  = a.line1,
+    city     = left(a.city, 30),
+    country  = a.country,
+    postcode = CASE WHEN a.postcode ~ '^[0-9]{1,9}$' THEN a.postcode::INTEGER END
+FROM addresses a
+WHERE a.user_id = u.user_id
+  AND a.is_default_shipping;
+
+DROP TABLE IF EXISTS addresses CASCADE;
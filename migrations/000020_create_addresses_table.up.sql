@@ -0,0 +1,54 @@
+-- Country is an ISO 3166-1 alpha-2 code, rows moved from users may still hold the old free text
+CREATE TABLE addresses
+(
+    address_id          UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    user_id             UUID                     NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    label               VARCHAR(50)              NOT NULL DEFAULT '',
+    first_name          VARCHAR(32)              NOT NULL,
+    last_name           VARCHAR(32)              NOT NULL,
+    company             VARCHAR(100),
+    line1               VARCHAR(250)             NOT NULL,
+    line2               VARCHAR(250),
+    city                VARCHAR(50)              NOT NULL,
+    region              VARCHAR(50),
+    postcode            VARCHAR(20),
+    country             VARCHAR(30)              NOT NULL,
+    phone_number        VARCHAR(20),
+    is_default_shipping BOOLEAN                  NOT NULL DEFAULT FALSE,
+    is_default_billing  BOOLEAN                  NOT NULL DEFAULT FALSE,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX addresses_user_id_idx ON addresses (user_id, created_at);
+
+CREATE UNIQUE INDEX addresses_default_shipping_idx ON addresses (user_id) WHERE is_default_shipping;
+
+CREATE UNIQUE INDEX addresses_default_billing_idx ON addresses (user_id) WHERE is_default_billing;
+
+INSERT INTO addresses(user_id, label, first_name, last_name, line1, city, postcode, country, phone_number,
+                      is_default_shipping, is_default_billing, created_at, updated_at)
+SELECT user_id,
+       'Home',
+       first_name,
+       last_name,
+       COALESCE(trim(address), ''),
+       COALESCE(trim(city), ''),
+       postcode::TEXT,
+       CASE WHEN length(trim(country)) = 2 THEN upper(trim(country)) ELSE COALESCE(trim(country), '') END,
+       phone_number,
+       TRUE,
+       TRUE,
+       NOW(),
+       NOW()
+FROM users
+WHERE COALESCE(trim(address), '') <> ''
+   OR COALESCE(trim(city), '') <> ''
+   OR COALESCE(trim(country), '') <> ''
+   OR postcode IS NOT NULL;
+
+ALTER TABLE users
+    DROP COLUMN address,
+    DROP COLUMN city,
+    DROP COLUMN country,
+    DROP COLUMN postcode;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS return_events CASCADE;
+DROP TABLE IF EXISTS return_items CASCADE;
+DROP TABLE IF EXISTS returns CASCADE;
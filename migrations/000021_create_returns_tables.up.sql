@@ -0,0 +1,54 @@
+CREATE TABLE returns
+(
+    return_id      UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    order_id       UUID                     NOT NULL REFERENCES orders (order_id) ON DELETE CASCADE,
+    user_id        UUID                     REFERENCES users (user_id) ON DELETE SET NULL,
+    status         VARCHAR(10)              NOT NULL DEFAULT 'requested'
+        CHECK ( status IN ('requested', 'approved', 'rejected', 'cancelled', 'received', 'refunded', 'closed') ),
+    note           VARCHAR(1000)            NOT NULL DEFAULT '',
+    label_carrier  VARCHAR(64),
+    label_tracking VARCHAR(100),
+    label_url      VARCHAR(512),
+    currency       CHAR(3)                  NOT NULL,
+    refund_amount  BIGINT                   NOT NULL DEFAULT 0 CHECK ( refund_amount >= 0 ),
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX returns_order_idx ON returns (order_id);
+CREATE INDEX returns_user_idx ON returns (user_id, created_at DESC);
+CREATE INDEX returns_status_idx ON returns (status, created_at DESC);
+
+CREATE TABLE return_items
+(
+    return_item_id     UUID PRIMARY KEY      DEFAULT uuid_generate_v4(),
+    return_id          UUID         NOT NULL REFERENCES returns (return_id) ON DELETE CASCADE,
+    order_item_id      UUID         NOT NULL REFERENCES order_items (order_item_id) ON DELETE CASCADE,
+    sku                VARCHAR(64)  NOT NULL,
+    quantity           INTEGER      NOT NULL CHECK ( quantity > 0 ),
+    reason             VARCHAR(20)  NOT NULL
+        CHECK ( reason IN ('damaged', 'defective', 'wrong_item', 'not_as_described', 'no_longer_needed', 'other') ),
+    note               VARCHAR(500) NOT NULL DEFAULT '',
+    received_quantity  INTEGER      NOT NULL DEFAULT 0 CHECK ( received_quantity >= 0 AND received_quantity <= quantity ),
+    restocked_quantity INTEGER      NOT NULL DEFAULT 0
+        CHECK ( restocked_quantity >= 0 AND restocked_quantity <= received_quantity ),
+    inspection_note    VARCHAR(500) NOT NULL DEFAULT '',
+    refund_amount      BIGINT       NOT NULL DEFAULT 0 CHECK ( refund_amount >= 0 ),
+    UNIQUE (return_id, order_item_id)
+);
+
+CREATE INDEX return_items_order_item_idx ON return_items (order_item_id);
+
+-- Audit history of every step, append only
+CREATE TABLE return_events
+(
+    event_id    UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    return_id   UUID                     NOT NULL REFERENCES returns (return_id) ON DELETE CASCADE,
+    from_status VARCHAR(10),
+    to_status   VARCHAR(10)              NOT NULL,
+    note        VARCHAR(1000)            NOT NULL DEFAULT '',
+    actor_id    UUID                     REFERENCES users (user_id) ON DELETE SET NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX return_events_return_idx ON return_events (return_id, created_at);
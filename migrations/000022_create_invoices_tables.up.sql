@@ -0,0 +1,57 @@
+-- Last issued number per store and document type. A number is taken in the transaction that inserts its
+-- document, the row stays locked until commit and a rollback gives the number back so numbering is gap free.
+CREATE TABLE invoice_sequences
+(
+    store_code  VARCHAR(20) NOT NULL,
+    type        VARCHAR(11) NOT NULL CHECK ( type IN ('invoice', 'credit_note') ),
+    last_number BIGINT      NOT NULL DEFAULT 0,
+    PRIMARY KEY (store_code, type)
+);
+
+-- Issued invoices and credit notes, never changed after issue except for the stored file
+CREATE TABLE invoices
+(
+    invoice_id          UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    store_code          VARCHAR(20)              NOT NULL,
+    type                VARCHAR(11)              NOT NULL CHECK ( type IN ('invoice', 'credit_note') ),
+    sequence_number     BIGINT                   NOT NULL CHECK ( sequence_number > 0 ),
+    number              VARCHAR(40)              NOT NULL UNIQUE,
+    order_id            UUID                     NOT NULL REFERENCES orders (order_id) ON DELETE RESTRICT,
+    return_id           UUID                     REFERENCES returns (return_id) ON DELETE RESTRICT,
+    original_invoice_id UUID                     REFERENCES invoices (invoice_id) ON DELETE RESTRICT,
+    user_id             UUID                     REFERENCES users (user_id) ON DELETE SET NULL,
+    email               VARCHAR(64)              NOT NULL,
+    bill_to             JSONB,
+    currency            CHAR(3)                  NOT NULL,
+    prices_include_tax  BOOLEAN                  NOT NULL DEFAULT FALSE,
+    subtotal            BIGINT                   NOT NULL DEFAULT 0,
+    discount_total      BIGINT                   NOT NULL DEFAULT 0,
+    shipping_total      BIGINT                   NOT NULL DEFAULT 0,
+    tax_total           BIGINT                   NOT NULL DEFAULT 0,
+    total               BIGINT                   NOT NULL DEFAULT 0,
+    object_key          VARCHAR(250),
+    issued_at           TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (store_code, type, sequence_number),
+    CHECK ( type = 'invoice' OR (return_id IS NOT NULL AND original_invoice_id IS NOT NULL) )
+);
+
+-- One invoice per order and one credit note per return
+CREATE UNIQUE INDEX invoices_order_invoice_idx ON invoices (order_id) WHERE type = 'invoice';
+CREATE UNIQUE INDEX invoices_return_credit_note_idx ON invoices (return_id) WHERE type = 'credit_note';
+CREATE INDEX invoices_user_idx ON invoices (user_id, issued_at DESC);
+
+-- Printed lines, discounts are negative lines
+CREATE TABLE invoice_lines
+(
+    line_id      UUID PRIMARY KEY      DEFAULT uuid_generate_v4(),
+    invoice_id   UUID         NOT NULL REFERENCES invoices (invoice_id) ON DELETE CASCADE,
+    position     INTEGER      NOT NULL,
+    sku          VARCHAR(64)  NOT NULL DEFAULT '',
+    description  VARCHAR(500) NOT NULL,
+    quantity     INTEGER      NOT NULL CHECK ( quantity > 0 ),
+    unit_price   BIGINT       NOT NULL,
+    tax_rate_ppm BIGINT       NOT NULL DEFAULT 0,
+    tax_amount   BIGINT       NOT NULL DEFAULT 0,
+    line_total   BIGINT       NOT NULL,
+    UNIQUE (invoice_id, position)
+);
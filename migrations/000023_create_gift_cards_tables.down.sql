@@ -0,0 +1,7 @@
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS store_credit_total,
+    DROP COLUMN IF EXISTS gift_card_total;
+
+DROP TABLE IF EXISTS store_credit_ledger CASCADE;
+DROP TABLE IF EXISTS gift_card_transactions CASCADE;
+DROP TABLE IF EXISTS gift_cards CASCADE;
@@ -0,0 +1,65 @@
+-- Gift cards, codes are stored upper case without separators. The balance is changed with a conditional update
+-- under the row lock and every change is recorded in gift_card_transactions.
+CREATE TABLE gift_cards
+(
+    gift_card_id    UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    code            VARCHAR(32)              NOT NULL UNIQUE,
+    currency        CHAR(3)                  NOT NULL,
+    initial_amount  BIGINT                   NOT NULL CHECK ( initial_amount > 0 ),
+    balance         BIGINT                   NOT NULL CHECK ( balance >= 0 AND balance <= initial_amount ),
+    status          VARCHAR(10)              NOT NULL DEFAULT 'active' CHECK ( status IN ('active', 'disabled') ),
+    expires_at      TIMESTAMP WITH TIME ZONE,
+    recipient_email VARCHAR(64),
+    note            VARCHAR(500)             NOT NULL DEFAULT '',
+    order_id        UUID                     REFERENCES orders (order_id) ON DELETE SET NULL,
+    issued_by       UUID                     REFERENCES users (user_id) ON DELETE SET NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX gift_cards_created_idx ON gift_cards (created_at DESC);
+
+-- Append only history of gift card balances, redemptions are negative
+CREATE TABLE gift_card_transactions
+(
+    transaction_id UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    gift_card_id   UUID                     NOT NULL REFERENCES gift_cards (gift_card_id) ON DELETE CASCADE,
+    kind           VARCHAR(10)              NOT NULL CHECK ( kind IN ('issue', 'redeem', 'reversal') ),
+    amount         BIGINT                   NOT NULL CHECK ( amount <> 0 ),
+    balance_after  BIGINT                   NOT NULL CHECK ( balance_after >= 0 ),
+    order_id       UUID                     REFERENCES orders (order_id) ON DELETE SET NULL,
+    note           VARCHAR(500)             NOT NULL DEFAULT '',
+    actor_id       UUID                     REFERENCES users (user_id) ON DELETE SET NULL,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX gift_card_transactions_card_idx ON gift_card_transactions (gift_card_id, created_at);
+CREATE INDEX gift_card_transactions_order_idx ON gift_card_transactions (order_id);
+
+-- Append only store credit ledger, the balance of a user is the sum of the entries per currency. Credits are
+-- positive and redemptions negative, debits are written while the user row is locked so balances never go below 0.
+CREATE TABLE store_credit_ledger
+(
+    entry_id   UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    user_id    UUID                     NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    currency   CHAR(3)                  NOT NULL,
+    amount     BIGINT                   NOT NULL CHECK ( amount <> 0 ),
+    reason     VARCHAR(10)              NOT NULL
+        CHECK ( reason IN ('refund', 'goodwill', 'adjustment', 'redemption', 'reversal') ),
+    order_id   UUID                     REFERENCES orders (order_id) ON DELETE SET NULL,
+    return_id  UUID                     REFERENCES returns (return_id) ON DELETE SET NULL,
+    note       VARCHAR(500)             NOT NULL DEFAULT '',
+    actor_id   UUID                     REFERENCES users (user_id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX store_credit_ledger_user_idx ON store_credit_ledger (user_id, currency, created_at DESC);
+CREATE INDEX store_credit_ledger_order_idx ON store_credit_ledger (order_id);
+
+-- A refund goes to store credit once per return
+CREATE UNIQUE INDEX store_credit_ledger_return_idx ON store_credit_ledger (return_id) WHERE reason = 'refund';
+
+-- Part of the order total paid with gift cards and store credit, the rest is paid through the payment provider
+ALTER TABLE orders
+    ADD COLUMN gift_card_total    BIGINT NOT NULL DEFAULT 0 CHECK ( gift_card_total >= 0 ),
+    ADD COLUMN store_credit_total BIGINT NOT NULL DEFAULT 0 CHECK ( store_credit_total >= 0 );
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS orders_subscription_idx;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS subscription_id;
+
+DROP TABLE IF EXISTS subscription_runs CASCADE;
+DROP TABLE IF EXISTS subscription_items CASCADE;
+DROP TABLE IF EXISTS subscriptions CASCADE;
@@ -0,0 +1,56 @@
+-- Recurring orders. A run is due at next_run_at, or at retry_at while a failed charge is retried (past_due).
+-- claimed_until keeps a run picked up by one scheduler instance away from the others.
+CREATE TABLE subscriptions
+(
+    subscription_id     UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    user_id             UUID                     NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    status              VARCHAR(10)              NOT NULL DEFAULT 'active'
+        CHECK ( status IN ('active', 'past_due', 'paused', 'suspended', 'cancelled') ),
+    interval_unit       VARCHAR(5)               NOT NULL CHECK ( interval_unit IN ('day', 'week', 'month') ),
+    interval_count      INTEGER                  NOT NULL CHECK ( interval_count > 0 AND interval_count <= 12 ),
+    next_run_at         TIMESTAMP WITH TIME ZONE NOT NULL,
+    retry_at            TIMESTAMP WITH TIME ZONE,
+    failed_attempts     INTEGER                  NOT NULL DEFAULT 0 CHECK ( failed_attempts >= 0 ),
+    last_error          VARCHAR(500)             NOT NULL DEFAULT '',
+    shipping_address_id UUID                     REFERENCES addresses (address_id) ON DELETE SET NULL,
+    shipping_method_id  UUID                     REFERENCES shipping_methods (method_id) ON DELETE SET NULL,
+    payment_customer_id VARCHAR(100)             NOT NULL DEFAULT '',
+    payment_method_id   VARCHAR(100)             NOT NULL CHECK ( payment_method_id <> '' ),
+    last_order_id       UUID                     REFERENCES orders (order_id) ON DELETE SET NULL,
+    claimed_until       TIMESTAMP WITH TIME ZONE,
+    cancelled_at        TIMESTAMP WITH TIME ZONE,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX subscriptions_user_idx ON subscriptions (user_id, created_at DESC);
+CREATE INDEX subscriptions_due_idx ON subscriptions (COALESCE(retry_at, next_run_at)) WHERE status IN ('active', 'past_due');
+
+CREATE TABLE subscription_items
+(
+    subscription_id UUID        NOT NULL REFERENCES subscriptions (subscription_id) ON DELETE CASCADE,
+    sku             VARCHAR(64) NOT NULL REFERENCES product_variants (sku) ON UPDATE CASCADE ON DELETE CASCADE,
+    quantity        INTEGER     NOT NULL CHECK ( quantity > 0 AND quantity <= 99 ),
+    PRIMARY KEY (subscription_id, sku)
+);
+
+-- Every order attempt of a subscription, scheduled_at is the delivery date the attempt belongs to
+CREATE TABLE subscription_runs
+(
+    run_id          UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    subscription_id UUID                     NOT NULL REFERENCES subscriptions (subscription_id) ON DELETE CASCADE,
+    scheduled_at    TIMESTAMP WITH TIME ZONE NOT NULL,
+    attempt         INTEGER                  NOT NULL CHECK ( attempt > 0 ),
+    status          VARCHAR(10)              NOT NULL CHECK ( status IN ('succeeded', 'failed') ),
+    order_id        UUID                     REFERENCES orders (order_id) ON DELETE SET NULL,
+    payment_id      UUID                     REFERENCES payments (payment_id) ON DELETE SET NULL,
+    error           VARCHAR(500)             NOT NULL DEFAULT '',
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX subscription_runs_subscription_idx ON subscription_runs (subscription_id, created_at DESC);
+
+ALTER TABLE orders
+    ADD COLUMN subscription_id UUID REFERENCES subscriptions (subscription_id) ON DELETE SET NULL;
+
+CREATE INDEX orders_subscription_idx ON orders (subscription_id) WHERE subscription_id IS NOT NULL;
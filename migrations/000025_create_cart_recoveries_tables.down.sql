@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS cart_recoveries CASCADE;
+DROP TABLE IF EXISTS email_unsubscribes CASCADE;
@@ -0,0 +1,36 @@
+-- Email lists a user opted out of, like cart_reminders
+CREATE TABLE email_unsubscribes
+(
+    user_id    UUID                     NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    list       VARCHAR(30)              NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, list)
+);
+
+-- Reminder sequence of an abandoned cart. step is the number of reminders sent, items the cart lines when the
+-- last one was sent. An open recovery converts when the user places an order within the attribution window.
+CREATE TABLE cart_recoveries
+(
+    recovery_id  UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    cart_id      UUID                     REFERENCES carts (cart_id) ON DELETE SET NULL,
+    user_id      UUID                     NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    status       VARCHAR(12)              NOT NULL DEFAULT 'open'
+        CHECK ( status IN ('open', 'converted', 'expired', 'unsubscribed') ),
+    step         INTEGER                  NOT NULL DEFAULT 1 CHECK ( step > 0 ),
+    items        JSONB                    NOT NULL DEFAULT '[]',
+    currency     CHAR(3)                  NOT NULL,
+    cart_total   BIGINT                   NOT NULL CHECK ( cart_total >= 0 ),
+    last_sent_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    restored_at  TIMESTAMP WITH TIME ZONE,
+    order_id     UUID                     REFERENCES orders (order_id) ON DELETE SET NULL,
+    order_total  BIGINT,
+    converted_at TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One reminder sequence at a time per cart
+CREATE UNIQUE INDEX cart_recoveries_open_cart_idx ON cart_recoveries (cart_id) WHERE status = 'open';
+CREATE INDEX cart_recoveries_due_idx ON cart_recoveries (step, last_sent_at) WHERE status = 'open';
+CREATE INDEX cart_recoveries_cart_idx ON cart_recoveries (cart_id, created_at DESC);
+CREATE INDEX cart_recoveries_created_idx ON cart_recoveries (created_at);
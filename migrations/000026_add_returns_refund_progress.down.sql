@@ -0,0 +1,15 @@
+UPDATE return_events SET from_status = 'received' WHERE from_status = 'refund_failed';
+UPDATE return_events SET to_status = 'received' WHERE to_status = 'refund_failed';
+UPDATE returns SET status = 'received', refund_amount = 0 WHERE status = 'refund_failed';
+
+ALTER TABLE return_events
+    ALTER COLUMN from_status TYPE VARCHAR(10),
+    ALTER COLUMN to_status TYPE VARCHAR(10);
+
+ALTER TABLE returns
+    DROP COLUMN IF EXISTS refunded_amount,
+    DROP COLUMN IF EXISTS refund_method,
+    DROP CONSTRAINT IF EXISTS returns_status_check,
+    ALTER COLUMN status TYPE VARCHAR(10),
+    ADD CONSTRAINT returns_status_check
+        CHECK ( status IN ('requested', 'approved', 'rejected', 'cancelled', 'received', 'refunded', 'closed') );
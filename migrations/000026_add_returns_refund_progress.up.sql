@@ -0,0 +1,18 @@
+-- A refund that fails part way leaves the return refund_failed with what was already refunded,
+-- the retry only refunds the rest with the same method
+ALTER TABLE returns
+    DROP CONSTRAINT IF EXISTS returns_status_check,
+    ALTER COLUMN status TYPE VARCHAR(13),
+    ADD CONSTRAINT returns_status_check
+        CHECK ( status IN ('requested', 'approved', 'rejected', 'cancelled', 'received', 'refunded', 'refund_failed',
+                           'closed') ),
+    ADD COLUMN refund_method   VARCHAR(12) NOT NULL DEFAULT 'original'
+        CHECK ( refund_method IN ('original', 'store_credit') ),
+    ADD COLUMN refunded_amount BIGINT      NOT NULL DEFAULT 0
+        CHECK ( refunded_amount >= 0 AND refunded_amount <= refund_amount );
+
+UPDATE returns SET refunded_amount = refund_amount WHERE status = 'refunded';
+
+ALTER TABLE return_events
+    ALTER COLUMN from_status TYPE VARCHAR(13),
+    ALTER COLUMN to_status TYPE VARCHAR(13);
@@ -0,0 +1,4 @@
+DELETE FROM payments WHERE intent_id IS NULL;
+
+ALTER TABLE payments
+    ALTER COLUMN intent_id SET NOT NULL;
@@ -0,0 +1,4 @@
+-- Payments are recorded before the provider is called, their id is the idempotency key of the call.
+-- The intent is filled in from the answer, an attempt without one is reused by the next try.
+ALTER TABLE payments
+    ALTER COLUMN intent_id DROP NOT NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS subscription_runs_succeeded_idx;
+DROP INDEX IF EXISTS subscription_runs_attempt_idx;
+DROP INDEX IF EXISTS orders_subscription_run_idx;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS subscription_run_at;
@@ -0,0 +1,12 @@
+-- One live order and one successful run per subscription delivery date, a run retried after a crash
+-- finds the order of the first attempt instead of placing and charging another one
+ALTER TABLE orders
+    ADD COLUMN subscription_run_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX orders_subscription_run_idx ON orders (subscription_id, subscription_run_at)
+    WHERE subscription_run_at IS NOT NULL AND status <> 'cancelled';
+
+CREATE UNIQUE INDEX subscription_runs_attempt_idx ON subscription_runs (subscription_id, scheduled_at, attempt);
+
+CREATE UNIQUE INDEX subscription_runs_succeeded_idx ON subscription_runs (subscription_id, scheduled_at)
+    WHERE status = 'succeeded';
@@ -0,0 +1,2 @@
+-- Headers of the stored response, replayed together with its body
+ALTER TABLE idempotency_keys ADD COLUMN response_headers JSONB;
@@ -0,0 +1,16 @@
+ALTER TABLE subscriptions
+    ADD COLUMN payment_customer_id VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN provider_payment_method_id VARCHAR(100) NOT NULL DEFAULT '';
+
+UPDATE subscriptions s
+SET payment_customer_id = m.customer_id, provider_payment_method_id = COALESCE(m.provider_method_id, '')
+FROM payment_methods m
+WHERE m.payment_method_id = s.payment_method_id;
+
+ALTER TABLE subscriptions
+    DROP COLUMN payment_method_id;
+
+ALTER TABLE subscriptions
+    RENAME COLUMN provider_payment_method_id TO payment_method_id;
+
+DROP TABLE IF EXISTS payment_methods;
@@ -0,0 +1,30 @@
+-- Payment methods saved at the provider through a setup the user confirmed. Subscriptions reference them by
+-- payment_method_id, the provider ids never come from a request.
+CREATE TABLE payment_methods
+(
+    payment_method_id  UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    user_id            UUID                     NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    provider           VARCHAR(20)              NOT NULL,
+    status             VARCHAR(10)              NOT NULL DEFAULT 'pending' CHECK ( status IN ('pending', 'active') ),
+    setup_id           VARCHAR(255)             NOT NULL,
+    customer_id        VARCHAR(255)             NOT NULL,
+    provider_method_id VARCHAR(255),
+    created_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, setup_id),
+    CHECK ( status = 'pending' OR provider_method_id IS NOT NULL )
+);
+
+CREATE INDEX payment_methods_user_idx ON payment_methods (user_id, created_at);
+
+-- Provider ids sent by clients are not trusted, subscriptions using them wait for a method set up again
+ALTER TABLE subscriptions
+    DROP COLUMN payment_customer_id,
+    DROP COLUMN payment_method_id;
+
+ALTER TABLE subscriptions
+    ADD COLUMN payment_method_id UUID REFERENCES payment_methods (payment_method_id) ON DELETE SET NULL;
+
+UPDATE subscriptions
+SET status = 'suspended', retry_at = NULL, last_error = 'Please set up your payment method again.', updated_at = now()
+WHERE status IN ('active', 'past_due');
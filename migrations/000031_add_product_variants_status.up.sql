@@ -0,0 +1,4 @@
+-- Variants stay referenced by stock movements, order lines, carts, wishlists and subscriptions,
+-- so they are archived instead of deleted
+ALTER TABLE product_variants
+    ADD COLUMN status VARCHAR(10) NOT NULL DEFAULT 'active' CHECK ( status IN ('active', 'archived') );
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS gift_card_transactions_append_only ON gift_card_transactions;
+DROP TRIGGER IF EXISTS store_credit_ledger_append_only ON store_credit_ledger;
+DROP FUNCTION IF EXISTS gift_card_transactions_append_only();
+DROP FUNCTION IF EXISTS store_credit_ledger_append_only();
@@ -0,0 +1,43 @@
+-- Ledgers are append only, only order, return and actor cleanup cascade into them. Rows still go away with the
+-- user or gift card they belong to, a delete cascaded from the parent runs nested in its foreign key trigger.
+CREATE OR REPLACE FUNCTION store_credit_ledger_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        IF pg_trigger_depth() > 1 THEN
+            RETURN OLD;
+        END IF;
+        RAISE EXCEPTION 'store_credit_ledger is append only';
+    END IF;
+    IF (NEW.entry_id, NEW.user_id, NEW.currency, NEW.amount, NEW.reason, NEW.note, NEW.created_at)
+        IS DISTINCT FROM (OLD.entry_id, OLD.user_id, OLD.currency, OLD.amount, OLD.reason, OLD.note, OLD.created_at)
+    THEN
+        RAISE EXCEPTION 'store_credit_ledger is append only';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER store_credit_ledger_append_only
+    BEFORE UPDATE OR DELETE ON store_credit_ledger
+    FOR EACH ROW EXECUTE PROCEDURE store_credit_ledger_append_only();
+
+CREATE OR REPLACE FUNCTION gift_card_transactions_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        IF pg_trigger_depth() > 1 THEN
+            RETURN OLD;
+        END IF;
+        RAISE EXCEPTION 'gift_card_transactions is append only';
+    END IF;
+    IF (NEW.transaction_id, NEW.gift_card_id, NEW.kind, NEW.amount, NEW.balance_after, NEW.note, NEW.created_at)
+        IS DISTINCT FROM (OLD.transaction_id, OLD.gift_card_id, OLD.kind, OLD.amount, OLD.balance_after, OLD.note, OLD.created_at)
+    THEN
+        RAISE EXCEPTION 'gift_card_transactions is append only';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER gift_card_transactions_append_only
+    BEFORE UPDATE OR DELETE ON gift_card_transactions
+    FOR EACH ROW EXECUTE PROCEDURE gift_card_transactions_append_only();
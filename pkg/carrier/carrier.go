@@ -0,0 +1,50 @@
+package carrier
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/pkg/errors"
+)
+
+// Carrier integrations
+const (
+	CarrierFake = "fake"
+	CarrierNone = "none"
+)
+
+// Shipping carrier quoting live rates
+type Carrier interface {
+	Name() string
+	Rates(ctx context.Context, req *RateRequest) ([]*Rate, error)
+}
+
+// Live rate request for a parcel, value is in minor units
+type RateRequest struct {
+	Country     string
+	Region      string
+	Postcode    string
+	WeightGrams int
+	Value       int64
+	Currency    string
+}
+
+// Rate of one carrier service, price is in minor units
+type Rate struct {
+	Service       string
+	Price         int64
+	Currency      string
+	EstimatedDays int
+}
+
+// Carrier of the configured integration, nil when live rates are disabled
+func NewCarrier(cfg *config.Config) (Carrier, error) {
+	switch cfg.Shipping.Carrier {
+	case CarrierNone:
+		return nil, nil
+	case CarrierFake, "":
+		return NewFake(), nil
+	default:
+		return nil, errors.Errorf("carrier.NewCarrier: unknown carrier %s", cfg.Shipping.Carrier)
+	}
+}
@@ -0,0 +1,34 @@
+package carrier
+
+import "context"
+
+// Services of the fake carrier
+const (
+	FakeServiceGround  = "ground"
+	FakeServiceExpress = "express"
+)
+
+// Deterministic carrier for tests and local development, prices grow with every started kilogram
+type Fake struct{}
+
+// Fake carrier constructor
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+func (f *Fake) Name() string {
+	return CarrierFake
+}
+
+func (f *Fake) Rates(ctx context.Context, req *RateRequest) ([]*Rate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	kilograms := int64((req.WeightGrams + 999) / 1000)
+
+	return []*Rate{
+		{Service: FakeServiceGround, Price: 500 + 100*kilograms, Currency: req.Currency, EstimatedDays: 5},
+		{Service: FakeServiceExpress, Price: 1500 + 250*kilograms, Currency: req.Currency, EstimatedDays: 2},
+	}, nil
+}
@@ -0,0 +1,128 @@
+package minioS3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/pkg/errors"
+)
+
+// Anonymous access policies, same names as `mc anonymous set`
+const (
+	PolicyNone     = "none"
+	PolicyDownload = "download"
+	PolicyUpload   = "upload"
+	PolicyPublic   = "public"
+)
+
+type policyStatement struct {
+	Effect    string              `json:"Effect"`
+	Principal map[string][]string `json:"Principal"`
+	Action    []string            `json:"Action"`
+	Resource  []string            `json:"Resource"`
+}
+
+type bucketPolicy struct {
+	Version   string            `json:"Version"`
+	Statement []policyStatement `json:"Statement"`
+}
+
+// Check that storage is reachable and every configured bucket exists with its policy and lifecycle rules
+func InitBuckets(ctx context.Context, client *minio.Client, buckets []config.MinioBucketConfig) error {
+	if _, err := client.ListBuckets(ctx); err != nil {
+		return errors.Wrap(err, "minioS3.InitBuckets.ListBuckets")
+	}
+
+	for _, b := range buckets {
+		if err := initBucket(ctx, client, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func initBucket(ctx context.Context, client *minio.Client, b config.MinioBucketConfig) error {
+	if b.Name == "" {
+		return errors.New("minioS3.initBucket: bucket name is required")
+	}
+
+	exists, err := client.BucketExists(ctx, b.Name)
+	if err != nil {
+		return errors.Wrapf(err, "minioS3.initBucket.BucketExists(%s)", b.Name)
+	}
+
+	if !exists {
+		if err = client.MakeBucket(ctx, b.Name, minio.MakeBucketOptions{Region: b.Region}); err != nil {
+			return errors.Wrapf(err, "minioS3.initBucket.MakeBucket(%s)", b.Name)
+		}
+	}
+
+	policy, err := buildBucketPolicy(b.Name, b.Policy)
+	if err != nil {
+		return err
+	}
+
+	if err = client.SetBucketPolicy(ctx, b.Name, policy); err != nil {
+		return errors.Wrapf(err, "minioS3.initBucket.SetBucketPolicy(%s)", b.Name)
+	}
+
+	if b.ExpirationDays > 0 {
+		lc := lifecycle.NewConfiguration()
+		lc.Rules = []lifecycle.Rule{
+			{
+				ID:         fmt.Sprintf("%s-expiration", b.Name),
+				Status:     "Enabled",
+				RuleFilter: lifecycle.Filter{Prefix: b.ExpirationPath},
+				Expiration: lifecycle.Expiration{Days: lifecycle.ExpirationDays(b.ExpirationDays)},
+			},
+		}
+
+		if err = client.SetBucketLifecycle(ctx, b.Name, lc); err != nil {
+			return errors.Wrapf(err, "minioS3.initBucket.SetBucketLifecycle(%s)", b.Name)
+		}
+	}
+
+	return nil
+}
+
+// Build anonymous access policy document, empty policy removes anonymous access
+func buildBucketPolicy(bucket string, policy string) (string, error) {
+	bucketResource := fmt.Sprintf("arn:aws:s3:::%s", bucket)
+	objectResource := fmt.Sprintf("arn:aws:s3:::%s/*", bucket)
+	anyone := map[string][]string{"AWS": {"*"}}
+
+	readStatements := []policyStatement{
+		{Effect: "Allow", Principal: anyone, Action: []string{"s3:GetBucketLocation", "s3:ListBucket"}, Resource: []string{bucketResource}},
+		{Effect: "Allow", Principal: anyone, Action: []string{"s3:GetObject"}, Resource: []string{objectResource}},
+	}
+	writeStatements := []policyStatement{
+		{Effect: "Allow", Principal: anyone, Action: []string{"s3:GetBucketLocation", "s3:ListBucketMultipartUploads"}, Resource: []string{bucketResource}},
+		{Effect: "Allow", Principal: anyone, Action: []string{"s3:AbortMultipartUpload", "s3:DeleteObject", "s3:ListMultipartUploadParts", "s3:PutObject"}, Resource: []string{objectResource}},
+	}
+
+	var statements []policyStatement
+	switch policy {
+	case "", PolicyNone:
+		return "", nil
+	case PolicyDownload:
+		statements = readStatements
+	case PolicyUpload:
+		statements = writeStatements
+	case PolicyPublic:
+		statements = append(readStatements, writeStatements...)
+	default:
+		return "", errors.Errorf("minioS3.buildBucketPolicy: unknown policy %q for bucket %s", policy, bucket)
+	}
+
+	doc, err := json.Marshal(bucketPolicy{Version: "2012-10-17", Statement: statements})
+	if err != nil {
+		return "", errors.Wrap(err, "minioS3.buildBucketPolicy.Marshal")
+	}
+
+	return string(doc), nil
+}
@@ -13,24 +13,33 @@ const (
 )
 
 var (
-	BadRequest            = errors.New("Bad request")
-	WrongCredentials      = errors.New("Wrong Credentials")
-	NotFound              = errors.New("Not Found")
-	Unauthorized          = errors.New("Unauthorized")
-	Forbidden             = errors.New("Forbidden")
-	PermissionDenied      = errors.New("Permission Denied")
-	ExpiredCSRFError      = errors.New("Expired CSRF token")
-	WrongCSRFToken        = errors.New("Wrong CSRF token")
-	CSRFNotPresented      = errors.New("CSRF not presented")
-	NotRequiredFields     = errors.New("No such required fields")
-	BadQueryParams        = errors.New("Invalid query params")
-	InternalServerError   = errors.New("Internal Server Error")
-	RequestTimeoutError   = errors.New("Request Timeout")
-	ExistsEmailError      = errors.New("User with given email already exists")
-	InvalidJWTToken       = errors.New("Invalid JWT token")
-	InvalidJWTClaims      = errors.New("Invalid JWT claims")
-	NotAllowedImageHeader = errors.New("Not allowed image header")
-	NoCookie              = errors.New("not found cookie header")
+	BadRequest             = errors.New("Bad request")
+	WrongCredentials       = errors.New("Wrong Credentials")
+	NotFound               = errors.New("Not Found")
+	Unauthorized           = errors.New("Unauthorized")
+	Forbidden              = errors.New("Forbidden")
+	PermissionDenied       = errors.New("Permission Denied")
+	ExpiredCSRFError       = errors.New("Expired CSRF token")
+	WrongCSRFToken         = errors.New("Wrong CSRF token")
+	CSRFNotPresented       = errors.New("CSRF not presented")
+	NotRequiredFields      = errors.New("No such required fields")
+	BadQueryParams         = errors.New("Invalid query params")
+	InternalServerError    = errors.New("Internal Server Error")
+	RequestTimeoutError    = errors.New("Request Timeout")
+	ExistsEmailError       = errors.New("User with given email already exists")
+	InvalidJWTToken        = errors.New("Invalid JWT token")
+	InvalidJWTClaims       = errors.New("Invalid JWT claims")
+	NotAllowedImageHeader  = errors.New("Not allowed image header")
+	NoCookie               = errors.New("not found cookie header")
+	AlreadyExists          = errors.New("Already exists")
+	InsufficientStock      = errors.New("Insufficient stock")
+	InvalidStateTransition = errors.New("Invalid state transition")
+	IdempotencyKeyInUse    = errors.New("A request with this Idempotency-Key is in progress")
+	IdempotencyKeyReused   = errors.New("Idempotency-Key was used with a different request")
+	PromotionUnavailable   = errors.New("Promotion is no longer available")
+	GiftCardUnavailable    = errors.New("Gift card balance is no longer available")
+	InsufficientCredit     = errors.New("Insufficient store credit")
+	CartChanged            = errors.New("Cart changed during checkout, review it and try again")
 )
 
 // Rest Err Interface
@@ -106,11 +115,45 @@ func NewUnauthorizedError(causes interface{}) RestErr {
 	}
 }
 
+// New Forbidden Error
+func NewForbiddenError(causes interface{}) RestErr {
+	return RestError{
+		ErrStatus: http.StatusForbidden,
+		ErrError:  Forbidden.Error(),
+		ErrCauses: causes,
+	}
+}
+
+// New Not Found Error
+func NewNotFoundError(causes interface{}) RestErr {
+	return RestError{
+		ErrStatus: http.StatusNotFound,
+		ErrError:  NotFound.Error(),
+		ErrCauses: causes,
+	}
+}
+
 // Parser of error string messages returns RestError
 func ParseError(err error) RestErr {
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return NewRestError(http.StatusNotFound, NotFound.Error(), err)
+	case errors.Is(err, InsufficientStock):
+		return NewRestError(http.StatusConflict, InsufficientStock.Error(), err)
+	case errors.Is(err, InvalidStateTransition):
+		return NewRestError(http.StatusConflict, InvalidStateTransition.Error(), err)
+	case errors.Is(err, IdempotencyKeyInUse):
+		return NewRestError(http.StatusConflict, IdempotencyKeyInUse.Error(), err)
+	case errors.Is(err, IdempotencyKeyReused):
+		return NewRestError(http.StatusUnprocessableEntity, IdempotencyKeyReused.Error(), err)
+	case errors.Is(err, PromotionUnavailable):
+		return NewRestError(http.StatusConflict, PromotionUnavailable.Error(), err)
+	case errors.Is(err, GiftCardUnavailable):
+		return NewRestError(http.StatusConflict, GiftCardUnavailable.Error(), err)
+	case errors.Is(err, InsufficientCredit):
+		return NewRestError(http.StatusConflict, InsufficientCredit.Error(), err)
+	case errors.Is(err, CartChanged):
+		return NewRestError(http.StatusConflict, CartChanged.Error(), err)
 	case errors.Is(err, context.DeadlineExceeded):
 		return NewRestError(http.StatusRequestTimeout, RequestTimeoutError.Error(), err)
 	case strings.Contains(err.Error(), "SQLSTATE"):
@@ -137,7 +180,10 @@ func ParseError(err error) RestErr {
 
 func parseSqlErrors(err error) RestErr {
 	if strings.Contains(err.Error(), "23505") {
-		return NewRestError(http.StatusBadRequest, ExistsEmailError.Error(), err)
+		if strings.Contains(err.Error(), "users_email_key") {
+			return NewRestError(http.StatusBadRequest, ExistsEmailError.Error(), err)
+		}
+		return NewRestError(http.StatusConflict, AlreadyExists.Error(), err)
 	}
 
 	return NewRestError(http.StatusBadRequest, BadRequest.Error(), err)
@@ -0,0 +1,27 @@
+package mailer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fekuna/go-store/pkg/logger"
+)
+
+// Writes messages to the application log instead of sending them, for local development
+type Log struct {
+	logger logger.Logger
+}
+
+// Log mailer constructor
+func NewLog(logger logger.Logger) *Log {
+	return &Log{logger: logger}
+}
+
+func (l *Log) Send(ctx context.Context, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.logger.Infof("mail to %s: %s\n%s", strings.Join(msg.To, ", "), msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/fekuna/go-store/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+// Mail providers
+const (
+	ProviderSMTP = "smtp"
+	ProviderLog  = "log"
+)
+
+// Plain text email
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Sends transactional email
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Mailer of the configured provider
+func NewMailer(cfg *config.Config, logger logger.Logger) (Mailer, error) {
+	switch cfg.Mailer.Provider {
+	case ProviderSMTP:
+		if cfg.Mailer.Host == "" || cfg.Mailer.From == "" {
+			return nil, errors.New("mailer.NewMailer: smtp needs Host and From")
+		}
+		return NewSMTP(cfg.Mailer.Host, cfg.Mailer.Port, cfg.Mailer.Username, cfg.Mailer.Password, cfg.Mailer.From), nil
+	case ProviderLog, "":
+		return NewLog(logger), nil
+	default:
+		return nil, errors.Errorf("mailer.NewMailer: unknown provider %s", cfg.Mailer.Provider)
+	}
+}
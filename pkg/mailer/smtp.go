@@ -0,0 +1,103 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SMTP mailer, authenticates with PLAIN when a username is set. STARTTLS is used when the server offers it.
+type SMTP struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+	envelope string
+}
+
+// SMTP mailer constructor, from may carry a display name like "Store <no-reply@example.com>"
+func NewSMTP(host string, port int, username string, password string, from string) *SMTP {
+	if port == 0 {
+		port = 587
+	}
+	envelope := from
+	if addr, err := mail.ParseAddress(from); err == nil {
+		envelope = addr.Address
+	}
+	return &SMTP{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+		envelope: envelope,
+	}
+}
+
+func (s *SMTP) Send(ctx context.Context, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(msg.To) == 0 {
+		return errors.New("SMTP.Send: message without recipients")
+	}
+
+	body, err := s.compose(msg)
+	if err != nil {
+		return errors.Wrap(err, "SMTP.Send.compose")
+	}
+
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	if err = smtp.SendMail(s.addr, auth, s.envelope, msg.To, body); err != nil {
+		return errors.Wrap(err, "SMTP.Send.SendMail")
+	}
+
+	return nil
+}
+
+// RFC 5322 message with a quoted-printable UTF-8 body
+func (s *SMTP) compose(msg *Message) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	header := func(name string, value string) {
+		fmt.Fprintf(buf, "%s: %s\r\n", name, stripNewlines(value))
+	}
+
+	header("From", s.from)
+	header("To", strings.Join(msg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=UTF-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(buf)
+	if _, err := w.Write([]byte(strings.ReplaceAll(msg.Body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Header values can't start new headers
+func stripNewlines(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
@@ -0,0 +1,265 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const fakeSignatureHeader = "Fake-Signature"
+
+// Saved payment method the fake gateway always declines
+const FakeDeclinedPaymentMethod = "pm_card_declined"
+
+// In process gateway for tests and local development. Intents are kept in memory and
+// webhooks are the JSON encoded Event signed like Stripe, see SignWebhook. Like Stripe a request
+// repeating an idempotency key gets the result of the first one.
+type Fake struct {
+	webhookSecret string
+
+	mu        sync.Mutex
+	seq       int
+	intents   map[string]*Intent
+	refunds   map[string]int64
+	keys      map[string]string
+	replays   map[string]*Refund
+	setups    map[string]*Setup
+	setupKeys map[string]string
+}
+
+// Fake gateway constructor
+func NewFake(webhookSecret string) *Fake {
+	return &Fake{
+		webhookSecret: webhookSecret,
+		intents:       make(map[string]*Intent),
+		refunds:       make(map[string]int64),
+		keys:          make(map[string]string),
+		replays:       make(map[string]*Refund),
+		setups:        make(map[string]*Setup),
+		setupKeys:     make(map[string]string),
+	}
+}
+
+func (f *Fake) Name() string {
+	return ProviderFake
+}
+
+func (f *Fake) CreateIntent(ctx context.Context, req *IntentRequest) (*Intent, error) {
+	if req.Amount <= 0 {
+		return nil, errors.New("Fake.CreateIntent: amount must be positive")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if intent, ok := f.replay(req.IdempotencyKey); ok {
+		return intent, nil
+	}
+
+	f.seq++
+	id := fmt.Sprintf("pi_fake_%d", f.seq)
+	intent := &Intent{
+		ID:           id,
+		Status:       IntentRequiresPaymentMethod,
+		Amount:       req.Amount,
+		Currency:     strings.ToUpper(req.Currency),
+		ClientSecret: id + "_secret",
+	}
+	f.intents[id] = intent
+	if req.IdempotencyKey != "" {
+		f.keys[req.IdempotencyKey] = id
+	}
+
+	copied := *intent
+	return &copied, nil
+}
+
+func (f *Fake) GetIntent(ctx context.Context, intentID string) (*Intent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	intent, ok := f.intents[intentID]
+	if !ok {
+		return nil, errors.Errorf("Fake.GetIntent: no such intent %s", intentID)
+	}
+
+	copied := *intent
+	return &copied, nil
+}
+
+func (f *Fake) Capture(ctx context.Context, intentID string, amount int64) (*Intent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	intent, ok := f.intents[intentID]
+	if !ok {
+		return nil, errors.Errorf("Fake.Capture: no such intent %s", intentID)
+	}
+	if amount > 0 && amount < intent.Amount {
+		intent.Amount = amount
+	}
+	intent.Status = IntentSucceeded
+
+	copied := *intent
+	return &copied, nil
+}
+
+func (f *Fake) Refund(ctx context.Context, intentID string, amount int64, idempotencyKey string) (*Refund, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if re, ok := f.replays[idempotencyKey]; ok && idempotencyKey != "" {
+		copied := *re
+		return &copied, nil
+	}
+
+	intent, ok := f.intents[intentID]
+	if !ok {
+		return nil, errors.Errorf("Fake.Refund: no such intent %s", intentID)
+	}
+	if amount <= 0 {
+		amount = intent.Amount - f.refunds[intentID]
+	}
+	if f.refunds[intentID]+amount > intent.Amount {
+		return nil, errors.New("Fake.Refund: amount exceeds captured amount")
+	}
+	f.refunds[intentID] += amount
+
+	f.seq++
+	re := &Refund{ID: fmt.Sprintf("re_fake_%d", f.seq), Status: IntentSucceeded, Amount: amount}
+	if idempotencyKey != "" {
+		f.replays[idempotencyKey] = re
+	}
+
+	copied := *re
+	return &copied, nil
+}
+
+// Charges succeed unless the payment method is FakeDeclinedPaymentMethod
+func (f *Fake) ChargeSaved(ctx context.Context, req *ChargeRequest) (*Intent, error) {
+	if req.Amount <= 0 {
+		return nil, errors.New("Fake.ChargeSaved: amount must be positive")
+	}
+	if req.PaymentMethodID == FakeDeclinedPaymentMethod {
+		return nil, &DeclineError{Code: "card_declined", Message: "Your card was declined."}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if intent, ok := f.replay(req.IdempotencyKey); ok {
+		return intent, nil
+	}
+
+	f.seq++
+	id := fmt.Sprintf("pi_fake_%d", f.seq)
+	intent := &Intent{
+		ID:       id,
+		Status:   IntentSucceeded,
+		Amount:   req.Amount,
+		Currency: strings.ToUpper(req.Currency),
+	}
+	f.intents[id] = intent
+	if req.IdempotencyKey != "" {
+		f.keys[req.IdempotencyKey] = id
+	}
+
+	copied := *intent
+	return &copied, nil
+}
+
+// Setups wait for ConfirmSetup, which stands in for the client confirming them at the provider
+func (f *Fake) CreateSetup(ctx context.Context, req *SetupRequest) (*Setup, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if id, ok := f.setupKeys[req.IdempotencyKey]; ok && req.IdempotencyKey != "" {
+		copied := *f.setups[id]
+		return &copied, nil
+	}
+
+	customerID := req.CustomerID
+	if customerID == "" {
+		f.seq++
+		customerID = fmt.Sprintf("cus_fake_%d", f.seq)
+	}
+
+	f.seq++
+	id := fmt.Sprintf("seti_fake_%d", f.seq)
+	setup := &Setup{
+		ID:           id,
+		Status:       IntentRequiresPaymentMethod,
+		CustomerID:   customerID,
+		ClientSecret: id + "_secret",
+	}
+	f.setups[id] = setup
+	if req.IdempotencyKey != "" {
+		f.setupKeys[req.IdempotencyKey] = id
+	}
+
+	copied := *setup
+	return &copied, nil
+}
+
+func (f *Fake) GetSetup(ctx context.Context, setupID string) (*Setup, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	setup, ok := f.setups[setupID]
+	if !ok {
+		return nil, errors.Errorf("Fake.GetSetup: no such setup %s", setupID)
+	}
+
+	copied := *setup
+	return &copied, nil
+}
+
+// Attach the payment method to a setup like the client would, FakeDeclinedPaymentMethod is attached too
+func (f *Fake) ConfirmSetup(setupID string, paymentMethodID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	setup, ok := f.setups[setupID]
+	if !ok {
+		return errors.Errorf("Fake.ConfirmSetup: no such setup %s", setupID)
+	}
+	setup.Status = IntentSucceeded
+	setup.PaymentMethodID = paymentMethodID
+
+	return nil
+}
+
+func (f *Fake) VerifyWebhook(payload []byte, header http.Header) (*Event, error) {
+	if err := verifySignature(f.webhookSecret, payload, header.Get(fakeSignatureHeader), 0); err != nil {
+		return nil, err
+	}
+
+	event := &Event{}
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, errors.Wrap(err, "Fake.VerifyWebhook.Unmarshal")
+	}
+
+	return event, nil
+}
+
+// Intent created before with the idempotency key, the caller holds the lock
+func (f *Fake) replay(idempotencyKey string) (*Intent, bool) {
+	id, ok := f.keys[idempotencyKey]
+	if !ok || idempotencyKey == "" {
+		return nil, false
+	}
+
+	copied := *f.intents[id]
+	return &copied, true
+}
+
+// Header name and value that make VerifyWebhook accept payload
+func (f *Fake) SignWebhook(payload []byte) (string, string) {
+	return fakeSignatureHeader, signPayload(f.webhookSecret, payload, time.Now())
+}
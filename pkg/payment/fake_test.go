@@ -0,0 +1,173 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestFakeCreateIntent(t *testing.T) {
+	f := NewFake("whsec_test")
+	ctx := context.Background()
+
+	first, err := f.CreateIntent(ctx, &IntentRequest{Amount: 1500, Currency: "usd", IdempotencyKey: "payment_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Status != IntentRequiresPaymentMethod || first.Amount != 1500 || first.Currency != "USD" || first.ClientSecret == "" {
+		t.Errorf("intent = %+v", first)
+	}
+
+	replayed, err := f.CreateIntent(ctx, &IntentRequest{Amount: 1500, Currency: "usd", IdempotencyKey: "payment_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.ID != first.ID {
+		t.Errorf("same key created intent %s, want %s", replayed.ID, first.ID)
+	}
+
+	other, err := f.CreateIntent(ctx, &IntentRequest{Amount: 1500, Currency: "usd", IdempotencyKey: "payment_2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.ID == first.ID {
+		t.Errorf("other key got intent %s again", other.ID)
+	}
+
+	if _, err = f.CreateIntent(ctx, &IntentRequest{Amount: 0, Currency: "usd"}); err == nil {
+		t.Error("intent without amount was created")
+	}
+}
+
+func TestFakeChargeSaved(t *testing.T) {
+	f := NewFake("whsec_test")
+	ctx := context.Background()
+
+	charge := &ChargeRequest{Amount: 2000, Currency: "eur", IdempotencyKey: "payment_1", PaymentMethodID: "pm_card_visa"}
+	first, err := f.ChargeSaved(ctx, charge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Status != IntentSucceeded || first.Amount != 2000 {
+		t.Errorf("charge = %+v", first)
+	}
+
+	replayed, err := f.ChargeSaved(ctx, charge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.ID != first.ID {
+		t.Errorf("retried charge created intent %s, want %s", replayed.ID, first.ID)
+	}
+
+	_, err = f.ChargeSaved(ctx, &ChargeRequest{Amount: 2000, Currency: "eur", IdempotencyKey: "payment_2", PaymentMethodID: FakeDeclinedPaymentMethod})
+	var decline *DeclineError
+	if !errors.As(err, &decline) {
+		t.Fatalf("declined payment method: error = %v, want DeclineError", err)
+	}
+}
+
+func TestFakeSetup(t *testing.T) {
+	f := NewFake("whsec_test")
+	ctx := context.Background()
+
+	setup, err := f.CreateSetup(ctx, &SetupRequest{Reference: "user_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if setup.Status != IntentRequiresPaymentMethod || setup.CustomerID == "" || setup.ClientSecret == "" {
+		t.Errorf("setup = %+v", setup)
+	}
+
+	again, err := f.CreateSetup(ctx, &SetupRequest{CustomerID: setup.CustomerID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID == setup.ID || again.CustomerID != setup.CustomerID {
+		t.Errorf("second setup = %+v, want a new setup of customer %s", again, setup.CustomerID)
+	}
+
+	if err = f.ConfirmSetup(setup.ID, "pm_card_visa"); err != nil {
+		t.Fatal(err)
+	}
+	confirmed, err := f.GetSetup(ctx, setup.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirmed.Status != IntentSucceeded || confirmed.PaymentMethodID != "pm_card_visa" {
+		t.Errorf("confirmed setup = %+v", confirmed)
+	}
+}
+
+func TestFakeRefund(t *testing.T) {
+	f := NewFake("whsec_test")
+	ctx := context.Background()
+
+	intent, err := f.ChargeSaved(ctx, &ChargeRequest{Amount: 1000, Currency: "usd", PaymentMethodID: "pm_card_visa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := f.Refund(ctx, intent.ID, 400, "return_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Amount != 400 {
+		t.Errorf("refunded %d, want 400", first.Amount)
+	}
+
+	replayed, err := f.Refund(ctx, intent.ID, 400, "return_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.ID != first.ID {
+		t.Errorf("retried refund created %s, want %s", replayed.ID, first.ID)
+	}
+
+	if _, err = f.Refund(ctx, intent.ID, 700, "return_2"); err == nil {
+		t.Error("refunded more than was captured")
+	}
+
+	rest, err := f.Refund(ctx, intent.ID, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rest.Amount != 600 {
+		t.Errorf("zero amount refunded %d, want the 600 left", rest.Amount)
+	}
+
+	if _, err = f.Refund(ctx, "pi_unknown", 100, ""); err == nil {
+		t.Error("refunded unknown intent")
+	}
+}
+
+func TestFakeWebhook(t *testing.T) {
+	f := NewFake("whsec_test")
+
+	payload, err := json.Marshal(&Event{ID: "evt_1", Type: EventPaymentRefunded, IntentID: "pi_fake_1", Amount: 500})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, value := f.SignWebhook(payload)
+	header := http.Header{}
+	header.Set(name, value)
+
+	event, err := f.VerifyWebhook(payload, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.ID != "evt_1" || event.Type != EventPaymentRefunded || event.IntentID != "pi_fake_1" || event.Amount != 500 {
+		t.Errorf("event = %+v", event)
+	}
+
+	if _, err = NewFake("whsec_other").VerifyWebhook(payload, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("other secret: error = %v, want %v", err, ErrInvalidSignature)
+	}
+	if _, err = f.VerifyWebhook(payload, http.Header{}); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("unsigned: error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
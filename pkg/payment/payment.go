@@ -0,0 +1,196 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fekuna/go-store/config"
+	"github.com/pkg/errors"
+)
+
+// Gateway providers
+const (
+	ProviderFake   = "fake"
+	ProviderStripe = "stripe"
+)
+
+// Intent statuses, same names as Stripe
+const (
+	IntentRequiresPaymentMethod = "requires_payment_method"
+	IntentRequiresCapture       = "requires_capture"
+	IntentSucceeded             = "succeeded"
+	IntentCanceled              = "canceled"
+)
+
+// Webhook event types, provider events are mapped to these
+const (
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentFailed    = "payment.failed"
+	EventPaymentRefunded  = "payment.refunded"
+	EventUnknown          = "unknown"
+)
+
+// Webhook signature errors
+var (
+	ErrInvalidSignature = errors.New("payment: invalid webhook signature")
+	ErrExpiredSignature = errors.New("payment: webhook signature timestamp out of tolerance")
+)
+
+// Payment provider
+type Gateway interface {
+	Name() string
+	CreateIntent(ctx context.Context, req *IntentRequest) (*Intent, error)
+	GetIntent(ctx context.Context, intentID string) (*Intent, error)
+	Capture(ctx context.Context, intentID string, amount int64) (*Intent, error)
+	Refund(ctx context.Context, intentID string, amount int64, idempotencyKey string) (*Refund, error)
+	ChargeSaved(ctx context.Context, req *ChargeRequest) (*Intent, error)
+	CreateSetup(ctx context.Context, req *SetupRequest) (*Setup, error)
+	GetSetup(ctx context.Context, setupID string) (*Setup, error)
+	VerifyWebhook(payload []byte, header http.Header) (*Event, error)
+}
+
+// Create intent request, amounts are in minor units
+type IntentRequest struct {
+	Amount         int64
+	Currency       string
+	Reference      string
+	IdempotencyKey string
+}
+
+// Off session charge of a payment method saved at the provider, amounts are in minor units
+type ChargeRequest struct {
+	Amount          int64
+	Currency        string
+	Reference       string
+	IdempotencyKey  string
+	CustomerID      string
+	PaymentMethodID string
+}
+
+// Setup of a payment method for off session charges, without CustomerID a customer is created at the provider
+type SetupRequest struct {
+	CustomerID     string
+	Reference      string
+	IdempotencyKey string
+}
+
+// Charge declined by the card issuer or the provider, the message can be shown to the customer
+type DeclineError struct {
+	Code    string
+	Message string
+}
+
+func (e *DeclineError) Error() string {
+	return "payment: declined: " + e.Message
+}
+
+// Payment intent at the provider
+type Intent struct {
+	ID           string
+	Status       string
+	Amount       int64
+	Currency     string
+	ClientSecret string
+}
+
+// Payment method setup at the provider, confirmed by the client with ClientSecret. PaymentMethodID is set
+// once the setup succeeded.
+type Setup struct {
+	ID              string
+	Status          string
+	CustomerID      string
+	PaymentMethodID string
+	ClientSecret    string
+}
+
+// Refund at the provider
+type Refund struct {
+	ID     string
+	Status string
+	Amount int64
+}
+
+// Verified webhook event, for refunds Amount is the total refunded so far
+type Event struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	IntentID string `json:"intent_id"`
+	Amount   int64  `json:"amount"`
+	Created  int64  `json:"created"`
+}
+
+// Gateway of the configured provider
+func NewGateway(cfg *config.Config) (Gateway, error) {
+	switch cfg.Payment.Provider {
+	case ProviderStripe:
+		if cfg.Payment.SecretKey == "" || cfg.Payment.WebhookSecret == "" {
+			return nil, errors.New("payment.NewGateway: stripe needs SecretKey and WebhookSecret")
+		}
+		return NewStripe(cfg.Payment.APIBase, cfg.Payment.SecretKey, cfg.Payment.WebhookSecret,
+			time.Second*cfg.Payment.Timeout, time.Second*cfg.Payment.WebhookTolerance), nil
+	case ProviderFake, "":
+		return NewFake(cfg.Payment.WebhookSecret), nil
+	default:
+		return nil, errors.Errorf("payment.NewGateway: unknown provider %s", cfg.Payment.Provider)
+	}
+}
+
+// Stripe style signature header value: t=<unix time>,v1=<hex HMAC-SHA256 of "<t>.<payload>">
+func signPayload(secret string, payload []byte, t time.Time) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(payloadMAC(secret, timestamp, payload))
+}
+
+func verifySignature(secret string, payload []byte, header string, tolerance time.Duration) error {
+	var timestamp string
+	signatures := make([][]byte, 0, 1)
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	expected := payloadMAC(secret, timestamp, payload)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			if tolerance > 0 && time.Since(time.Unix(unix, 0)) > tolerance {
+				return ErrExpiredSignature
+			}
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
+
+func payloadMAC(secret string, timestamp string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,50 @@
+package payment
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "whsec_test"
+	payload := []byte(`{"id":"evt_1","type":"payment.succeeded"}`)
+	now := time.Now()
+	valid := signPayload(secret, payload, now)
+	old := signPayload(secret, payload, now.Add(-10*time.Minute))
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name      string
+		secret    string
+		payload   []byte
+		header    string
+		tolerance time.Duration
+		want      error
+	}{
+		{name: "valid", secret: secret, payload: payload, header: valid, tolerance: time.Minute},
+		{name: "spaces around parts", secret: secret, payload: payload, header: "t=" + timestamp + ", v1=" + valid[len("t="+timestamp+",v1="):]},
+		{name: "one of several signatures", secret: secret, payload: payload, header: valid + ",v1=00ff"},
+		{name: "other secret", secret: "whsec_other", payload: payload, header: valid, want: ErrInvalidSignature},
+		{name: "changed payload", secret: secret, payload: []byte(`{"id":"evt_2","type":"payment.succeeded"}`), header: valid, want: ErrInvalidSignature},
+		{name: "changed timestamp", secret: secret, payload: payload, header: "t=1" + valid[len("t="):], want: ErrInvalidSignature},
+		{name: "empty header", secret: secret, payload: payload, header: "", want: ErrInvalidSignature},
+		{name: "no signature", secret: secret, payload: payload, header: "t=" + timestamp, want: ErrInvalidSignature},
+		{name: "no timestamp", secret: secret, payload: payload, header: valid[len("t="+timestamp+","):], want: ErrInvalidSignature},
+		{name: "signature not hex", secret: secret, payload: payload, header: "t=" + timestamp + ",v1=xyz", want: ErrInvalidSignature},
+		{name: "timestamp not a number", secret: secret, payload: payload, header: "t=now,v1=00", want: ErrInvalidSignature},
+		{name: "older than tolerance", secret: secret, payload: payload, header: old, tolerance: time.Minute, want: ErrExpiredSignature},
+		{name: "old without tolerance", secret: secret, payload: payload, header: old},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifySignature(tt.secret, tt.payload, tt.header, tt.tolerance)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("verifySignature() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	stripeAPIBase         = "https://api.stripe.com"
+	stripeSignatureHeader = "Stripe-Signature"
+)
+
+// Stripe gateway over the REST API, form encoded requests and JSON responses
+type Stripe struct {
+	apiBase       string
+	secretKey     string
+	webhookSecret string
+	tolerance     time.Duration
+	client        *http.Client
+}
+
+type stripeIntent struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	Amount       int64  `json:"amount"`
+	Currency     string `json:"currency"`
+	ClientSecret string `json:"client_secret"`
+}
+
+type stripeSetup struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	Customer      string `json:"customer"`
+	PaymentMethod string `json:"payment_method"`
+	ClientSecret  string `json:"client_secret"`
+}
+
+type stripeCustomer struct {
+	ID string `json:"id"`
+}
+
+type stripeRefund struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Amount int64  `json:"amount"`
+}
+
+type stripeError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type stripeEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object struct {
+			ID             string `json:"id"`
+			Object         string `json:"object"`
+			Amount         int64  `json:"amount"`
+			AmountRefunded int64  `json:"amount_refunded"`
+			PaymentIntent  string `json:"payment_intent"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+// Stripe gateway constructor, empty apiBase uses the public API
+func NewStripe(apiBase string, secretKey string, webhookSecret string, timeout time.Duration, tolerance time.Duration) *Stripe {
+	if apiBase == "" {
+		apiBase = stripeAPIBase
+	}
+	return &Stripe{
+		apiBase:       strings.TrimRight(apiBase, "/"),
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		tolerance:     tolerance,
+		client:        &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *Stripe) Name() string {
+	return ProviderStripe
+}
+
+func (s *Stripe) CreateIntent(ctx context.Context, req *IntentRequest) (*Intent, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(req.Amount, 10))
+	form.Set("currency", strings.ToLower(req.Currency))
+	form.Set("metadata[reference]", req.Reference)
+	form.Set("automatic_payment_methods[enabled]", "true")
+
+	pi := &stripeIntent{}
+	if err := s.post(ctx, "/v1/payment_intents", form, req.IdempotencyKey, pi); err != nil {
+		return nil, errors.Wrap(err, "Stripe.CreateIntent")
+	}
+
+	return pi.toIntent(), nil
+}
+
+func (s *Stripe) GetIntent(ctx context.Context, intentID string) (*Intent, error) {
+	pi := &stripeIntent{}
+	if err := s.do(ctx, http.MethodGet, "/v1/payment_intents/"+url.PathEscape(intentID), nil, "", pi); err != nil {
+		return nil, errors.Wrap(err, "Stripe.GetIntent")
+	}
+
+	return pi.toIntent(), nil
+}
+
+func (s *Stripe) Capture(ctx context.Context, intentID string, amount int64) (*Intent, error) {
+	form := url.Values{}
+	if amount > 0 {
+		form.Set("amount_to_capture", strconv.FormatInt(amount, 10))
+	}
+
+	pi := &stripeIntent{}
+	if err := s.post(ctx, "/v1/payment_intents/"+url.PathEscape(intentID)+"/capture", form, "", pi); err != nil {
+		return nil, errors.Wrap(err, "Stripe.Capture")
+	}
+
+	return pi.toIntent(), nil
+}
+
+func (s *Stripe) Refund(ctx context.Context, intentID string, amount int64, idempotencyKey string) (*Refund, error) {
+	form := url.Values{}
+	form.Set("payment_intent", intentID)
+	if amount > 0 {
+		form.Set("amount", strconv.FormatInt(amount, 10))
+	}
+
+	re := &stripeRefund{}
+	if err := s.post(ctx, "/v1/refunds", form, idempotencyKey, re); err != nil {
+		return nil, errors.Wrap(err, "Stripe.Refund")
+	}
+
+	return &Refund{ID: re.ID, Status: re.Status, Amount: re.Amount}, nil
+}
+
+// Confirm a payment intent off session with the saved payment method, declines come back as DeclineError
+func (s *Stripe) ChargeSaved(ctx context.Context, req *ChargeRequest) (*Intent, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(req.Amount, 10))
+	form.Set("currency", strings.ToLower(req.Currency))
+	form.Set("metadata[reference]", req.Reference)
+	form.Set("payment_method", req.PaymentMethodID)
+	if req.CustomerID != "" {
+		form.Set("customer", req.CustomerID)
+	}
+	form.Set("off_session", "true")
+	form.Set("confirm", "true")
+
+	pi := &stripeIntent{}
+	if err := s.post(ctx, "/v1/payment_intents", form, req.IdempotencyKey, pi); err != nil {
+		return nil, errors.Wrap(err, "Stripe.ChargeSaved")
+	}
+
+	return pi.toIntent(), nil
+}
+
+// Setup intent for off session use, a customer is created first when the request has none
+func (s *Stripe) CreateSetup(ctx context.Context, req *SetupRequest) (*Setup, error) {
+	customerID := req.CustomerID
+	if customerID == "" {
+		form := url.Values{}
+		form.Set("metadata[reference]", req.Reference)
+
+		customer := &stripeCustomer{}
+		if err := s.post(ctx, "/v1/customers", form, keyWithSuffix(req.IdempotencyKey, "customer"), customer); err != nil {
+			return nil, errors.Wrap(err, "Stripe.CreateSetup.customer")
+		}
+		customerID = customer.ID
+	}
+
+	form := url.Values{}
+	form.Set("customer", customerID)
+	form.Set("usage", "off_session")
+	form.Set("metadata[reference]", req.Reference)
+	form.Set("automatic_payment_methods[enabled]", "true")
+
+	si := &stripeSetup{}
+	if err := s.post(ctx, "/v1/setup_intents", form, req.IdempotencyKey, si); err != nil {
+		return nil, errors.Wrap(err, "Stripe.CreateSetup")
+	}
+
+	return si.toSetup(), nil
+}
+
+func (s *Stripe) GetSetup(ctx context.Context, setupID string) (*Setup, error) {
+	si := &stripeSetup{}
+	if err := s.do(ctx, http.MethodGet, "/v1/setup_intents/"+url.PathEscape(setupID), nil, "", si); err != nil {
+		return nil, errors.Wrap(err, "Stripe.GetSetup")
+	}
+
+	return si.toSetup(), nil
+}
+
+// Verify Stripe-Signature header and map the event, unsupported types come back as EventUnknown
+func (s *Stripe) VerifyWebhook(payload []byte, header http.Header) (*Event, error) {
+	if err := verifySignature(s.webhookSecret, payload, header.Get(stripeSignatureHeader), s.tolerance); err != nil {
+		return nil, err
+	}
+
+	se := &stripeEvent{}
+	if err := json.Unmarshal(payload, se); err != nil {
+		return nil, errors.Wrap(err, "Stripe.VerifyWebhook.Unmarshal")
+	}
+
+	event := &Event{ID: se.ID, Type: EventUnknown, Created: se.Created}
+	object := se.Data.Object
+	switch se.Type {
+	case "payment_intent.succeeded":
+		event.Type, event.IntentID, event.Amount = EventPaymentSucceeded, object.ID, object.Amount
+	case "payment_intent.payment_failed":
+		event.Type, event.IntentID, event.Amount = EventPaymentFailed, object.ID, object.Amount
+	case "charge.refunded":
+		event.Type, event.IntentID, event.Amount = EventPaymentRefunded, object.PaymentIntent, object.AmountRefunded
+	}
+
+	return event, nil
+}
+
+func (s *Stripe) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out interface{}) error {
+	return s.do(ctx, http.MethodPost, path, form, idempotencyKey, out)
+}
+
+func (s *Stripe) do(ctx context.Context, method string, path string, form url.Values, idempotencyKey string, out interface{}) error {
+	var reqBody io.Reader
+	if form != nil {
+		reqBody = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.apiBase+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.secretKey, "")
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		se := &stripeError{}
+		if err = json.Unmarshal(body, se); err != nil || se.Error.Message == "" {
+			return errors.Errorf("stripe: status %d", resp.StatusCode)
+		}
+		if se.Error.Type == "card_error" {
+			return &DeclineError{Code: se.Error.Code, Message: se.Error.Message}
+		}
+		return errors.Errorf("stripe: %s (%s)", se.Error.Message, se.Error.Type)
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+func (pi *stripeIntent) toIntent() *Intent {
+	return &Intent{
+		ID:           pi.ID,
+		Status:       pi.Status,
+		Amount:       pi.Amount,
+		Currency:     strings.ToUpper(pi.Currency),
+		ClientSecret: pi.ClientSecret,
+	}
+}
+
+func (si *stripeSetup) toSetup() *Setup {
+	return &Setup{
+		ID:              si.ID,
+		Status:          si.Status,
+		CustomerID:      si.Customer,
+		PaymentMethodID: si.PaymentMethod,
+		ClientSecret:    si.ClientSecret,
+	}
+}
+
+// Key of a second request made for the same call, empty keys stay empty
+func keyWithSuffix(idempotencyKey string, suffix string) string {
+	if idempotencyKey == "" {
+		return ""
+	}
+	return idempotencyKey + "_" + suffix
+}
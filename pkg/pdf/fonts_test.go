@@ -0,0 +1,75 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		font string
+		size float64
+		in   string
+		want float64
+	}{
+		{name: "empty", font: FontRegular, size: 10, in: "", want: 0},
+		{name: "regular", font: FontRegular, size: 10, in: "Ab", want: (667 + 556) * 10 / 1000.0},
+		{name: "bold", font: FontBold, size: 10, in: "Ab", want: (722 + 611) * 10 / 1000.0},
+		{name: "unknown font falls back to regular", font: "Courier", size: 10, in: "Ab", want: (667 + 556) * 10 / 1000.0},
+		{name: "outside ascii", font: FontRegular, size: 10, in: "é", want: defaultWidth * 10 / 1000.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TextWidth(tt.font, tt.size, tt.in); got != tt.want {
+				t.Fatalf("TextWidth(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	long := strings.Repeat("Wide product title ", 10)
+
+	tests := []struct {
+		name  string
+		font  string
+		in    string
+		width float64
+		cut   bool
+		want  string
+	}{
+		{name: "fits", font: FontRegular, in: "Mug", width: 100, want: "Mug"},
+		{name: "exact width", font: FontRegular, in: "Mug", width: TextWidth(FontRegular, 9, "Mug"), want: "Mug"},
+		{name: "cut", font: FontRegular, in: long, width: 100, cut: true},
+		{name: "cut bold", font: FontBold, in: long, width: 100, cut: true},
+		{name: "cut accented", font: FontRegular, in: strings.Repeat("é", 50), width: 60, cut: true},
+		{name: "nothing fits", font: FontRegular, in: "Mug", width: 5, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.font, 9, tt.in, tt.width)
+			if w := TextWidth(tt.font, 9, got); w > tt.width {
+				t.Fatalf("Truncate(%q) = %q is %v wide, want at most %v", tt.in, got, w, tt.width)
+			}
+			if !tt.cut {
+				if got != tt.want {
+					t.Fatalf("Truncate(%q) = %q, want %q", tt.in, got, tt.want)
+				}
+				return
+			}
+
+			prefix := strings.TrimSuffix(got, "...")
+			if prefix == got || !strings.HasPrefix(tt.in, prefix) {
+				t.Fatalf("Truncate(%q) = %q, want a prefix ending with ...", tt.in, got)
+			}
+			// One more character would not fit
+			longer := string([]rune(tt.in)[:len([]rune(prefix))+1]) + "..."
+			if TextWidth(tt.font, 9, longer) <= tt.width {
+				t.Fatalf("Truncate(%q) = %q, but %q fits too", tt.in, got, longer)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Standard fonts every PDF reader has, so nothing is embedded
+const (
+	FontRegular = "Helvetica"
+	FontBold    = "Helvetica-Bold"
+)
+
+// A4 page size in points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Resource names of the fonts in page content
+var fontResources = map[string]string{
+	FontRegular: "F1",
+	FontBold:    "F2",
+}
+
+// PDF document of A4 pages drawn with the standard fonts
+type Document struct {
+	pages []*Page
+}
+
+// Page content, coordinates are in points from the top left corner
+type Page struct {
+	content bytes.Buffer
+}
+
+// New empty document
+func New() *Document {
+	return &Document{}
+}
+
+// Add page at the end of the document
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Number of pages
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Draw text with its baseline at y
+func (p *Page) Text(x float64, y float64, font string, size float64, s string) {
+	resource, ok := fontResources[font]
+	if !ok {
+		resource = fontResources[FontRegular]
+	}
+
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td ", resource, num(size), num(x), num(PageHeight-y))
+	writeString(&p.content, encode(s))
+	p.content.WriteString(" Tj ET\n")
+}
+
+// Draw text ending at x
+func (p *Page) TextRight(x float64, y float64, font string, size float64, s string) {
+	p.Text(x-TextWidth(font, size, s), y, font, size, s)
+}
+
+// Draw straight line
+func (p *Page) Line(x1 float64, y1 float64, x2 float64, y2 float64, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", num(width), num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// Fill rectangle with gray, 0 is black and 1 is white
+func (p *Page) FillRect(x float64, y float64, width float64, height float64, gray float64) {
+	fmt.Fprintf(&p.content, "%s g %s %s %s %s re f 0 g\n", num(gray), num(x), num(PageHeight-y-height), num(width), num(height))
+}
+
+// Write the document as PDF
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		return 0, errors.New("pdf.Document.WriteTo: document has no pages")
+	}
+
+	// Objects: 1 catalog, 2 page tree, 3 and 4 fonts, then page and content of every page
+	objects := make([][]byte, 0, 4+2*len(d.pages))
+
+	kids := make([]string, 0, len(d.pages))
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+
+	objects = append(objects,
+		[]byte("<< /Type /Catalog /Pages 2 0 R >>"),
+		[]byte(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages))),
+		fontObject(FontRegular),
+		fontObject(FontBold),
+	)
+
+	for i, p := range d.pages {
+		page := fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(PageWidth), num(PageHeight), 6+2*i,
+		)
+
+		content, err := compress(p.content.Bytes())
+		if err != nil {
+			return 0, err
+		}
+		stream := fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n", len(content))
+		stream += string(content) + "\nendstream"
+
+		objects = append(objects, []byte(page), []byte(stream))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, 0, len(objects))
+	for i, obj := range objects {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n", i+1)
+		buf.Write(obj)
+		buf.WriteString("\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	n, err := w.Write(buf.Bytes())
+	if err != nil {
+		return int64(n), errors.Wrap(err, "pdf.Document.WriteTo.Write")
+	}
+
+	return int64(n), nil
+}
+
+// Document as PDF bytes
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func fontObject(font string) []byte {
+	return []byte(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font))
+}
+
+func compress(content []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(content); err != nil {
+		return nil, errors.Wrap(err, "pdf.compress.Write")
+	}
+	if err := zw.Close(); err != nil {
+		return nil, errors.Wrap(err, "pdf.compress.Close")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Write literal string, delimiters and bytes outside ASCII are escaped
+func writeString(buf *bytes.Buffer, s []byte) {
+	buf.WriteByte('(')
+	for _, c := range s {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c < 0x20 || c > 0x7e:
+			fmt.Fprintf(buf, "\\%03o", c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte(')')
+}
+
+// Number with at most two decimals
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
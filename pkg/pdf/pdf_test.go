@@ -0,0 +1,131 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testDocument(t *testing.T, pages int) []byte {
+	t.Helper()
+
+	doc := New()
+	for i := 0; i < pages; i++ {
+		p := doc.AddPage()
+		p.Text(50, 50, FontBold, 12, fmt.Sprintf("Page %d", i+1))
+		p.TextRight(545, 70, FontRegular, 9, "Total (incl. tax)")
+		p.Line(50, 80, 545, 80, 0.5)
+		p.FillRect(50, 90, 495, 16, 0.9)
+	}
+
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	return data
+}
+
+func TestWriteToXrefOffsets(t *testing.T) {
+	for _, pages := range []int{1, 3} {
+		t.Run(fmt.Sprintf("%d pages", pages), func(t *testing.T) {
+			data := testDocument(t, pages)
+
+			m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(data)
+			if m == nil {
+				t.Fatalf("no startxref trailer in %q", data[len(data)-40:])
+			}
+			xref, _ := strconv.Atoi(string(m[1]))
+			if !bytes.HasPrefix(data[xref:], []byte("xref\n")) {
+				t.Fatalf("startxref %d points at %q", xref, data[xref:xref+10])
+			}
+
+			lines := strings.Split(string(data[xref:]), "\n")
+			var first, count int
+			if _, err := fmt.Sscanf(lines[1], "%d %d", &first, &count); err != nil {
+				t.Fatalf("xref subsection header %q: %v", lines[1], err)
+			}
+			if want := 4 + 2*pages + 1; first != 0 || count != want {
+				t.Fatalf("xref subsection = %d %d, want 0 %d", first, count, want)
+			}
+
+			for i := 1; i < count; i++ {
+				entry := lines[2+i] + "\n"
+				if len(entry) != 20 {
+					t.Fatalf("xref entry %d is %d bytes, want 20", i, len(entry))
+				}
+				offset, _ := strconv.Atoi(entry[:10])
+				if want := fmt.Sprintf("%d 0 obj\n", i); !bytes.HasPrefix(data[offset:], []byte(want)) {
+					t.Fatalf("xref entry %d = %d points at %q, want %q", i, offset, data[offset:offset+len(want)], want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteToStreamLength(t *testing.T) {
+	data := testDocument(t, 2)
+
+	re := regexp.MustCompile(`<< /Length (\d+) /Filter /FlateDecode >>\nstream\n`)
+	matches := re.FindAllSubmatchIndex(data, -1)
+	if len(matches) != 2 {
+		t.Fatalf("found %d content streams, want 2", len(matches))
+	}
+
+	for i, m := range matches {
+		length, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		start := m[1]
+		if !bytes.HasPrefix(data[start+length:], []byte("\nendstream")) {
+			t.Fatalf("stream %d: /Length %d doesn't end at endstream", i, length)
+		}
+
+		zr, err := zlib.NewReader(bytes.NewReader(data[start : start+length]))
+		if err != nil {
+			t.Fatalf("stream %d: zlib.NewReader error = %v", i, err)
+		}
+		content, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("stream %d: inflate error = %v", i, err)
+		}
+		if want := fmt.Sprintf("(Page %d) Tj", i+1); !bytes.Contains(content, []byte(want)) {
+			t.Fatalf("stream %d content %q doesn't contain %q", i, content, want)
+		}
+	}
+}
+
+func TestWriteToWithoutPages(t *testing.T) {
+	if _, err := New().Bytes(); err == nil {
+		t.Fatal("Bytes() of a document without pages succeeded")
+	}
+}
+
+func TestWriteString(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain", in: "Invoice 42", want: `(Invoice 42)`},
+		{name: "parentheses", in: "Total (net)", want: `(Total \(net\))`},
+		{name: "unbalanced parenthesis", in: "a)b", want: `(a\)b)`},
+		{name: "backslash", in: `C:\path`, want: `(C:\\path)`},
+		{name: "latin-1", in: "Café", want: `(Caf\351)`},
+		{name: "euro", in: "€5", want: `(\2005)`},
+		{name: "outside winansi", in: "日本", want: `(??)`},
+		{name: "control characters", in: "a\tb\nc", want: `(a b c)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeString(&buf, encode(tt.in))
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("writeString(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package pdf
+
+import "fmt"
+
+// Page layout in points
+const (
+	margin      = 50
+	rowHeight   = 16
+	headerSize  = 20
+	bodySize    = 9
+	footerSpace = 40
+	detailWidth = 180
+)
+
+// Layout of a business document like an invoice: title with details, seller and customer, a table of lines and
+// totals. Templates only differ in labels and columns so documents of one store look alike.
+type Template struct {
+	Title      string
+	SellerHead string
+	PartyHead  string
+	Columns    []Column
+}
+
+// Table column, widths of all columns should add up to the page width without margins (495 points)
+type Column struct {
+	Title      string
+	Width      float64
+	AlignRight bool
+}
+
+// Label and value, like a detail in the header or a total
+type Field struct {
+	Label string
+	Value string
+}
+
+// Content of a document, every row has one cell per template column. The last total is printed in bold.
+type Data struct {
+	Details []Field
+	Seller  []string
+	Party   []string
+	Rows    [][]string
+	Totals  []Field
+	Notes   []string
+}
+
+// Render document, the table continues on new pages when it doesn't fit
+func (t *Template) Render(data *Data) ([]byte, error) {
+	doc := New()
+	page := doc.AddPage()
+
+	page.Text(margin, margin+headerSize, FontBold, headerSize, t.Title)
+
+	y := float64(margin + headerSize)
+	for _, d := range data.Details {
+		page.TextRight(PageWidth-margin-detailWidth-10, y, FontBold, bodySize, d.Label)
+		page.Text(PageWidth-margin-detailWidth, y, FontRegular, bodySize, Truncate(FontRegular, bodySize, d.Value, detailWidth))
+		y += rowHeight - 4
+	}
+
+	y += rowHeight * 2
+	half := (PageWidth - 2*margin) / 2
+	sellerEnd := t.block(page, margin, y, half-10, t.SellerHead, data.Seller)
+	partyEnd := t.block(page, margin+half, y, half, t.PartyHead, data.Party)
+	y = sellerEnd
+	if partyEnd > y {
+		y = partyEnd
+	}
+
+	y += rowHeight * 2
+	y = t.tableHeader(page, y)
+	for _, row := range data.Rows {
+		if y+rowHeight > PageHeight-margin-footerSpace {
+			page = doc.AddPage()
+			y = t.tableHeader(page, margin)
+		}
+		t.row(page, y, FontRegular, row)
+		y += rowHeight
+	}
+
+	page.Line(margin, y-rowHeight+6, PageWidth-margin, y-rowHeight+6, 0.5)
+	y += 4
+
+	if y+rowHeight*float64(len(data.Totals)+len(data.Notes)+1) > PageHeight-margin-footerSpace {
+		page = doc.AddPage()
+		y = margin + rowHeight
+	}
+
+	for i, total := range data.Totals {
+		font := FontRegular
+		if i == len(data.Totals)-1 {
+			font = FontBold
+		}
+		page.TextRight(PageWidth-margin-110, y, font, bodySize, total.Label)
+		page.TextRight(PageWidth-margin, y, font, bodySize, total.Value)
+		y += rowHeight
+	}
+
+	y += rowHeight
+	for _, note := range data.Notes {
+		page.Text(margin, y, FontRegular, bodySize, Truncate(FontRegular, bodySize, note, PageWidth-2*margin))
+		y += rowHeight - 4
+	}
+
+	for i, p := range doc.pages {
+		footer := fmt.Sprintf("Page %d of %d", i+1, doc.PageCount())
+		p.TextRight(PageWidth-margin, PageHeight-margin, FontRegular, bodySize-1, footer)
+	}
+
+	return doc.Bytes()
+}
+
+// Block of lines under a bold head, returns y after the block
+func (t *Template) block(page *Page, x float64, y float64, width float64, head string, lines []string) float64 {
+	page.Text(x, y, FontBold, bodySize, head)
+	for _, line := range lines {
+		y += rowHeight - 4
+		page.Text(x, y, FontRegular, bodySize, Truncate(FontRegular, bodySize, line, width))
+	}
+
+	return y
+}
+
+// Table head on gray background, returns y of the first row
+func (t *Template) tableHeader(page *Page, y float64) float64 {
+	page.FillRect(margin, y-rowHeight+4, PageWidth-2*margin, rowHeight, 0.9)
+
+	titles := make([]string, 0, len(t.Columns))
+	for _, c := range t.Columns {
+		titles = append(titles, c.Title)
+	}
+	t.row(page, y, FontBold, titles)
+
+	return y + rowHeight + 2
+}
+
+func (t *Template) row(page *Page, y float64, font string, cells []string) {
+	x := float64(margin)
+	for i, c := range t.Columns {
+		if i < len(cells) && cells[i] != "" {
+			text := Truncate(font, bodySize, cells[i], c.Width-6)
+			if c.AlignRight {
+				page.TextRight(x+c.Width-3, y, font, bodySize, text)
+			} else {
+				page.Text(x+3, y, font, bodySize, text)
+			}
+		}
+		x += c.Width
+	}
+}
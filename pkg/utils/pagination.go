@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPageSize = 10
+	maxPageSize     = 100
+)
+
+// Pagination query params
+type PaginationQuery struct {
+	Size int `json:"size,omitempty"`
+	Page int `json:"page,omitempty"`
+}
+
+// Set page size
+func (q *PaginationQuery) SetSize(sizeQuery string) error {
+	if sizeQuery == "" {
+		q.Size = defaultPageSize
+		return nil
+	}
+	n, err := strconv.Atoi(sizeQuery)
+	if err != nil {
+		return err
+	}
+	if n <= 0 {
+		n = defaultPageSize
+	}
+	if n > maxPageSize {
+		n = maxPageSize
+	}
+	q.Size = n
+
+	return nil
+}
+
+// Set page number
+func (q *PaginationQuery) SetPage(pageQuery string) error {
+	if pageQuery == "" {
+		q.Page = 1
+		return nil
+	}
+	n, err := strconv.Atoi(pageQuery)
+	if err != nil {
+		return err
+	}
+	if n <= 0 {
+		n = 1
+	}
+	q.Page = n
+
+	return nil
+}
+
+// Get offset
+func (q *PaginationQuery) GetOffset() int {
+	return (q.Page - 1) * q.Size
+}
+
+// Get limit
+func (q *PaginationQuery) GetLimit() int {
+	return q.Size
+}
+
+// Get page
+func (q *PaginationQuery) GetPage() int {
+	return q.Page
+}
+
+// Get size
+func (q *PaginationQuery) GetSize() int {
+	return q.Size
+}
+
+// Get total pages
+func GetTotalPages(totalCount int, pageSize int) int {
+	return int(math.Ceil(float64(totalCount) / float64(pageSize)))
+}
+
+// Get has more
+func GetHasMore(currentPage int, totalCount int, pageSize int) bool {
+	return currentPage < GetTotalPages(totalCount, pageSize)
+}
+
+// Get pagination query struct from echo context
+func GetPaginationFromCtx(c echo.Context) (*PaginationQuery, error) {
+	q := &PaginationQuery{}
+	if err := q.SetPage(c.QueryParam("page")); err != nil {
+		return nil, err
+	}
+	if err := q.SetSize(c.QueryParam("size")); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidIDToken = errors.New("invalid signed id")
+	ErrExpiredIDToken = errors.New("expired signed id")
+)
+
+// Sign id for purpose, the token is the id, its expiry in unix seconds and their HMAC-SHA256, base64url encoded.
+// A zero expiresAt never expires. The purpose is part of the MAC so a token of one purpose is rejected by another.
+func SignID(secret string, purpose string, id uuid.UUID, expiresAt time.Time) string {
+	expiry := idTokenExpiry(expiresAt)
+
+	return base64.RawURLEncoding.EncodeToString(id[:]) + "." +
+		base64.RawURLEncoding.EncodeToString(expiry) + "." +
+		base64.RawURLEncoding.EncodeToString(idTokenMAC(secret, purpose, id, expiry))
+}
+
+// Id of a token made by SignID with the same secret and purpose, now is checked against its expiry
+func VerifySignedID(secret string, purpose string, token string, now time.Time) (uuid.UUID, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return uuid.Nil, ErrInvalidIDToken
+	}
+
+	idBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return uuid.Nil, ErrInvalidIDToken
+	}
+	id, err := uuid.FromBytes(idBytes)
+	if err != nil {
+		return uuid.Nil, ErrInvalidIDToken
+	}
+
+	expiry, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || len(expiry) != 8 {
+		return uuid.Nil, ErrInvalidIDToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(mac, idTokenMAC(secret, purpose, id, expiry)) {
+		return uuid.Nil, ErrInvalidIDToken
+	}
+
+	if unix := int64(binary.BigEndian.Uint64(expiry)); unix != 0 && now.Unix() >= unix {
+		return uuid.Nil, ErrExpiredIDToken
+	}
+
+	return id, nil
+}
+
+func idTokenExpiry(expiresAt time.Time) []byte {
+	expiry := make([]byte, 8)
+	if !expiresAt.IsZero() {
+		binary.BigEndian.PutUint64(expiry, uint64(expiresAt.Unix()))
+	}
+	return expiry
+}
+
+func idTokenMAC(secret string, purpose string, id uuid.UUID, expiry []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	mac.Write([]byte{0})
+	mac.Write(id[:])
+	mac.Write(expiry)
+	return mac.Sum(nil)
+}
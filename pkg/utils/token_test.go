@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+func TestVerifySignedID(t *testing.T) {
+	id := uuid.New()
+	now := time.Unix(1700000000, 0)
+	token := SignID("secret", "cart", id, time.Time{})
+	expiring := SignID("secret", "cart-recovery", id, now.Add(time.Hour))
+
+	tampered := []byte(token)
+	tampered[len(tampered)-1] ^= 1
+	otherID := SignID("secret", "cart", uuid.New(), time.Time{})
+	parts := strings.Split(token, ".")
+
+	tests := []struct {
+		name    string
+		secret  string
+		purpose string
+		token   string
+		now     time.Time
+		want    error
+	}{
+		{name: "valid", secret: "secret", purpose: "cart", token: token, now: now},
+		{name: "no expiry far in the future", secret: "secret", purpose: "cart", token: token, now: now.AddDate(10, 0, 0)},
+		{name: "before expiry", secret: "secret", purpose: "cart-recovery", token: expiring, now: now.Add(time.Hour - time.Second)},
+		{name: "at expiry", secret: "secret", purpose: "cart-recovery", token: expiring, now: now.Add(time.Hour), want: ErrExpiredIDToken},
+		{name: "other secret", secret: "other", purpose: "cart", token: token, now: now, want: ErrInvalidIDToken},
+		{name: "other purpose", secret: "secret", purpose: "unsubscribe", token: token, now: now, want: ErrInvalidIDToken},
+		{name: "tampered mac", secret: "secret", purpose: "cart", token: string(tampered), now: now, want: ErrInvalidIDToken},
+		{name: "swapped id", secret: "secret", purpose: "cart", token: strings.Split(otherID, ".")[0] + "." + parts[1] + "." + parts[2], now: now, want: ErrInvalidIDToken},
+		{name: "extended expiry", secret: "secret", purpose: "cart", token: parts[0] + "." + strings.Split(expiring, ".")[1] + "." + parts[2], now: now, want: ErrInvalidIDToken},
+		{name: "missing part", secret: "secret", purpose: "cart", token: parts[0] + "." + parts[2], now: now, want: ErrInvalidIDToken},
+		{name: "not base64", secret: "secret", purpose: "cart", token: "!!." + parts[1] + "." + parts[2], now: now, want: ErrInvalidIDToken},
+		{name: "empty", secret: "secret", purpose: "cart", token: "", now: now, want: ErrInvalidIDToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifySignedID(tt.secret, tt.purpose, tt.token, tt.now)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("VerifySignedID() error = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && got != id {
+				t.Fatalf("VerifySignedID() = %s, want %s", got, id)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
 )
 
 // Use a single instance of validate, it caches struct info
@@ -17,3 +18,14 @@ func init() {
 func ValidateStruct(ctx context.Context, s interface{}) error {
 	return validate.StructCtx(ctx, s)
 }
+
+// Validate single value against validator tag, malformed tags are reported as errors
+func ValidateVar(ctx context.Context, field interface{}, tag string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("invalid validation rules %q: %v", tag, r)
+		}
+	}()
+
+	return validate.VarCtx(ctx, field, tag)
+}